@@ -6,7 +6,6 @@ package db
 import (
 	"context"
 	"database/sql"
-	"strings"
 	"sync"
 	"time"
 
@@ -24,9 +23,10 @@ const (
 )
 
 var (
-	logger   = runtime.Logger()
-	tracer   trace.Tracer
-	initOnce sync.Once
+	logger      = runtime.Logger()
+	tracer      trace.Tracer
+	queryConfig semconv.QueryConfig
+	initOnce    sync.Once
 )
 
 // dbClientEnabler controls whether client instrumentation is enabled
@@ -38,6 +38,10 @@ func (n dbClientEnabler) Enable() bool {
 
 var clientEnabler = dbClientEnabler{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, clientEnabler)
+}
+
 func beforeOpenInstrumentation(ictx hook.HookContext, driverName, dataSourceName string) {
 	info := ParseDSN(driverName, dataSourceName)
 	addr := info.Addr()
@@ -182,7 +186,7 @@ func afterQueryContextInstrumentation(ictx hook.HookContext, rows *sql.Rows, err
 	if !clientEnabler.Enable() {
 		return
 	}
-	instrumentEnd(ictx, err)
+	instrumentQueryEnd(ictx, rows, err)
 }
 
 func beforeTxInstrumentation(ictx hook.HookContext, db *sql.DB, ctx context.Context, opts *sql.TxOptions) {
@@ -357,7 +361,7 @@ func afterConnQueryContextInstrumentation(ictx hook.HookContext, rows *sql.Rows,
 	if !clientEnabler.Enable() {
 		return
 	}
-	instrumentEnd(ictx, err)
+	instrumentQueryEnd(ictx, rows, err)
 }
 
 func beforeConnTxInstrumentation(ictx hook.HookContext, conn *sql.Conn, ctx context.Context, opts *sql.TxOptions) {
@@ -502,7 +506,7 @@ func afterTxQueryContextInstrumentation(ictx hook.HookContext, rows *sql.Rows, e
 	if !clientEnabler.Enable() {
 		return
 	}
-	instrumentEnd(ictx, err)
+	instrumentQueryEnd(ictx, rows, err)
 }
 
 func beforeTxCommitInstrumentation(ictx hook.HookContext, tx *sql.Tx) {
@@ -588,7 +592,7 @@ func afterStmtQueryContextInstrumentation(ictx hook.HookContext, rows *sql.Rows,
 	if !clientEnabler.Enable() {
 		return
 	}
-	instrumentEnd(ictx, err)
+	instrumentQueryEnd(ictx, rows, err)
 }
 
 func instrumentStart(
@@ -601,22 +605,34 @@ func instrumentStart(
 		logger.Debug("Db client instrumentation disabled")
 		return
 	}
+	if runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient) {
+		return
+	}
 	initInstrumentation()
+	info := semconv.ParseQuery(query, driverName)
+	queryText := query
+	if queryConfig.Sanitize {
+		queryText = semconv.SanitizeQuery(query, driverName)
+	}
 	req := semconv.DatabaseSqlRequest{
-		OpType:     calOp(query),
-		Sql:        query,
+		OpType:     info.Operation,
+		Sql:        queryText,
 		Endpoint:   endpoint,
 		DriverName: driverName,
 		Dsn:        dsn,
 		Params:     args,
 		DbName:     dbName,
+		Collection: info.Collection(),
+		Summary:    info.Summary,
 	}
 	// Get trace attributes from semconv
 	attrs := semconv.DbClientRequestTraceAttrs(req)
+	if queryConfig.CaptureParameters {
+		attrs = append(attrs, semconv.DbQueryParameterAttrs(args)...)
+	}
 
-	// Start span
 	ctx, span := tracer.Start(ctx,
-		req.OpType,
+		querySpanName(req),
 		trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(attrs...),
 	)
@@ -649,16 +665,13 @@ func instrumentEnd(ictx hook.HookContext, err error) {
 	}
 }
 
-func calOp(sql string) string {
-	trimmed := strings.TrimSpace(sql)
-	if trimmed == "" {
-		return ""
-	}
-	fields := strings.Fields(trimmed)
-	if len(fields) == 0 {
-		return ""
+// querySpanName follows db.query.summary (e.g. "SELECT users") and falls
+// back to the operation for statements without a recognizable target.
+func querySpanName(req semconv.DatabaseSqlRequest) string {
+	if req.Summary != "" {
+		return req.Summary
 	}
-	return strings.ToUpper(fields[0])
+	return req.OpType
 }
 
 func initInstrumentation() {
@@ -667,6 +680,7 @@ func initInstrumentation() {
 			instrumentationName,
 			trace.WithInstrumentationVersion(runtime.ModuleVersion()),
 		)
+		queryConfig = semconv.QueryConfigFromEnv()
 		logger.Info("DB client instrumentation initialized")
 	})
 }
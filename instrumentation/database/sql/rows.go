@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otelc/instrumentation/database/sql/semconv"
+	"go.opentelemetry.io/otelc/pkg/hook"
+)
+
+// rowsData is stored in the SpanData field of a *sql.Rows returned by an
+// instrumented query. It accumulates the returned row count while the caller
+// iterates and emits a child span of the query span covering fetch time once
+// the rows are closed.
+//
+// The span is only created at close time, with an explicit start timestamp,
+// so it never sits open on the goroutine-local span stack while user code
+// runs inside the rows.Next loop.
+type rowsData struct {
+	ctx   context.Context
+	req   semconv.DatabaseSqlRequest
+	start time.Time
+	rows  atomic.Int64
+	once  sync.Once
+
+	mu  sync.Mutex
+	err error
+}
+
+func (d *rowsData) setErr(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *rowsData) end(err error) {
+	d.once.Do(func() {
+		if err == nil {
+			d.mu.Lock()
+			err = d.err
+			d.mu.Unlock()
+		}
+		_, span := tracer.Start(d.ctx,
+			querySpanName(d.req)+" rows",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithTimestamp(d.start),
+			trace.WithAttributes(semconv.DbClientRowsTraceAttrs(d.req, int(d.rows.Load()))...),
+		)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	})
+}
+
+// instrumentQueryEnd ends the query span and, when the query produced rows,
+// attaches the state needed to trace their iteration.
+func instrumentQueryEnd(ictx hook.HookContext, rows *sql.Rows, err error) {
+	instrumentEnd(ictx, err)
+	if err != nil || rows == nil {
+		return
+	}
+	ctx, ok := ictx.GetKeyData("ctx").(context.Context)
+	if !ok {
+		return
+	}
+	req, ok := ictx.GetKeyData("req").(semconv.DatabaseSqlRequest)
+	if !ok {
+		return
+	}
+	rows.SpanData = &rowsData{ctx: ctx, req: req, start: time.Now()}
+}
+
+// The Rows hooks run once per fetched row, so they deliberately skip the
+// enabler check: rows only carry rowsData when the query itself was traced.
+
+func beforeRowsNextInstrumentation(ictx hook.HookContext, rows *sql.Rows) {
+	if rows == nil || rows.SpanData == nil {
+		return
+	}
+	ictx.SetData(rows.SpanData)
+}
+
+func afterRowsNextInstrumentation(ictx hook.HookContext, ok bool) {
+	data, isRows := ictx.GetData().(*rowsData)
+	if !isRows || !ok {
+		return
+	}
+	data.rows.Add(1)
+}
+
+func beforeRowsErrInstrumentation(ictx hook.HookContext, rows *sql.Rows) {
+	if rows == nil || rows.SpanData == nil {
+		return
+	}
+	ictx.SetData(rows.SpanData)
+}
+
+func afterRowsErrInstrumentation(ictx hook.HookContext, err error) {
+	data, ok := ictx.GetData().(*rowsData)
+	if !ok || err == nil {
+		return
+	}
+	data.setErr(err)
+}
+
+func beforeRowsCloseInstrumentation(ictx hook.HookContext, rows *sql.Rows) {
+	if rows == nil || rows.SpanData == nil {
+		return
+	}
+	ictx.SetData(rows)
+}
+
+func afterRowsCloseInstrumentation(ictx hook.HookContext, err error) {
+	rows, ok := ictx.GetData().(*sql.Rows)
+	if !ok {
+		return
+	}
+	data, ok := rows.SpanData.(*rowsData)
+	if !ok {
+		return
+	}
+	// Rows.Next closes the rows itself on EOF or a driver error, before the
+	// caller gets a chance to call Err, so read the iteration error here.
+	if err == nil {
+		err = rows.Err()
+	}
+	data.end(err)
+}
@@ -19,6 +19,10 @@ type DatabaseSqlRequest struct {
 	Dsn        string
 	Params     []any
 	DbName     string
+	// Collection is the single table targeted by the statement, if any.
+	Collection string
+	// Summary is the low-cardinality db.query.summary, e.g. "SELECT users".
+	Summary string
 }
 
 func DbClientRequestTraceAttrs(req DatabaseSqlRequest) []attribute.KeyValue {
@@ -35,6 +39,13 @@ func DbClientRequestTraceAttrs(req DatabaseSqlRequest) []attribute.KeyValue {
 		semconv.DBQueryText(req.Sql),
 	}
 
+	if req.Collection != "" {
+		attrs = append(attrs, semconv.DBCollectionName(req.Collection))
+	}
+	if req.Summary != "" {
+		attrs = append(attrs, semconv.DBQuerySummary(req.Summary))
+	}
+
 	if err == nil {
 		if port, convErr := strconv.Atoi(portStr); convErr == nil && port > 0 {
 			attrs = append(attrs, semconv.ServerPort(port))
@@ -60,3 +71,9 @@ func DbClientRequestTraceAttrs(req DatabaseSqlRequest) []attribute.KeyValue {
 
 	return attrs
 }
+
+// DbClientRowsTraceAttrs returns the attributes for the span covering result
+// set iteration: the request attributes plus db.response.returned_rows.
+func DbClientRowsTraceAttrs(req DatabaseSqlRequest, returnedRows int) []attribute.KeyValue {
+	return append(DbClientRequestTraceAttrs(req), semconv.DBResponseReturnedRows(returnedRows))
+}
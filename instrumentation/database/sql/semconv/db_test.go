@@ -270,3 +270,23 @@ func TestDbClientRequestTraceAttrs_ContainsExpectedKeys(t *testing.T) {
 		assert.True(t, keySet[key], "expected key %s not found in attributes", key)
 	}
 }
+
+func TestDbClientRowsTraceAttrs(t *testing.T) {
+	req := DatabaseSqlRequest{
+		OpType:     "SELECT",
+		Sql:        "SELECT * FROM users",
+		Endpoint:   "localhost:5432",
+		DriverName: "postgres",
+		DbName:     "app",
+	}
+
+	attrs := DbClientRowsTraceAttrs(req, 3)
+
+	attrMap := make(map[string]interface{})
+	for _, attr := range attrs {
+		attrMap[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	assert.Equal(t, int64(3), attrMap["db.response.returned_rows"])
+	assert.Equal(t, "SELECT", attrMap["db.operation.name"])
+	assert.Len(t, attrs, len(DbClientRequestTraceAttrs(req))+1)
+}
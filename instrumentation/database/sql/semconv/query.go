@@ -0,0 +1,613 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package semconv
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const (
+	// envQuerySanitization toggles literal obfuscation of db.query.text.
+	// Sanitization is on by default; set it to "false" to record raw SQL.
+	envQuerySanitization = "OTEL_GO_DB_QUERY_SANITIZATION_ENABLED"
+	// envQueryParameters toggles recording of db.query.parameter.<key>.
+	// Parameters may carry PII, so they are only recorded when set to "true".
+	envQueryParameters = "OTEL_GO_DB_QUERY_PARAMETERS_ENABLED"
+
+	// maxSummaryLength bounds db.query.summary, which is also used as the
+	// span name, per the database semantic conventions.
+	maxSummaryLength = 255
+)
+
+// QueryConfig controls how statements and their arguments are recorded.
+type QueryConfig struct {
+	// Sanitize replaces literals in db.query.text with "?" placeholders.
+	Sanitize bool
+	// CaptureParameters records the statement arguments as
+	// db.query.parameter.<key> attributes.
+	CaptureParameters bool
+}
+
+// QueryConfigFromEnv reads the QueryConfig from the environment. Unset or
+// unparsable values fall back to the safe defaults: sanitize, and do not
+// capture parameters.
+func QueryConfigFromEnv() QueryConfig {
+	return QueryConfig{
+		Sanitize:          envBool(envQuerySanitization, true),
+		CaptureParameters: envBool(envQueryParameters, false),
+	}
+}
+
+func envBool(key string, def bool) bool {
+	v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(key)))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// QueryInfo is the low-cardinality shape of a SQL statement.
+type QueryInfo struct {
+	// Operation is the upper-cased leading keyword, e.g. "SELECT".
+	Operation string
+	// Collections lists the tables the statement touches, in order of
+	// appearance.
+	Collections []string
+	// Summary is the db.query.summary value, e.g. "SELECT users".
+	Summary string
+}
+
+// Collection returns the db.collection.name value. It is only set when the
+// statement targets exactly one table, as a join or a subquery over several
+// tables has no single collection.
+func (q QueryInfo) Collection() string {
+	if len(q.Collections) != 1 {
+		return ""
+	}
+	return q.Collections[0]
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenQuotedIdent
+	tokenLiteral
+	tokenPlaceholder
+	tokenPunct
+	tokenSpace
+	tokenComment
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// backslashEscapes reports whether a backslash escapes the next character in
+// the string literals of the database behind driverName. Only MySQL and its
+// derivatives do so by default; elsewhere, such as PostgreSQL with
+// standard_conforming_strings, a backslash is an ordinary character and only
+// a doubled quote continues a literal.
+func backslashEscapes(driverName string) bool {
+	switch driverName {
+	case "mysql", "mariadb", "clickhouse":
+		return true
+	}
+	return false
+}
+
+// tokenize splits a SQL statement into a flat token stream. It understands
+// just enough of the common dialects (MySQL, PostgreSQL, SQLite, SQL Server,
+// Oracle) to tell literals apart from identifiers and placeholders; it never
+// fails, and unterminated constructs simply run to the end of the input.
+// backslash tells whether a backslash escapes the next character in string
+// literals, see backslashEscapes.
+func tokenize(query string, backslash bool) []token {
+	var tokens []token
+	emit := func(kind tokenKind, text string) {
+		tokens = append(tokens, token{kind: kind, text: text})
+	}
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case isSpace(c):
+			j := i + 1
+			for j < len(query) && isSpace(query[j]) {
+				j++
+			}
+			emit(tokenSpace, query[i:j])
+			i = j
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			j := strings.IndexByte(query[i:], '\n')
+			if j < 0 {
+				j = len(query) - i
+			}
+			emit(tokenComment, query[i:i+j])
+			i += j
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			j := strings.Index(query[i+2:], "*/")
+			if j < 0 {
+				j = len(query) - i
+			} else {
+				j += 4
+			}
+			emit(tokenComment, query[i:i+j])
+			i += j
+		case c == '\'':
+			j := scanQuoted(query, i, '\'', backslash)
+			emit(tokenLiteral, query[i:j])
+			i = j
+		case c == '"' || c == '`':
+			j := scanQuoted(query, i, c, false)
+			emit(tokenQuotedIdent, query[i:j])
+			i = j
+		case c == '$':
+			if j, ok := scanDollarQuoted(query, i); ok {
+				emit(tokenLiteral, query[i:j])
+				i = j
+				continue
+			}
+			j := i + 1
+			for j < len(query) && isDigit(query[j]) {
+				j++
+			}
+			emit(tokenPlaceholder, query[i:j])
+			i = j
+		case c == '?':
+			emit(tokenPlaceholder, "?")
+			i++
+		case (c == ':' || c == '@') && i+1 < len(query) && isWordStart(query[i+1]):
+			// Named placeholders such as :name, @p1 and @name. A "::" cast
+			// never reaches here because the second ':' is not a word start.
+			j := i + 1
+			for j < len(query) && isWordPart(query[j]) {
+				j++
+			}
+			emit(tokenPlaceholder, query[i:j])
+			i = j
+		case isDigit(c) || (c == '.' && i+1 < len(query) && isDigit(query[i+1])):
+			j := scanNumber(query, i)
+			emit(tokenLiteral, query[i:j])
+			i = j
+		case isWordStart(c):
+			j := i + 1
+			for j < len(query) && isWordPart(query[j]) {
+				j++
+			}
+			// String literals with a one-letter prefix: N'..', E'..', X'..', B'..'.
+			if j == i+1 && j < len(query) && query[j] == '\'' && strings.ContainsRune("NnEeXxBb", rune(c)) {
+				// PostgreSQL E'..' strings take backslash escapes in any dialect
+				k := scanQuoted(query, j, '\'', backslash || c|0x20 == 'e')
+				emit(tokenLiteral, query[i:k])
+				i = k
+				continue
+			}
+			emit(tokenWord, query[i:j])
+			i = j
+		default:
+			_, size := utf8.DecodeRuneInString(query[i:])
+			emit(tokenPunct, query[i:i+size])
+			i += size
+		}
+	}
+	return tokens
+}
+
+// scanQuoted returns the index just past the quoted run starting at start.
+// A doubled quote character continues the run and so, when backslash is set,
+// does a backslash escape.
+func scanQuoted(s string, start int, quote byte, backslash bool) int {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// scanDollarQuoted recognises PostgreSQL dollar-quoted strings ($$..$$ and
+// $tag$..$tag$) starting at start.
+func scanDollarQuoted(s string, start int) (int, bool) {
+	j := start + 1
+	if j < len(s) && isDigit(s[j]) {
+		// $1 is a positional placeholder, not a string.
+		return 0, false
+	}
+	for j < len(s) && (isWordStart(s[j]) || isDigit(s[j])) {
+		j++
+	}
+	if j >= len(s) || s[j] != '$' {
+		return 0, false
+	}
+	tag := s[start : j+1]
+	end := strings.Index(s[j+1:], tag)
+	if end < 0 {
+		return len(s), true
+	}
+	return j + 1 + end + len(tag), true
+}
+
+func scanNumber(s string, start int) int {
+	i := start
+	if strings.HasPrefix(s[i:], "0x") || strings.HasPrefix(s[i:], "0X") {
+		i += 2
+		for i < len(s) && isHexDigit(s[i]) {
+			i++
+		}
+		return i
+	}
+	for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+		i++
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isDigit(s[j]) {
+			i = j
+			for i < len(s) && isDigit(s[i]) {
+				i++
+			}
+		}
+	}
+	return i
+}
+
+func isSpace(c byte) bool    { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' }
+func isDigit(c byte) bool    { return c >= '0' && c <= '9' }
+func isHexDigit(c byte) bool { return isDigit(c) || (c|0x20 >= 'a' && c|0x20 <= 'f') }
+func isWordStart(c byte) bool {
+	return c == '_' || (c|0x20 >= 'a' && c|0x20 <= 'z') || c >= utf8.RuneSelf
+}
+func isWordPart(c byte) bool { return isWordStart(c) || isDigit(c) || c == '$' || c == '#' }
+
+// inListPattern matches an IN-list made only of placeholders, such as the
+// result of sanitizing IN (1, 2, 3). Collapsing it keeps db.query.text from
+// growing a distinct value per list length.
+var inListPattern = regexp.MustCompile(`(?i)\b(IN)\s*\(\s*(?:\?|\$\d+)(?:\s*,\s*(?:\?|\$\d+))*\s*\)`)
+
+// SanitizeQuery replaces string, numeric and hex literals with "?", drops
+// comments and collapses IN-lists to a single placeholder. Identifiers and
+// existing placeholders ($1, :name, @p1) are kept as written. driverName
+// selects how string literals are escaped, see backslashEscapes.
+func SanitizeQuery(query, driverName string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	for _, tok := range tokenize(query, backslashEscapes(driverName)) {
+		switch tok.kind {
+		case tokenLiteral:
+			b.WriteByte('?')
+		case tokenComment:
+			// Comments may embed arbitrary values; drop them entirely.
+		default:
+			b.WriteString(tok.text)
+		}
+	}
+	return strings.TrimSpace(inListPattern.ReplaceAllString(b.String(), "$1 (?)"))
+}
+
+// tableKeywords are the keywords after which a table name is expected.
+// UPDATE is one only as the leading keyword of a statement: elsewhere it is
+// part of ON DUPLICATE KEY UPDATE, FOR UPDATE or MERGE ... THEN UPDATE.
+var tableKeywords = map[string]bool{
+	"FROM":  true,
+	"JOIN":  true,
+	"INTO":  true,
+	"TABLE": true,
+	"USING": true,
+}
+
+// operationKeywords start a statement, or a nested statement, and become part
+// of db.query.summary.
+var operationKeywords = map[string]bool{
+	"SELECT":   true,
+	"INSERT":   true,
+	"UPDATE":   true,
+	"DELETE":   true,
+	"MERGE":    true,
+	"REPLACE":  true,
+	"UPSERT":   true,
+	"CREATE":   true,
+	"DROP":     true,
+	"ALTER":    true,
+	"TRUNCATE": true,
+	"CALL":     true,
+}
+
+// objectKeywords follow a DDL operation and are folded into it, so that
+// "CREATE TABLE users" summarizes as "CREATE TABLE users".
+var objectKeywords = map[string]bool{
+	"TABLE":     true,
+	"INDEX":     true,
+	"VIEW":      true,
+	"DATABASE":  true,
+	"SCHEMA":    true,
+	"SEQUENCE":  true,
+	"PROCEDURE": true,
+	"FUNCTION":  true,
+}
+
+// ParseQuery extracts the operation, target tables and db.query.summary from
+// a SQL statement without a full parser. Statements it does not understand
+// still yield their leading keyword as Operation and Summary, matching what
+// span names looked like before summaries were introduced. A statement with
+// common table expressions is summarized by its outer statement. driverName
+// selects how string literals are escaped, see backslashEscapes.
+func ParseQuery(query, driverName string) QueryInfo {
+	var words []token
+	for _, tok := range tokenize(query, backslashEscapes(driverName)) {
+		if tok.kind != tokenSpace && tok.kind != tokenComment {
+			words = append(words, tok)
+		}
+	}
+	words = skipCommonTableExpressions(words)
+	var info QueryInfo
+	var parts []string
+	seen := map[string]bool{}
+	// For each open parenthesis, whether it opens a subquery rather than the
+	// arguments of a function call or an expression: FROM is a table keyword
+	// in a subquery, not in EXTRACT(YEAR FROM created).
+	var subquery []bool
+	for i := 0; i < len(words); i++ {
+		tok := words[i]
+		if tok.kind == tokenPunct {
+			switch tok.text {
+			case "(":
+				subquery = append(subquery, startsSubquery(words, i+1))
+			case ")":
+				if len(subquery) > 0 {
+					subquery = subquery[:len(subquery)-1]
+				}
+			}
+			continue
+		}
+		if tok.kind != tokenWord {
+			continue
+		}
+		kw := strings.ToUpper(tok.text)
+		if info.Operation == "" {
+			info.Operation = kw
+			parts = append(parts, kw)
+			if operationKeywords[kw] && i+1 < len(words) && objectKeywords[strings.ToUpper(words[i+1].text)] {
+				parts[len(parts)-1] += " " + strings.ToUpper(words[i+1].text)
+				i++
+				i = collectTables(words, i, &info, &parts, seen)
+			}
+			if kw == "UPDATE" {
+				i = collectTables(words, i, &info, &parts, seen)
+			}
+			continue
+		}
+		switch {
+		case kw == "SELECT":
+			parts = append(parts, kw)
+		case kw == "FROM" && len(subquery) > 0 && !subquery[len(subquery)-1]:
+			// EXTRACT(YEAR FROM created), SUBSTRING(name FROM 2)
+		case kw == "FROM" && strings.EqualFold(words[i-1].text, "DISTINCT"):
+			// a IS DISTINCT FROM b
+		case tableKeywords[kw]:
+			i = collectTables(words, i, &info, &parts, seen)
+		}
+	}
+	info.Summary = truncateSummary(strings.Join(parts, " "))
+	return info
+}
+
+// startsSubquery reports whether words[i], just past an open parenthesis,
+// starts a subquery.
+func startsSubquery(words []token, i int) bool {
+	if i >= len(words) || words[i].kind != tokenWord {
+		return false
+	}
+	kw := strings.ToUpper(words[i].text)
+	return kw == "SELECT" || kw == "WITH"
+}
+
+// skipCommonTableExpressions returns words past the WITH clause that starts
+// them, if any, so that the statement is summarized by its outer statement:
+// "WITH a AS (SELECT ...) SELECT ... FROM a" as "SELECT a". words are returned
+// as is when the clause does not parse.
+func skipCommonTableExpressions(words []token) []token {
+	if len(words) == 0 || words[0].kind != tokenWord || !strings.EqualFold(words[0].text, "WITH") {
+		return words
+	}
+	i := 1
+	if i < len(words) && strings.EqualFold(words[i].text, "RECURSIVE") {
+		i++
+	}
+	for {
+		// name [(columns)] AS [[NOT] MATERIALIZED] (statement)
+		if i >= len(words) || (words[i].kind != tokenWord && words[i].kind != tokenQuotedIdent) {
+			return words
+		}
+		i++
+		if i < len(words) && words[i].text == "(" {
+			i = skipParenthesized(words, i)
+		}
+		if i >= len(words) || !strings.EqualFold(words[i].text, "AS") {
+			return words
+		}
+		i++
+		for i < len(words) && (strings.EqualFold(words[i].text, "NOT") ||
+			strings.EqualFold(words[i].text, "MATERIALIZED")) {
+			i++
+		}
+		if i >= len(words) || words[i].text != "(" {
+			return words
+		}
+		i = skipParenthesized(words, i)
+		if i >= len(words) || words[i].text != "," {
+			break
+		}
+		i++
+	}
+	if i >= len(words) {
+		return words
+	}
+	return words[i:]
+}
+
+// skipParenthesized returns the index just past the parenthesis matching the
+// one at words[i], or len(words) when it is not closed.
+func skipParenthesized(words []token, i int) int {
+	depth := 0
+	for ; i < len(words); i++ {
+		if words[i].kind != tokenPunct {
+			continue
+		}
+		switch words[i].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(words)
+}
+
+// collectTables reads the comma-separated table list following the keyword
+// at words[i] and returns the index of the last token consumed.
+func collectTables(words []token, i int, info *QueryInfo, parts *[]string, seen map[string]bool) int {
+	for {
+		name, next := readTableName(words, i+1)
+		if name == "" {
+			return i
+		}
+		if !seen[name] {
+			seen[name] = true
+			info.Collections = append(info.Collections, name)
+			*parts = append(*parts, name)
+		}
+		i = next - 1
+		// Skip an optional alias: "users u" or "users AS u".
+		if next < len(words) && words[next].kind == tokenWord && strings.EqualFold(words[next].text, "AS") {
+			next++
+		}
+		if next < len(words) && words[next].kind == tokenWord && !isReserved(words[next].text) {
+			next++
+		}
+		if next >= len(words) || words[next].text != "," {
+			return next - 1
+		}
+		i = next
+	}
+}
+
+// readTableName reads a possibly schema-qualified, possibly quoted table name
+// starting at words[i]. It returns "" when words[i] does not start a name, for
+// example when FROM is followed by a subquery.
+func readTableName(words []token, i int) (string, int) {
+	var name strings.Builder
+	for i < len(words) {
+		tok := words[i]
+		if tok.kind == tokenWord && name.Len() == 0 && isTableModifier(tok.text) {
+			i++
+			continue
+		}
+		if (tok.kind != tokenWord || isReserved(tok.text)) && tok.kind != tokenQuotedIdent {
+			break
+		}
+		name.WriteString(tok.text)
+		i++
+		if i < len(words) && words[i].text == "." {
+			name.WriteByte('.')
+			i++
+			continue
+		}
+		break
+	}
+	return strings.TrimSuffix(name.String(), "."), i
+}
+
+// isTableModifier reports whether word may sit between a keyword and the
+// table name, as in "CREATE TABLE IF NOT EXISTS users" or "FROM ONLY users".
+func isTableModifier(word string) bool {
+	switch strings.ToUpper(word) {
+	case "IF", "NOT", "EXISTS", "ONLY":
+		return true
+	}
+	return false
+}
+
+// isReserved reports whether word is a keyword that can follow a table name,
+// and therefore must not be mistaken for a table or an alias.
+func isReserved(word string) bool {
+	switch strings.ToUpper(word) {
+	case "SELECT", "WHERE", "SET", "VALUES", "ON", "JOIN", "INNER", "LEFT", "RIGHT", "FULL", "OUTER",
+		"CROSS", "NATURAL", "GROUP", "ORDER", "HAVING", "LIMIT", "OFFSET", "UNION", "EXCEPT", "INTERSECT",
+		"RETURNING", "USING", "WHEN", "DEFAULT", "FOR", "AS", "IF", "NOT", "EXISTS", "WITH", "LATERAL":
+		return true
+	}
+	return false
+}
+
+func truncateSummary(s string) string {
+	if len(s) <= maxSummaryLength {
+		return s
+	}
+	if cut := strings.LastIndexByte(s[:maxSummaryLength+1], ' '); cut > 0 {
+		return s[:cut]
+	}
+	return s[:maxSummaryLength]
+}
+
+// DbQueryParameterAttrs returns db.query.parameter.<key> attributes for the
+// statement arguments. Positional arguments are keyed by their zero-based
+// index; sql.NamedArg values are keyed by name.
+func DbQueryParameterAttrs(params []any) []attribute.KeyValue {
+	if len(params) == 0 {
+		return nil
+	}
+	attrs := make([]attribute.KeyValue, 0, len(params))
+	for i, p := range params {
+		key := strconv.Itoa(i)
+		if named, ok := p.(sql.NamedArg); ok {
+			if named.Name != "" {
+				key = named.Name
+			}
+			p = named.Value
+		}
+		attrs = append(attrs, semconv.DBQueryParameter(key, formatParameter(p)))
+	}
+	return attrs
+}
+
+func formatParameter(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		return v
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return "<bytes>"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package semconv
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSanitizeQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		query    string
+		expected string
+	}{
+		{
+			name:     "placeholders are kept",
+			query:    "SELECT * FROM users WHERE id=?",
+			expected: "SELECT * FROM users WHERE id=?",
+		},
+		{
+			name:     "string literal",
+			query:    "SELECT * FROM users WHERE name = 'alice'",
+			expected: "SELECT * FROM users WHERE name = ?",
+		},
+		{
+			name:     "escaped quotes inside string",
+			driver:   "mysql",
+			query:    `SELECT * FROM users WHERE name = 'o''brien' AND note = 'a\'b'`,
+			expected: "SELECT * FROM users WHERE name = ? AND note = ?",
+		},
+		{
+			name:     "backslash is not an escape in standard strings",
+			driver:   "postgres",
+			query:    `SELECT * FROM t WHERE a = 'x\' AND b = 'o''brien'`,
+			expected: "SELECT * FROM t WHERE a = ? AND b = ?",
+		},
+		{
+			name:     "backslash escapes in postgres escape strings",
+			driver:   "pgx",
+			query:    `SELECT * FROM t WHERE a = E'x\'y' AND b = 'z'`,
+			expected: "SELECT * FROM t WHERE a = ? AND b = ?",
+		},
+		{
+			name:     "numbers",
+			query:    "UPDATE accounts SET balance = 10.5, limit = 1e3 WHERE id = 42",
+			expected: "UPDATE accounts SET balance = ?, limit = ? WHERE id = ?",
+		},
+		{
+			name:     "hex and prefixed strings",
+			query:    "SELECT * FROM t WHERE a = 0xFF AND b = N'x' AND c = E'y'",
+			expected: "SELECT * FROM t WHERE a = ? AND b = ? AND c = ?",
+		},
+		{
+			name:     "identifiers with digits are kept",
+			query:    "SELECT col1 FROM table2 WHERE t3.id = 7",
+			expected: "SELECT col1 FROM table2 WHERE t3.id = ?",
+		},
+		{
+			name:     "quoted identifiers are kept",
+			query:    "SELECT \"Name\", `age` FROM people WHERE id = 1",
+			expected: "SELECT \"Name\", `age` FROM people WHERE id = ?",
+		},
+		{
+			name:     "in list collapsed",
+			query:    "SELECT * FROM users WHERE id IN (1, 2, 3)",
+			expected: "SELECT * FROM users WHERE id IN (?)",
+		},
+		{
+			name:     "in list of placeholders collapsed",
+			query:    "SELECT * FROM users WHERE id in ($1,$2,$3)",
+			expected: "SELECT * FROM users WHERE id in (?)",
+		},
+		{
+			name:     "in subquery untouched",
+			query:    "SELECT * FROM users WHERE id IN (SELECT user_id FROM orders)",
+			expected: "SELECT * FROM users WHERE id IN (SELECT user_id FROM orders)",
+		},
+		{
+			name:     "named and positional placeholders",
+			query:    "SELECT * FROM t WHERE a = :name AND b = @p1 AND c = $2",
+			expected: "SELECT * FROM t WHERE a = :name AND b = @p1 AND c = $2",
+		},
+		{
+			name:     "postgres cast and dollar quoting",
+			query:    "SELECT '1'::int, $$secret$$, $tag$x$tag$",
+			expected: "SELECT ?::int, ?, ?",
+		},
+		{
+			name:     "comments dropped",
+			query:    "SELECT 1 -- password=hunter2\nFROM dual /* id=5 */",
+			expected: "SELECT ? \nFROM dual",
+		},
+		{
+			name:     "unterminated string",
+			query:    "SELECT 'abc",
+			expected: "SELECT ?",
+		},
+		{
+			name:     "empty",
+			query:    "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeQuery(tt.query, tt.driver))
+		})
+	}
+}
+
+func TestSanitizeQuery_NoLiteralSurvives(t *testing.T) {
+	tests := []struct {
+		driver string
+		query  string
+	}{
+		{driver: "postgres", query: `SELECT * FROM t WHERE a = 'x\' AND b = 'secret'`},
+		{driver: "sqlite3", query: `SELECT * FROM t WHERE a = 'x\' AND b = 'secret'`},
+		{driver: "", query: `UPDATE t SET a = 'x\', b = 'secret'`},
+		{driver: "mysql", query: `SELECT * FROM t WHERE a = 'x\'' AND b = 'secret'`},
+		{driver: "mysql", query: `SELECT * FROM t WHERE a = 'x\\' AND b = 'secret'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driver+" "+tt.query, func(t *testing.T) {
+			sanitized := SanitizeQuery(tt.query, tt.driver)
+			assert.NotContains(t, sanitized, "secret")
+			assert.NotContains(t, sanitized, "'")
+		})
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		operation  string
+		collection string
+		summary    string
+	}{
+		{
+			name:       "select",
+			query:      "SELECT id, name FROM users WHERE name = ?",
+			operation:  "SELECT",
+			collection: "users",
+			summary:    "SELECT users",
+		},
+		{
+			name:       "insert",
+			query:      "insert into users (name, email) values (?, ?)",
+			operation:  "INSERT",
+			collection: "users",
+			summary:    "INSERT users",
+		},
+		{
+			name:       "update",
+			query:      "UPDATE accounts SET balance = 0 WHERE id = 1",
+			operation:  "UPDATE",
+			collection: "accounts",
+			summary:    "UPDATE accounts",
+		},
+		{
+			name:       "delete",
+			query:      "DELETE FROM sessions WHERE expires < now()",
+			operation:  "DELETE",
+			collection: "sessions",
+			summary:    "DELETE sessions",
+		},
+		{
+			name:      "join has no single collection",
+			query:     "SELECT * FROM songs s JOIN artists AS a ON s.artist_id = a.id",
+			operation: "SELECT",
+			summary:   "SELECT songs artists",
+		},
+		{
+			name:      "comma separated tables with aliases",
+			query:     "SELECT * FROM a x, b y WHERE x.id = y.id",
+			operation: "SELECT",
+			summary:   "SELECT a b",
+		},
+		{
+			name:      "insert from select",
+			query:     "INSERT INTO shipping_details SELECT * FROM orders",
+			operation: "INSERT",
+			summary:   "INSERT shipping_details SELECT orders",
+		},
+		{
+			name:       "schema qualified and quoted",
+			query:      `SELECT * FROM public."Users"`,
+			operation:  "SELECT",
+			collection: `public."Users"`,
+			summary:    `SELECT public."Users"`,
+		},
+		{
+			name:       "create table",
+			query:      "CREATE TABLE IF NOT EXISTS users (id INT)",
+			operation:  "CREATE",
+			collection: "users",
+			summary:    "CREATE TABLE users",
+		},
+		{
+			name:       "on duplicate key update",
+			query:      "INSERT INTO users (id, a) VALUES (?, ?) ON DUPLICATE KEY UPDATE a = 1",
+			operation:  "INSERT",
+			collection: "users",
+			summary:    "INSERT users",
+		},
+		{
+			name:       "select for update",
+			query:      "SELECT * FROM users WHERE id = ? FOR UPDATE SKIP LOCKED",
+			operation:  "SELECT",
+			collection: "users",
+			summary:    "SELECT users",
+		},
+		{
+			name:       "from inside function call",
+			query:      "SELECT EXTRACT(YEAR FROM created), SUBSTRING(name FROM 2) FROM users",
+			operation:  "SELECT",
+			collection: "users",
+			summary:    "SELECT users",
+		},
+		{
+			name:      "subquery inside function call",
+			query:     "SELECT COALESCE((SELECT max(id) FROM orders), 0) FROM users",
+			operation: "SELECT",
+			summary:   "SELECT SELECT orders users",
+		},
+		{
+			name:       "is distinct from",
+			query:      "SELECT * FROM users WHERE a IS DISTINCT FROM b",
+			operation:  "SELECT",
+			collection: "users",
+			summary:    "SELECT users",
+		},
+		{
+			name:       "common table expression",
+			query:      "WITH a AS (SELECT id FROM orders), b (id) AS (SELECT 1) SELECT * FROM a",
+			operation:  "SELECT",
+			collection: "a",
+			summary:    "SELECT a",
+		},
+		{
+			name:       "recursive common table expression before update",
+			query:      "WITH RECURSIVE t AS MATERIALIZED (SELECT 1 UNION ALL SELECT n FROM t) UPDATE users SET n = 1",
+			operation:  "UPDATE",
+			collection: "users",
+			summary:    "UPDATE users",
+		},
+		{
+			name:       "subquery in from",
+			query:      "SELECT * FROM (SELECT id FROM users) AS u",
+			operation:  "SELECT",
+			collection: "users",
+			summary:    "SELECT SELECT users",
+		},
+		{
+			name:       "leading comment",
+			query:      "/* app=api */ SELECT * FROM users",
+			operation:  "SELECT",
+			collection: "users",
+			summary:    "SELECT users",
+		},
+		{
+			name:      "transaction control",
+			query:     "START TRANSACTION",
+			operation: "START",
+			summary:   "START",
+		},
+		{
+			name:      "ping",
+			query:     "ping",
+			operation: "PING",
+			summary:   "PING",
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := ParseQuery(tt.query, "")
+			assert.Equal(t, tt.operation, info.Operation)
+			assert.Equal(t, tt.collection, info.Collection())
+			assert.Equal(t, tt.summary, info.Summary)
+		})
+	}
+}
+
+func TestParseQuery_SummaryTruncated(t *testing.T) {
+	var tables []string
+	for range 100 {
+		tables = append(tables, "some_long_table_name")
+	}
+	// Distinct names so none are deduplicated.
+	for i := range tables {
+		tables[i] += strings.Repeat("x", i)
+	}
+	info := ParseQuery("SELECT * FROM "+strings.Join(tables, ", "), "")
+	assert.LessOrEqual(t, len(info.Summary), maxSummaryLength)
+	assert.True(t, strings.HasPrefix(info.Summary, "SELECT some_long_table_name "))
+}
+
+func TestQueryConfigFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv(envQuerySanitization, "")
+		t.Setenv(envQueryParameters, "")
+		assert.Equal(t, QueryConfig{Sanitize: true}, QueryConfigFromEnv())
+	})
+	t.Run("overridden", func(t *testing.T) {
+		t.Setenv(envQuerySanitization, "false")
+		t.Setenv(envQueryParameters, "true")
+		assert.Equal(t, QueryConfig{CaptureParameters: true}, QueryConfigFromEnv())
+	})
+	t.Run("invalid values keep defaults", func(t *testing.T) {
+		t.Setenv(envQuerySanitization, "nope")
+		t.Setenv(envQueryParameters, "yes please")
+		assert.Equal(t, QueryConfig{Sanitize: true}, QueryConfigFromEnv())
+	})
+}
+
+func TestDbQueryParameterAttrs(t *testing.T) {
+	attrs := DbQueryParameterAttrs([]any{1, "alice", nil, []byte("raw"), sql.Named("email", "a@example.com")})
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("db.query.parameter.0", "1"),
+		attribute.String("db.query.parameter.1", "alice"),
+		attribute.String("db.query.parameter.2", "<nil>"),
+		attribute.String("db.query.parameter.3", "raw"),
+		attribute.String("db.query.parameter.email", "a@example.com"),
+	}, attrs)
+	assert.Nil(t, DbQueryParameterAttrs(nil))
+}
+
+func TestDbClientRequestTraceAttrs_CollectionAndSummary(t *testing.T) {
+	attrs := DbClientRequestTraceAttrs(DatabaseSqlRequest{
+		OpType:     "SELECT",
+		Sql:        "SELECT * FROM users",
+		Endpoint:   "localhost:5432",
+		DriverName: "postgres",
+		DbName:     "app",
+		Collection: "users",
+		Summary:    "SELECT users",
+	})
+	assert.Contains(t, attrs, attribute.String("db.collection.name", "users"))
+	assert.Contains(t, attrs, attribute.String("db.query.summary", "SELECT users"))
+
+	attrs = DbClientRequestTraceAttrs(DatabaseSqlRequest{OpType: "PING", Sql: "ping"})
+	for _, attr := range attrs {
+		assert.NotEqual(t, attribute.Key("db.collection.name"), attr.Key)
+		assert.NotEqual(t, attribute.Key("db.query.summary"), attr.Key)
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+)
+
+// ChatCapture collects the messages of an OpenAI Chat Completions or legacy
+// Completions call for the InferenceDetailsEventName event. It is nil when
+// content capture is disabled, and every method is a no-op on a nil receiver.
+type ChatCapture struct {
+	cfg        ContentConfig
+	completion bool
+	details    InferenceDetails
+	// choices accumulates the deltas of a streaming response by choice
+	// index.
+	choices map[int]*streamChoice
+}
+
+// streamChoice is a choice of a streaming response being reassembled from its
+// deltas.
+type streamChoice struct {
+	role         string
+	content      strings.Builder
+	toolCalls    map[int]*streamToolCall
+	finishReason string
+}
+
+type streamToolCall struct {
+	id        string
+	name      string
+	arguments strings.Builder
+}
+
+// NewChatCapture returns the content capture of a Chat Completions call with
+// the request body requestBody, or nil when capture is disabled.
+func NewChatCapture(cfg ContentConfig, requestBody []byte) *ChatCapture {
+	if !cfg.Capture {
+		return nil
+	}
+	c := &ChatCapture{cfg: cfg}
+	c.details.InputMessages = parseChatInput(requestBody)
+	return c
+}
+
+// NewCompletionCapture returns the content capture of a legacy Completions
+// call with the request body requestBody, or nil when capture is disabled.
+func NewCompletionCapture(cfg ContentConfig, requestBody []byte) *ChatCapture {
+	if !cfg.Capture {
+		return nil
+	}
+	c := &ChatCapture{cfg: cfg, completion: true}
+	c.details.InputMessages = parseCompletionInput(requestBody)
+	return c
+}
+
+// Emit emits the event of the call with logger and the operation attributes
+// attrs.
+func (c *ChatCapture) Emit(ctx context.Context, logger log.Logger, attrs []attribute.KeyValue) {
+	if c == nil {
+		return
+	}
+	if len(c.choices) > 0 {
+		c.details.OutputMessages = c.streamedOutput()
+	}
+	c.cfg.Emit(ctx, logger, attrs, c.details)
+}
+
+// chatMessage is a message of the Chat Completions API, as sent in a request
+// or returned in a choice of a response.
+type chatMessage struct {
+	Role       string          `json:"role"`
+	Content    json.RawMessage `json:"content"`
+	ToolCalls  []chatToolCall  `json:"tool_calls"`
+	ToolCallID string          `json:"tool_call_id"`
+}
+
+type chatToolCall struct {
+	Index    int    `json:"index"`
+	ID       string `json:"id"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// parts converts m to message parts. Only text content is kept; images and
+// audio are left out.
+func (m chatMessage) parts() []MessagePart {
+	text := contentText(m.Content)
+	if m.Role == "tool" {
+		return []MessagePart{{
+			Type:     PartTypeToolCallResponse,
+			ID:       m.ToolCallID,
+			Response: strings.Join(text, "\n"),
+		}}
+	}
+	parts := make([]MessagePart, 0, len(text)+len(m.ToolCalls))
+	for _, t := range text {
+		parts = append(parts, MessagePart{Type: PartTypeText, Content: t})
+	}
+	for _, tc := range m.ToolCalls {
+		parts = append(parts, MessagePart{
+			Type:      PartTypeToolCall,
+			ID:        tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: tc.Function.Arguments,
+		})
+	}
+	return parts
+}
+
+// contentText returns the texts of a message content, which is either a
+// string or an array of typed content parts.
+func contentText(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return nil
+		}
+		return []string{s}
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return nil
+	}
+	var texts []string
+	for _, p := range parts {
+		if p.Type == "text" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return texts
+}
+
+func parseChatInput(body []byte) []Message {
+	var req struct {
+		Messages []chatMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil
+	}
+	messages := make([]Message, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		messages = append(messages, Message{Role: m.Role, Parts: m.parts()})
+	}
+	return messages
+}
+
+func parseCompletionInput(body []byte) []Message {
+	var req struct {
+		Prompt json.RawMessage `json:"prompt"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil
+	}
+	var prompts []string
+	if err := json.Unmarshal(req.Prompt, &prompts); err != nil {
+		var prompt string
+		if err := json.Unmarshal(req.Prompt, &prompt); err != nil {
+			return nil
+		}
+		prompts = []string{prompt}
+	}
+	messages := make([]Message, 0, len(prompts))
+	for _, p := range prompts {
+		messages = append(messages, Message{
+			Role:  "user",
+			Parts: []MessagePart{{Type: PartTypeText, Content: p}},
+		})
+	}
+	return messages
+}
+
+// AddResponse records the output messages of a non-streaming response body.
+func (c *ChatCapture) AddResponse(body []byte) {
+	if c == nil {
+		return
+	}
+	var resp struct {
+		Choices []struct {
+			Message      chatMessage `json:"message"`
+			Text         string      `json:"text"`
+			FinishReason string      `json:"finish_reason"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return
+	}
+	for _, choice := range resp.Choices {
+		out := Message{Role: "assistant", FinishReason: choice.FinishReason}
+		if c.completion {
+			out.Parts = []MessagePart{{Type: PartTypeText, Content: choice.Text}}
+		} else {
+			if choice.Message.Role != "" {
+				out.Role = choice.Message.Role
+			}
+			out.Parts = choice.Message.parts()
+		}
+		c.details.OutputMessages = append(c.details.OutputMessages, out)
+	}
+}
+
+// AddChunk accumulates the deltas of the payload of a streaming response
+// chunk.
+func (c *ChatCapture) AddChunk(payload []byte) {
+	if c == nil {
+		return
+	}
+	var chunk struct {
+		Choices []struct {
+			Index        int         `json:"index"`
+			Delta        chatMessage `json:"delta"`
+			Text         string      `json:"text"`
+			FinishReason string      `json:"finish_reason"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(payload, &chunk); err != nil {
+		return
+	}
+	if c.choices == nil {
+		c.choices = map[int]*streamChoice{}
+	}
+	for _, delta := range chunk.Choices {
+		choice, ok := c.choices[delta.Index]
+		if !ok {
+			choice = &streamChoice{role: "assistant", toolCalls: map[int]*streamToolCall{}}
+			c.choices[delta.Index] = choice
+		}
+		if delta.Delta.Role != "" {
+			choice.role = delta.Delta.Role
+		}
+		if delta.FinishReason != "" {
+			choice.finishReason = delta.FinishReason
+		}
+		if c.completion {
+			choice.content.WriteString(delta.Text)
+			continue
+		}
+		for _, t := range contentText(delta.Delta.Content) {
+			choice.content.WriteString(t)
+		}
+		for _, tc := range delta.Delta.ToolCalls {
+			call, ok := choice.toolCalls[tc.Index]
+			if !ok {
+				call = &streamToolCall{}
+				choice.toolCalls[tc.Index] = call
+			}
+			if tc.ID != "" {
+				call.id = tc.ID
+			}
+			if tc.Function.Name != "" {
+				call.name = tc.Function.Name
+			}
+			call.arguments.WriteString(tc.Function.Arguments)
+		}
+	}
+}
+
+// streamedOutput returns the output messages reassembled from the deltas, in
+// choice order.
+func (c *ChatCapture) streamedOutput() []Message {
+	messages := make([]Message, 0, len(c.choices))
+	for _, i := range slices.Sorted(maps.Keys(c.choices)) {
+		choice := c.choices[i]
+		out := Message{Role: choice.role, FinishReason: choice.finishReason}
+		if choice.content.Len() > 0 {
+			out.Parts = append(out.Parts, MessagePart{
+				Type:    PartTypeText,
+				Content: choice.content.String(),
+			})
+		}
+		for _, j := range slices.Sorted(maps.Keys(choice.toolCalls)) {
+			call := choice.toolCalls[j]
+			out.Parts = append(out.Parts, MessagePart{
+				Type:      PartTypeToolCall,
+				ID:        call.id,
+				Name:      call.name,
+				Arguments: call.arguments.String(),
+			})
+		}
+		messages = append(messages, out)
+	}
+	return messages
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChatInput(t *testing.T) {
+	body := []byte(`{"model":"gpt-4","messages":[
+		{"role":"system","content":"Be brief."},
+		{"role":"user","content":[{"type":"text","text":"What is in this image?"},{"type":"image_url","image_url":{"url":"data:..."}}]},
+		{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"describe","arguments":"{\"detail\":\"low\"}"}}]},
+		{"role":"tool","tool_call_id":"call_1","content":"a cat"}
+	]}`)
+
+	messages := parseChatInput(body)
+	require.Len(t, messages, 4)
+	assert.Equal(t, Message{
+		Role:  "system",
+		Parts: []MessagePart{{Type: PartTypeText, Content: "Be brief."}},
+	}, messages[0])
+	assert.Equal(t, []MessagePart{
+		{Type: PartTypeText, Content: "What is in this image?"},
+	}, messages[1].Parts, "only text content is captured")
+	assert.Equal(t, []MessagePart{
+		{Type: PartTypeToolCall, ID: "call_1", Name: "describe", Arguments: `{"detail":"low"}`},
+	}, messages[2].Parts)
+	assert.Equal(t, []MessagePart{
+		{Type: PartTypeToolCallResponse, ID: "call_1", Response: "a cat"},
+	}, messages[3].Parts)
+
+	assert.Nil(t, parseChatInput([]byte(`invalid json`)))
+}
+
+func TestParseCompletionInput(t *testing.T) {
+	messages := parseCompletionInput([]byte(`{"model":"gpt-3.5-turbo-instruct","prompt":"Say hi"}`))
+	require.Len(t, messages, 1)
+	assert.Equal(t, "user", messages[0].Role)
+	assert.Equal(t, "Say hi", messages[0].Parts[0].Content)
+
+	messages = parseCompletionInput([]byte(`{"prompt":["one","two"]}`))
+	require.Len(t, messages, 2)
+	assert.Equal(t, "two", messages[1].Parts[0].Content)
+}
+
+func TestChatCapture_StreamedToolCalls(t *testing.T) {
+	c := NewChatCapture(ContentConfig{Capture: true}, []byte(`{"messages":[]}`))
+	require.NotNil(t, c)
+
+	c.AddChunk([]byte(`{"choices":[{"index":0,"delta":{"role":"assistant","content":"Let me check."}}]}`))
+	c.AddChunk([]byte(`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","function":{"name":"get_weather","arguments":""}}]}}]}`))
+	c.AddChunk([]byte(`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}`))
+	c.AddChunk([]byte(`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}`))
+	c.AddChunk([]byte(`{"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`))
+
+	assert.Equal(t, []Message{{
+		Role: "assistant",
+		Parts: []MessagePart{
+			{Type: PartTypeText, Content: "Let me check."},
+			{Type: PartTypeToolCall, ID: "call_1", Name: "get_weather", Arguments: `{"city":"Paris"}`},
+		},
+		FinishReason: "tool_calls",
+	}}, c.streamedOutput())
+}
+
+func TestChatCapture_Response(t *testing.T) {
+	c := NewCompletionCapture(ContentConfig{Capture: true}, []byte(`{"prompt":"Say hi"}`))
+	require.NotNil(t, c)
+	c.AddResponse([]byte(`{"choices":[{"text":"Hi","finish_reason":"stop"}]}`))
+	assert.Equal(t, []Message{{
+		Role:         "assistant",
+		Parts:        []MessagePart{{Type: PartTypeText, Content: "Hi"}},
+		FinishReason: "stop",
+	}}, c.details.OutputMessages)
+}
+
+func TestNewChatCapture_Disabled(t *testing.T) {
+	assert.Nil(t, NewChatCapture(ContentConfig{}, []byte(`{}`)))
+	assert.Nil(t, NewCompletionCapture(ContentConfig{}, []byte(`{}`)))
+}
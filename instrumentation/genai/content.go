@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package genai captures the messages of GenAI calls and records the GenAI
+// client metrics for the GenAI instrumentations (anthropic-sdk-go and
+// openai-go v1, v2 and v3).
+package genai
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+)
+
+const (
+	// envCaptureMessageContent toggles the capture of the messages of GenAI
+	// calls. Prompts and completions may carry PII, so they are only captured
+	// when set to "true".
+	envCaptureMessageContent = "OTEL_INSTRUMENTATION_GENAI_CAPTURE_MESSAGE_CONTENT"
+	// envMessageContentMaxLength bounds every captured text, tool call
+	// arguments and tool call response, in bytes. 0 disables truncation.
+	envMessageContentMaxLength = "OTEL_GO_GENAI_MESSAGE_CONTENT_MAX_LENGTH"
+
+	// defaultMessageContentMaxLength keeps a single event well below the
+	// size limits of common log backends.
+	defaultMessageContentMaxLength = 8192
+
+	// InferenceDetailsEventName is the name of the log event carrying the
+	// messages of a GenAI call.
+	InferenceDetailsEventName = "gen_ai.client.inference.operation.details"
+
+	GenAIInputMessagesKey      = "gen_ai.input.messages"
+	GenAIOutputMessagesKey     = "gen_ai.output.messages"
+	GenAISystemInstructionsKey = "gen_ai.system_instructions"
+)
+
+// Types of a MessagePart.
+const (
+	PartTypeText             = "text"
+	PartTypeToolCall         = "tool_call"
+	PartTypeToolCallResponse = "tool_call_response"
+)
+
+// ContentConfig controls the capture of the messages of GenAI calls.
+type ContentConfig struct {
+	// Capture emits an InferenceDetailsEventName event with the input and
+	// output messages of every call.
+	Capture bool
+	// MaxLength truncates every text, tool call arguments and tool call
+	// response to at most MaxLength bytes. 0 disables truncation.
+	MaxLength int
+}
+
+// ContentConfigFromEnv reads the ContentConfig from the environment. Unset or
+// unparsable values fall back to the defaults: no capture, and 8192 bytes per
+// content when enabled.
+func ContentConfigFromEnv() ContentConfig {
+	cfg := ContentConfig{MaxLength: defaultMessageContentMaxLength}
+	if v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(envCaptureMessageContent))); err == nil {
+		cfg.Capture = v
+	}
+	if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv(envMessageContentMaxLength))); err == nil && v >= 0 {
+		cfg.MaxLength = v
+	}
+	return cfg
+}
+
+// MessagePart is one part of a GenAI message: a text, a tool call requested by
+// the model, or the response of a tool call sent back to the model.
+type MessagePart struct {
+	Type string
+	// Content is the text of a PartTypeText part.
+	Content string
+	// ID identifies the tool call of a PartTypeToolCall or
+	// PartTypeToolCallResponse part.
+	ID string
+	// Name is the tool name of a PartTypeToolCall part.
+	Name string
+	// Arguments are the tool call arguments of a PartTypeToolCall part, as
+	// the JSON sent by the model.
+	Arguments string
+	// Response is the tool output of a PartTypeToolCallResponse part.
+	Response string
+}
+
+// Message is an input or output message of a GenAI call.
+type Message struct {
+	Role  string
+	Parts []MessagePart
+	// FinishReason is only set on output messages.
+	FinishReason string
+}
+
+// InferenceDetails are the messages of a GenAI call.
+type InferenceDetails struct {
+	SystemInstructions []MessagePart
+	InputMessages      []Message
+	OutputMessages     []Message
+}
+
+// Emit emits the InferenceDetailsEventName event of a call with logger. attrs
+// are the attributes of the operation, such as gen_ai.operation.name and
+// gen_ai.request.model. The event is emitted with ctx, so that it is
+// correlated with the span of the call.
+func (cfg ContentConfig) Emit(ctx context.Context, logger log.Logger, attrs []attribute.KeyValue, details InferenceDetails) {
+	if !cfg.Capture || logger == nil {
+		return
+	}
+	if !logger.Enabled(ctx, log.EnabledParameters{Severity: log.SeverityInfo, EventName: InferenceDetailsEventName}) {
+		return
+	}
+
+	var record log.Record
+	record.SetEventName(InferenceDetailsEventName)
+	record.SetTimestamp(time.Now())
+	record.SetSeverity(log.SeverityInfo)
+	for _, kv := range attrs {
+		record.AddAttributes(log.KeyValueFromAttribute(kv))
+	}
+	if len(details.SystemInstructions) > 0 {
+		record.AddAttributes(log.Slice(GenAISystemInstructionsKey, cfg.partValues(details.SystemInstructions)...))
+	}
+	if len(details.InputMessages) > 0 {
+		record.AddAttributes(log.Slice(GenAIInputMessagesKey, cfg.messageValues(details.InputMessages)...))
+	}
+	if len(details.OutputMessages) > 0 {
+		record.AddAttributes(log.Slice(GenAIOutputMessagesKey, cfg.messageValues(details.OutputMessages)...))
+	}
+	logger.Emit(ctx, record)
+}
+
+func (cfg ContentConfig) messageValues(messages []Message) []log.Value {
+	values := make([]log.Value, 0, len(messages))
+	for _, m := range messages {
+		kvs := []log.KeyValue{
+			log.String("role", m.Role),
+			log.Slice("parts", cfg.partValues(m.Parts)...),
+		}
+		if m.FinishReason != "" {
+			kvs = append(kvs, log.String("finish_reason", m.FinishReason))
+		}
+		values = append(values, log.MapValue(kvs...))
+	}
+	return values
+}
+
+func (cfg ContentConfig) partValues(parts []MessagePart) []log.Value {
+	values := make([]log.Value, 0, len(parts))
+	for _, p := range parts {
+		kvs := []log.KeyValue{log.String("type", p.Type)}
+		switch p.Type {
+		case PartTypeText:
+			kvs = append(kvs, log.String("content", cfg.truncate(p.Content)))
+		case PartTypeToolCall:
+			kvs = append(kvs,
+				log.String("id", p.ID),
+				log.String("name", p.Name),
+				log.String("arguments", cfg.truncate(p.Arguments)),
+			)
+		case PartTypeToolCallResponse:
+			kvs = append(kvs,
+				log.String("id", p.ID),
+				log.String("response", cfg.truncate(p.Response)),
+			)
+		}
+		values = append(values, log.MapValue(kvs...))
+	}
+	return values
+}
+
+// truncate cuts s to at most cfg.MaxLength bytes without splitting a UTF-8
+// encoded rune.
+func (cfg ContentConfig) truncate(s string) string {
+	if cfg.MaxLength <= 0 || len(s) <= cfg.MaxLength {
+		return s
+	}
+	cut := cfg.MaxLength
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
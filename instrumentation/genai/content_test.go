@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+)
+
+type recordingLogger struct {
+	embedded.Logger
+	records []log.Record
+}
+
+func (l *recordingLogger) Emit(_ context.Context, record log.Record) {
+	l.records = append(l.records, record)
+}
+
+func (*recordingLogger) Enabled(context.Context, log.EnabledParameters) bool {
+	return true
+}
+
+func recordAttributes(record log.Record) map[string]log.Value {
+	attrs := map[string]log.Value{}
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	return attrs
+}
+
+func mapValue(v log.Value) map[string]log.Value {
+	m := map[string]log.Value{}
+	for _, kv := range v.AsMap() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestContentConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		capture   string
+		maxLength string
+		want      ContentConfig
+	}{
+		{"defaults", "", "", ContentConfig{MaxLength: 8192}},
+		{"enabled", "true", "", ContentConfig{Capture: true, MaxLength: 8192}},
+		{"custom limit", "true", "100", ContentConfig{Capture: true, MaxLength: 100}},
+		{"no limit", "true", "0", ContentConfig{Capture: true}},
+		{"invalid values", "yes", "-1", ContentConfig{MaxLength: 8192}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envCaptureMessageContent, tt.capture)
+			t.Setenv(envMessageContentMaxLength, tt.maxLength)
+			assert.Equal(t, tt.want, ContentConfigFromEnv())
+		})
+	}
+}
+
+func TestContentConfig_Emit(t *testing.T) {
+	logger := &recordingLogger{}
+	cfg := ContentConfig{Capture: true}
+
+	cfg.Emit(context.Background(), logger, []attribute.KeyValue{attribute.String("gen_ai.operation.name", "chat")}, InferenceDetails{
+		SystemInstructions: []MessagePart{{Type: PartTypeText, Content: "Be brief."}},
+		InputMessages: []Message{
+			{Role: "user", Parts: []MessagePart{{Type: PartTypeText, Content: "Weather in Paris?"}}},
+			{Role: "tool", Parts: []MessagePart{{Type: PartTypeToolCallResponse, ID: "call_1", Response: "sunny"}}},
+		},
+		OutputMessages: []Message{{
+			Role:         "assistant",
+			Parts:        []MessagePart{{Type: PartTypeToolCall, ID: "call_2", Name: "get_time", Arguments: `{"city":"Paris"}`}},
+			FinishReason: "tool_calls",
+		}},
+	})
+
+	require.Len(t, logger.records, 1)
+	record := logger.records[0]
+	assert.Equal(t, InferenceDetailsEventName, record.EventName())
+	assert.Equal(t, log.SeverityInfo, record.Severity())
+
+	attrs := recordAttributes(record)
+	assert.Equal(t, "chat", attrs["gen_ai.operation.name"].AsString())
+
+	system := attrs[GenAISystemInstructionsKey].AsSlice()
+	require.Len(t, system, 1)
+	assert.Equal(t, "Be brief.", mapValue(system[0])["content"].AsString())
+
+	input := attrs[GenAIInputMessagesKey].AsSlice()
+	require.Len(t, input, 2)
+	toolMessage := mapValue(input[1])
+	assert.Equal(t, "tool", toolMessage["role"].AsString())
+	toolResponse := mapValue(toolMessage["parts"].AsSlice()[0])
+	assert.Equal(t, PartTypeToolCallResponse, toolResponse["type"].AsString())
+	assert.Equal(t, "call_1", toolResponse["id"].AsString())
+	assert.Equal(t, "sunny", toolResponse["response"].AsString())
+
+	output := attrs[GenAIOutputMessagesKey].AsSlice()
+	require.Len(t, output, 1)
+	outMessage := mapValue(output[0])
+	assert.Equal(t, "tool_calls", outMessage["finish_reason"].AsString())
+	toolCall := mapValue(outMessage["parts"].AsSlice()[0])
+	assert.Equal(t, PartTypeToolCall, toolCall["type"].AsString())
+	assert.Equal(t, "get_time", toolCall["name"].AsString())
+	assert.JSONEq(t, `{"city":"Paris"}`, toolCall["arguments"].AsString())
+}
+
+func TestContentConfig_Emit_Disabled(t *testing.T) {
+	logger := &recordingLogger{}
+	ContentConfig{}.Emit(context.Background(), logger, nil, InferenceDetails{
+		InputMessages: []Message{{Role: "user"}},
+	})
+	assert.Empty(t, logger.records)
+
+	assert.NotPanics(t, func() {
+		ContentConfig{Capture: true}.Emit(context.Background(), nil, nil, InferenceDetails{})
+	})
+}
+
+func TestContentConfig_Truncate(t *testing.T) {
+	cfg := ContentConfig{MaxLength: 4}
+	assert.Equal(t, "abc", cfg.truncate("abc"))
+	assert.Equal(t, "abcd", cfg.truncate("abcdef"))
+	// "é" is two bytes long and is not split.
+	assert.Equal(t, "abc", cfg.truncate("abcé"))
+	assert.Equal(t, "abcdef", ContentConfig{}.truncate("abcdef"))
+}
@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/genaiconv"
+)
+
+const GenAITokenTypeKey = attribute.Key("gen_ai.token.type")
+
+// Values of gen_ai.token.type.
+const (
+	GenAITokenTypeInput  = "input"
+	GenAITokenTypeOutput = "output"
+)
+
+func GenAITokenType(val string) attribute.KeyValue {
+	return GenAITokenTypeKey.String(val)
+}
+
+// Bucket boundaries advised by the GenAI semantic conventions.
+var (
+	tokenUsageBuckets = []float64{
+		1, 4, 16, 64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216, 67108864,
+	}
+	operationDurationBuckets = []float64{
+		0.01, 0.02, 0.04, 0.08, 0.16, 0.32, 0.64, 1.28, 2.56, 5.12, 10.24, 20.48, 40.96, 81.92,
+	}
+)
+
+// Metrics records the GenAI client metrics, gen_ai.client.token.usage and
+// gen_ai.client.operation.duration. A nil *Metrics records nothing.
+type Metrics struct {
+	tokenUsage        genaiconv.ClientTokenUsage
+	operationDuration genaiconv.ClientOperationDuration
+}
+
+// NewMetrics creates the GenAI client instruments from meter. An instrument
+// that cannot be created records nothing, and the returned error reports why.
+func NewMetrics(meter metric.Meter) (*Metrics, error) {
+	tokenUsage, tokenUsageErr := genaiconv.NewClientTokenUsage(meter,
+		metric.WithExplicitBucketBoundaries(tokenUsageBuckets...))
+	operationDuration, operationDurationErr := genaiconv.NewClientOperationDuration(meter,
+		metric.WithExplicitBucketBoundaries(operationDurationBuckets...))
+	return &Metrics{
+		tokenUsage:        tokenUsage,
+		operationDuration: operationDuration,
+	}, errors.Join(tokenUsageErr, operationDurationErr)
+}
+
+// RecordTokenUsage records the number of tokens of tokenType (input or output)
+// used by an operation.
+func (m *Metrics) RecordTokenUsage(ctx context.Context, tokenType string, tokens int64, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	set := attribute.NewSet(append(attrs[:len(attrs):len(attrs)], GenAITokenType(tokenType))...)
+	m.tokenUsage.RecordSet(ctx, tokens, set)
+}
+
+// RecordOperationDuration records the duration of an operation, in seconds.
+func (m *Metrics) RecordOperationDuration(ctx context.Context, d time.Duration, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	m.operationDuration.RecordSet(ctx, d.Seconds(), attribute.NewSet(attrs...))
+}
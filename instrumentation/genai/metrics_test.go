@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package genai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestGenAITokenType(t *testing.T) {
+	kv := GenAITokenType(GenAITokenTypeInput)
+	assert.Equal(t, attribute.Key("gen_ai.token.type"), kv.Key)
+	assert.Equal(t, "input", kv.Value.AsString())
+}
+
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	m, err := NewMetrics(mp.Meter("test"))
+	require.NoError(t, err)
+
+	attrs := []attribute.KeyValue{attribute.String("gen_ai.operation.name", "chat"), attribute.String("gen_ai.request.model", "gpt-4")}
+	m.RecordTokenUsage(context.Background(), GenAITokenTypeInput, 10, attrs)
+	m.RecordTokenUsage(context.Background(), GenAITokenTypeOutput, 20, attrs)
+	m.RecordOperationDuration(context.Background(), 1500*time.Millisecond, attrs)
+	assert.Len(t, attrs, 2, "the attributes of the caller are not modified")
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	got := map[string]metricdata.Metrics{}
+	for _, metric := range rm.ScopeMetrics[0].Metrics {
+		got[metric.Name] = metric
+	}
+
+	usage, ok := got["gen_ai.client.token.usage"].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	assert.Equal(t, "{token}", got["gen_ai.client.token.usage"].Unit)
+	require.Len(t, usage.DataPoints, 2)
+	for _, dp := range usage.DataPoints {
+		tokenType, ok := dp.Attributes.Value(GenAITokenTypeKey)
+		require.True(t, ok)
+		want := map[string]int64{"input": 10, "output": 20}[tokenType.AsString()]
+		assert.Equal(t, want, dp.Sum)
+		assert.Equal(t, tokenUsageBuckets, dp.Bounds)
+	}
+
+	duration, ok := got["gen_ai.client.operation.duration"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	assert.Equal(t, "s", got["gen_ai.client.operation.duration"].Unit)
+	require.Len(t, duration.DataPoints, 1)
+	assert.InDelta(t, 1.5, duration.DataPoints[0].Sum, 1e-9)
+	assert.Equal(t, operationDurationBuckets, duration.DataPoints[0].Bounds)
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+	assert.NotPanics(t, func() {
+		m.RecordTokenUsage(context.Background(), GenAITokenTypeInput, 1, nil)
+		m.RecordOperationDuration(context.Background(), time.Second, nil)
+	})
+}
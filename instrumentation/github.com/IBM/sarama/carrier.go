@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sarama
+
+import (
+	"github.com/IBM/sarama"
+)
+
+// producerCarrier adapts the headers of a sarama.ProducerMessage to the
+// OpenTelemetry TextMapCarrier interface so trace context can be propagated
+// through Kafka message headers.
+type producerCarrier struct {
+	msg *sarama.ProducerMessage
+}
+
+// Get returns the value of the first header matching key, or "" if absent.
+func (c producerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces any existing header with key, otherwise appends a new one.
+//
+// The headers are copied before being modified: callers commonly build one
+// header slice and reuse it for several messages.
+func (c producerCarrier) Set(key, value string) {
+	headers := make([]sarama.RecordHeader, 0, len(c.msg.Headers)+1)
+	replaced := false
+	for _, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			h.Value = []byte(value)
+			replaced = true
+		}
+		headers = append(headers, h)
+	}
+	if !replaced {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+	c.msg.Headers = headers
+}
+
+// Keys lists the header keys carried by this carrier.
+func (c producerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		keys = append(keys, string(h.Key))
+	}
+	return keys
+}
+
+// consumerCarrier adapts the headers of a sarama.ConsumerMessage to the
+// OpenTelemetry TextMapCarrier interface.
+type consumerCarrier struct {
+	msg *sarama.ConsumerMessage
+}
+
+// Get returns the value of the first header matching key, or "" if absent.
+func (c consumerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces any existing header with key, otherwise appends a new one.
+func (c consumerCarrier) Set(key, value string) {
+	for _, h := range c.msg.Headers {
+		if h != nil && string(h.Key) == key {
+			h.Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, &sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+// Keys lists the header keys carried by this carrier.
+func (c consumerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		if h != nil {
+			keys = append(keys, string(h.Key))
+		}
+	}
+	return keys
+}
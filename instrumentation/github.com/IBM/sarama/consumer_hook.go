@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sarama
+
+import (
+	"context"
+	"sync"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/github.com/segmentio/kafka-go/semconv"
+	"go.opentelemetry.io/otelc/pkg/hook"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+// consumeHandlerParamIndex is the index of the handler in
+// (*consumerGroup).Consume(ctx, topics, handler), after the receiver.
+const consumeHandlerParamIndex = 3
+
+// groupIDs maps a sarama.ConsumerGroup to its group ID, which the consumer
+// group does not expose. Entries are removed when the group is closed.
+var groupIDs sync.Map
+
+// -----------------------------------------------------------------------------
+// Consumer group: newConsumerGroup(groupID, client) and Close()
+// -----------------------------------------------------------------------------
+
+// BeforeNewConsumerGroup remembers the group ID for AfterNewConsumerGroup.
+func BeforeNewConsumerGroup(ictx hook.HookContext, groupID string, _ sarama.Client) {
+	ictx.SetData(groupID)
+}
+
+// AfterNewConsumerGroup associates the new consumer group with its ID.
+func AfterNewConsumerGroup(ictx hook.HookContext, group sarama.ConsumerGroup, err error) {
+	groupID, ok := ictx.GetData().(string)
+	if !ok || err != nil || group == nil {
+		return
+	}
+	groupIDs.Store(group, groupID)
+}
+
+// BeforeConsumerGroupClose forgets the group ID of a closed consumer group.
+func BeforeConsumerGroupClose(_ hook.HookContext, group any) {
+	groupIDs.Delete(group)
+}
+
+// -----------------------------------------------------------------------------
+// Consumer group: (*consumerGroup).Consume(ctx, topics, handler)
+// -----------------------------------------------------------------------------
+
+// BeforeConsume wraps the ConsumerGroupHandler so that every message the
+// handler reads from a claim gets a receive span.
+func BeforeConsume(
+	ictx hook.HookContext,
+	group any,
+	ctx context.Context,
+	_ []string,
+	handler sarama.ConsumerGroupHandler,
+) {
+	if !saramaEnabler.Enable() {
+		logger.Debug("Sarama consumer instrumentation disabled")
+		return
+	}
+	if handler == nil || runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindConsumer) {
+		return
+	}
+	if _, ok := handler.(*tracingHandler); ok {
+		return
+	}
+	initInstrumentation()
+
+	groupID, _ := groupIDs.Load(group)
+	wrapped := &tracingHandler{ConsumerGroupHandler: handler}
+	wrapped.groupID, _ = groupID.(string)
+	ictx.SetParam(consumeHandlerParamIndex, sarama.ConsumerGroupHandler(wrapped))
+}
+
+// tracingHandler wraps a ConsumerGroupHandler and hands it claims whose
+// message channel emits a receive span per message.
+type tracingHandler struct {
+	sarama.ConsumerGroupHandler
+	groupID string
+}
+
+func (h *tracingHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	c := newTracingClaim(session, claim, h.groupID)
+	defer c.stop()
+	return h.ConsumerGroupHandler.ConsumeClaim(session, c)
+}
+
+// tracingClaim forwards the messages of a claim, starting a receive span for
+// each one as it is handed to the application.
+type tracingClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newTracingClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, groupID string) *tracingClaim {
+	c := &tracingClaim{
+		ConsumerGroupClaim: claim,
+		messages:           make(chan *sarama.ConsumerMessage),
+		done:               make(chan struct{}),
+	}
+	ctx := context.Background()
+	if session != nil && session.Context() != nil {
+		ctx = session.Context()
+	}
+	go c.forward(ctx, groupID)
+	return c
+}
+
+// Messages returns the instrumented message channel.
+func (c *tracingClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+func (c *tracingClaim) forward(ctx context.Context, groupID string) {
+	defer close(c.messages)
+	for msg := range c.ConsumerGroupClaim.Messages() {
+		receive(ctx, msg, groupID)
+		select {
+		case c.messages <- msg:
+		case <-c.done:
+			// The handler returned; sarama drains the remaining messages
+			// of the claim itself.
+			return
+		}
+	}
+}
+
+// stop releases the forwarding goroutine once ConsumeClaim returns.
+func (c *tracingClaim) stop() {
+	c.stopOnce.Do(func() { close(c.done) })
+}
+
+// receive emits the receive span for msg and replaces the trace context in
+// its headers with the span's, so ExtractContext continues the trace below
+// the receive span.
+//
+// The span is parented on the producer context carried in the message
+// headers, falling back to the session context, and also links to the
+// producer context.
+func receive(ctx context.Context, msg *sarama.ConsumerMessage, groupID string) {
+	if msg == nil {
+		return
+	}
+	carrier := consumerCarrier{msg: msg}
+
+	parent := ctx
+	var opts []trace.SpanStartOption
+	producerCtx := propagator.Extract(context.Background(), carrier)
+	if sc := trace.SpanContextFromContext(producerCtx); sc.IsValid() {
+		parent = trace.ContextWithRemoteSpanContext(ctx, sc)
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+
+	req := semconv.KafkaRequest{
+		Destination:     msg.Topic,
+		Operation:       semconv.KafkaOperationReceive,
+		ConsumerGroupID: groupID,
+		MessageKey:      string(msg.Key),
+		MessageBodySize: len(msg.Value),
+		Partition:       int(msg.Partition),
+		Offset:          msg.Offset,
+		HasPartition:    true,
+		HasOffset:       true,
+	}
+	opts = append(opts,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(semconv.KafkaRequestTraceAttrs(req)...),
+	)
+	spanCtx, span := tracer.Start(parent, msg.Topic+" receive", opts...)
+	propagator.Inject(spanCtx, carrier)
+	span.End()
+}
+
+// ExtractContext extracts the trace context from a consumed message's headers
+// and returns a context.Context that carries the propagated span context.
+//
+// Inside an instrumented ConsumeClaim, the headers carry the receive span, so
+// spans created with the returned context are its children:
+//
+//	for msg := range claim.Messages() {
+//		ctx := sarama.ExtractContext(msg)
+//		// spans created with ctx will be children of the receive span.
+//	}
+func ExtractContext(msg *sarama.ConsumerMessage) context.Context {
+	initInstrumentation()
+	if msg == nil {
+		return context.Background()
+	}
+	return propagator.Extract(context.Background(), consumerCarrier{msg: msg})
+}
@@ -0,0 +1,215 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sarama
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+// recordingHandler hands every consumed message to the test and marks it.
+type recordingHandler struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (*recordingHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (*recordingHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+func (h *recordingHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		session.MarkMessage(msg, "")
+		h.messages <- msg
+	}
+	return nil
+}
+
+// wrapHandler runs BeforeConsume the way the injected trampoline would and
+// returns the handler that Consume receives.
+func wrapHandler(t *testing.T, group any, handler sarama.ConsumerGroupHandler) sarama.ConsumerGroupHandler {
+	t.Helper()
+	ictx := hooktest.NewMockHookContext(group, context.Background(), []string{"orders"}, handler)
+	BeforeConsume(ictx, group, context.Background(), []string{"orders"}, handler)
+	wrapped, ok := ictx.GetParam(consumeHandlerParamIndex).(sarama.ConsumerGroupHandler)
+	require.True(t, ok)
+	return wrapped
+}
+
+func TestConsumerGroup_MockBroker(t *testing.T) {
+	sr := setupTest(t)
+
+	broker := sarama.NewMockBroker(t, 0)
+	t.Cleanup(broker.Close)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("orders", 0, broker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset("orders", 0, sarama.OffsetOldest, 0).
+			SetOffset("orders", 0, sarama.OffsetNewest, 1),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, "workers", broker),
+		"HeartbeatRequest": sarama.NewMockHeartbeatResponse(t),
+		"JoinGroupRequest": sarama.NewMockJoinGroupResponse(t).
+			SetGroupProtocol(sarama.RangeBalanceStrategyName),
+		"SyncGroupRequest": sarama.NewMockSyncGroupResponse(t).SetMemberAssignment(
+			&sarama.ConsumerGroupMemberAssignment{
+				Version: 0,
+				Topics:  map[string][]int32{"orders": {0}},
+			}),
+		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+			SetOffset("workers", "orders", 0, 0, "", sarama.ErrNoError).
+			SetError(sarama.ErrNoError),
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			SetMessageWithKey("orders", 0, 0, sarama.StringEncoder("order-1"), sarama.StringEncoder("hello")),
+		"LeaveGroupRequest": sarama.NewMockLeaveGroupResponse(t),
+	})
+
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_0_0_0
+	// The mock broker does not answer ApiVersions requests.
+	config.ApiVersionsRequest = false
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.Offsets.AutoCommit.Enable = false
+
+	// Outside of an instrumented build the hooks are not injected, so fire
+	// them around the real constructor and Consume call by hand.
+	newCtx := hooktest.NewMockHookContext("workers", nil)
+	BeforeNewConsumerGroup(newCtx, "workers", nil)
+	group, err := sarama.NewConsumerGroup([]string{broker.Addr()}, "workers", config)
+	require.NoError(t, err)
+	AfterNewConsumerGroup(newCtx, group, nil)
+
+	handler := &recordingHandler{messages: make(chan *sarama.ConsumerMessage, 1)}
+	wrapped := wrapHandler(t, group, handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = group.Consume(ctx, []string{"orders"}, wrapped)
+	}()
+
+	var msg *sarama.ConsumerMessage
+	select {
+	case msg = <-handler.messages:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for a message")
+	}
+	cancel()
+	<-done
+	BeforeConsumerGroupClose(hooktest.NewMockHookContext(group), group)
+	require.NoError(t, group.Close())
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "orders receive", span.Name())
+	assert.Equal(t, trace.SpanKindConsumer, span.SpanKind())
+
+	m := spanAttrs(span)
+	assert.Equal(t, "kafka", m["messaging.system"])
+	assert.Equal(t, "receive", m["messaging.operation.name"])
+	assert.Equal(t, "orders", m["messaging.destination.name"])
+	assert.Equal(t, "workers", m["messaging.consumer.group.name"])
+	assert.Equal(t, "order-1", m["messaging.kafka.message.key"])
+	assert.Equal(t, "0", m["messaging.destination.partition.id"])
+	assert.Equal(t, int64(0), m["messaging.kafka.offset"])
+
+	// The handler can continue the trace below the receive span.
+	sc := trace.SpanContextFromContext(ExtractContext(msg))
+	assert.Equal(t, span.SpanContext().SpanID(), sc.SpanID())
+
+	_, tracked := groupIDs.Load(group)
+	assert.False(t, tracked)
+}
+
+// fakeClaim is a ConsumerGroupClaim over a plain channel.
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+func TestTracingClaim_ParentsOnProducer(t *testing.T) {
+	sr := setupTest(t)
+
+	producer := remoteSpanContext(t, "0102030405060708090a0b0c0d0e0f10", "0102030405060708")
+	msg := &sarama.ConsumerMessage{Topic: "orders", Partition: 2, Offset: 9}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), producer), consumerCarrier{msg: msg})
+
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 1)}
+	claim.messages <- msg
+	close(claim.messages)
+
+	c := newTracingClaim(nil, claim, "")
+	var got []*sarama.ConsumerMessage
+	for m := range c.Messages() {
+		got = append(got, m)
+	}
+	c.stop()
+
+	require.Len(t, got, 1)
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, producer.TraceID(), span.Parent().TraceID())
+	assert.Equal(t, producer.SpanID(), span.Parent().SpanID())
+	require.Len(t, span.Links(), 1)
+	assert.Equal(t, producer.SpanID(), span.Links()[0].SpanContext.SpanID())
+	_, hasGroup := spanAttrs(span)["messaging.consumer.group.name"]
+	assert.False(t, hasGroup)
+}
+
+func TestTracingClaim_StopReleasesForwarder(t *testing.T) {
+	setupTest(t)
+
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 2)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "orders"}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "orders"}
+
+	// The handler returns without reading; the forwarder must not block.
+	c := newTracingClaim(nil, claim, "")
+	c.stop()
+
+	select {
+	case _, ok := <-c.Messages():
+		for ok {
+			_, ok = <-c.Messages()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("forwarding goroutine did not exit")
+	}
+}
+
+func TestBeforeConsume_Disabled(t *testing.T) {
+	setupTest(t)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "sarama")
+
+	handler := &recordingHandler{}
+	ictx := hooktest.NewMockHookContext(nil, context.Background(), []string{"orders"}, handler)
+	BeforeConsume(ictx, nil, context.Background(), []string{"orders"}, handler)
+
+	assert.Same(t, handler, ictx.GetParam(consumeHandlerParamIndex))
+}
+
+func TestBeforeConsume_Suppressed(t *testing.T) {
+	setupTest(t)
+
+	handler := &recordingHandler{}
+	ctx := runtime.Suppress(context.Background(), "SARAMA")
+	ictx := hooktest.NewMockHookContext(nil, ctx, []string{"orders"}, handler)
+	BeforeConsume(ictx, nil, ctx, []string{"orders"}, handler)
+
+	assert.Same(t, handler, ictx.GetParam(consumeHandlerParamIndex))
+}
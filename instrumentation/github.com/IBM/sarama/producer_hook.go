@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sarama
+
+import (
+	"context"
+	"sync"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/github.com/segmentio/kafka-go/semconv"
+	"go.opentelemetry.io/otelc/pkg/hook"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+const (
+	instrumentationName = "go.opentelemetry.io/otelc/instrumentation/github.com/IBM/sarama"
+	instrumentationKey  = "SARAMA"
+)
+
+// saramaEnablerImpl controls whether the sarama instrumentation is enabled.
+type saramaEnablerImpl struct{}
+
+func (saramaEnablerImpl) Enable() bool {
+	return runtime.Instrumented(instrumentationKey)
+}
+
+var saramaEnabler = saramaEnablerImpl{}
+
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, saramaEnabler)
+	runtime.RegisterUnsuppressible(instrumentationKey, "SyncProducer.SendMessage", "SyncProducer.SendMessages", "AsyncProducer.Input")
+}
+
+var (
+	logger     = runtime.Logger()
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	initOnce   sync.Once
+)
+
+func initInstrumentation() {
+	initOnce.Do(func() {
+		tracer = otel.GetTracerProvider().Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(runtime.ModuleVersion()),
+		)
+		propagator = otel.GetTextMapPropagator()
+		logger.Info("Kafka (IBM/sarama) instrumentation initialized")
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Producer
+//
+// Every sarama producer, sync or async, hands messages to the internal async
+// producer. A producer span is started when a message is first partitioned
+// (or earlier, on the caller's goroutine, for SyncProducer) and ended when the
+// async producer reports the message as delivered or failed.
+// -----------------------------------------------------------------------------
+
+type producerData struct {
+	span trace.Span
+	req  semconv.KafkaRequest
+}
+
+// inflight maps a *sarama.ProducerMessage to the *producerData of its
+// producer span until the message is delivered or fails.
+var inflight sync.Map
+
+// startProducerSpan starts the producer span for msg and injects its context
+// into the message headers. It is a no-op when msg already has a span, which
+// is the case for retried messages and for messages sent by a SyncProducer.
+func startProducerSpan(ctx context.Context, msg *sarama.ProducerMessage) {
+	if msg == nil {
+		return
+	}
+	if _, ok := inflight.Load(msg); ok {
+		return
+	}
+	req := semconv.KafkaRequest{
+		Destination: msg.Topic,
+		Operation:   semconv.KafkaOperationSend,
+		MessageKey:  messageKey(msg.Key),
+	}
+	if msg.Value != nil {
+		req.MessageBodySize = msg.Value.Length()
+	}
+	msgCtx, span := tracer.Start(ctx, msg.Topic+" send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.KafkaRequestTraceAttrs(req)...),
+	)
+	propagator.Inject(msgCtx, producerCarrier{msg: msg})
+	inflight.Store(msg, &producerData{span: span, req: req})
+}
+
+// endProducerSpan ends the producer span of msg, recording the partition and
+// offset assigned by the broker on success.
+func endProducerSpan(msg *sarama.ProducerMessage, err error) {
+	if msg == nil {
+		return
+	}
+	v, ok := inflight.LoadAndDelete(msg)
+	if !ok {
+		return
+	}
+	data := v.(*producerData)
+	if err != nil {
+		data.span.RecordError(err)
+		data.span.SetStatus(codes.Error, err.Error())
+	} else {
+		req := data.req
+		req.Partition = int(msg.Partition)
+		req.Offset = msg.Offset
+		req.HasPartition = true
+		req.HasOffset = true
+		data.span.SetAttributes(semconv.KafkaRequestTraceAttrs(req)...)
+	}
+	data.span.End()
+}
+
+// messageKey returns the message key for the span attributes. Only the
+// built-in encoders are read: calling Encode on an arbitrary Encoder could be
+// expensive or have side effects.
+func messageKey(key sarama.Encoder) string {
+	switch k := key.(type) {
+	case sarama.StringEncoder:
+		return string(k)
+	case sarama.ByteEncoder:
+		return string(k)
+	}
+	return ""
+}
+
+// BeforeSyncSendMessage starts the producer span on the caller's goroutine so
+// it is parented on the caller's active span.
+func BeforeSyncSendMessage(_ hook.HookContext, _ any, msg *sarama.ProducerMessage) {
+	if !saramaEnabler.Enable() {
+		logger.Debug("Sarama producer instrumentation disabled")
+		return
+	}
+	initInstrumentation()
+	startProducerSpan(context.Background(), msg)
+}
+
+// BeforeSyncSendMessages is the batch variant of BeforeSyncSendMessage.
+func BeforeSyncSendMessages(_ hook.HookContext, _ any, msgs []*sarama.ProducerMessage) {
+	if !saramaEnabler.Enable() {
+		logger.Debug("Sarama producer instrumentation disabled")
+		return
+	}
+	initInstrumentation()
+	for _, msg := range msgs {
+		startProducerSpan(context.Background(), msg)
+	}
+}
+
+// BeforePartitionMessage starts the producer span for messages written to an
+// AsyncProducer's Input channel. It runs on the producer's own goroutine, so
+// the span is parented on a trace context the caller injected into the
+// message headers, if any.
+func BeforePartitionMessage(_ hook.HookContext, _ any, msg *sarama.ProducerMessage) {
+	if !saramaEnabler.Enable() {
+		return
+	}
+	if msg == nil {
+		return
+	}
+	if _, ok := inflight.Load(msg); ok {
+		return
+	}
+	initInstrumentation()
+	startProducerSpan(propagator.Extract(context.Background(), producerCarrier{msg: msg}), msg)
+}
+
+// The completion hooks deliberately skip the enabler check: a span that was
+// started must be ended even if the instrumentation was disabled meanwhile.
+
+// BeforeReturnSuccesses ends the producer spans of delivered messages.
+func BeforeReturnSuccesses(_ hook.HookContext, _ any, batch []*sarama.ProducerMessage) {
+	for _, msg := range batch {
+		endProducerSpan(msg, nil)
+	}
+}
+
+// BeforeReturnError ends the producer span of a failed message.
+func BeforeReturnError(_ hook.HookContext, _ any, msg *sarama.ProducerMessage, err error) {
+	endProducerSpan(msg, err)
+}
+
+// BeforeReturnErrors ends the producer spans of a failed batch.
+func BeforeReturnErrors(_ hook.HookContext, _ any, batch []*sarama.ProducerMessage, err error) {
+	for _, msg := range batch {
+		endProducerSpan(msg, err)
+	}
+}
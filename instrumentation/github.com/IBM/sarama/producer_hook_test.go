@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sarama
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+)
+
+// setupTest wires the package-level tracer/propagator to an in-memory span
+// recorder, bypassing the real OTel SDK setup so hook behavior can be asserted
+// deterministically. It also enables the sarama instrumentation for the test.
+func setupTest(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "sarama")
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	// Consume initOnce so initInstrumentation() becomes a no-op and does not
+	// overwrite the tracer/propagator we install below.
+	initOnce.Do(func() {})
+	tracer = tp.Tracer("test")
+	propagator = propagation.TraceContext{}
+
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		initOnce = sync.Once{}
+		tracer = nil
+		propagator = nil
+		inflight.Clear()
+		groupIDs.Clear()
+	})
+	return sr
+}
+
+func spanAttrs(span sdktrace.ReadOnlySpan) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, a := range span.Attributes() {
+		m[string(a.Key)] = a.Value.AsInterface()
+	}
+	return m
+}
+
+func remoteSpanContext(t *testing.T, traceID, spanID string) trace.SpanContext {
+	t.Helper()
+	tid, err := trace.TraceIDFromHex(traceID)
+	require.NoError(t, err)
+	sid, err := trace.SpanIDFromHex(spanID)
+	require.NoError(t, err)
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+}
+
+func TestSyncProducer_MockBroker(t *testing.T) {
+	sr := setupTest(t)
+
+	broker := sarama.NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("orders", 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t).
+			SetError("orders", 0, sarama.ErrNoError),
+	})
+
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_0_0_0
+	// The mock broker does not answer ApiVersions requests.
+	config.ApiVersionsRequest = false
+	config.Producer.Return.Successes = true
+	producer, err := sarama.NewSyncProducer([]string{broker.Addr()}, config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = producer.Close() })
+
+	msg := &sarama.ProducerMessage{
+		Topic: "orders",
+		Key:   sarama.StringEncoder("order-1"),
+		Value: sarama.StringEncoder("hello"),
+	}
+
+	// Outside of an instrumented build the hooks are not injected, so fire
+	// them around the real call by hand.
+	BeforeSyncSendMessage(hooktest.NewMockHookContext(), producer, msg)
+	BeforePartitionMessage(hooktest.NewMockHookContext(), nil, msg)
+	partition, offset, err := producer.SendMessage(msg)
+	require.NoError(t, err)
+	BeforeReturnSuccesses(hooktest.NewMockHookContext(), nil, []*sarama.ProducerMessage{msg})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "orders send", span.Name())
+	assert.Equal(t, trace.SpanKindProducer, span.SpanKind())
+
+	m := spanAttrs(span)
+	assert.Equal(t, "kafka", m["messaging.system"])
+	assert.Equal(t, "send", m["messaging.operation.name"])
+	assert.Equal(t, "orders", m["messaging.destination.name"])
+	assert.Equal(t, "order-1", m["messaging.kafka.message.key"])
+	assert.Equal(t, int64(5), m["messaging.message.body.size"])
+	assert.Equal(t, "0", m["messaging.destination.partition.id"])
+	assert.Equal(t, int32(0), partition)
+	assert.Equal(t, offset, m["messaging.kafka.offset"])
+
+	// The producer span context travels in the message headers.
+	sc := trace.SpanContextFromContext(propagator.Extract(context.Background(), producerCarrier{msg: msg}))
+	assert.Equal(t, span.SpanContext().SpanID(), sc.SpanID())
+}
+
+func TestAsyncProducer_ParentFromHeadersAndError(t *testing.T) {
+	sr := setupTest(t)
+
+	parent := remoteSpanContext(t, "0102030405060708090a0b0c0d0e0f10", "0102030405060708")
+	msg := &sarama.ProducerMessage{Topic: "orders", Value: sarama.ByteEncoder("x")}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), parent), producerCarrier{msg: msg})
+
+	BeforePartitionMessage(hooktest.NewMockHookContext(), nil, msg)
+	// A retried message is partitioned again but keeps its span.
+	BeforePartitionMessage(hooktest.NewMockHookContext(), nil, msg)
+	BeforeReturnError(hooktest.NewMockHookContext(), nil, msg, errors.New("leader not available"))
+	// Completion is reported once; a second report is ignored.
+	BeforeReturnErrors(hooktest.NewMockHookContext(), nil, []*sarama.ProducerMessage{msg}, errors.New("again"))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, parent.TraceID(), span.Parent().TraceID())
+	assert.Equal(t, parent.SpanID(), span.Parent().SpanID())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Equal(t, "leader not available", span.Status().Description)
+	_, hasOffset := spanAttrs(span)["messaging.kafka.offset"]
+	assert.False(t, hasOffset)
+
+	// The headers now carry the producer span, not the caller's.
+	sc := trace.SpanContextFromContext(propagator.Extract(context.Background(), producerCarrier{msg: msg}))
+	assert.Equal(t, span.SpanContext().SpanID(), sc.SpanID())
+}
+
+func TestProducer_Disabled(t *testing.T) {
+	sr := setupTest(t)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "sarama")
+
+	msg := &sarama.ProducerMessage{Topic: "orders"}
+	BeforeSyncSendMessages(hooktest.NewMockHookContext(), nil, []*sarama.ProducerMessage{msg})
+	BeforePartitionMessage(hooktest.NewMockHookContext(), nil, msg)
+	BeforeReturnSuccesses(hooktest.NewMockHookContext(), nil, []*sarama.ProducerMessage{msg})
+
+	assert.Empty(t, sr.Ended())
+	assert.Empty(t, msg.Headers)
+}
+
+func TestProducerCarrier_DoesNotShareHeaders(t *testing.T) {
+	setupTest(t)
+
+	shared := make([]sarama.RecordHeader, 1, 4)
+	shared[0] = sarama.RecordHeader{Key: []byte("tenant"), Value: []byte("a")}
+	first := &sarama.ProducerMessage{Topic: "orders", Headers: shared}
+	second := &sarama.ProducerMessage{Topic: "orders", Headers: shared}
+
+	BeforeSyncSendMessages(hooktest.NewMockHookContext(), nil, []*sarama.ProducerMessage{first, second})
+	BeforeReturnSuccesses(hooktest.NewMockHookContext(), nil, []*sarama.ProducerMessage{first, second})
+
+	firstSC := trace.SpanContextFromContext(propagator.Extract(context.Background(), producerCarrier{msg: first}))
+	secondSC := trace.SpanContextFromContext(propagator.Extract(context.Background(), producerCarrier{msg: second}))
+	assert.NotEqual(t, firstSC.SpanID(), secondSC.SpanID())
+	assert.Len(t, shared, 1)
+	assert.Equal(t, "tenant", string(first.Headers[0].Key))
+}
@@ -0,0 +1,236 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"go.opentelemetry.io/otelc/instrumentation/genai"
+)
+
+// contentCapture collects the messages of a call for the
+// gen_ai.client.inference.operation.details event. It is nil when content
+// capture is disabled, and every method is a no-op on a nil receiver.
+type contentCapture struct {
+	cfg     genai.ContentConfig
+	details genai.InferenceDetails
+	// blocks accumulates the content blocks of a streaming response by
+	// block index.
+	blocks     map[int]*streamBlock
+	stopReason string
+}
+
+// streamBlock is a content block of a streaming response being reassembled
+// from its deltas. data holds the text of a text block, or the partial JSON
+// input of a tool_use block.
+type streamBlock struct {
+	typ  string
+	id   string
+	name string
+	data strings.Builder
+}
+
+// newContentCapture returns the content capture of a call, or nil when
+// capture is disabled.
+func newContentCapture(cfg genai.ContentConfig, requestBody []byte) *contentCapture {
+	if !cfg.Capture {
+		return nil
+	}
+	c := &contentCapture{cfg: cfg}
+	c.details.SystemInstructions, c.details.InputMessages = parseMessagesInput(requestBody)
+	return c
+}
+
+// emit emits the event of the call with the operation attributes attrs.
+func (c *contentCapture) emit(ctx context.Context, attrs []attribute.KeyValue) {
+	if c == nil {
+		return
+	}
+	if len(c.blocks) > 0 {
+		c.details.OutputMessages = []genai.Message{c.streamedOutput()}
+	}
+	c.cfg.Emit(ctx, eventLogger, attrs, c.details)
+}
+
+// contentBlock is a content block of the Messages API, as sent in a request
+// or returned in a response.
+type contentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	Content   json.RawMessage `json:"content"`
+}
+
+// contentBlocks decodes a content, which is either a string or an array of
+// content blocks.
+func contentBlocks(raw json.RawMessage) []contentBlock {
+	if len(raw) == 0 {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []contentBlock{{Type: "text", Text: s}}
+	}
+	var blocks []contentBlock
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return nil
+	}
+	return blocks
+}
+
+// blockParts converts content blocks to message parts. Only text, tool_use
+// and tool_result blocks are kept; images, documents and thinking blocks are
+// left out.
+func blockParts(blocks []contentBlock) []genai.MessagePart {
+	parts := make([]genai.MessagePart, 0, len(blocks))
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			parts = append(parts, genai.MessagePart{Type: genai.PartTypeText, Content: b.Text})
+		case "tool_use":
+			parts = append(parts, genai.MessagePart{
+				Type:      genai.PartTypeToolCall,
+				ID:        b.ID,
+				Name:      b.Name,
+				Arguments: string(b.Input),
+			})
+		case "tool_result":
+			var texts []string
+			for _, p := range blockParts(contentBlocks(b.Content)) {
+				if p.Type == genai.PartTypeText {
+					texts = append(texts, p.Content)
+				}
+			}
+			parts = append(parts, genai.MessagePart{
+				Type:     genai.PartTypeToolCallResponse,
+				ID:       b.ToolUseID,
+				Response: strings.Join(texts, "\n"),
+			})
+		}
+	}
+	return parts
+}
+
+// parseMessagesInput returns the system instructions and the input messages of
+// a Messages API request.
+func parseMessagesInput(body []byte) ([]genai.MessagePart, []genai.Message) {
+	var req struct {
+		System   json.RawMessage `json:"system"`
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, nil
+	}
+	messages := make([]genai.Message, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		messages = append(messages, genai.Message{Role: m.Role, Parts: blockParts(contentBlocks(m.Content))})
+	}
+	return blockParts(contentBlocks(req.System)), messages
+}
+
+// addResponse records the output message of a non-streaming response body.
+func (c *contentCapture) addResponse(body []byte) {
+	if c == nil {
+		return
+	}
+	var resp struct {
+		Role       string          `json:"role"`
+		Content    json.RawMessage `json:"content"`
+		StopReason string          `json:"stop_reason"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return
+	}
+	c.details.OutputMessages = []genai.Message{{
+		Role:         resp.Role,
+		Parts:        blockParts(contentBlocks(resp.Content)),
+		FinishReason: resp.StopReason,
+	}}
+}
+
+// addEvent accumulates the content blocks of a streaming response event.
+func (c *contentCapture) addEvent(payload []byte) {
+	if c == nil {
+		return
+	}
+	var event struct {
+		Type         string       `json:"type"`
+		Index        int          `json:"index"`
+		ContentBlock contentBlock `json:"content_block"`
+		Delta        struct {
+			Type        string `json:"type"`
+			Text        string `json:"text"`
+			PartialJSON string `json:"partial_json"`
+			StopReason  string `json:"stop_reason"`
+		} `json:"delta"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return
+	}
+
+	switch event.Type {
+	case "content_block_start":
+		if c.blocks == nil {
+			c.blocks = map[int]*streamBlock{}
+		}
+		block := &streamBlock{
+			typ:  event.ContentBlock.Type,
+			id:   event.ContentBlock.ID,
+			name: event.ContentBlock.Name,
+		}
+		block.data.WriteString(event.ContentBlock.Text)
+		c.blocks[event.Index] = block
+	case "content_block_delta":
+		block, ok := c.blocks[event.Index]
+		if !ok {
+			return
+		}
+		switch event.Delta.Type {
+		case "text_delta":
+			block.data.WriteString(event.Delta.Text)
+		case "input_json_delta":
+			block.data.WriteString(event.Delta.PartialJSON)
+		}
+	case "message_delta":
+		if event.Delta.StopReason != "" {
+			c.stopReason = event.Delta.StopReason
+		}
+	}
+}
+
+// streamedOutput returns the output message reassembled from the content
+// blocks, in block order.
+func (c *contentCapture) streamedOutput() genai.Message {
+	out := genai.Message{Role: "assistant", FinishReason: c.stopReason}
+	for _, i := range slices.Sorted(maps.Keys(c.blocks)) {
+		block := c.blocks[i]
+		switch block.typ {
+		case "text":
+			out.Parts = append(out.Parts, genai.MessagePart{
+				Type:    genai.PartTypeText,
+				Content: block.data.String(),
+			})
+		case "tool_use":
+			out.Parts = append(out.Parts, genai.MessagePart{
+				Type:      genai.PartTypeToolCall,
+				ID:        block.id,
+				Name:      block.name,
+				Arguments: block.data.String(),
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,246 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/genai"
+)
+
+type recordingLogger struct {
+	embedded.Logger
+	records []log.Record
+	ctxs    []context.Context
+}
+
+func (l *recordingLogger) Emit(ctx context.Context, record log.Record) {
+	l.records = append(l.records, record)
+	l.ctxs = append(l.ctxs, ctx)
+}
+
+func (*recordingLogger) Enabled(context.Context, log.EnabledParameters) bool {
+	return true
+}
+
+func setupTestEventLogger(t *testing.T) *recordingLogger {
+	t.Helper()
+	t.Setenv("OTEL_INSTRUMENTATION_GENAI_CAPTURE_MESSAGE_CONTENT", "true")
+	logger := &recordingLogger{}
+	eventLogger = logger
+	t.Cleanup(func() { eventLogger = nil })
+	return logger
+}
+
+// eventMessages returns the messages of attribute key of the single recorded
+// event as role and parts maps.
+func eventMessages(t *testing.T, logger *recordingLogger, key string) []map[string]log.Value {
+	t.Helper()
+	require.Len(t, logger.records, 1)
+	var messages []map[string]log.Value
+	logger.records[0].WalkAttributes(func(kv log.KeyValue) bool {
+		if kv.Key == key {
+			for _, v := range kv.Value.AsSlice() {
+				messages = append(messages, logMap(v))
+			}
+		}
+		return true
+	})
+	return messages
+}
+
+func logMap(v log.Value) map[string]log.Value {
+	m := map[string]log.Value{}
+	for _, kv := range v.AsMap() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func messageParts(message map[string]log.Value) []map[string]log.Value {
+	var parts []map[string]log.Value
+	for _, v := range message["parts"].AsSlice() {
+		parts = append(parts, logMap(v))
+	}
+	return parts
+}
+
+func TestParseMessagesInput(t *testing.T) {
+	body := []byte(`{"model":"claude-sonnet-4-5","system":[{"type":"text","text":"Be brief."}],"messages":[
+		{"role":"user","content":[{"type":"text","text":"What is in this image?"},{"type":"image","source":{"type":"base64","data":"..."}}]},
+		{"role":"assistant","content":[{"type":"tool_use","id":"toolu_1","name":"describe","input":{"detail":"low"}}]},
+		{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"a cat"}]}]},
+		{"role":"user","content":"Thanks"}
+	]}`)
+
+	system, messages := parseMessagesInput(body)
+	assert.Equal(t, []genai.MessagePart{{Type: genai.PartTypeText, Content: "Be brief."}}, system)
+	require.Len(t, messages, 4)
+	assert.Equal(t, []genai.MessagePart{
+		{Type: genai.PartTypeText, Content: "What is in this image?"},
+	}, messages[0].Parts, "only text content is captured")
+	assert.Equal(t, []genai.MessagePart{
+		{Type: genai.PartTypeToolCall, ID: "toolu_1", Name: "describe", Arguments: `{"detail":"low"}`},
+	}, messages[1].Parts)
+	assert.Equal(t, []genai.MessagePart{
+		{Type: genai.PartTypeToolCallResponse, ID: "toolu_1", Response: "a cat"},
+	}, messages[2].Parts)
+	assert.Equal(t, genai.Message{
+		Role:  "user",
+		Parts: []genai.MessagePart{{Type: genai.PartTypeText, Content: "Thanks"}},
+	}, messages[3])
+
+	system, messages = parseMessagesInput([]byte(`{"system":"Be brief.","messages":[]}`))
+	assert.Equal(t, "Be brief.", system[0].Content)
+	assert.Empty(t, messages)
+
+	system, messages = parseMessagesInput([]byte(`invalid json`))
+	assert.Nil(t, system)
+	assert.Nil(t, messages)
+}
+
+func TestContentCapture_StreamedToolUse(t *testing.T) {
+	c := newContentCapture(genai.ContentConfig{Capture: true}, []byte(`{"messages":[]}`))
+	require.NotNil(t, c)
+
+	c.addEvent([]byte(`{"type":"message_start","message":{"id":"msg_1","role":"assistant"}}`))
+	c.addEvent([]byte(`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`))
+	c.addEvent([]byte(`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Let me "}}`))
+	c.addEvent([]byte(`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"check."}}`))
+	c.addEvent([]byte(`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}`))
+	c.addEvent([]byte(`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`))
+	c.addEvent([]byte(`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}`))
+	c.addEvent([]byte(`{"type":"message_delta","delta":{"stop_reason":"tool_use"}}`))
+
+	assert.Equal(t, genai.Message{
+		Role: "assistant",
+		Parts: []genai.MessagePart{
+			{Type: genai.PartTypeText, Content: "Let me check."},
+			{Type: genai.PartTypeToolCall, ID: "toolu_1", Name: "get_weather", Arguments: `{"city":"Paris"}`},
+		},
+		FinishReason: "tool_use",
+	}, c.streamedOutput())
+}
+
+func TestNewContentCapture_Disabled(t *testing.T) {
+	assert.Nil(t, newContentCapture(genai.ContentConfig{}, []byte(`{}`)))
+}
+
+func TestOtelMiddleware_CaptureContent(t *testing.T) {
+	sr := setupTestTracer(t)
+	logger := setupTestEventLogger(t)
+
+	middleware := OtelMiddleware()
+
+	reqBody := `{"model":"claude-sonnet-4-5","max_tokens":1024,"system":"Be brief.","messages":[{"role":"user","content":"Hello"}]}`
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.anthropic.com/v1/messages",
+		io.NopCloser(bytes.NewReader([]byte(reqBody))),
+	)
+
+	respBody := `{"id":"msg_test_123","type":"message","role":"assistant","model":"claude-sonnet-4-5","content":[{"type":"text","text":"Hi there!"}],"stop_reason":"end_turn","usage":{"input_tokens":10,"output_tokens":3}}`
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(respBody))),
+		}, nil
+	}
+
+	_, err := middleware(req, next)
+	require.NoError(t, err)
+
+	require.Len(t, logger.records, 1)
+	assert.Equal(t, genai.InferenceDetailsEventName, logger.records[0].EventName())
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, spans[0].SpanContext(), trace.SpanContextFromContext(logger.ctxs[0]),
+		"the event is correlated with the GenAI span")
+
+	system := eventMessages(t, logger, genai.GenAISystemInstructionsKey)
+	require.Len(t, system, 1)
+	assert.Equal(t, "Be brief.", system[0]["content"].AsString())
+
+	input := eventMessages(t, logger, genai.GenAIInputMessagesKey)
+	require.Len(t, input, 1)
+	assert.Equal(t, "user", input[0]["role"].AsString())
+	assert.Equal(t, "Hello", messageParts(input[0])[0]["content"].AsString())
+
+	output := eventMessages(t, logger, genai.GenAIOutputMessagesKey)
+	require.Len(t, output, 1)
+	assert.Equal(t, "assistant", output[0]["role"].AsString())
+	assert.Equal(t, "end_turn", output[0]["finish_reason"].AsString())
+	assert.Equal(t, "Hi there!", messageParts(output[0])[0]["content"].AsString())
+}
+
+func TestOtelMiddleware_CaptureContent_Streaming(t *testing.T) {
+	setupTestTracer(t)
+	logger := setupTestEventLogger(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.anthropic.com/v1/messages",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"claude-sonnet-4-5","stream":true,"messages":[{"role":"user","content":"Hello"}]}`))),
+	)
+
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(testStream))),
+		}, nil
+	}
+
+	resp, err := middleware(req, next)
+	require.NoError(t, err)
+	assert.Empty(t, logger.records, "the event is emitted once the stream ends")
+
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	output := eventMessages(t, logger, genai.GenAIOutputMessagesKey)
+	require.Len(t, output, 1)
+	assert.Equal(t, "end_turn", output[0]["finish_reason"].AsString())
+	assert.Equal(t, "Hello", messageParts(output[0])[0]["content"].AsString())
+}
+
+func TestOtelMiddleware_CaptureContent_Disabled(t *testing.T) {
+	setupTestTracer(t)
+	logger := setupTestEventLogger(t)
+	t.Setenv("OTEL_INSTRUMENTATION_GENAI_CAPTURE_MESSAGE_CONTENT", "")
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.anthropic.com/v1/messages",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"claude-sonnet-4-5","messages":[{"role":"user","content":"Hello"}]}`))),
+	)
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"content":[{"type":"text","text":"Hi"}]}`))),
+		}, nil
+	}
+
+	_, err := middleware(req, next)
+	require.NoError(t, err)
+	assert.Empty(t, logger.records)
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
+	"go.opentelemetry.io/otelc/instrumentation/genai"
 	"go.opentelemetry.io/otelc/instrumentation/github.com/anthropics/anthropic-sdk-go/semconv"
 	"go.opentelemetry.io/otelc/pkg/runtime"
 )
@@ -68,9 +70,14 @@ func operationName(op operationType) string {
 
 // OtelMiddleware returns an HTTP middleware that creates spans for Anthropic
 // API calls following GenAI semantic conventions.
+//
+// When OTEL_INSTRUMENTATION_GENAI_CAPTURE_MESSAGE_CONTENT is "true", the
+// messages of every call are also emitted as a
+// gen_ai.client.inference.operation.details log event.
 func OtelMiddleware() func(*http.Request, func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	contentCfg := genai.ContentConfigFromEnv()
 	return func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
-		if req.Body == nil {
+		if req.Body == nil || runtime.IsSpanSuppressed(req.Context(), instrumentationKey, trace.SpanKindClient) {
 			return next(req)
 		}
 
@@ -98,12 +105,8 @@ func OtelMiddleware() func(*http.Request, func(*http.Request) (*http.Response, e
 		}
 
 		model, isStream, spanAttrs := parseMessagesRequest(bodyBytes)
-
-		// Streaming responses need event accumulation before their spans carry
-		// usage data; until that lands (#679, follow-up PR), pass streaming
-		// requests through uninstrumented rather than emit incomplete spans.
 		if isStream {
-			return next(req)
+			spanAttrs = append(spanAttrs, semconv.GenAIRequestIsStream(true))
 		}
 
 		spanName := opName + " " + model
@@ -114,13 +117,16 @@ func OtelMiddleware() func(*http.Request, func(*http.Request) (*http.Response, e
 			semconv.GenAIProviderName(provider),
 		}
 		spanAttrs = append(baseAttrs, spanAttrs...)
+		metricAttrs := metricAttributes(opName, provider, model)
+		content := newContentCapture(contentCfg, bodyBytes)
 
 		ctx := req.Context()
 		ctx, span := tracer.Start(ctx, spanName,
 			trace.WithSpanKind(trace.SpanKindClient),
 			trace.WithAttributes(spanAttrs...),
 		)
-		ctx = runtime.SuppressHTTPClientInstrumentation(ctx)
+		// The GenAI span replaces the span of the underlying HTTP request.
+		ctx = runtime.Suppress(ctx, httpInstrumentationKey)
 		req = req.WithContext(ctx)
 
 		resp, err := next(req)
@@ -128,6 +134,8 @@ func OtelMiddleware() func(*http.Request, func(*http.Request) (*http.Response, e
 			span.SetStatus(codes.Error, err.Error())
 			span.RecordError(err)
 			span.End()
+			endCall(ctx, start, "", nil, content,
+				append(metricAttrs, attribute.String("error.type", fmt.Sprintf("%T", err))))
 			return resp, err
 		}
 
@@ -135,32 +143,46 @@ func OtelMiddleware() func(*http.Request, func(*http.Request) (*http.Response, e
 			span.SetStatus(codes.Error, resp.Status)
 			span.SetAttributes(attribute.String("error.type", resp.Status))
 			span.End()
+			endCall(ctx, start, "", nil, content,
+				append(metricAttrs, attribute.String("error.type", resp.Status)))
 			return resp, nil
 		}
 
-		// Streaming requests were already passed through above; if the server
-		// still answers with SSE, end the span without response attributes
-		// rather than hold it open on a body we do not accumulate yet.
 		contentType := resp.Header.Get("Content-Type")
-		if strings.HasPrefix(contentType, "text/event-stream") {
+		isStreaming := strings.HasPrefix(contentType, "text/event-stream")
+
+		if isStreaming {
 			span.SetAttributes(semconv.GenAIRequestIsStream(true))
-			span.End()
-			return resp, nil
+			if resp.Body == nil {
+				span.End()
+				endCall(ctx, start, "", nil, content, metricAttrs)
+				return resp, nil
+			}
+			resp.Body = newStreamingReader(ctx, resp.Body, span, start, content, metricAttrs)
+		} else {
+			handleNonStreamingResponse(ctx, resp, span, start, content, metricAttrs)
 		}
 
-		handleNonStreamingResponse(ctx, resp, span, start)
-
 		return resp, nil
 	}
 }
 
 func handleNonStreamingResponse(
-	_ context.Context,
+	ctx context.Context,
 	resp *http.Response,
 	span trace.Span,
-	_ time.Time,
+	start time.Time,
+	content *contentCapture,
+	metricAttrs []attribute.KeyValue,
 ) {
-	defer span.End()
+	var (
+		model string
+		usage *messageUsage
+	)
+	defer func() {
+		span.End()
+		endCall(ctx, start, model, usage, content, metricAttrs)
+	}()
 
 	if resp.Body == nil {
 		return
@@ -179,7 +201,8 @@ func handleNonStreamingResponse(
 		return
 	}
 
-	parseMessagesResponse(bodyBytes, span)
+	model, usage = parseMessagesResponse(bodyBytes, span)
+	content.addResponse(bodyBytes)
 }
 
 func parseMessagesRequest(body []byte) (string, bool, []attribute.KeyValue) {
@@ -211,53 +234,107 @@ func parseMessagesRequest(body []byte) (string, bool, []attribute.KeyValue) {
 	return req.Model, req.Stream, attrs
 }
 
-func parseMessagesResponse(body []byte, span trace.Span) {
+// messageUsage is the usage block of a Messages API response. Streaming
+// responses report the same fields, split across message_start and
+// message_delta events.
+type messageUsage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+}
+
+// totalInputTokens returns the input tokens of the call. Unlike OpenAI's
+// prompt_tokens, Anthropic's input_tokens excludes cache reads and creations,
+// which are reported separately; fold them back in so the count reflects the
+// full prompt per semconv.
+func (u messageUsage) totalInputTokens() int64 {
+	return u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens
+}
+
+// parseMessagesResponse sets the response attributes on span and returns the
+// response model and token usage, or a nil usage if body is not a valid
+// response.
+func parseMessagesResponse(body []byte, span trace.Span) (string, *messageUsage) {
 	var resp struct {
-		ID         string `json:"id"`
-		Model      string `json:"model"`
-		StopReason string `json:"stop_reason"`
-		Usage      struct {
-			InputTokens              int64 `json:"input_tokens"`
-			OutputTokens             int64 `json:"output_tokens"`
-			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
-			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
-		} `json:"usage"`
+		ID         string       `json:"id"`
+		Model      string       `json:"model"`
+		StopReason string       `json:"stop_reason"`
+		Usage      messageUsage `json:"usage"`
 	}
 	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", nil
+	}
+
+	setResponseAttributes(span, resp.ID, resp.Model, resp.StopReason, resp.Usage)
+	return resp.Model, &resp.Usage
+}
+
+// metricAttributes returns the attributes shared by the GenAI metrics of an
+// operation. Per-request attributes such as the sampling parameters are left
+// out to keep metric cardinality bounded.
+func metricAttributes(opName, provider, model string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.GenAIOperationName(opName),
+		semconv.GenAIProviderName(provider),
+		semconv.GenAIRequestModel(model),
+	}
+}
+
+// endCall records the duration of a Messages API call started at start and,
+// when the response reported it, its token usage, then emits the captured
+// content of the call. Input tokens include the prompt-cache tokens, matching
+// gen_ai.usage.input_tokens on the span.
+func endCall(
+	ctx context.Context,
+	start time.Time,
+	responseModel string,
+	usage *messageUsage,
+	content *contentCapture,
+	attrs []attribute.KeyValue,
+) {
+	if responseModel != "" {
+		attrs = append(attrs[:len(attrs):len(attrs)], semconv.GenAIResponseModel(responseModel))
+	}
+	metrics.RecordOperationDuration(ctx, time.Since(start), attrs)
+	content.emit(ctx, attrs)
+
+	if usage == nil {
 		return
 	}
+	metrics.RecordTokenUsage(ctx, genai.GenAITokenTypeInput, usage.totalInputTokens(), attrs)
+	metrics.RecordTokenUsage(ctx, genai.GenAITokenTypeOutput, usage.OutputTokens, attrs)
+}
 
+// setResponseAttributes records the response identity, stop reason and token
+// usage of a Messages API call on span.
+func setResponseAttributes(span trace.Span, id, model, stopReason string, usage messageUsage) {
 	// Anthropic returns a single stop_reason rather than per-choice
 	// finish_reason values; wrap it to keep the shared attribute shape.
 	var reasons []string
-	if resp.StopReason != "" {
-		reasons = append(reasons, resp.StopReason)
+	if stopReason != "" {
+		reasons = append(reasons, stopReason)
 	}
 
-	// Unlike OpenAI's prompt_tokens, Anthropic's input_tokens excludes cache
-	// reads and creations, which are reported separately. Fold them back in so
-	// gen_ai.usage.input_tokens reflects the full prompt per semconv.
-	totalInput := resp.Usage.InputTokens +
-		resp.Usage.CacheReadInputTokens +
-		resp.Usage.CacheCreationInputTokens
+	totalInput := usage.totalInputTokens()
 
 	span.SetAttributes(
-		semconv.GenAIResponseID(resp.ID),
-		semconv.GenAIResponseModel(resp.Model),
+		semconv.GenAIResponseID(id),
+		semconv.GenAIResponseModel(model),
 		semconv.GenAIResponseFinishReasons(reasons),
 		semconv.GenAIUsageInputTokens(totalInput),
-		semconv.GenAIUsageOutputTokens(resp.Usage.OutputTokens),
+		semconv.GenAIUsageOutputTokens(usage.OutputTokens),
 		// The Messages API reports no total_tokens field; derive it so the
 		// span shape matches the other GenAI instrumentations.
-		semconv.GenAIUsageTotalTokens(totalInput+resp.Usage.OutputTokens),
+		semconv.GenAIUsageTotalTokens(totalInput+usage.OutputTokens),
 	)
 
 	// Prompt-cache usage is Anthropic-specific; only record it when the
 	// request actually used the cache.
-	if resp.Usage.CacheReadInputTokens > 0 {
-		span.SetAttributes(semconv.GenAIUsageCacheReadInputTokens(resp.Usage.CacheReadInputTokens))
+	if usage.CacheReadInputTokens > 0 {
+		span.SetAttributes(semconv.GenAIUsageCacheReadInputTokens(usage.CacheReadInputTokens))
 	}
-	if resp.Usage.CacheCreationInputTokens > 0 {
-		span.SetAttributes(semconv.GenAIUsageCacheCreationInputTokens(resp.Usage.CacheCreationInputTokens))
+	if usage.CacheCreationInputTokens > 0 {
+		span.SetAttributes(semconv.GenAIUsageCacheCreationInputTokens(usage.CacheCreationInputTokens))
 	}
 }
@@ -18,8 +18,13 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"go.opentelemetry.io/otelc/instrumentation/genai"
+	"go.opentelemetry.io/otelc/pkg/runtime"
 )
 
 func TestClassifyOperation(t *testing.T) {
@@ -98,6 +103,71 @@ func setupTestTracer(t *testing.T) *tracetest.SpanRecorder {
 	return sr
 }
 
+func setupTestMeter(t *testing.T) *sdkmetric.ManualReader {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	var err error
+	metrics, err = genai.NewMetrics(mp.Meter("test"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		metrics = nil
+		_ = mp.Shutdown(context.Background())
+	})
+	return reader
+}
+
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) (metricdata.Metrics, bool) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+// tokenUsage returns the recorded token counts of gen_ai.client.token.usage
+// by gen_ai.token.type.
+func tokenUsage(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+	m, ok := collectMetric(t, reader, "gen_ai.client.token.usage")
+	if !ok {
+		return nil
+	}
+	hist, ok := m.Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	usage := map[string]int64{}
+	for _, dp := range hist.DataPoints {
+		tokenType, _ := dp.Attributes.Value(genai.GenAITokenTypeKey)
+		usage[tokenType.AsString()] += dp.Sum
+	}
+	return usage
+}
+
+// operationDuration returns the single data point of
+// gen_ai.client.operation.duration.
+func operationDuration(t *testing.T, reader *sdkmetric.ManualReader) metricdata.HistogramDataPoint[float64] {
+	t.Helper()
+	m, ok := collectMetric(t, reader, "gen_ai.client.operation.duration")
+	require.True(t, ok)
+	hist, ok := m.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+	return hist.DataPoints[0]
+}
+
+func assertMetricAttribute(t *testing.T, set attribute.Set, key, expected string) {
+	t.Helper()
+	v, ok := set.Value(attribute.Key(key))
+	require.True(t, ok, "attribute %s not found", key)
+	assert.Equal(t, expected, v.AsString())
+}
+
 // TestOtelMiddleware_Messages defines the expected span shape for a
 // non-streaming Messages API call (POST /v1/messages).
 func TestOtelMiddleware_Messages(t *testing.T) {
@@ -213,10 +283,10 @@ func TestOtelMiddleware_SkipsCountTokens(t *testing.T) {
 	assert.Empty(t, sr.Ended())
 }
 
-// TestOtelMiddleware_StreamingRequestPassThrough verifies that streaming
-// requests are not instrumented until event accumulation lands, and that the
-// SDK still receives the complete request body.
-func TestOtelMiddleware_StreamingRequestPassThrough(t *testing.T) {
+// TestOtelMiddleware_Streaming verifies that a streaming Messages API call
+// gets a span that ends once the SDK has consumed the event stream, carrying
+// the usage accumulated from message_start and message_delta.
+func TestOtelMiddleware_Streaming(t *testing.T) {
 	sr := setupTestTracer(t)
 
 	middleware := OtelMiddleware()
@@ -236,21 +306,38 @@ func TestOtelMiddleware_StreamingRequestPassThrough(t *testing.T) {
 		return &http.Response{
 			StatusCode: 200,
 			Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
-			Body:       io.NopCloser(strings.NewReader("event: message_stop\n\n")),
+			Body:       io.NopCloser(strings.NewReader(testStream)),
 		}, nil
 	}
 
 	resp, err := middleware(req, next)
 	require.NoError(t, err)
 	require.NotNil(t, resp)
-
 	assert.Equal(t, reqBody, string(received), "SDK must see the full request body")
-	assert.Empty(t, sr.Ended(), "streaming requests must not produce spans yet")
+	assert.Empty(t, sr.Ended(), "the span stays open until the stream is consumed")
+
+	got, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, testStream, string(got))
+	require.NoError(t, resp.Body.Close())
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "chat claude-sonnet-4-5", spans[0].Name())
+
+	attrs := spans[0].Attributes()
+	assertBoolAttribute(t, attrs, "gen_ai.request.is_stream", true)
+	assertAttribute(t, attrs, "gen_ai.response.id", "msg_stream_1")
+	assertAttribute(t, attrs, "gen_ai.response.model", "claude-sonnet-4-5-20250929")
+	assertStringSliceAttribute(t, attrs, "gen_ai.response.finish_reasons", []string{"end_turn"})
+	assertInt64Attribute(t, attrs, "gen_ai.usage.input_tokens", 27)
+	assertInt64Attribute(t, attrs, "gen_ai.usage.output_tokens", 15)
+	assertInt64Attribute(t, attrs, "gen_ai.usage.total_tokens", 42)
 }
 
 // TestOtelMiddleware_SSEResponseFallback covers the defensive path where a
-// request without the stream flag still receives an SSE response: the span
-// ends immediately instead of staying open on an unconsumed body.
+// request without the stream flag still receives an SSE response: the body is
+// wrapped like a streaming one instead of being parsed as JSON.
 func TestOtelMiddleware_SSEResponseFallback(t *testing.T) {
 	sr := setupTestTracer(t)
 
@@ -275,14 +362,14 @@ func TestOtelMiddleware_SSEResponseFallback(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 
-	spans := sr.Ended()
-	require.Len(t, spans, 1)
-	assertBoolAttribute(t, spans[0].Attributes(), "gen_ai.request.is_stream", true)
-
-	// The body is returned untouched for the caller to consume.
+	// The body is returned unchanged for the caller to consume.
 	got, err := io.ReadAll(resp.Body)
 	require.NoError(t, err)
 	assert.Equal(t, sse, string(got))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assertBoolAttribute(t, spans[0].Attributes(), "gen_ai.request.is_stream", true)
 }
 
 // TestOtelMiddleware_RequestBodyReadError verifies that a failing request body
@@ -528,3 +615,184 @@ func assertBoolAttribute(t *testing.T, attrs []attribute.KeyValue, key string, e
 	require.True(t, ok, "attribute %s not found", key)
 	assert.Equal(t, expected, val.AsBool(), "attribute %s", key)
 }
+
+func TestOtelMiddleware_Suppressed(t *testing.T) {
+	sr := setupTestTracer(t)
+
+	middleware := OtelMiddleware()
+
+	ctx := runtime.Suppress(context.Background(), "ANTHROPIC")
+	req, _ := http.NewRequestWithContext(ctx,
+		"POST",
+		"https://api.anthropic.com/v1/messages",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"claude-sonnet-4-5"}`))),
+	)
+
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{}`))),
+		}, nil
+	}
+
+	resp, err := middleware(req, next)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	assert.Empty(t, sr.Ended(), "suppressed context should skip instrumentation")
+}
+
+func TestOtelMiddleware_SuppressesHTTPClient(t *testing.T) {
+	setupTestTracer(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"https://api.anthropic.com/v1/messages",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"claude-sonnet-4-5"}`))),
+	)
+
+	var nextCtx context.Context
+	next := func(r *http.Request) (*http.Response, error) {
+		nextCtx = r.Context()
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{}`))),
+		}, nil
+	}
+
+	_, err := middleware(req, next)
+	require.NoError(t, err)
+
+	require.NotNil(t, nextCtx)
+	assert.True(t, runtime.IsSuppressed(nextCtx, "NETHTTP"), "the HTTP client span should be suppressed below the GenAI span")
+}
+
+// TestOtelMiddleware_Metrics verifies the GenAI metrics of a non-streaming
+// Messages API call. Input tokens include the prompt-cache tokens, like
+// gen_ai.usage.input_tokens on the span.
+func TestOtelMiddleware_Metrics(t *testing.T) {
+	setupTestTracer(t)
+	reader := setupTestMeter(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.anthropic.com/v1/messages",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"claude-sonnet-4-5","max_tokens":1024,"temperature":0.7}`))),
+	)
+
+	respBody := `{"id":"msg_test_123","type":"message","model":"claude-sonnet-4-5-20250929","stop_reason":"end_turn","usage":{"input_tokens":10,"output_tokens":20,"cache_read_input_tokens":7,"cache_creation_input_tokens":3}}`
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(respBody)),
+		}, nil
+	}
+
+	_, err := middleware(req, next)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{"input": 20, "output": 20}, tokenUsage(t, reader))
+
+	dp := operationDuration(t, reader)
+	assert.Equal(t, uint64(1), dp.Count)
+	assertMetricAttribute(t, dp.Attributes, "gen_ai.operation.name", "chat")
+	assertMetricAttribute(t, dp.Attributes, "gen_ai.provider.name", "anthropic")
+	assertMetricAttribute(t, dp.Attributes, "gen_ai.request.model", "claude-sonnet-4-5")
+	assertMetricAttribute(t, dp.Attributes, "gen_ai.response.model", "claude-sonnet-4-5-20250929")
+	_, ok := dp.Attributes.Value("gen_ai.request.temperature")
+	assert.False(t, ok, "request parameters are not metric attributes")
+}
+
+func TestOtelMiddleware_Metrics_Streaming(t *testing.T) {
+	setupTestTracer(t)
+	reader := setupTestMeter(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.anthropic.com/v1/messages",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"claude-sonnet-4-5","max_tokens":1024,"stream":true}`))),
+	)
+
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+			Body:       io.NopCloser(strings.NewReader(testStream)),
+		}, nil
+	}
+
+	resp, err := middleware(req, next)
+	require.NoError(t, err)
+
+	_, ok := collectMetric(t, reader, "gen_ai.client.operation.duration")
+	assert.False(t, ok, "streaming metrics are recorded once the stream ends")
+
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, map[string]int64{"input": 27, "output": 15}, tokenUsage(t, reader))
+	dp := operationDuration(t, reader)
+	assertMetricAttribute(t, dp.Attributes, "gen_ai.response.model", "claude-sonnet-4-5-20250929")
+}
+
+func TestOtelMiddleware_Metrics_HTTPError(t *testing.T) {
+	setupTestTracer(t)
+	reader := setupTestMeter(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.anthropic.com/v1/messages",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"claude-sonnet-4-5","max_tokens":10}`))),
+	)
+
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 429,
+			Status:     "429 Too Many Requests",
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+
+	_, err := middleware(req, next)
+	require.NoError(t, err)
+
+	assert.Nil(t, tokenUsage(t, reader), "failed calls report no token usage")
+	dp := operationDuration(t, reader)
+	assertMetricAttribute(t, dp.Attributes, "error.type", "429 Too Many Requests")
+}
+
+func TestOtelMiddleware_Metrics_TransportError(t *testing.T) {
+	setupTestTracer(t)
+	reader := setupTestMeter(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.anthropic.com/v1/messages",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"claude-sonnet-4-5","max_tokens":10}`))),
+	)
+
+	next := func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	_, err := middleware(req, next)
+	require.Error(t, err)
+
+	dp := operationDuration(t, reader)
+	assertMetricAttribute(t, dp.Attributes, "error.type", "*errors.errorString")
+}
@@ -0,0 +1,199 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/github.com/anthropics/anthropic-sdk-go/semconv"
+)
+
+// streamingReader wraps the SSE body of a streaming Messages API response. It
+// accumulates the message_start and message_delta events as the SDK reads the
+// stream and ends the span, with the response attributes, once the stream is
+// exhausted or closed.
+type streamingReader struct {
+	ctx         context.Context
+	reader      io.ReadCloser
+	lineBuffer  bytes.Buffer
+	start       time.Time
+	first       time.Time
+	span        trace.Span
+	content     *contentCapture
+	metricAttrs []attribute.KeyValue
+	done        atomic.Bool
+
+	id         string
+	model      string
+	stopReason string
+	usage      messageUsage
+	errType    string
+	errMessage string
+}
+
+func newStreamingReader(
+	ctx context.Context,
+	body io.ReadCloser,
+	span trace.Span,
+	start time.Time,
+	content *contentCapture,
+	metricAttrs []attribute.KeyValue,
+) *streamingReader {
+	return &streamingReader{
+		ctx:         ctx,
+		reader:      body,
+		start:       start,
+		span:        span,
+		content:     content,
+		metricAttrs: metricAttrs,
+	}
+}
+
+func (r *streamingReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+
+	if n > 0 {
+		r.lineBuffer.Write(p[:n])
+		r.processSSELines()
+	}
+
+	if err != nil && r.done.CompareAndSwap(false, true) {
+		r.finalize()
+	}
+
+	return n, err
+}
+
+func (r *streamingReader) Close() error {
+	if r.done.CompareAndSwap(false, true) {
+		r.finalize()
+	}
+	return r.reader.Close()
+}
+
+func (r *streamingReader) finalize() {
+	// The stream may end without a trailing newline.
+	if payload := parseSSELine(bytes.TrimSpace(r.lineBuffer.Bytes())); payload != nil {
+		r.processEvent(payload)
+	}
+	r.lineBuffer.Reset()
+
+	// A stream closed before message_start carries no response data; leave
+	// the span without usage attributes rather than report zero tokens.
+	var usage *messageUsage
+	if r.id != "" {
+		setResponseAttributes(r.span, r.id, r.model, r.stopReason, r.usage)
+		usage = &r.usage
+	}
+	if !r.first.IsZero() {
+		firstTokenUs := r.first.Sub(r.start).Microseconds()
+		r.span.SetAttributes(semconv.GenAIResponseTimeToFirstToken(firstTokenUs))
+	}
+	metricAttrs := r.metricAttrs
+	if r.errType != "" {
+		r.span.SetStatus(codes.Error, r.errMessage)
+		r.span.SetAttributes(attribute.String("error.type", r.errType))
+		metricAttrs = append(metricAttrs[:len(metricAttrs):len(metricAttrs)],
+			attribute.String("error.type", r.errType))
+	}
+
+	r.span.End()
+	endCall(r.ctx, r.start, r.model, usage, r.content, metricAttrs)
+}
+
+// processSSELines consumes every complete line in the buffer, keeping a
+// trailing partial line for the next read.
+func (r *streamingReader) processSSELines() {
+	for {
+		data := r.lineBuffer.Bytes()
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			return
+		}
+		line := bytes.TrimSpace(data[:i])
+		if payload := parseSSELine(line); payload != nil {
+			r.processEvent(payload)
+		}
+		r.lineBuffer.Next(i + 1)
+	}
+}
+
+// parseSSELine returns the payload of an SSE data line, or nil for any other
+// line. Anthropic repeats the event name in the payload's type field, so the
+// preceding "event:" lines are not needed.
+func parseSSELine(line []byte) []byte {
+	payload, ok := bytes.CutPrefix(line, []byte("data:"))
+	if !ok {
+		return nil
+	}
+	return bytes.TrimSpace(payload)
+}
+
+func (r *streamingReader) processEvent(payload []byte) {
+	var event struct {
+		Type    string `json:"type"`
+		Message struct {
+			ID    string       `json:"id"`
+			Model string       `json:"model"`
+			Usage messageUsage `json:"usage"`
+		} `json:"message"`
+		Delta struct {
+			StopReason string `json:"stop_reason"`
+		} `json:"delta"`
+		Usage messageUsage `json:"usage"`
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return
+	}
+
+	switch event.Type {
+	case "message_start":
+		r.id = event.Message.ID
+		r.model = event.Message.Model
+		r.usage = event.Message.Usage
+	case "content_block_delta":
+		if r.first.IsZero() {
+			r.first = time.Now()
+		}
+	case "message_delta":
+		if event.Delta.StopReason != "" {
+			r.stopReason = event.Delta.StopReason
+		}
+		r.mergeUsage(event.Usage)
+	case "error":
+		r.errType = event.Error.Type
+		r.errMessage = event.Error.Message
+	}
+	r.content.addEvent(payload)
+}
+
+// mergeUsage applies the usage of a message_delta event. Its counts are
+// cumulative, and fields the delta leaves out keep their message_start value.
+func (r *streamingReader) mergeUsage(u messageUsage) {
+	if u.InputTokens > 0 {
+		r.usage.InputTokens = u.InputTokens
+	}
+	if u.OutputTokens > 0 {
+		r.usage.OutputTokens = u.OutputTokens
+	}
+	if u.CacheReadInputTokens > 0 {
+		r.usage.CacheReadInputTokens = u.CacheReadInputTokens
+	}
+	if u.CacheCreationInputTokens > 0 {
+		r.usage.CacheCreationInputTokens = u.CacheCreationInputTokens
+	}
+}
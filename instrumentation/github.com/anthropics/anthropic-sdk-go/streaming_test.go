@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package anthropic
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// testStream is a Messages API event stream as sent by the server.
+const testStream = "event: message_start\n" +
+	`data: {"type":"message_start","message":{"id":"msg_stream_1","type":"message","role":"assistant","model":"claude-sonnet-4-5-20250929","content":[],"stop_reason":null,"usage":{"input_tokens":25,"cache_read_input_tokens":2,"cache_creation_input_tokens":0,"output_tokens":1}}}` + "\n\n" +
+	"event: content_block_start\n" +
+	`data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}` + "\n\n" +
+	"event: ping\n" +
+	`data: {"type":"ping"}` + "\n\n" +
+	"event: content_block_delta\n" +
+	`data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hello"}}` + "\n\n" +
+	"event: content_block_stop\n" +
+	`data: {"type":"content_block_stop","index":0}` + "\n\n" +
+	"event: message_delta\n" +
+	`data: {"type":"message_delta","delta":{"stop_reason":"end_turn","stop_sequence":null},"usage":{"output_tokens":15}}` + "\n\n" +
+	"event: message_stop\n" +
+	`data: {"type":"message_stop"}` + "\n\n"
+
+func startTestSpan(t *testing.T) (*tracetest.SpanRecorder, trace.Span) {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	t.Cleanup(func() { _ = tp.Shutdown(t.Context()) })
+	_, span := tp.Tracer("test").Start(t.Context(), "test-stream")
+	return sr, span
+}
+
+func TestStreamingReader_Events(t *testing.T) {
+	sr, span := startTestSpan(t)
+
+	reader := newStreamingReader(t.Context(), io.NopCloser(strings.NewReader(testStream)), span, time.Now(), nil, nil)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, testStream, string(data))
+	require.NoError(t, reader.Close())
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+
+	attrs := spans[0].Attributes()
+	assertAttribute(t, attrs, "gen_ai.response.id", "msg_stream_1")
+	assertAttribute(t, attrs, "gen_ai.response.model", "claude-sonnet-4-5-20250929")
+	assertStringSliceAttribute(t, attrs, "gen_ai.response.finish_reasons", []string{"end_turn"})
+	// input_tokens folds in the cache reads reported by message_start.
+	assertInt64Attribute(t, attrs, "gen_ai.usage.input_tokens", 27)
+	// message_delta carries the cumulative output count.
+	assertInt64Attribute(t, attrs, "gen_ai.usage.output_tokens", 15)
+	assertInt64Attribute(t, attrs, "gen_ai.usage.total_tokens", 42)
+	assertInt64Attribute(t, attrs, "gen_ai.usage.cache_read.input_tokens", 2)
+	_, found := findAttribute(attrs, "gen_ai.response.time_to_first_token")
+	assert.True(t, found)
+	assert.NotEqual(t, codes.Error, spans[0].Status().Code)
+}
+
+// TestStreamingReader_IncrementalRead splits every event across reads.
+func TestStreamingReader_IncrementalRead(t *testing.T) {
+	sr, span := startTestSpan(t)
+
+	body := io.NopCloser(iotest.OneByteReader(strings.NewReader(testStream)))
+	reader := newStreamingReader(t.Context(), body, span, time.Now(), nil, nil)
+	_, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1, "reaching EOF ends the span")
+	attrs := spans[0].Attributes()
+	assertAttribute(t, attrs, "gen_ai.response.id", "msg_stream_1")
+	assertInt64Attribute(t, attrs, "gen_ai.usage.output_tokens", 15)
+}
+
+func TestStreamingReader_NoTrailingNewline(t *testing.T) {
+	sr, span := startTestSpan(t)
+
+	stream := strings.TrimSuffix(testStream, "event: message_stop\n"+`data: {"type":"message_stop"}`+"\n\n")
+	stream = strings.TrimSuffix(stream, "\n\n")
+	reader := newStreamingReader(t.Context(), io.NopCloser(strings.NewReader(stream)), span, time.Now(), nil, nil)
+	_, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assertStringSliceAttribute(t, spans[0].Attributes(), "gen_ai.response.finish_reasons", []string{"end_turn"})
+}
+
+func TestStreamingReader_UsageInMessageDelta(t *testing.T) {
+	sr, span := startTestSpan(t)
+
+	stream := `data: {"type":"message_start","message":{"id":"msg_1","model":"claude-haiku-4-5","usage":{"input_tokens":3,"output_tokens":1}}}` + "\n\n" +
+		`data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"input_tokens":10,"cache_creation_input_tokens":4,"output_tokens":7}}` + "\n\n"
+	reader := newStreamingReader(t.Context(), io.NopCloser(strings.NewReader(stream)), span, time.Now(), nil, nil)
+	_, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+	assertStringSliceAttribute(t, attrs, "gen_ai.response.finish_reasons", []string{"tool_use"})
+	assertInt64Attribute(t, attrs, "gen_ai.usage.input_tokens", 14)
+	assertInt64Attribute(t, attrs, "gen_ai.usage.output_tokens", 7)
+	assertInt64Attribute(t, attrs, "gen_ai.usage.cache_creation.input_tokens", 4)
+	_, found := findAttribute(attrs, "gen_ai.response.time_to_first_token")
+	assert.False(t, found, "no content was streamed")
+}
+
+func TestStreamingReader_ErrorEvent(t *testing.T) {
+	sr, span := startTestSpan(t)
+
+	stream := "event: error\n" +
+		`data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}` + "\n\n"
+	reader := newStreamingReader(t.Context(), io.NopCloser(strings.NewReader(stream)), span, time.Now(), nil, nil)
+	_, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "Overloaded", spans[0].Status().Description)
+	attrs := spans[0].Attributes()
+	assertAttribute(t, attrs, "error.type", "overloaded_error")
+	_, found := findAttribute(attrs, "gen_ai.usage.input_tokens")
+	assert.False(t, found, "no usage is reported without message_start")
+}
+
+func TestStreamingReader_CloseBeforeRead(t *testing.T) {
+	sr, span := startTestSpan(t)
+
+	reader := newStreamingReader(t.Context(), io.NopCloser(strings.NewReader(testStream)), span, time.Now(), nil, nil)
+	require.NoError(t, reader.Close())
+	require.NoError(t, reader.Close())
+
+	require.Len(t, sr.Ended(), 1, "the span ends exactly once")
+}
+
+func TestStreamingReader_ReadErrorEndsSpan(t *testing.T) {
+	sr, span := startTestSpan(t)
+
+	partial := io.MultiReader(strings.NewReader(testStream[:200]), iotest.ErrReader(io.ErrUnexpectedEOF))
+	reader := newStreamingReader(t.Context(), io.NopCloser(partial), span, time.Now(), nil, nil)
+	_, err := io.ReadAll(reader)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.NoError(t, reader.Close())
+
+	require.Len(t, sr.Ended(), 1)
+}
+
+func TestParseSSELine(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`data: {"type":"ping"}`, `{"type":"ping"}`},
+		{`data:{"type":"ping"}`, `{"type":"ping"}`},
+		{"event: ping", ""},
+		{": comment", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, string(parseSSELine([]byte(tt.line))), tt.line)
+	}
+}
+
+func TestStreamingReader_ErrorEventMetrics(t *testing.T) {
+	_, span := startTestSpan(t)
+	reader := setupTestMeter(t)
+
+	stream := "event: error\n" +
+		`data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}` + "\n\n"
+	r := newStreamingReader(t.Context(), io.NopCloser(strings.NewReader(stream)), span, time.Now(), nil,
+		metricAttributes("chat", "anthropic", "claude-sonnet-4-5"))
+	_, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	assert.Nil(t, tokenUsage(t, reader), "a stream without message_start reports no token usage")
+	dp := operationDuration(t, reader)
+	assertMetricAttribute(t, dp.Attributes, "error.type", "overloaded_error")
+	assertMetricAttribute(t, dp.Attributes, "gen_ai.request.model", "claude-sonnet-4-5")
+}
@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package awssdk
+
+import (
+	"context"
+	"maps"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// maxMessageAttributes is the number of message attributes SQS accepts on a
+// message, and SNS delivers to SQS subscriptions. The trace context is not
+// injected into a message it would push over the limit.
+const maxMessageAttributes = 10
+
+// stringAttributeType is the data type of the injected message attributes.
+const stringAttributeType = "String"
+
+// messageAttributes returns the trace context of ctx as string message
+// attributes, leaving out the keys already present in existing. It returns
+// nil when there is nothing to inject or when the attributes would not fit.
+func messageAttributes(ctx context.Context, existing []string) map[string]string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	for _, key := range existing {
+		delete(carrier, key)
+	}
+	if len(carrier) == 0 {
+		return nil
+	}
+	if len(existing)+len(carrier) > maxMessageAttributes {
+		logger.Debug("message has too many attributes to propagate the trace context",
+			"attributes", len(existing))
+		return nil
+	}
+	return carrier
+}
+
+// injectSQS returns a copy of attrs carrying the trace context of ctx, or
+// attrs itself when nothing is injected. The map of the caller is never
+// modified: it is commonly shared by every message sent.
+func injectSQS(
+	ctx context.Context,
+	attrs map[string]sqstypes.MessageAttributeValue,
+) map[string]sqstypes.MessageAttributeValue {
+	injected := messageAttributes(ctx, keys(attrs))
+	if injected == nil {
+		return attrs
+	}
+	out := make(map[string]sqstypes.MessageAttributeValue, len(attrs)+len(injected))
+	maps.Copy(out, attrs)
+	for key, value := range injected {
+		out[key] = sqstypes.MessageAttributeValue{
+			DataType:    aws.String(stringAttributeType),
+			StringValue: aws.String(value),
+		}
+	}
+	return out
+}
+
+// injectSNS is the SNS counterpart of injectSQS.
+func injectSNS(
+	ctx context.Context,
+	attrs map[string]snstypes.MessageAttributeValue,
+) map[string]snstypes.MessageAttributeValue {
+	injected := messageAttributes(ctx, keys(attrs))
+	if injected == nil {
+		return attrs
+	}
+	out := make(map[string]snstypes.MessageAttributeValue, len(attrs)+len(injected))
+	maps.Copy(out, attrs)
+	for key, value := range injected {
+		out[key] = snstypes.MessageAttributeValue{
+			DataType:    aws.String(stringAttributeType),
+			StringValue: aws.String(value),
+		}
+	}
+	return out
+}
+
+func keys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for key := range m {
+		out = append(out, key)
+	}
+	return out
+}
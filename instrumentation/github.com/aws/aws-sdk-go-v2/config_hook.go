@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package awssdk instruments the AWS SDK for Go v2. Every aws.Config returned
+// by config.LoadDefaultConfig carries the otelaws middleware, so each client
+// created from it records a span per API call, and the messages published to
+// SQS and SNS carry the trace context in their message attributes.
+package awssdk
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+const (
+	instrumentationKey = "AWS"
+
+	// otelawsMiddlewareID is the ID of the otelaws middleware starting the
+	// span of an API call.
+	otelawsMiddlewareID = "OTelInitializeMiddlewareAfter"
+	// otelawsDeserializeMiddlewareID is the ID of the otelaws middleware
+	// setting the response attributes on the span of an API call.
+	otelawsDeserializeMiddlewareID = "OTelDeserializeMiddleware"
+	// messageAttributesMiddlewareID is the ID of the middleware injecting the
+	// trace context into SQS and SNS messages.
+	messageAttributesMiddlewareID = "otelc.MessageAttributes"
+)
+
+// awsEnablerImpl controls whether the AWS SDK instrumentation is enabled.
+type awsEnablerImpl struct{}
+
+func (awsEnablerImpl) Enable() bool {
+	return runtime.Instrumented(instrumentationKey)
+}
+
+var awsEnabler = awsEnablerImpl{}
+
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, awsEnabler)
+}
+
+var (
+	logger          = runtime.Logger()
+	propagator      propagation.TextMapPropagator
+	otelMiddlewares []func(*middleware.Stack) error
+	initOnce        sync.Once
+)
+
+func initInstrumentation() {
+	initOnce.Do(func() {
+		propagator = otel.GetTextMapPropagator()
+		// The default attribute builder adds the DynamoDB, SQS and SNS
+		// attributes to the spans of these services.
+		otelaws.AppendMiddlewares(&otelMiddlewares)
+		logger.Info("AWS SDK instrumentation initialized")
+	})
+}
+
+// AfterLoadDefaultConfig hooks after config.LoadDefaultConfig to add the
+// OpenTelemetry middleware to the API options of the returned config.
+func AfterLoadDefaultConfig(ictx hook.HookContext, cfg aws.Config, err error) {
+	if err != nil || !awsEnabler.Enable() {
+		return
+	}
+	initInstrumentation()
+
+	apiOptions := make([]func(*middleware.Stack) error, 0, len(cfg.APIOptions)+1)
+	apiOptions = append(apiOptions, cfg.APIOptions...)
+	cfg.APIOptions = append(apiOptions, instrumentStack)
+	ictx.SetReturnVal(0, cfg)
+}
+
+// BeforeAppendMiddlewares hooks before otelaws.AppendMiddlewares. The
+// application instruments these API options itself, so the middleware added
+// by AfterLoadDefaultConfig is removed to avoid recording every call twice.
+func BeforeAppendMiddlewares(_ hook.HookContext, apiOptions *[]func(*middleware.Stack) error, _ ...otelaws.Option) {
+	if apiOptions == nil {
+		return
+	}
+	own := reflect.ValueOf(instrumentStack).Pointer()
+	kept := make([]func(*middleware.Stack) error, 0, len(*apiOptions))
+	for _, fn := range *apiOptions {
+		if fn != nil && reflect.ValueOf(fn).Pointer() == own {
+			logger.Debug("otelaws middleware added by the application, skipping otelc middleware")
+			continue
+		}
+		kept = append(kept, fn)
+	}
+	*apiOptions = kept
+}
+
+// instrumentStack adds the otelaws middleware and the message attributes
+// middleware to the stack of an API call, unless the application already
+// added the otelaws middleware through the API options of the config.
+func instrumentStack(stack *middleware.Stack) error {
+	if _, ok := stack.Initialize.Get(otelawsMiddlewareID); ok {
+		return nil
+	}
+	for _, fn := range otelMiddlewares {
+		if err := fn(stack); err != nil {
+			return err
+		}
+	}
+	if err := makeSuppressible(stack); err != nil {
+		return err
+	}
+	// Added after the otelaws middleware, so that the injected trace context
+	// is the one of the span of the API call.
+	return stack.Initialize.Add(
+		middleware.InitializeMiddlewareFunc(messageAttributesMiddlewareID, injectMessageAttributes),
+		middleware.After,
+	)
+}
+
+// makeSuppressible wraps the otelaws middlewares recording the span of an API
+// call, so that API calls made with a context suppressing the instrumentation
+// are not recorded.
+func makeSuppressible(stack *middleware.Stack) error {
+	if m, ok := stack.Initialize.Get(otelawsMiddlewareID); ok {
+		if _, err := stack.Initialize.Swap(otelawsMiddlewareID, suppressibleInitialize{m}); err != nil {
+			return err
+		}
+	}
+	if m, ok := stack.Deserialize.Get(otelawsDeserializeMiddlewareID); ok {
+		if _, err := stack.Deserialize.Swap(otelawsDeserializeMiddlewareID, suppressibleDeserialize{m}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func suppressed(ctx context.Context) bool {
+	return runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient)
+}
+
+// suppressibleInitialize skips the wrapped middleware for suppressed contexts.
+type suppressibleInitialize struct {
+	middleware.InitializeMiddleware
+}
+
+func (m suppressibleInitialize) HandleInitialize(
+	ctx context.Context,
+	in middleware.InitializeInput,
+	next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+	if suppressed(ctx) {
+		return next.HandleInitialize(ctx, in)
+	}
+	return m.InitializeMiddleware.HandleInitialize(ctx, in, next)
+}
+
+// suppressibleDeserialize skips the wrapped middleware for suppressed
+// contexts, which would otherwise set the response attributes on the span of
+// the caller.
+type suppressibleDeserialize struct {
+	middleware.DeserializeMiddleware
+}
+
+func (m suppressibleDeserialize) HandleDeserialize(
+	ctx context.Context,
+	in middleware.DeserializeInput,
+	next middleware.DeserializeHandler,
+) (middleware.DeserializeOutput, middleware.Metadata, error) {
+	if suppressed(ctx) {
+		return next.HandleDeserialize(ctx, in)
+	}
+	return m.DeserializeMiddleware.HandleDeserialize(ctx, in, next)
+}
+
+// injectMessageAttributes propagates the trace context through the message
+// attributes of the SQS and SNS messages sent. The input of the application is
+// copied, never modified.
+func injectMessageAttributes(
+	ctx context.Context,
+	in middleware.InitializeInput,
+	next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+	switch params := in.Parameters.(type) {
+	case *sqs.SendMessageInput:
+		input := *params
+		input.MessageAttributes = injectSQS(ctx, params.MessageAttributes)
+		in.Parameters = &input
+	case *sqs.SendMessageBatchInput:
+		input := *params
+		input.Entries = append(input.Entries[:0:0], params.Entries...)
+		for i := range input.Entries {
+			input.Entries[i].MessageAttributes = injectSQS(ctx, input.Entries[i].MessageAttributes)
+		}
+		in.Parameters = &input
+	case *sns.PublishInput:
+		input := *params
+		input.MessageAttributes = injectSNS(ctx, params.MessageAttributes)
+		in.Parameters = &input
+	case *sns.PublishBatchInput:
+		input := *params
+		input.PublishBatchRequestEntries = append(input.PublishBatchRequestEntries[:0:0],
+			params.PublishBatchRequestEntries...)
+		for i := range input.PublishBatchRequestEntries {
+			entry := &input.PublishBatchRequestEntries[i]
+			entry.MessageAttributes = injectSNS(ctx, entry.MessageAttributes)
+		}
+		in.Parameters = &input
+	}
+	return next.HandleInitialize(ctx, in)
+}
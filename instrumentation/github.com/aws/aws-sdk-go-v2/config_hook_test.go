@@ -0,0 +1,294 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package awssdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+// fakeAWS is a local HTTP stand-in for the SQS, SNS and DynamoDB endpoints. It
+// records the bodies of the requests it receives.
+type fakeAWS struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	bodies []string
+}
+
+func newFakeAWS(t *testing.T) *fakeAWS {
+	f := &fakeAWS{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		f.mu.Lock()
+		f.bodies = append(f.bodies, string(body))
+		f.mu.Unlock()
+
+		w.Header().Set("X-Amzn-RequestId", "req-1")
+		switch target := r.Header.Get("X-Amz-Target"); {
+		case strings.HasPrefix(target, "AmazonSQS."):
+			w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+			_, _ = io.WriteString(w, `{"MessageId":"msg-1"}`)
+		case strings.HasPrefix(target, "DynamoDB_"):
+			w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+			_, _ = io.WriteString(w, `{}`)
+		default: // SNS speaks the query protocol
+			w.Header().Set("Content-Type", "text/xml")
+			_, _ = io.WriteString(w, `<PublishResponse><PublishResult><MessageId>msg-1</MessageId></PublishResult></PublishResponse>`)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeAWS) lastBody(t *testing.T) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	require.NotEmpty(t, f.bodies)
+	return f.bodies[len(f.bodies)-1]
+}
+
+func setupTracing(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "aws")
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	oldTP, oldPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	// The otelaws middleware keeps the tracer provider it was created with
+	otelMiddlewares = nil
+	initOnce = sync.Once{}
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		otel.SetTracerProvider(oldTP)
+		otel.SetTextMapPropagator(oldPropagator)
+	})
+	return exporter
+}
+
+// loadConfig calls config.LoadDefaultConfig against the fake endpoints and
+// runs AfterLoadDefaultConfig as the instrumented function would.
+func loadConfig(t *testing.T, endpoint string, optFns ...func(*config.LoadOptions) error) aws.Config {
+	t.Helper()
+	optFns = append([]func(*config.LoadOptions) error{
+		config.WithRegion("us-east-1"),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("key", "secret", "")),
+		config.WithBaseEndpoint(endpoint),
+	}, optFns...)
+	cfg, err := config.LoadDefaultConfig(t.Context(), optFns...)
+	require.NoError(t, err)
+
+	ictx := hooktest.NewMockHookContext()
+	ictx.ReturnVals = []interface{}{cfg, nil}
+	AfterLoadDefaultConfig(ictx, cfg, nil)
+	cfg, ok := ictx.GetReturnVal(0).(aws.Config)
+	require.True(t, ok)
+	return cfg
+}
+
+func newSQSClient(cfg aws.Config) *sqs.Client {
+	return sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+		// The fake endpoint does not compute message checksums
+		o.DisableMessageChecksumValidation = true
+	})
+}
+
+func TestAfterLoadDefaultConfig_SQSSendMessage(t *testing.T) {
+	exporter := setupTracing(t)
+	fake := newFakeAWS(t)
+	cfg := loadConfig(t, fake.URL)
+
+	attrs := map[string]sqstypes.MessageAttributeValue{
+		"tenant": {DataType: aws.String("String"), StringValue: aws.String("acme")},
+	}
+	_, err := newSQSClient(cfg).SendMessage(t.Context(), &sqs.SendMessageInput{
+		QueueUrl:          aws.String(fake.URL + "/123456789012/orders"),
+		MessageBody:       aws.String("hello"),
+		MessageAttributes: attrs,
+	})
+	require.NoError(t, err)
+	assert.Len(t, attrs, 1, "the attributes of the application must not be modified")
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "SQS.SendMessage", span.Name)
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+	assert.Contains(t, span.Attributes, attribute.String("messaging.system", "aws_sqs"))
+
+	var body struct {
+		MessageAttributes map[string]struct{ DataType, StringValue string }
+	}
+	require.NoError(t, json.Unmarshal([]byte(fake.lastBody(t)), &body))
+	assert.Equal(t, "acme", body.MessageAttributes["tenant"].StringValue)
+	traceparent := body.MessageAttributes["traceparent"]
+	assert.Equal(t, "String", traceparent.DataType)
+	assert.Contains(t, traceparent.StringValue, span.SpanContext.TraceID().String())
+	assert.Contains(t, traceparent.StringValue, span.SpanContext.SpanID().String())
+}
+
+func TestAfterLoadDefaultConfig_SQSSendMessageBatch(t *testing.T) {
+	setupTracing(t)
+	fake := newFakeAWS(t)
+	cfg := loadConfig(t, fake.URL)
+
+	// An entry with no room left for the trace context is sent as is
+	full := make(map[string]sqstypes.MessageAttributeValue, maxMessageAttributes)
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		full[k] = sqstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(k)}
+	}
+	_, err := newSQSClient(cfg).SendMessageBatch(t.Context(), &sqs.SendMessageBatchInput{
+		QueueUrl: aws.String(fake.URL + "/123456789012/orders"),
+		Entries: []sqstypes.SendMessageBatchRequestEntry{
+			{Id: aws.String("1"), MessageBody: aws.String("one")},
+			{Id: aws.String("2"), MessageBody: aws.String("two"), MessageAttributes: full},
+		},
+	})
+	require.NoError(t, err)
+
+	var body struct {
+		Entries []struct {
+			MessageAttributes map[string]struct{ StringValue string }
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(fake.lastBody(t)), &body))
+	require.Len(t, body.Entries, 2)
+	assert.Contains(t, body.Entries[0].MessageAttributes, "traceparent")
+	assert.Len(t, body.Entries[1].MessageAttributes, maxMessageAttributes)
+	assert.NotContains(t, body.Entries[1].MessageAttributes, "traceparent")
+}
+
+func TestAfterLoadDefaultConfig_SNSPublish(t *testing.T) {
+	exporter := setupTracing(t)
+	fake := newFakeAWS(t)
+	cfg := loadConfig(t, fake.URL)
+
+	_, err := sns.NewFromConfig(cfg).Publish(t.Context(), &sns.PublishInput{
+		TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:orders"),
+		Message:  aws.String("hello"),
+	})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "SNS.Publish", spans[0].Name)
+	assert.Contains(t, spans[0].Attributes, attribute.String("messaging.system", "aws_sns"))
+	assert.Contains(t, spans[0].Attributes, attribute.String("messaging.destination.name", "orders"))
+
+	form, err := url.ParseQuery(fake.lastBody(t))
+	require.NoError(t, err)
+	assert.Equal(t, "traceparent", form.Get("MessageAttributes.entry.1.Name"))
+	assert.Contains(t, form.Get("MessageAttributes.entry.1.Value.StringValue"),
+		spans[0].SpanContext.SpanID().String())
+}
+
+func TestAfterLoadDefaultConfig_DynamoDB(t *testing.T) {
+	exporter := setupTracing(t)
+	fake := newFakeAWS(t)
+	cfg := loadConfig(t, fake.URL)
+
+	_, err := dynamodb.NewFromConfig(cfg).PutItem(t.Context(), &dynamodb.PutItemInput{
+		TableName: aws.String("orders"),
+		Item:      map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "1"}},
+	})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "DynamoDB.PutItem", spans[0].Name)
+	assert.Contains(t, spans[0].Attributes, attribute.String("db.system.name", "aws.dynamodb"))
+	assert.Contains(t, spans[0].Attributes, attribute.StringSlice("aws.dynamodb.table_names", []string{"orders"}))
+	assert.Contains(t, spans[0].Attributes, attribute.String("aws.request_id", "req-1"))
+}
+
+func TestAfterLoadDefaultConfig_ApplicationOtelaws(t *testing.T) {
+	exporter := setupTracing(t)
+	fake := newFakeAWS(t)
+	queueURL := aws.String(fake.URL + "/123456789012/orders")
+
+	// otelaws added through the config options: the otelc middleware steps aside
+	var apiOptions []func(*middleware.Stack) error
+	otelaws.AppendMiddlewares(&apiOptions)
+	cfg := loadConfig(t, fake.URL, config.WithAPIOptions(apiOptions))
+	_, err := newSQSClient(cfg).SendMessage(t.Context(), &sqs.SendMessageInput{QueueUrl: queueURL, MessageBody: aws.String("a")})
+	require.NoError(t, err)
+	assert.Len(t, exporter.GetSpans(), 1)
+	exporter.Reset()
+
+	// otelaws added to the returned config: the otelc middleware is removed
+	cfg = loadConfig(t, fake.URL)
+	BeforeAppendMiddlewares(hooktest.NewMockHookContext(), &cfg.APIOptions)
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
+	_, err = newSQSClient(cfg).SendMessage(t.Context(), &sqs.SendMessageInput{QueueUrl: queueURL, MessageBody: aws.String("b")})
+	require.NoError(t, err)
+	assert.Len(t, exporter.GetSpans(), 1)
+	assert.NotContains(t, fake.lastBody(t), "traceparent")
+}
+
+func TestAfterLoadDefaultConfig_Suppressed(t *testing.T) {
+	exporter := setupTracing(t)
+	fake := newFakeAWS(t)
+	cfg := loadConfig(t, fake.URL)
+
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, parent := tp.Tracer("test").Start(t.Context(), "parent")
+	ctx = runtime.Suppress(ctx, "AWS")
+	_, err := newSQSClient(cfg).SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(fake.URL + "/123456789012/orders"),
+		MessageBody: aws.String("hello"),
+	})
+	require.NoError(t, err)
+	parent.End()
+
+	assert.Empty(t, exporter.GetSpans())
+	ro, ok := parent.(sdktrace.ReadOnlySpan)
+	require.True(t, ok)
+	for _, kv := range ro.Attributes() {
+		assert.NotEqual(t, "http.response.status_code", string(kv.Key),
+			"the response attributes must not be set on the caller's span")
+	}
+}
+
+func TestAfterLoadDefaultConfig_Disabled(t *testing.T) {
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "aws")
+
+	cfg, err := config.LoadDefaultConfig(t.Context(), config.WithRegion("us-east-1"))
+	require.NoError(t, err)
+	ictx := hooktest.NewMockHookContext()
+	ictx.ReturnVals = []interface{}{cfg, nil}
+	AfterLoadDefaultConfig(ictx, cfg, nil)
+	assert.Len(t, ictx.GetReturnVal(0).(aws.Config).APIOptions, len(cfg.APIOptions))
+}
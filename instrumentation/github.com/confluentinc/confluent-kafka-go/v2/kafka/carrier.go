@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// messageCarrier adapts the headers of a kafka.Message to the OpenTelemetry
+// TextMapCarrier interface so trace context can be propagated through Kafka
+// message headers.
+type messageCarrier struct {
+	msg *kafka.Message
+}
+
+// Get returns the value of the first header matching key, or "" if absent.
+func (c messageCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces any existing header with key, otherwise appends a new one.
+//
+// The headers are copied before being modified: callers commonly build one
+// header slice and reuse it for several messages.
+func (c messageCarrier) Set(key, value string) {
+	headers := make([]kafka.Header, 0, len(c.msg.Headers)+1)
+	replaced := false
+	for _, h := range c.msg.Headers {
+		if h.Key == key {
+			h.Value = []byte(value)
+			replaced = true
+		}
+		headers = append(headers, h)
+	}
+	if !replaced {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+	c.msg.Headers = headers
+}
+
+// Keys lists the header keys carried by this carrier.
+func (c messageCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"context"
+	"sync"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/github.com/segmentio/kafka-go/semconv"
+	"go.opentelemetry.io/otelc/pkg/hook"
+)
+
+// groupIDs maps a *kafka.Consumer to the group.id it was configured with,
+// which the consumer does not expose. Entries are removed when the consumer is
+// closed.
+var groupIDs sync.Map
+
+// -----------------------------------------------------------------------------
+// Consumer: NewConsumer(conf) and (*kafka.Consumer).Close()
+// -----------------------------------------------------------------------------
+
+// BeforeNewConsumer remembers the group ID for AfterNewConsumer.
+func BeforeNewConsumer(ictx hook.HookContext, conf *kafka.ConfigMap) {
+	if conf == nil {
+		return
+	}
+	if groupID, err := conf.Get("group.id", ""); err == nil {
+		ictx.SetData(groupID)
+	}
+}
+
+// AfterNewConsumer associates the new consumer with its group ID.
+func AfterNewConsumer(ictx hook.HookContext, c *kafka.Consumer, err error) {
+	groupID, ok := ictx.GetData().(string)
+	if !ok || groupID == "" || err != nil || c == nil {
+		return
+	}
+	groupIDs.Store(c, groupID)
+}
+
+// BeforeConsumerClose forgets the group ID of a closed consumer.
+func BeforeConsumerClose(_ hook.HookContext, c *kafka.Consumer) {
+	groupIDs.Delete(c)
+}
+
+// -----------------------------------------------------------------------------
+// Consumer: (*kafka.Consumer).Poll(timeoutMs)
+//
+// ReadMessage polls too, so both entry points are covered. The deprecated
+// channel-based consumer (go.events.channel.enable) reads events without
+// Poll and is not instrumented.
+// -----------------------------------------------------------------------------
+
+// BeforePoll remembers the consumer for AfterPoll.
+func BeforePoll(ictx hook.HookContext, c *kafka.Consumer, _ int) {
+	if !confluentEnabler.Enable() {
+		logger.Debug("confluent-kafka-go consumer instrumentation disabled")
+		return
+	}
+	if c == nil {
+		return
+	}
+	ictx.SetData(c)
+}
+
+// AfterPoll emits a receive span for a polled message and replaces the trace
+// context in its headers with the span's, so ExtractContext continues the
+// trace below the receive span.
+//
+// The span is parented on and links to the producer context carried in the
+// message headers.
+func AfterPoll(ictx hook.HookContext, ev kafka.Event) {
+	c, ok := ictx.GetData().(*kafka.Consumer)
+	if !ok {
+		return
+	}
+	msg, ok := ev.(*kafka.Message)
+	if !ok || msg == nil {
+		return
+	}
+	initInstrumentation()
+
+	carrier := messageCarrier{msg: msg}
+	parent := context.Background()
+	var opts []trace.SpanStartOption
+	producerCtx := propagator.Extract(context.Background(), carrier)
+	if sc := trace.SpanContextFromContext(producerCtx); sc.IsValid() {
+		parent = trace.ContextWithRemoteSpanContext(parent, sc)
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+
+	topic := topicOf(msg)
+	groupID, _ := groupIDs.Load(c)
+	req := semconv.KafkaRequest{
+		Destination:     topic,
+		Operation:       semconv.KafkaOperationReceive,
+		MessageKey:      string(msg.Key),
+		MessageBodySize: len(msg.Value),
+		Partition:       int(msg.TopicPartition.Partition),
+		Offset:          int64(msg.TopicPartition.Offset),
+		HasPartition:    true,
+		HasOffset:       msg.TopicPartition.Offset >= 0,
+	}
+	req.ConsumerGroupID, _ = groupID.(string)
+	opts = append(opts,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(semconv.KafkaRequestTraceAttrs(req)...),
+	)
+	spanCtx, span := tracer.Start(parent, spanName(topic, semconv.KafkaOperationReceive), opts...)
+	if err := msg.TopicPartition.Error; err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	propagator.Inject(spanCtx, carrier)
+	span.End()
+}
+
+// ExtractContext extracts the trace context from a consumed message's headers
+// and returns a context.Context that carries the propagated span context.
+//
+// For messages read with an instrumented Poll or ReadMessage, the headers
+// carry the receive span, so spans created with the returned context are its
+// children:
+//
+//	msg, err := consumer.ReadMessage(-1)
+//	ctx := kafka.ExtractContext(msg)
+//	// spans created with ctx will be children of the receive span.
+func ExtractContext(msg *kafka.Message) context.Context {
+	initInstrumentation()
+	if msg == nil {
+		return context.Background()
+	}
+	return propagator.Extract(context.Background(), messageCarrier{msg: msg})
+}
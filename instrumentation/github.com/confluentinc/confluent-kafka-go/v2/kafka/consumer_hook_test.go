@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+)
+
+// newConsumer fires the NewConsumer hooks around a real call.
+func newConsumer(t *testing.T, conf *kafka.ConfigMap) *kafka.Consumer {
+	t.Helper()
+	ictx := hooktest.NewMockHookContext(conf)
+	BeforeNewConsumer(ictx, conf)
+	c, err := kafka.NewConsumer(conf)
+	AfterNewConsumer(ictx, c, err)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		BeforeConsumerClose(hooktest.NewMockHookContext(c), c)
+		_ = c.Close()
+	})
+	return c
+}
+
+// poll fires the Poll hooks around a real call.
+func poll(c *kafka.Consumer, timeoutMs int) kafka.Event {
+	ictx := hooktest.NewMockHookContext(c, timeoutMs)
+	BeforePoll(ictx, c, timeoutMs)
+	ev := c.Poll(timeoutMs)
+	AfterPoll(ictx, ev)
+	return ev
+}
+
+func TestPoll_ReceiveSpanFromProducerHeaders(t *testing.T) {
+	sr := setupTest(t)
+	cluster := newCluster(t, "orders")
+
+	p, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": cluster.BootstrapServers()})
+	require.NoError(t, err)
+	t.Cleanup(p.Close)
+
+	producer := remoteSpanContext(t, "0102030405060708090a0b0c0d0e0f10", "0102030405060708")
+	topic := "orders"
+	sent := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            []byte("order-1"),
+		Value:          []byte("hello"),
+	}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), producer), messageCarrier{msg: sent})
+	deliveryChan := make(chan kafka.Event, 1)
+	require.NoError(t, p.Produce(sent, deliveryChan))
+	<-deliveryChan
+
+	c := newConsumer(t, &kafka.ConfigMap{
+		"bootstrap.servers": cluster.BootstrapServers(),
+		"group.id":          "workers",
+		"auto.offset.reset": "earliest",
+	})
+	require.NoError(t, c.Subscribe(topic, nil))
+
+	var msg *kafka.Message
+	deadline := time.Now().Add(30 * time.Second)
+	for msg == nil && time.Now().Before(deadline) {
+		msg, _ = poll(c, 100).(*kafka.Message)
+	}
+	require.NotNil(t, msg, "no message consumed")
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "orders receive", span.Name())
+	assert.Equal(t, trace.SpanKindConsumer, span.SpanKind())
+	assert.Equal(t, producer.TraceID(), span.Parent().TraceID())
+	assert.Equal(t, producer.SpanID(), span.Parent().SpanID())
+	require.Len(t, span.Links(), 1)
+	assert.Equal(t, producer.SpanID(), span.Links()[0].SpanContext.SpanID())
+
+	m := spanAttrs(span)
+	assert.Equal(t, "receive", m["messaging.operation.name"])
+	assert.Equal(t, "orders", m["messaging.destination.name"])
+	assert.Equal(t, "workers", m["messaging.consumer.group.name"])
+	assert.Equal(t, "order-1", m["messaging.kafka.message.key"])
+	assert.Equal(t, "0", m["messaging.destination.partition.id"])
+	assert.Equal(t, int64(0), m["messaging.kafka.offset"])
+
+	// The headers now carry the receive span for ExtractContext.
+	sc := trace.SpanContextFromContext(ExtractContext(msg))
+	assert.Equal(t, span.SpanContext().SpanID(), sc.SpanID())
+}
+
+func TestPoll_PartitionError(t *testing.T) {
+	sr := setupTest(t)
+
+	topic := "orders"
+	msg := &kafka.Message{TopicPartition: kafka.TopicPartition{
+		Topic:     &topic,
+		Partition: 1,
+		Offset:    kafka.OffsetInvalid,
+		Error:     kafka.NewError(kafka.ErrUnknownTopicOrPart, "unknown topic", false),
+	}}
+	ictx := hooktest.NewMockHookContext()
+	ictx.SetData(&kafka.Consumer{})
+	AfterPoll(ictx, msg)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	_, hasOffset := spanAttrs(spans[0])["messaging.kafka.offset"]
+	assert.False(t, hasOffset)
+}
+
+func TestPoll_IgnoresOtherEventsAndDisabled(t *testing.T) {
+	sr := setupTest(t)
+
+	c := &kafka.Consumer{}
+	ictx := hooktest.NewMockHookContext(c, 0)
+	BeforePoll(ictx, c, 0)
+	AfterPoll(ictx, kafka.PartitionEOF{})
+	AfterPoll(ictx, nil)
+
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "confluent_kafka")
+	topic := "orders"
+	ictx = hooktest.NewMockHookContext(c, 0)
+	BeforePoll(ictx, c, 0)
+	AfterPoll(ictx, &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic}})
+
+	assert.Empty(t, sr.Ended())
+}
@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"context"
+	"sync"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/github.com/segmentio/kafka-go/semconv"
+	"go.opentelemetry.io/otelc/pkg/hook"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+const (
+	instrumentationName = "go.opentelemetry.io/otelc/instrumentation/github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	instrumentationKey  = "CONFLUENT_KAFKA"
+)
+
+// produceDeliveryChanParamIndex is the index of deliveryChan in
+// (*Producer).produce(msg, msgFlags, deliveryChan), after the receiver.
+const produceDeliveryChanParamIndex = 3
+
+// confluentEnablerImpl controls whether the confluent-kafka-go instrumentation
+// is enabled.
+type confluentEnablerImpl struct{}
+
+func (confluentEnablerImpl) Enable() bool {
+	return runtime.Instrumented(instrumentationKey)
+}
+
+var confluentEnabler = confluentEnablerImpl{}
+
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, confluentEnabler)
+	runtime.RegisterUnsuppressible(instrumentationKey, "Producer.Produce", "Consumer.Poll")
+}
+
+var (
+	logger     = runtime.Logger()
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	initOnce   sync.Once
+)
+
+func initInstrumentation() {
+	initOnce.Do(func() {
+		tracer = otel.GetTracerProvider().Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(runtime.ModuleVersion()),
+		)
+		propagator = otel.GetTextMapPropagator()
+		logger.Info("Kafka (confluent-kafka-go) instrumentation initialized")
+	})
+}
+
+// topicOf returns the topic of msg, or "" if it has none.
+func topicOf(msg *kafka.Message) string {
+	if msg.TopicPartition.Topic == nil {
+		return ""
+	}
+	return *msg.TopicPartition.Topic
+}
+
+// spanName returns "<topic> <operation>", or the operation alone when the
+// topic is unknown.
+func spanName(topic string, op semconv.KafkaOperation) string {
+	if topic == "" {
+		return string(op)
+	}
+	return topic + " " + string(op)
+}
+
+// -----------------------------------------------------------------------------
+// Producer: (*kafka.Producer).produce(msg, msgFlags, deliveryChan)
+//
+// Produce and the ProduceChannel goroutine both enqueue messages through
+// produce. librdkafka delivers asynchronously and reports the outcome as a new
+// *kafka.Message that cannot be matched to the produced one, so the producer
+// span can only wait for the delivery report when the caller passes a
+// delivery channel: the channel is then wrapped and the span ends when the
+// report arrives. Otherwise the span ends once the message is enqueued.
+// -----------------------------------------------------------------------------
+
+type produceData struct {
+	span trace.Span
+	req  semconv.KafkaRequest
+	// reports and deliveryChan are set when the delivery report is awaited:
+	// librdkafka sends it on reports, which is forwarded to deliveryChan.
+	reports      chan kafka.Event
+	deliveryChan chan kafka.Event
+}
+
+// BeforeProduce starts the producer span on the caller's goroutine, so it is
+// parented on the caller's active span, and injects its context into the
+// message headers.
+func BeforeProduce(
+	ictx hook.HookContext,
+	_ *kafka.Producer,
+	msg *kafka.Message,
+	_ int,
+	deliveryChan chan kafka.Event,
+) {
+	if !confluentEnabler.Enable() {
+		logger.Debug("confluent-kafka-go producer instrumentation disabled")
+		return
+	}
+	if msg == nil {
+		return
+	}
+	initInstrumentation()
+
+	topic := topicOf(msg)
+	req := semconv.KafkaRequest{
+		Destination:     topic,
+		Operation:       semconv.KafkaOperationSend,
+		MessageKey:      string(msg.Key),
+		MessageBodySize: len(msg.Value),
+	}
+	if msg.TopicPartition.Partition != kafka.PartitionAny {
+		req.Partition = int(msg.TopicPartition.Partition)
+		req.HasPartition = true
+	}
+	ctx, span := tracer.Start(context.Background(), spanName(topic, semconv.KafkaOperationSend),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.KafkaRequestTraceAttrs(req)...),
+	)
+	propagator.Inject(ctx, messageCarrier{msg: msg})
+
+	data := &produceData{span: span, req: req}
+	if deliveryChan != nil {
+		data.reports = make(chan kafka.Event, 1)
+		data.deliveryChan = deliveryChan
+		ictx.SetParam(produceDeliveryChanParamIndex, data.reports)
+	}
+	ictx.SetData(data)
+}
+
+// AfterProduce ends the producer span if the message could not be enqueued
+// or no delivery report is awaited. Otherwise it starts forwarding the
+// delivery report, which ends the span.
+func AfterProduce(ictx hook.HookContext, err error) {
+	data, ok := ictx.GetData().(*produceData)
+	if !ok || data == nil {
+		return
+	}
+	switch {
+	case err != nil:
+		// librdkafka never reports a message it refused to enqueue
+		data.end(err, nil)
+	case data.reports == nil:
+		data.end(nil, nil)
+	default:
+		go data.forward()
+	}
+}
+
+// forward waits for the delivery report of the message, ends the producer
+// span and hands the report to the caller's delivery channel.
+func (data *produceData) forward() {
+	ev := <-data.reports
+	if msg, ok := ev.(*kafka.Message); ok {
+		data.end(msg.TopicPartition.Error, msg)
+	} else {
+		data.end(nil, nil)
+	}
+	data.deliveryChan <- ev
+}
+
+// end ends the producer span, recording the partition and offset the broker
+// assigned to delivered.
+func (data *produceData) end(err error, delivered *kafka.Message) {
+	if err != nil {
+		data.span.RecordError(err)
+		data.span.SetStatus(codes.Error, err.Error())
+	} else if delivered != nil {
+		req := data.req
+		req.Partition = int(delivered.TopicPartition.Partition)
+		req.Offset = int64(delivered.TopicPartition.Offset)
+		req.HasPartition = true
+		req.HasOffset = true
+		data.span.SetAttributes(semconv.KafkaRequestTraceAttrs(req)...)
+	}
+	data.span.End()
+}
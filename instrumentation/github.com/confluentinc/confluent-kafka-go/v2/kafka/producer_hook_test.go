@@ -0,0 +1,213 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+)
+
+// setupTest wires the package-level tracer/propagator to an in-memory span
+// recorder, bypassing the real OTel SDK setup so hook behavior can be asserted
+// deterministically. It also enables the confluent-kafka-go instrumentation
+// for the test.
+func setupTest(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "confluent_kafka")
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	// Consume initOnce so initInstrumentation() becomes a no-op and does not
+	// overwrite the tracer/propagator we install below.
+	initOnce.Do(func() {})
+	tracer = tp.Tracer("test")
+	propagator = propagation.TraceContext{}
+
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		initOnce = sync.Once{}
+		tracer = nil
+		propagator = nil
+		groupIDs.Clear()
+	})
+	return sr
+}
+
+// newCluster starts librdkafka's in-process mock cluster with the topic
+// created.
+func newCluster(t *testing.T, topic string) *kafka.MockCluster {
+	t.Helper()
+	cluster, err := kafka.NewMockCluster(1)
+	require.NoError(t, err)
+	t.Cleanup(cluster.Close)
+	require.NoError(t, cluster.CreateTopic(topic, 1, 1))
+	return cluster
+}
+
+func spanAttrs(span sdktrace.ReadOnlySpan) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, a := range span.Attributes() {
+		m[string(a.Key)] = a.Value.AsInterface()
+	}
+	return m
+}
+
+func remoteSpanContext(t *testing.T, traceID, spanID string) trace.SpanContext {
+	t.Helper()
+	tid, err := trace.TraceIDFromHex(traceID)
+	require.NoError(t, err)
+	sid, err := trace.SpanIDFromHex(spanID)
+	require.NoError(t, err)
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+}
+
+// produce fires the producer hooks around a real Produce call, as an
+// instrumented build would.
+func produce(t *testing.T, p *kafka.Producer, msg *kafka.Message, deliveryChan chan kafka.Event) error {
+	t.Helper()
+	ictx := hooktest.NewMockHookContext(p, msg, 0, deliveryChan)
+	BeforeProduce(ictx, p, msg, 0, deliveryChan)
+	reports, _ := ictx.GetParam(produceDeliveryChanParamIndex).(chan kafka.Event)
+	err := p.Produce(msg, reports)
+	AfterProduce(ictx, err)
+	return err
+}
+
+func TestProduce_EndsOnDeliveryReport(t *testing.T) {
+	sr := setupTest(t)
+	cluster := newCluster(t, "orders")
+
+	p, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": cluster.BootstrapServers()})
+	require.NoError(t, err)
+	t.Cleanup(p.Close)
+
+	topic := "orders"
+	msg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            []byte("order-1"),
+		Value:          []byte("hello"),
+	}
+	deliveryChan := make(chan kafka.Event, 1)
+	require.NoError(t, produce(t, p, msg, deliveryChan))
+
+	var delivered *kafka.Message
+	select {
+	case ev := <-deliveryChan:
+		delivered = ev.(*kafka.Message)
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "no delivery report")
+	}
+	require.NoError(t, delivered.TopicPartition.Error)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "orders send", span.Name())
+	assert.Equal(t, trace.SpanKindProducer, span.SpanKind())
+
+	m := spanAttrs(span)
+	assert.Equal(t, "kafka", m["messaging.system"])
+	assert.Equal(t, "send", m["messaging.operation.name"])
+	assert.Equal(t, "orders", m["messaging.destination.name"])
+	assert.Equal(t, "order-1", m["messaging.kafka.message.key"])
+	assert.Equal(t, int64(5), m["messaging.message.body.size"])
+	assert.Equal(t, "0", m["messaging.destination.partition.id"])
+	assert.Equal(t, int64(delivered.TopicPartition.Offset), m["messaging.kafka.offset"])
+
+	// The producer span context travels in the message headers.
+	sc := trace.SpanContextFromContext(propagator.Extract(context.Background(), messageCarrier{msg: msg}))
+	assert.Equal(t, span.SpanContext().SpanID(), sc.SpanID())
+}
+
+func TestProduce_WithoutDeliveryChanEndsOnEnqueue(t *testing.T) {
+	sr := setupTest(t)
+
+	topic := "orders"
+	msg := &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: 2}}
+	ictx := hooktest.NewMockHookContext(nil, msg, 0, nil)
+	BeforeProduce(ictx, nil, msg, 0, nil)
+	assert.Nil(t, ictx.GetParam(produceDeliveryChanParamIndex))
+	AfterProduce(ictx, nil)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	m := spanAttrs(spans[0])
+	assert.Equal(t, "2", m["messaging.destination.partition.id"])
+	_, hasOffset := m["messaging.kafka.offset"]
+	assert.False(t, hasOffset)
+}
+
+func TestProduce_EnqueueErrorEndsSpan(t *testing.T) {
+	sr := setupTest(t)
+
+	topic := "orders"
+	msg := &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny}}
+	deliveryChan := make(chan kafka.Event)
+	ictx := hooktest.NewMockHookContext(nil, msg, 0, deliveryChan)
+	BeforeProduce(ictx, nil, msg, 0, deliveryChan)
+	AfterProduce(ictx, errors.New("queue full"))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "queue full", spans[0].Status().Description)
+}
+
+func TestProduce_Disabled(t *testing.T) {
+	sr := setupTest(t)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "confluent_kafka")
+
+	topic := "orders"
+	msg := &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic}}
+	deliveryChan := make(chan kafka.Event)
+	ictx := hooktest.NewMockHookContext(nil, msg, 0, deliveryChan)
+	BeforeProduce(ictx, nil, msg, 0, deliveryChan)
+	AfterProduce(ictx, nil)
+
+	assert.Empty(t, sr.Ended())
+	assert.Empty(t, msg.Headers)
+	assert.Equal(t, deliveryChan, ictx.GetParam(produceDeliveryChanParamIndex))
+}
+
+func TestMessageCarrier_DoesNotShareHeaders(t *testing.T) {
+	setupTest(t)
+
+	shared := make([]kafka.Header, 1, 4)
+	shared[0] = kafka.Header{Key: "tenant", Value: []byte("a")}
+	topic := "orders"
+	first := &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic}, Headers: shared}
+	second := &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic}, Headers: shared}
+
+	for _, msg := range []*kafka.Message{first, second} {
+		ictx := hooktest.NewMockHookContext(nil, msg, 0, nil)
+		BeforeProduce(ictx, nil, msg, 0, nil)
+		AfterProduce(ictx, nil)
+	}
+
+	firstSC := trace.SpanContextFromContext(propagator.Extract(context.Background(), messageCarrier{msg: first}))
+	secondSC := trace.SpanContextFromContext(propagator.Extract(context.Background(), messageCarrier{msg: second}))
+	assert.NotEqual(t, firstSC.SpanID(), secondSC.SpanID())
+	assert.Len(t, shared, 1)
+	assert.Equal(t, "tenant", first.Headers[0].Key)
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v8
+
+import (
+	redis "github.com/go-redis/redis/v8"
+
+	"go.opentelemetry.io/otelc/pkg/hook"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+const (
+	instrumentationName = "go.opentelemetry.io/otelc/instrumentation/github.com/go-redis/redis/v8"
+	instrumentationKey  = "REDIS"
+)
+
+// redisClientEnabler controls whether client instrumentation is enabled
+type redisClientEnabler struct{}
+
+func (g redisClientEnabler) Enable() bool {
+	return runtime.Instrumented(instrumentationKey)
+}
+
+var redisEnabler = redisClientEnabler{}
+
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, redisEnabler)
+}
+
+// instrumentClient adds the tracing hook to client and reports the metrics of
+// its connection pool. Ring and cluster clients need no hook of their own:
+// they create a client per shard or node through NewClient.
+func instrumentClient(client *redis.Client) {
+	if client == nil || !trackPool(client) {
+		return
+	}
+	client.AddHook(newOtelRedisHook(client.Options().Addr))
+	if redisEnabler.Enable() {
+		initInstrumentation()
+	}
+}
+
+func afterNewRedisClientV8(ictx hook.HookContext, client *redis.Client) {
+	instrumentClient(client)
+}
+
+func afterNewFailOverRedisClientV8(call hook.HookContext, client *redis.Client) {
+	instrumentClient(client)
+}
+
+func afterNewSentinelClientV8(call hook.HookContext, client *redis.SentinelClient) {
+	client.AddHook(newOtelRedisHook(client.String()))
+}
+
+func afterClientConnV8(call hook.HookContext, client *redis.Conn) {
+	client.AddHook(newOtelRedisHook(client.String()))
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClientEnabler(t *testing.T) {
+	tests := []struct {
+		name     string
+		setupEnv func(t *testing.T)
+		expected bool
+	}{
+		{
+			name: "enabled explicitly",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+			},
+			expected: true,
+		},
+		{
+			name: "disabled explicitly",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "redis")
+			},
+			expected: false,
+		},
+		{
+			name: "not in enabled list",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "nethttp")
+			},
+			expected: false,
+		},
+		{
+			name: "default enabled when no env set",
+			setupEnv: func(t *testing.T) {
+				// No environment variables set - should be enabled by default
+			},
+			expected: true,
+		},
+		{
+			name: "enabled with multiple instrumentations",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "nethttp,redis,grpc")
+			},
+			expected: true,
+		},
+		{
+			name: "disabled with multiple instrumentations",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "redis,grpc")
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupEnv(t)
+
+			enabler := redisClientEnabler{}
+			result := enabler.Enable()
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestInstrumentationConstants(t *testing.T) {
+	assert.Equal(
+		t,
+		"go.opentelemetry.io/otelc/instrumentation/github.com/go-redis/redis/v8",
+		instrumentationName,
+	)
+	assert.Equal(t, "REDIS", instrumentationKey)
+}
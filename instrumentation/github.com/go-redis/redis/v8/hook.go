@@ -0,0 +1,252 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v8
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	otelsemconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/dbconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/github.com/redis/semconv"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+var (
+	logger   = runtime.Logger()
+	tracer   trace.Tracer
+	meter    metric.Meter
+	initOnce sync.Once
+
+	// Metrics
+	operationDuration dbconv.ClientOperationDuration
+)
+
+func initInstrumentation() {
+	initOnce.Do(func() {
+		version := runtime.ModuleVersion()
+		tracer = otel.GetTracerProvider().Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(version),
+		)
+		meter = otel.GetMeterProvider().Meter(
+			instrumentationName,
+			metric.WithInstrumentationVersion(version),
+			metric.WithSchemaURL(otelsemconv.SchemaURL),
+		)
+
+		var err error
+		operationDuration, err = dbconv.NewClientOperationDuration(meter)
+		if err != nil {
+			logger.Error("failed to create operation duration metric", "error", err)
+		}
+
+		if err = registerPoolMetrics(meter); err != nil {
+			logger.Error("failed to create connection pool metrics", "error", err)
+		}
+
+		logger.Info("Redis v8 client instrumentation initialized")
+	})
+}
+
+// operationKey is the context key under which BeforeProcess and
+// BeforeProcessPipeline hand the started operation to the matching After hook.
+type operationKey struct{}
+
+type operation struct {
+	span    trace.Span
+	request semconv.RedisRequest
+	start   time.Time
+}
+
+type otelRedisHook struct {
+	Addr string
+}
+
+func newOtelRedisHook(addr string) *otelRedisHook {
+	return &otelRedisHook{
+		Addr: addr,
+	}
+}
+
+func (o *otelRedisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	if !redisEnabler.Enable() {
+		logger.Debug("Redis Client instrumentation disabled")
+		return ctx, nil
+	}
+	if runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient) {
+		return ctx, nil
+	}
+	initInstrumentation()
+	request := semconv.RedisRequest{
+		Endpoint:  o.Addr,
+		FullName:  cmd.FullName(),
+		Statement: getRedisV8Statement(cmd),
+	}
+	return startOperation(ctx, request), nil
+}
+
+func (o *otelRedisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	endOperation(ctx, cmd.Err())
+	return nil
+}
+
+func (o *otelRedisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	if !redisEnabler.Enable() {
+		logger.Debug("Redis Client instrumentation disabled")
+		return ctx, nil
+	}
+	if runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient) {
+		return ctx, nil
+	}
+	initInstrumentation()
+
+	summary := ""
+	summaryCmds := cmds
+	if len(summaryCmds) > 10 {
+		summaryCmds = summaryCmds[:10]
+	}
+	for i := range summaryCmds {
+		summary += summaryCmds[i].FullName() + "/"
+	}
+	if len(cmds) > 10 {
+		summary += "..."
+	}
+	cmd := redis.NewCmd(ctx, "pipeline", summary)
+	request := semconv.RedisRequest{
+		Endpoint:  o.Addr,
+		FullName:  cmd.FullName(),
+		Statement: getRedisV8Statement(cmd),
+	}
+	return startOperation(ctx, request), nil
+}
+
+func (o *otelRedisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	// A pipeline is recorded as a single operation that fails with its first
+	// failed command.
+	var err error
+	for _, cmd := range cmds {
+		if cmdErr := cmd.Err(); cmdErr != nil && !errors.Is(cmdErr, redis.Nil) {
+			err = cmdErr
+			break
+		}
+	}
+	endOperation(ctx, err)
+	return nil
+}
+
+func startOperation(ctx context.Context, request semconv.RedisRequest) context.Context {
+	// Get trace attributes from semconv
+	attrs := semconv.RedisClientRequestTraceAttrs(request)
+
+	// Start span
+	spanName := request.FullName
+	start := time.Now()
+	ctx, span := tracer.Start(ctx,
+		spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return context.WithValue(ctx, operationKey{}, &operation{
+		span:    span,
+		request: request,
+		start:   start,
+	})
+}
+
+func endOperation(ctx context.Context, err error) {
+	op, ok := ctx.Value(operationKey{}).(*operation)
+	if !ok {
+		return
+	}
+	defer op.span.End()
+
+	if err != nil && !errors.Is(err, redis.Nil) {
+		op.span.SetStatus(codes.Error, err.Error())
+	}
+	recordOperation(ctx, op.request, op.start, err)
+}
+
+func getRedisV8Statement(cmd redis.Cmder) string {
+	b := make([]byte, 0, 64)
+
+	for i, arg := range cmd.Args() {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = redisV8AppendArg(b, arg)
+	}
+
+	if err := cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+		b = append(b, ": "...)
+		b = append(b, err.Error()...)
+	}
+
+	if cmd, ok := cmd.(*redis.Cmd); ok {
+		b = append(b, ": "...)
+		b = redisV8AppendArg(b, cmd.Name())
+	}
+
+	return string(b)
+}
+
+func redisV8AppendArg(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, "<nil>"...)
+	case string:
+		if utf8.ValidString(v) {
+			return append(b, v...)
+		}
+		return append(b, "<string>"...)
+	case []byte:
+		if utf8.Valid(v) {
+			return append(b, v...)
+		}
+		return append(b, "<byte>"...)
+	case int:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int8:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int16:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int32:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	case uint:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint8:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint16:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint32:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(b, v, 10)
+	case float32:
+		return strconv.AppendFloat(b, float64(v), 'f', -1, 64)
+	case float64:
+		return strconv.AppendFloat(b, v, 'f', -1, 64)
+	case bool:
+		if v {
+			return append(b, "true"...)
+		}
+		return append(b, "false"...)
+	case time.Time:
+		return v.AppendFormat(b, time.RFC3339Nano)
+	default:
+		return append(b, "not_support_type"...)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v8
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+func setupTestTracer(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return sr
+}
+
+// process runs cmd through the hook the way go-redis does, failing it with
+// err.
+func process(t *testing.T, hook *otelRedisHook, cmd redis.Cmder, err error) {
+	t.Helper()
+	ctx, hookErr := hook.BeforeProcess(context.Background(), cmd)
+	require.NoError(t, hookErr)
+	if err != nil {
+		cmd.SetErr(err)
+	}
+	require.NoError(t, hook.AfterProcess(ctx, cmd))
+}
+
+func TestGetRedisV8Statement(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      redis.Cmder
+		contains string
+	}{
+		{
+			name:     "GET command",
+			cmd:      redis.NewCmd(context.Background(), "get", "mykey"),
+			contains: "get mykey",
+		},
+		{
+			name:     "SET command with value",
+			cmd:      redis.NewCmd(context.Background(), "set", "mykey", "myvalue"),
+			contains: "set mykey myvalue",
+		},
+		{
+			name:     "command with nil arg",
+			cmd:      redis.NewCmd(context.Background(), "set", nil),
+			contains: "set <nil>",
+		},
+		{
+			name:     "command with int arg",
+			cmd:      redis.NewCmd(context.Background(), "expire", "mykey", 60),
+			contains: "expire mykey 60",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, getRedisV8Statement(tt.cmd), tt.contains)
+		})
+	}
+}
+
+func TestRedisV8AppendArg(t *testing.T) {
+	tests := []struct {
+		name     string
+		arg      interface{}
+		expected string
+	}{
+		{"nil", nil, "<nil>"},
+		{"string", "hello", "hello"},
+		{"int64", int64(64), "64"},
+		{"uint32", uint32(32), "32"},
+		{"float64", float64(3.14159), "3.14159"},
+		{"bool true", true, "true"},
+		{"bytes valid utf8", []byte("hello"), "hello"},
+		{"invalid utf8 string", string([]byte{0xff, 0xfe}), "<string>"},
+		{"invalid utf8 bytes", []byte{0xff, 0xfe}, "<byte>"},
+		{"unsupported type", struct{}{}, "not_support_type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, string(redisV8AppendArg(nil, tt.arg)))
+		})
+	}
+}
+
+func TestRedisV8AppendArg_Time(t *testing.T) {
+	b := redisV8AppendArg(nil, time.Now())
+	_, err := time.Parse(time.RFC3339Nano, string(b))
+	assert.NoError(t, err)
+}
+
+func TestProcess_CreatesSpan(t *testing.T) {
+	initOnce = *new(sync.Once)
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+
+	sr := setupTestTracer(t)
+
+	hook := newOtelRedisHook("localhost:6379")
+	parentCtx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	cmd := redis.NewCmd(parentCtx, "get", "mykey")
+	ctx, err := hook.BeforeProcess(parentCtx, cmd)
+	require.NoError(t, err)
+	assert.True(t, trace.SpanFromContext(ctx).SpanContext().IsValid())
+	require.NoError(t, hook.AfterProcess(ctx, cmd))
+	parent.End()
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+
+	span := spans[0]
+	assert.Equal(t, "get", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+
+	attrMap := make(map[string]interface{})
+	for _, attr := range span.Attributes() {
+		attrMap[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	assert.Equal(t, "redis", attrMap["db.system.name"])
+	assert.Equal(t, "get", attrMap["db.operation.name"])
+	assert.Contains(t, attrMap["db.query.text"], "get mykey")
+	assert.Equal(t, "localhost", attrMap["server.address"])
+	assert.Equal(t, int64(6379), attrMap["server.port"])
+}
+
+func TestProcess_RecordsError(t *testing.T) {
+	initOnce = *new(sync.Once)
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+
+	sr := setupTestTracer(t)
+
+	process(t, newOtelRedisHook("localhost:6379"),
+		redis.NewCmd(context.Background(), "get", "mykey"), errors.New("connection refused"))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Status().Description, "connection refused")
+}
+
+func TestProcess_RedisNilNotError(t *testing.T) {
+	initOnce = *new(sync.Once)
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+
+	sr := setupTestTracer(t)
+
+	process(t, newOtelRedisHook("localhost:6379"),
+		redis.NewCmd(context.Background(), "get", "nonexistent"), redis.Nil)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestProcess_Disabled(t *testing.T) {
+	initOnce = *new(sync.Once)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "redis")
+
+	sr := setupTestTracer(t)
+
+	process(t, newOtelRedisHook("localhost:6379"),
+		redis.NewCmd(context.Background(), "get", "mykey"), nil)
+
+	assert.Empty(t, sr.Ended(), "no spans should be created when instrumentation is disabled")
+}
+
+func TestProcess_Suppressed(t *testing.T) {
+	initOnce = *new(sync.Once)
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+
+	sr := setupTestTracer(t)
+
+	hook := newOtelRedisHook("localhost:6379")
+	ctx := runtime.Suppress(context.Background(), runtime.SpanKindKey(trace.SpanKindClient))
+	cmd := redis.NewCmd(ctx, "get", "mykey")
+	ctx, err := hook.BeforeProcess(ctx, cmd)
+	require.NoError(t, err)
+	require.NoError(t, hook.AfterProcess(ctx, cmd))
+
+	assert.Empty(t, sr.Ended(), "no spans should be created for a suppressed context")
+}
+
+func TestProcessPipeline_CreatesSpan(t *testing.T) {
+	initOnce = *new(sync.Once)
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+
+	sr := setupTestTracer(t)
+
+	hook := newOtelRedisHook("localhost:6379")
+	cmds := make([]redis.Cmder, 0, 12)
+	for range 12 {
+		cmds = append(cmds, redis.NewCmd(context.Background(), "set", "k", "v"))
+	}
+	ctx, err := hook.BeforeProcessPipeline(context.Background(), cmds)
+	require.NoError(t, err)
+	cmds[1].SetErr(redis.Nil)
+	cmds[3].SetErr(errors.New("READONLY You can't write against a read only replica."))
+	require.NoError(t, hook.AfterProcessPipeline(ctx, cmds))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "pipeline", span.Name())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Status().Description, "READONLY")
+
+	for _, attr := range span.Attributes() {
+		if attr.Key == "db.query.text" {
+			assert.Contains(t, attr.Value.AsString(), "...")
+		}
+	}
+}
+
+func TestProcessPipeline_Disabled(t *testing.T) {
+	initOnce = *new(sync.Once)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "redis")
+
+	sr := setupTestTracer(t)
+
+	hook := newOtelRedisHook("localhost:6379")
+	cmds := []redis.Cmder{redis.NewCmd(context.Background(), "set", "k", "v")}
+	ctx, err := hook.BeforeProcessPipeline(context.Background(), cmds)
+	require.NoError(t, err)
+	require.NoError(t, hook.AfterProcessPipeline(ctx, cmds))
+
+	assert.Empty(t, sr.Ended())
+}
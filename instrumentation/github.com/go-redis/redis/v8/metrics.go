@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v8
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"weak"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	otelsemconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/dbconv"
+
+	"go.opentelemetry.io/otelc/instrumentation/github.com/redis/semconv"
+)
+
+// poolSource is the connection pool of one *redis.Client. stats returns nil
+// once the client has been garbage collected.
+type poolSource struct {
+	client  weak.Pointer[redis.Client]
+	name    string
+	stats   func() *redis.PoolStats
+	max     int64
+	idleMin int64
+}
+
+// pools holds every tracked connection pool. Ring and cluster clients are
+// tracked per node, through the clients they create for each shard.
+var (
+	poolsMu sync.Mutex
+	pools   []*poolSource
+)
+
+// trackPool starts reporting the connection pool metrics of client and
+// reports whether the client was not tracked yet. Only a weak reference to
+// the client is kept, so tracking does not extend its lifetime.
+func trackPool(client *redis.Client) bool {
+	ref := weak.Make(client)
+
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	for _, src := range pools {
+		if src.client == ref {
+			return false
+		}
+	}
+
+	opt := client.Options()
+	pools = append(pools, &poolSource{
+		client: ref,
+		name:   opt.Addr,
+		stats: func() *redis.PoolStats {
+			if c := ref.Value(); c != nil {
+				return c.PoolStats()
+			}
+			return nil
+		},
+		max:     int64(opt.PoolSize),
+		idleMin: int64(opt.MinIdleConns),
+	})
+	return true
+}
+
+// poolUsage is the combined usage of the pools sharing a name.
+type poolUsage struct {
+	idle, used, timeouts int64
+	max, idleMin         int64
+}
+
+// snapshotPools returns the usage of every live pool, keyed by pool name,
+// and forgets the pools of collected clients.
+func snapshotPools() map[string]*poolUsage {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	usage := make(map[string]*poolUsage, len(pools))
+	live := pools[:0]
+	for _, src := range pools {
+		stats := src.stats()
+		if stats == nil {
+			continue
+		}
+		live = append(live, src)
+
+		u, ok := usage[src.name]
+		if !ok {
+			u = &poolUsage{}
+			usage[src.name] = u
+		}
+		u.idle += int64(stats.IdleConns)
+		u.used += int64(stats.TotalConns) - int64(stats.IdleConns)
+		u.timeouts += int64(stats.Timeouts)
+		u.max += src.max
+		u.idleMin += src.idleMin
+	}
+	clear(pools[len(live):])
+	pools = live
+	return usage
+}
+
+// registerPoolMetrics creates the asynchronous connection pool instruments,
+// which are observed from PoolStats on every collection. The v8 pool does not
+// cap idle connections or count waiting requests, so
+// db.client.connection.idle.max and db.client.connection.pending_requests are
+// not reported.
+func registerPoolMetrics(m metric.Meter) error {
+	count, err := m.Int64ObservableUpDownCounter(
+		dbconv.ClientConnectionCount{}.Name(),
+		metric.WithUnit(dbconv.ClientConnectionCount{}.Unit()),
+		metric.WithDescription(dbconv.ClientConnectionCount{}.Description()),
+	)
+	if err != nil {
+		return err
+	}
+	idleMin, err := m.Int64ObservableUpDownCounter(
+		dbconv.ClientConnectionIdleMin{}.Name(),
+		metric.WithUnit(dbconv.ClientConnectionIdleMin{}.Unit()),
+		metric.WithDescription(dbconv.ClientConnectionIdleMin{}.Description()),
+	)
+	if err != nil {
+		return err
+	}
+	maxConns, err := m.Int64ObservableUpDownCounter(
+		dbconv.ClientConnectionMax{}.Name(),
+		metric.WithUnit(dbconv.ClientConnectionMax{}.Unit()),
+		metric.WithDescription(dbconv.ClientConnectionMax{}.Description()),
+	)
+	if err != nil {
+		return err
+	}
+	timeouts, err := m.Int64ObservableCounter(
+		dbconv.ClientConnectionTimeouts{}.Name(),
+		metric.WithUnit(dbconv.ClientConnectionTimeouts{}.Unit()),
+		metric.WithDescription(dbconv.ClientConnectionTimeouts{}.Description()),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for name, u := range snapshotPools() {
+			attrs := semconv.RedisPoolMetricAttrs(name)
+			pool := metric.WithAttributeSet(attribute.NewSet(attrs...))
+			attrs = attrs[:len(attrs):len(attrs)]
+			o.ObserveInt64(count, u.idle, metric.WithAttributeSet(attribute.NewSet(
+				append(attrs, otelsemconv.DBClientConnectionStateIdle)...)))
+			o.ObserveInt64(count, u.used, metric.WithAttributeSet(attribute.NewSet(
+				append(attrs, otelsemconv.DBClientConnectionStateUsed)...)))
+			o.ObserveInt64(idleMin, u.idleMin, pool)
+			o.ObserveInt64(maxConns, u.max, pool)
+			o.ObserveInt64(timeouts, u.timeouts, pool)
+		}
+		return nil
+	}, count, idleMin, maxConns, timeouts)
+	return err
+}
+
+// recordOperation records db.client.operation.duration for a command or
+// pipeline that started at start.
+func recordOperation(ctx context.Context, req semconv.RedisRequest, start time.Time, err error) {
+	errType := ""
+	if err != nil && !errors.Is(err, redis.Nil) {
+		errType = redisErrorType(err)
+	}
+	attrs := semconv.RedisClientRequestMetricAttrs(req, errType)
+	operationDuration.RecordSet(ctx, time.Since(start).Seconds(), attribute.NewSet(attrs...))
+}
+
+// redisErrorType returns the error prefix of a Redis server error, such as
+// "WRONGTYPE" or "ERR", and the Go type of any other error.
+func redisErrorType(err error) string {
+	var redisErr redis.Error
+	if errors.As(err, &redisErr) {
+		msg := redisErr.Error()
+		if prefix, _, ok := strings.Cut(msg, " "); ok {
+			return prefix
+		}
+		return msg
+	}
+	return fmt.Sprintf("%T", err)
+}
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// headerLine and crlf frame the encoded headers of a NATS message.
+	headerLine = "NATS/1.0\r\n"
+	crlf       = "\r\n"
+)
+
+// headerCarrier adapts a nats.Header to the OpenTelemetry TextMapCarrier
+// interface so trace context can be propagated through NATS message headers.
+type headerCarrier struct {
+	header nats.Header
+}
+
+// Get returns the first value of the header matching key, or "" if absent.
+func (c headerCarrier) Get(key string) string {
+	return c.header.Get(key)
+}
+
+// Set replaces any existing values of the header with key.
+func (c headerCarrier) Set(key, value string) {
+	c.header.Set(key, value)
+}
+
+// Keys lists the header keys carried by this carrier.
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.header))
+	for key := range c.header {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// extractHeaders returns the context carried by the encoded headers hdr of a
+// published message, or context.Background() when they carry none.
+func extractHeaders(hdr []byte) context.Context {
+	ctx := context.Background()
+	if len(hdr) == 0 {
+		return ctx
+	}
+	header, err := nats.DecodeHeadersMsg(hdr)
+	if err != nil {
+		logger.Debug("failed to decode NATS message headers", "error", err)
+		return ctx
+	}
+	return propagator.Extract(ctx, headerCarrier{header: header})
+}
+
+// injectHeaders returns the encoded headers hdr of a published message with
+// the trace context of ctx added. It returns hdr unchanged when the headers
+// cannot be decoded.
+func injectHeaders(ctx context.Context, hdr []byte) []byte {
+	header := nats.Header{}
+	if len(hdr) > 0 {
+		decoded, err := nats.DecodeHeadersMsg(hdr)
+		if err != nil {
+			logger.Debug("failed to decode NATS message headers", "error", err)
+			return hdr
+		}
+		header = decoded
+	}
+	propagator.Inject(ctx, headerCarrier{header: header})
+	return encodeHeaders(header)
+}
+
+// encodeHeaders encodes header the way nats.go encodes the headers of a
+// nats.Msg.
+func encodeHeaders(header nats.Header) []byte {
+	var b bytes.Buffer
+	b.WriteString(headerLine)
+	for key, values := range header {
+		for _, value := range values {
+			b.WriteString(key)
+			b.WriteString(": ")
+			b.WriteString(value)
+			b.WriteString(crlf)
+		}
+	}
+	b.WriteString(crlf)
+	return b.Bytes()
+}
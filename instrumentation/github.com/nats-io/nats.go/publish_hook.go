@@ -0,0 +1,302 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats instruments the NATS client github.com/nats-io/nats.go.
+// Published messages get a producer span whose trace context travels in the
+// message headers, requests get a client span covering the round trip, and
+// the handlers of Subscribe and QueueSubscribe run inside a consumer span
+// continuing the trace of the producer.
+package nats
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	otelsemconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/messagingconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+const (
+	instrumentationName = "go.opentelemetry.io/otelc/instrumentation/github.com/nats-io/nats.go"
+	instrumentationKey  = "NATS"
+
+	// publishHeaderParamIndex is the index of the headers in
+	// (*Conn).publish(subj, reply, validateReply, hdr, data), after the
+	// receiver.
+	publishHeaderParamIndex = 4
+	// requestHeaderParamIndex is the index of the headers in
+	// (*Conn).request(subj, hdr, data, timeout), after the receiver.
+	requestHeaderParamIndex = 2
+	// requestWithContextHeaderParamIndex is the index of the headers in
+	// (*Conn).requestWithContext(ctx, subj, hdr, data), after the receiver.
+	requestWithContextHeaderParamIndex = 3
+
+	// systemSubjectPrefix starts the subjects of the NATS system and
+	// JetStream APIs, such as acknowledgements and flow control replies.
+	systemSubjectPrefix = "$"
+)
+
+// natsEnablerImpl controls whether the NATS instrumentation is enabled.
+type natsEnablerImpl struct{}
+
+func (natsEnablerImpl) Enable() bool {
+	return runtime.Instrumented(instrumentationKey)
+}
+
+var natsEnabler = natsEnablerImpl{}
+
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, natsEnabler)
+	runtime.RegisterUnsuppressible(instrumentationKey, "Conn.Publish", "Conn.Request")
+}
+
+var (
+	logger     = runtime.Logger()
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	meter      metric.Meter
+	initOnce   sync.Once
+
+	// Metrics
+	sentMessages      messagingconv.ClientSentMessages
+	operationDuration messagingconv.ClientOperationDuration
+	consumedMessages  messagingconv.ClientConsumedMessages
+	processDuration   messagingconv.ProcessDuration
+)
+
+func initInstrumentation() {
+	initOnce.Do(func() {
+		version := runtime.ModuleVersion()
+		tracer = otel.GetTracerProvider().Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(version),
+		)
+		propagator = otel.GetTextMapPropagator()
+		meter = otel.GetMeterProvider().Meter(
+			instrumentationName,
+			metric.WithInstrumentationVersion(version),
+			metric.WithSchemaURL(otelsemconv.SchemaURL),
+		)
+
+		var err error
+		sentMessages, err = messagingconv.NewClientSentMessages(meter)
+		if err != nil {
+			logger.Error("failed to create sent messages metric", "error", err)
+		}
+		operationDuration, err = messagingconv.NewClientOperationDuration(meter)
+		if err != nil {
+			logger.Error("failed to create operation duration metric", "error", err)
+		}
+		consumedMessages, err = messagingconv.NewClientConsumedMessages(meter)
+		if err != nil {
+			logger.Error("failed to create consumed messages metric", "error", err)
+		}
+		processDuration, err = messagingconv.NewProcessDuration(meter)
+		if err != nil {
+			logger.Error("failed to create process duration metric", "error", err)
+		}
+
+		logger.Info("NATS (nats-io/nats.go) instrumentation initialized")
+	})
+}
+
+// newRequest returns the request of an operation of nc on subject.
+func newRequest(nc *nats.Conn, subject string, op natsOperation) natsRequest {
+	req := natsRequest{subject: subject, operation: op}
+	if nc != nil {
+		req.endpoint = nc.ConnectedAddr()
+		req.temporary = isInbox(nc, subject)
+	}
+	return req
+}
+
+// isInbox reports whether subject is a reply inbox of nc.
+func isInbox(nc *nats.Conn, subject string) bool {
+	if strings.HasPrefix(subject, nats.InboxPrefix) {
+		return true
+	}
+	prefix := nc.Opts.InboxPrefix
+	return prefix != "" && strings.HasPrefix(subject, prefix+".")
+}
+
+type operationData struct {
+	ctx   context.Context
+	span  trace.Span
+	req   natsRequest
+	start time.Time
+}
+
+// end ends the span of the operation and records its duration, and for
+// publish operations the sent message.
+func (data *operationData) end(err error) {
+	if err != nil {
+		data.span.RecordError(err)
+		data.span.SetStatus(codes.Error, err.Error())
+	}
+	data.span.End()
+
+	set := attribute.NewSet(data.req.metricAttrs(err)...)
+	if operationDuration.Inst() != nil {
+		operationDuration.RecordSet(data.ctx, time.Since(data.start).Seconds(), set)
+	}
+	if data.req.operation == operationPublish && err == nil && sentMessages.Inst() != nil {
+		sentMessages.AddSet(data.ctx, 1, set)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Producer: (*nats.Conn).publish(subj, reply, validateReply, hdr, data)
+//
+// Publish, PublishMsg, PublishRequest, the requests and Msg.Respond all send
+// their message through the unexported publish method.
+// -----------------------------------------------------------------------------
+
+// BeforePublish starts a producer span and injects its trace context into the
+// headers of the published message. Messages to system subjects are not
+// traced.
+func BeforePublish(
+	ictx hook.HookContext,
+	nc *nats.Conn,
+	subj, _ string,
+	_ bool,
+	hdr, data []byte,
+) {
+	if !natsEnabler.Enable() {
+		logger.Debug("NATS instrumentation disabled")
+		return
+	}
+	if nc == nil || strings.HasPrefix(subj, systemSubjectPrefix) {
+		return
+	}
+	initInstrumentation()
+
+	req := newRequest(nc, subj, operationPublish)
+	req.bodySize = len(data)
+	start := time.Now()
+	// The headers carry the parent context of a request message, set by the
+	// request hooks, or the one the application propagated itself.
+	ctx, span := tracer.Start(extractHeaders(hdr), req.spanName(),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(req.traceAttrs()...),
+	)
+	// Headers can only be sent to servers supporting them.
+	if len(hdr) > 0 || nc.HeadersSupported() {
+		ictx.SetParam(publishHeaderParamIndex, injectHeaders(ctx, hdr))
+	}
+	ictx.SetData(&operationData{ctx: ctx, span: span, req: req, start: start})
+}
+
+// AfterPublish ends the producer span started by BeforePublish.
+func AfterPublish(ictx hook.HookContext, err error) {
+	data, ok := ictx.GetData().(*operationData)
+	if !ok || data == nil {
+		return
+	}
+	data.end(err)
+}
+
+// -----------------------------------------------------------------------------
+// Requests: (*nats.Conn).request(subj, hdr, data, timeout) and
+// (*nats.Conn).requestWithContext(ctx, subj, hdr, data)
+//
+// The exported Request, RequestMsg, RequestWithContext and
+// RequestMsgWithContext methods rely on these.
+// -----------------------------------------------------------------------------
+
+// startRequest starts the client span of a request. The request message is
+// published with its own producer span, a child of this one: its trace context
+// is injected into the request headers, the parameter at hdrIndex, where
+// BeforePublish finds it.
+func startRequest(
+	ictx hook.HookContext,
+	parent context.Context,
+	nc *nats.Conn,
+	subj string,
+	hdrIndex int,
+	hdr, data []byte,
+) {
+	initInstrumentation()
+
+	req := newRequest(nc, subj, operationRequest)
+	req.bodySize = len(data)
+	start := time.Now()
+	ctx, span := tracer.Start(parent, req.spanName(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(req.traceAttrs()...),
+	)
+	if len(hdr) > 0 || nc.HeadersSupported() {
+		ictx.SetParam(hdrIndex, injectHeaders(ctx, hdr))
+	}
+	ictx.SetData(&operationData{ctx: ctx, span: span, req: req, start: start})
+}
+
+// BeforeRequest starts the client span of a request waiting for its reply
+// until a timeout.
+func BeforeRequest(
+	ictx hook.HookContext,
+	nc *nats.Conn,
+	subj string,
+	hdr, data []byte,
+	_ time.Duration,
+) {
+	if !natsEnabler.Enable() {
+		logger.Debug("NATS instrumentation disabled")
+		return
+	}
+	if nc == nil {
+		return
+	}
+	startRequest(ictx, context.Background(), nc, subj, requestHeaderParamIndex, hdr, data)
+}
+
+// AfterRequest ends the client span started by BeforeRequest.
+func AfterRequest(ictx hook.HookContext, _ *nats.Msg, err error) {
+	data, ok := ictx.GetData().(*operationData)
+	if !ok || data == nil {
+		return
+	}
+	data.end(err)
+}
+
+// BeforeRequestWithContext starts the client span of a request waiting for
+// its reply until ctx is done. The span is a child of ctx.
+func BeforeRequestWithContext(
+	ictx hook.HookContext,
+	nc *nats.Conn,
+	ctx context.Context,
+	subj string,
+	hdr, data []byte,
+) {
+	if !natsEnabler.Enable() {
+		logger.Debug("NATS instrumentation disabled")
+		return
+	}
+	if nc == nil || ctx == nil || runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient) {
+		return
+	}
+	startRequest(ictx, ctx, nc, subj, requestWithContextHeaderParamIndex, hdr, data)
+}
+
+// AfterRequestWithContext ends the client span started by
+// BeforeRequestWithContext.
+func AfterRequestWithContext(ictx hook.HookContext, _ *nats.Msg, err error) {
+	data, ok := ictx.GetData().(*operationData)
+	if !ok || data == nil {
+		return
+	}
+	data.end(err)
+}
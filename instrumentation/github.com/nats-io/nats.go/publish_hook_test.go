@@ -0,0 +1,324 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/messagingconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+func setupTest(t *testing.T) (*tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "nats")
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	// Consume initOnce so initInstrumentation() becomes a no-op and does not
+	// overwrite the tracer/propagator/instruments we install below.
+	initOnce.Do(func() {})
+	tracer = tp.Tracer("test")
+	propagator = propagation.TraceContext{}
+	meter = mp.Meter("test")
+	var err error
+	sentMessages, err = messagingconv.NewClientSentMessages(meter)
+	require.NoError(t, err)
+	operationDuration, err = messagingconv.NewClientOperationDuration(meter)
+	require.NoError(t, err)
+	consumedMessages, err = messagingconv.NewClientConsumedMessages(meter)
+	require.NoError(t, err)
+	processDuration, err = messagingconv.NewProcessDuration(meter)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		_ = mp.Shutdown(context.Background())
+		initOnce = sync.Once{}
+		tracer = nil
+		propagator = nil
+		meter = nil
+		sentMessages = messagingconv.ClientSentMessages{}
+		operationDuration = messagingconv.ClientOperationDuration{}
+		consumedMessages = messagingconv.ClientConsumedMessages{}
+		processDuration = messagingconv.ProcessDuration{}
+	})
+	return sr, reader
+}
+
+// connect starts an in-process NATS server and returns a connection to it.
+func connect(t *testing.T) *nats.Conn {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{
+		Host:   "127.0.0.1",
+		Port:   server.RANDOM_PORT,
+		NoLog:  true,
+		NoSigs: true,
+	})
+	require.NoError(t, err)
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+	require.True(t, srv.ReadyForConnections(5*time.Second), "NATS server not ready")
+
+	nc, err := nats.Connect(srv.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) (metricdata.Metrics, bool) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+func attrMap(attrs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(attrs))
+	for _, kv := range attrs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+// publish runs the publish hooks around nc.PublishMsg the way the injected
+// trampoline wraps (*Conn).publish, and returns the published headers.
+func publish(t *testing.T, nc *nats.Conn, subj string, hdr nats.Header, data []byte) []byte {
+	t.Helper()
+	var encoded []byte
+	if hdr != nil {
+		encoded = encodeHeaders(hdr)
+	}
+	ictx := hooktest.NewMockHookContext(nc, subj, "", false, encoded, data)
+	BeforePublish(ictx, nc, subj, "", false, encoded, data)
+	sent, _ := ictx.GetParam(publishHeaderParamIndex).([]byte)
+
+	msg := &nats.Msg{Subject: subj, Data: data}
+	if len(sent) > 0 {
+		header, err := nats.DecodeHeadersMsg(sent)
+		require.NoError(t, err)
+		msg.Header = header
+	}
+	err := nc.PublishMsg(msg)
+	AfterPublish(ictx, err)
+	require.NoError(t, err)
+	return sent
+}
+
+func TestPublish_InjectsTraceContext(t *testing.T) {
+	sr, reader := setupTest(t)
+	nc := connect(t)
+
+	sent := publish(t, nc, "orders", nil, []byte("hello"))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "orders publish", span.Name())
+	assert.Equal(t, trace.SpanKindProducer, span.SpanKind())
+
+	attrs := attrMap(span.Attributes())
+	assert.Equal(t, "nats", attrs[semconv.MessagingSystemKey].AsString())
+	assert.Equal(t, "publish", attrs[semconv.MessagingOperationNameKey].AsString())
+	assert.Equal(t, "send", attrs[semconv.MessagingOperationTypeKey].AsString())
+	assert.Equal(t, "orders", attrs[semconv.MessagingDestinationNameKey].AsString())
+	assert.Equal(t, int64(5), attrs[semconv.MessagingMessageBodySizeKey].AsInt64())
+	assert.Equal(t, "127.0.0.1", attrs[semconv.ServerAddressKey].AsString())
+	assert.Positive(t, attrs[semconv.ServerPortKey].AsInt64())
+
+	header, err := nats.DecodeHeadersMsg(sent)
+	require.NoError(t, err)
+	sc := trace.SpanContextFromContext(
+		propagator.Extract(context.Background(), headerCarrier{header: header}))
+	assert.Equal(t, span.SpanContext().SpanID(), sc.SpanID())
+
+	m, ok := collectMetric(t, reader, "messaging.client.sent.messages")
+	require.True(t, ok)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+
+	_, ok = collectMetric(t, reader, "messaging.client.operation.duration")
+	assert.True(t, ok)
+}
+
+func TestPublish_KeepsHeaders(t *testing.T) {
+	sr, _ := setupTest(t)
+	nc := connect(t)
+
+	hdr := nats.Header{"Order-Id": []string{"42"}}
+	sent := publish(t, nc, "orders", hdr, nil)
+
+	header, err := nats.DecodeHeadersMsg(sent)
+	require.NoError(t, err)
+	assert.Equal(t, "42", header.Get("Order-Id"))
+	assert.NotEmpty(t, header.Get("traceparent"))
+	assert.Equal(t, nats.Header{"Order-Id": []string{"42"}}, hdr, "caller headers must not be modified")
+	require.Len(t, sr.Ended(), 1)
+}
+
+func TestPublish_Inbox(t *testing.T) {
+	sr, reader := setupTest(t)
+	nc := connect(t)
+
+	publish(t, nc, nats.NewInbox(), nil, []byte("reply"))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "publish", spans[0].Name())
+	attrs := attrMap(spans[0].Attributes())
+	assert.True(t, attrs[semconv.MessagingDestinationTemporaryKey].AsBool())
+
+	m, ok := collectMetric(t, reader, "messaging.client.sent.messages")
+	require.True(t, ok)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	_, hasDestination := sum.DataPoints[0].Attributes.Value(semconv.MessagingDestinationNameKey)
+	assert.False(t, hasDestination, "inbox subjects must not be metric attributes")
+}
+
+func TestPublish_SystemSubject(t *testing.T) {
+	sr, _ := setupTest(t)
+	nc := connect(t)
+
+	ictx := hooktest.NewMockHookContext(nc, "$JS.ACK.orders", "", false, []byte(nil), []byte(nil))
+	BeforePublish(ictx, nc, "$JS.ACK.orders", "", false, nil, nil)
+	AfterPublish(ictx, nil)
+
+	assert.Nil(t, ictx.GetData())
+	assert.Nil(t, ictx.GetParam(publishHeaderParamIndex))
+	assert.Empty(t, sr.Ended())
+}
+
+func TestPublish_Disabled(t *testing.T) {
+	sr, _ := setupTest(t)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "nats")
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "")
+	nc := connect(t)
+
+	ictx := hooktest.NewMockHookContext(nc, "orders", "", false, []byte(nil), []byte(nil))
+	BeforePublish(ictx, nc, "orders", "", false, nil, nil)
+	AfterPublish(ictx, nil)
+
+	assert.Nil(t, ictx.GetParam(publishHeaderParamIndex))
+	assert.Empty(t, sr.Ended())
+}
+
+func TestRequestWithContext(t *testing.T) {
+	sr, reader := setupTest(t)
+	nc := connect(t)
+
+	_, err := nc.Subscribe("prices", func(msg *nats.Msg) {
+		_ = msg.Respond([]byte("10"))
+	})
+	require.NoError(t, err)
+	require.NoError(t, nc.Flush())
+
+	parentCtx, parent := tracer.Start(context.Background(), "parent")
+	ictx := hooktest.NewMockHookContext(nc, parentCtx, "prices", []byte(nil), []byte("apple"))
+	BeforeRequestWithContext(ictx, nc, parentCtx, "prices", nil, []byte("apple"))
+	reply, err := nc.RequestWithContext(parentCtx, "prices", []byte("apple"))
+	AfterRequestWithContext(ictx, reply, err)
+	parent.End()
+	require.NoError(t, err)
+
+	var span sdktrace.ReadOnlySpan
+	for _, s := range sr.Ended() {
+		if s.Name() == "prices request" {
+			span = s
+		}
+	}
+	require.NotNil(t, span)
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	attrs := attrMap(span.Attributes())
+	assert.Equal(t, "request", attrs[semconv.MessagingOperationNameKey].AsString())
+	assert.Equal(t, "prices", attrs[semconv.MessagingDestinationNameKey].AsString())
+
+	_, ok := collectMetric(t, reader, "messaging.client.operation.duration")
+	assert.True(t, ok)
+	_, ok = collectMetric(t, reader, "messaging.client.sent.messages")
+	assert.False(t, ok, "requests are counted by the publish of their message")
+}
+
+func TestRequestWithContext_Suppressed(t *testing.T) {
+	sr, _ := setupTest(t)
+	nc := connect(t)
+
+	ctx := runtime.Suppress(context.Background(), "NATS")
+	ictx := hooktest.NewMockHookContext(nc, ctx, "prices", []byte(nil), []byte("apple"))
+	BeforeRequestWithContext(ictx, nc, ctx, "prices", nil, []byte("apple"))
+	AfterRequestWithContext(ictx, nil, nats.ErrNoResponders)
+
+	assert.Empty(t, sr.Ended())
+	assert.Nil(t, ictx.GetParam(requestWithContextHeaderParamIndex))
+}
+
+func TestRequest_ParentsPublish(t *testing.T) {
+	sr, _ := setupTest(t)
+	nc := connect(t)
+
+	ictx := hooktest.NewMockHookContext(nc, "prices", []byte(nil), []byte("apple"), time.Second)
+	BeforeRequest(ictx, nc, "prices", nil, []byte("apple"), time.Second)
+	hdr, ok := ictx.GetParam(requestHeaderParamIndex).([]byte)
+	require.True(t, ok)
+	header, err := nats.DecodeHeadersMsg(hdr)
+	require.NoError(t, err)
+
+	// The request message is published with the headers set by BeforeRequest.
+	publish(t, nc, "prices", header, []byte("apple"))
+	AfterRequest(ictx, nil, nil)
+
+	request := spanNamed(t, sr, "prices request")
+	published := spanNamed(t, sr, "prices publish")
+	assert.Equal(t, request.SpanContext().TraceID(), published.SpanContext().TraceID())
+	assert.Equal(t, request.SpanContext().SpanID(), published.Parent().SpanID())
+}
+
+func TestRequest_NoResponders(t *testing.T) {
+	sr, _ := setupTest(t)
+	nc := connect(t)
+
+	ictx := hooktest.NewMockHookContext(nc, "prices", []byte(nil), []byte(nil), time.Second)
+	BeforeRequest(ictx, nc, "prices", nil, nil, time.Second)
+	reply, err := nc.Request("prices", nil, time.Second)
+	AfterRequest(ictx, reply, err)
+	require.ErrorIs(t, err, nats.ErrNoResponders)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	attrs := attrMap(spans[0].Attributes())
+	assert.Equal(t, "prices", attrs[semconv.MessagingDestinationNameKey].AsString())
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"net"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+
+	httpsemconv "go.opentelemetry.io/otelc/instrumentation/net/http/semconv"
+)
+
+// messagingSystem is the messaging.system of NATS, which has no predefined
+// value in the semantic conventions.
+var messagingSystem = semconv.MessagingSystemKey.String("nats")
+
+// natsOperation identifies the messaging operation performed by the client.
+type natsOperation string
+
+const (
+	// operationPublish is the operation name for publishing a message.
+	operationPublish natsOperation = "publish"
+	// operationRequest is the operation name for a request waiting for its
+	// reply.
+	operationRequest natsOperation = "request"
+	// operationProcess is the operation name for a subscription handler
+	// processing a message.
+	operationProcess natsOperation = "process"
+)
+
+// natsRequest carries the information needed to build the semantic convention
+// attributes for a single NATS client operation.
+type natsRequest struct {
+	// endpoint is the server address in host:port form. It may be empty.
+	endpoint string
+	// subject is the subject the message is published or delivered on.
+	subject string
+	// temporary reports whether subject is an inbox, which is unique to a
+	// request or a connection.
+	temporary bool
+	// operation is the messaging operation.
+	operation natsOperation
+	// queue is the queue group of the subscription (process only).
+	queue string
+	// bodySize is the size of the message data in bytes.
+	bodySize int
+}
+
+// spanName builds the span name from the subject and the operation. Inbox
+// subjects are unique, so they are left out of the name.
+func (req natsRequest) spanName() string {
+	if req.subject == "" || req.temporary {
+		return string(req.operation)
+	}
+	return req.subject + " " + string(req.operation)
+}
+
+// traceAttrs returns the span attributes of the operation.
+func (req natsRequest) traceAttrs() []attribute.KeyValue {
+	attrs := req.baseAttrs()
+	if req.subject != "" {
+		attrs = append(attrs, semconv.MessagingDestinationName(req.subject))
+	}
+	if req.bodySize > 0 {
+		attrs = append(attrs, semconv.MessagingMessageBodySize(req.bodySize))
+	}
+	return attrs
+}
+
+// metricAttrs returns the metric attributes of the operation. Inbox subjects
+// are left out to keep metric cardinality bounded; error.type is added when
+// err is non-nil.
+func (req natsRequest) metricAttrs(err error) []attribute.KeyValue {
+	attrs := req.baseAttrs()
+	if req.subject != "" && !req.temporary {
+		attrs = append(attrs, semconv.MessagingDestinationName(req.subject))
+	}
+	if err != nil {
+		attrs = append(attrs, httpsemconv.ErrorType(err))
+	}
+	return attrs
+}
+
+// baseAttrs returns the attributes shared by spans and metrics.
+func (req natsRequest) baseAttrs() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		messagingSystem,
+		semconv.MessagingOperationName(string(req.operation)),
+	}
+	switch req.operation {
+	case operationPublish, operationRequest:
+		attrs = append(attrs, semconv.MessagingOperationTypeSend)
+	case operationProcess:
+		attrs = append(attrs, semconv.MessagingOperationTypeProcess)
+	}
+	if req.temporary {
+		attrs = append(attrs, semconv.MessagingDestinationTemporary(true))
+	}
+	if req.queue != "" {
+		attrs = append(attrs, semconv.MessagingConsumerGroupName(req.queue))
+	}
+	if req.endpoint != "" {
+		host, portStr, err := net.SplitHostPort(req.endpoint)
+		if err != nil {
+			attrs = append(attrs, semconv.ServerAddress(req.endpoint))
+		} else {
+			attrs = append(attrs, semconv.ServerAddress(host))
+			if port, convErr := strconv.Atoi(portStr); convErr == nil && port > 0 {
+				attrs = append(attrs, semconv.ServerPort(port))
+			}
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook"
+)
+
+const (
+	// subscribeHandlerParamIndex is the index of the handler in
+	// (*Conn).Subscribe(subj, cb), after the receiver.
+	subscribeHandlerParamIndex = 2
+	// queueSubscribeHandlerParamIndex is the index of the handler in
+	// (*Conn).QueueSubscribe(subj, queue, cb), after the receiver.
+	queueSubscribeHandlerParamIndex = 3
+)
+
+// -----------------------------------------------------------------------------
+// Consumer: (*nats.Conn).Subscribe(subj, cb) and
+// (*nats.Conn).QueueSubscribe(subj, queue, cb)
+// -----------------------------------------------------------------------------
+
+// BeforeSubscribe wraps the message handler so that every message it
+// processes gets a process span.
+func BeforeSubscribe(ictx hook.HookContext, nc *nats.Conn, _ string, cb nats.MsgHandler) {
+	if !natsEnabler.Enable() {
+		logger.Debug("NATS instrumentation disabled")
+		return
+	}
+	if nc == nil || cb == nil {
+		return
+	}
+	initInstrumentation()
+
+	ictx.SetParam(subscribeHandlerParamIndex, wrapHandler(nc, "", cb))
+}
+
+// BeforeQueueSubscribe mirrors BeforeSubscribe for queue group subscriptions.
+func BeforeQueueSubscribe(ictx hook.HookContext, nc *nats.Conn, _, queue string, cb nats.MsgHandler) {
+	if !natsEnabler.Enable() {
+		logger.Debug("NATS instrumentation disabled")
+		return
+	}
+	if nc == nil || cb == nil {
+		return
+	}
+	initInstrumentation()
+
+	ictx.SetParam(queueSubscribeHandlerParamIndex, wrapHandler(nc, queue, cb))
+}
+
+// wrapHandler returns a handler running cb inside a process span.
+func wrapHandler(nc *nats.Conn, queue string, cb nats.MsgHandler) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		processMessage(nc, queue, msg, cb)
+	}
+}
+
+// processMessage runs cb inside a process span and replaces the trace context
+// in the headers of msg with the span's, so ExtractContext continues the trace
+// below the process span.
+//
+// The span is parented on the producer context carried in the message headers
+// and also links to it, so backends that only follow links still connect the
+// consumer to the producer.
+func processMessage(nc *nats.Conn, queue string, msg *nats.Msg, cb nats.MsgHandler) {
+	if msg.Header == nil {
+		msg.Header = nats.Header{}
+	}
+	carrier := headerCarrier{header: msg.Header}
+
+	req := newRequest(nc, msg.Subject, operationProcess)
+	req.queue = queue
+	req.bodySize = len(msg.Data)
+
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(req.traceAttrs()...),
+	}
+	parent := propagator.Extract(context.Background(), carrier)
+	if sc := trace.SpanContextFromContext(parent); sc.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+
+	start := time.Now()
+	ctx, span := tracer.Start(parent, req.spanName(), append(opts, trace.WithTimestamp(start))...)
+	propagator.Inject(ctx, carrier)
+
+	set := attribute.NewSet(req.metricAttrs(nil)...)
+	if consumedMessages.Inst() != nil {
+		consumedMessages.AddSet(ctx, 1, set)
+	}
+	defer func() {
+		span.End()
+		if processDuration.Inst() != nil {
+			processDuration.RecordSet(ctx, time.Since(start).Seconds(), set)
+		}
+	}()
+	cb(msg)
+}
+
+// ExtractContext extracts the trace context from a NATS message's headers and
+// returns a context.Context that carries the propagated span context.
+//
+// Inside an instrumented Subscribe or QueueSubscribe handler, the headers
+// carry the process span, so spans created with the returned context are its
+// children:
+//
+//	nc.Subscribe("orders", func(msg *nats.Msg) {
+//		ctx := otelcnats.ExtractContext(msg)
+//		// spans created with ctx will be children of the process span.
+//	})
+func ExtractContext(msg *nats.Msg) context.Context {
+	initInstrumentation()
+	if msg == nil || msg.Header == nil {
+		return context.Background()
+	}
+	return propagator.Extract(context.Background(), headerCarrier{header: msg.Header})
+}
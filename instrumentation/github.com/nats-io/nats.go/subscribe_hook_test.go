@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+)
+
+// handled is what a subscription handler observed for a message.
+type handled struct {
+	msg *nats.Msg
+	ctx context.Context
+}
+
+// recordingHandler hands every message, and the context extracted from it, to
+// the returned channel.
+func recordingHandler() (nats.MsgHandler, chan handled) {
+	ch := make(chan handled, 1)
+	return func(msg *nats.Msg) {
+		ch <- handled{msg: msg, ctx: ExtractContext(msg)}
+	}, ch
+}
+
+func receive(t *testing.T, ch chan handled) handled {
+	t.Helper()
+	select {
+	case h := <-ch:
+		return h
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not delivered")
+		return handled{}
+	}
+}
+
+// spanNamed returns the ended span with the given name.
+func spanNamed(t *testing.T, sr *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	var span sdktrace.ReadOnlySpan
+	require.Eventually(t, func() bool {
+		for _, s := range sr.Ended() {
+			if s.Name() == name {
+				span = s
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond, "span %q not ended", name)
+	return span
+}
+
+func TestSubscribe_ContinuesProducerTrace(t *testing.T) {
+	sr, reader := setupTest(t)
+	nc := connect(t)
+
+	cb, ch := recordingHandler()
+	ictx := hooktest.NewMockHookContext(nc, "orders", cb)
+	BeforeSubscribe(ictx, nc, "orders", cb)
+	wrapped, ok := ictx.GetParam(subscribeHandlerParamIndex).(nats.MsgHandler)
+	require.True(t, ok)
+	_, err := nc.Subscribe("orders", wrapped)
+	require.NoError(t, err)
+	require.NoError(t, nc.Flush())
+
+	publish(t, nc, "orders", nil, []byte("hello"))
+	h := receive(t, ch)
+
+	producer := spanNamed(t, sr, "orders publish")
+	process := spanNamed(t, sr, "orders process")
+	assert.Equal(t, trace.SpanKindConsumer, process.SpanKind())
+	assert.Equal(t, producer.SpanContext().TraceID(), process.SpanContext().TraceID())
+	assert.Equal(t, producer.SpanContext().SpanID(), process.Parent().SpanID())
+	require.Len(t, process.Links(), 1)
+	assert.Equal(t, producer.SpanContext().SpanID(), process.Links()[0].SpanContext.SpanID())
+
+	attrs := attrMap(process.Attributes())
+	assert.Equal(t, "nats", attrs[semconv.MessagingSystemKey].AsString())
+	assert.Equal(t, "process", attrs[semconv.MessagingOperationTypeKey].AsString())
+	assert.Equal(t, "orders", attrs[semconv.MessagingDestinationNameKey].AsString())
+	assert.Equal(t, int64(5), attrs[semconv.MessagingMessageBodySizeKey].AsInt64())
+
+	// The handler continues the trace below the process span.
+	assert.Equal(t, process.SpanContext().SpanID(), trace.SpanContextFromContext(h.ctx).SpanID())
+	assert.Equal(t, "hello", string(h.msg.Data))
+
+	m, ok := collectMetric(t, reader, "messaging.client.consumed.messages")
+	require.True(t, ok)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+
+	_, ok = collectMetric(t, reader, "messaging.process.duration")
+	assert.True(t, ok)
+}
+
+func TestQueueSubscribe_ConsumerGroup(t *testing.T) {
+	sr, _ := setupTest(t)
+	nc := connect(t)
+
+	cb, ch := recordingHandler()
+	ictx := hooktest.NewMockHookContext(nc, "orders", "workers", cb)
+	BeforeQueueSubscribe(ictx, nc, "orders", "workers", cb)
+	wrapped, ok := ictx.GetParam(queueSubscribeHandlerParamIndex).(nats.MsgHandler)
+	require.True(t, ok)
+	_, err := nc.QueueSubscribe("orders", "workers", wrapped)
+	require.NoError(t, err)
+	require.NoError(t, nc.Flush())
+
+	// Messages published without trace context start a new trace.
+	require.NoError(t, nc.Publish("orders", []byte("hello")))
+	receive(t, ch)
+
+	process := spanNamed(t, sr, "orders process")
+	assert.False(t, process.Parent().IsValid())
+	assert.Empty(t, process.Links())
+	attrs := attrMap(process.Attributes())
+	assert.Equal(t, "workers", attrs[semconv.MessagingConsumerGroupNameKey].AsString())
+}
+
+func TestSubscribe_Disabled(t *testing.T) {
+	sr, _ := setupTest(t)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "nats")
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "")
+	nc := connect(t)
+
+	cb, ch := recordingHandler()
+	ictx := hooktest.NewMockHookContext(nc, "orders", cb)
+	BeforeSubscribe(ictx, nc, "orders", cb)
+
+	// The original handler is left in place.
+	got, ok := ictx.GetParam(subscribeHandlerParamIndex).(nats.MsgHandler)
+	require.True(t, ok)
+	got(&nats.Msg{Subject: "orders"})
+	receive(t, ch)
+	assert.Empty(t, sr.Ended())
+}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/genai"
+)
+
+type recordingLogger struct {
+	embedded.Logger
+	records []log.Record
+	ctxs    []context.Context
+}
+
+func (l *recordingLogger) Emit(ctx context.Context, record log.Record) {
+	l.records = append(l.records, record)
+	l.ctxs = append(l.ctxs, ctx)
+}
+
+func (*recordingLogger) Enabled(context.Context, log.EnabledParameters) bool {
+	return true
+}
+
+func setupTestEventLogger(t *testing.T) *recordingLogger {
+	t.Helper()
+	t.Setenv("OTEL_INSTRUMENTATION_GENAI_CAPTURE_MESSAGE_CONTENT", "true")
+	logger := &recordingLogger{}
+	eventLogger = logger
+	t.Cleanup(func() { eventLogger = nil })
+	return logger
+}
+
+// eventMessages returns the messages of attribute key of the single recorded
+// event as role and parts maps.
+func eventMessages(t *testing.T, logger *recordingLogger, key string) []map[string]log.Value {
+	t.Helper()
+	require.Len(t, logger.records, 1)
+	var messages []map[string]log.Value
+	logger.records[0].WalkAttributes(func(kv log.KeyValue) bool {
+		if kv.Key == key {
+			for _, v := range kv.Value.AsSlice() {
+				messages = append(messages, logMap(v))
+			}
+		}
+		return true
+	})
+	return messages
+}
+
+func logMap(v log.Value) map[string]log.Value {
+	m := map[string]log.Value{}
+	for _, kv := range v.AsMap() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func messageParts(message map[string]log.Value) []map[string]log.Value {
+	var parts []map[string]log.Value
+	for _, v := range message["parts"].AsSlice() {
+		parts = append(parts, logMap(v))
+	}
+	return parts
+}
+
+func TestNewContentCapture(t *testing.T) {
+	assert.Nil(t, newContentCapture(genai.ContentConfig{}, opChat, []byte(`{}`)))
+	assert.NotNil(t, newContentCapture(genai.ContentConfig{Capture: true}, opCompletion, []byte(`{}`)))
+	assert.Nil(t, newContentCapture(genai.ContentConfig{Capture: true}, opEmbedding, []byte(`{}`)),
+		"embeddings carry no messages")
+}
+
+func TestOtelMiddleware_CaptureContent(t *testing.T) {
+	sr := setupTestTracer(t)
+	logger := setupTestEventLogger(t)
+
+	middleware := OtelMiddleware()
+
+	reqBody := `{"model":"gpt-4","messages":[{"role":"user","content":"Hello"}]}`
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.openai.com/v1/chat/completions",
+		io.NopCloser(bytes.NewReader([]byte(reqBody))),
+	)
+
+	respBody := `{"id":"chatcmpl-123","model":"gpt-4-0613","choices":[{"index":0,"message":{"role":"assistant","content":"Hi there!"},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":3,"total_tokens":8}}`
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(respBody))),
+		}, nil
+	}
+
+	_, err := middleware(req, next)
+	require.NoError(t, err)
+
+	require.Len(t, logger.records, 1)
+	record := logger.records[0]
+	assert.Equal(t, genai.InferenceDetailsEventName, record.EventName())
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, spans[0].SpanContext(), trace.SpanContextFromContext(logger.ctxs[0]),
+		"the event is correlated with the GenAI span")
+
+	input := eventMessages(t, logger, genai.GenAIInputMessagesKey)
+	require.Len(t, input, 1)
+	assert.Equal(t, "user", input[0]["role"].AsString())
+	assert.Equal(t, "Hello", messageParts(input[0])[0]["content"].AsString())
+
+	output := eventMessages(t, logger, genai.GenAIOutputMessagesKey)
+	require.Len(t, output, 1)
+	assert.Equal(t, "assistant", output[0]["role"].AsString())
+	assert.Equal(t, "stop", output[0]["finish_reason"].AsString())
+	assert.Equal(t, "Hi there!", messageParts(output[0])[0]["content"].AsString())
+}
+
+func TestOtelMiddleware_CaptureContent_Streaming(t *testing.T) {
+	setupTestTracer(t)
+	logger := setupTestEventLogger(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.openai.com/v1/chat/completions",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"gpt-4","stream":true,"messages":[{"role":"user","content":"Hello"}]}`))),
+	)
+
+	streamData := "data: {\"id\":\"chatcmpl-stream\",\"model\":\"gpt-4\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Hi\"},\"finish_reason\":null}]}\n\n" +
+		"data: {\"id\":\"chatcmpl-stream\",\"model\":\"gpt-4\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\" there\"},\"finish_reason\":\"stop\"}]}\n\n" +
+		"data: [DONE]\n\n"
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(streamData))),
+		}, nil
+	}
+
+	resp, err := middleware(req, next)
+	require.NoError(t, err)
+	assert.Empty(t, logger.records, "the event is emitted once the stream ends")
+
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	output := eventMessages(t, logger, genai.GenAIOutputMessagesKey)
+	require.Len(t, output, 1)
+	assert.Equal(t, "stop", output[0]["finish_reason"].AsString())
+	assert.Equal(t, "Hi there", messageParts(output[0])[0]["content"].AsString())
+}
+
+func TestOtelMiddleware_CaptureContent_Disabled(t *testing.T) {
+	setupTestTracer(t)
+	logger := setupTestEventLogger(t)
+	t.Setenv("OTEL_INSTRUMENTATION_GENAI_CAPTURE_MESSAGE_CONTENT", "")
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.openai.com/v1/chat/completions",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"gpt-4","messages":[{"role":"user","content":"Hello"}]}`))),
+	)
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"choices":[{"message":{"content":"Hi"}}]}`))),
+		}, nil
+	}
+
+	_, err := middleware(req, next)
+	require.NoError(t, err)
+	assert.Empty(t, logger.records)
+}
@@ -13,8 +13,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"go.opentelemetry.io/otelc/instrumentation/genai"
+	"go.opentelemetry.io/otelc/pkg/runtime"
 )
 
 func setupTestTracer(t *testing.T) *tracetest.SpanRecorder {
@@ -27,6 +33,71 @@ func setupTestTracer(t *testing.T) *tracetest.SpanRecorder {
 	return sr
 }
 
+func setupTestMeter(t *testing.T) *sdkmetric.ManualReader {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	var err error
+	metrics, err = genai.NewMetrics(mp.Meter("test"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		metrics = nil
+		_ = mp.Shutdown(context.Background())
+	})
+	return reader
+}
+
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) (metricdata.Metrics, bool) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+// tokenUsage returns the recorded token counts of gen_ai.client.token.usage
+// by gen_ai.token.type.
+func tokenUsage(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+	m, ok := collectMetric(t, reader, "gen_ai.client.token.usage")
+	if !ok {
+		return nil
+	}
+	hist, ok := m.Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	usage := map[string]int64{}
+	for _, dp := range hist.DataPoints {
+		tokenType, _ := dp.Attributes.Value(genai.GenAITokenTypeKey)
+		usage[tokenType.AsString()] += dp.Sum
+	}
+	return usage
+}
+
+// operationDuration returns the single data point of
+// gen_ai.client.operation.duration.
+func operationDuration(t *testing.T, reader *sdkmetric.ManualReader) metricdata.HistogramDataPoint[float64] {
+	t.Helper()
+	m, ok := collectMetric(t, reader, "gen_ai.client.operation.duration")
+	require.True(t, ok)
+	hist, ok := m.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+	return hist.DataPoints[0]
+}
+
+func assertMetricAttribute(t *testing.T, set attribute.Set, key, expected string) {
+	t.Helper()
+	v, ok := set.Value(attribute.Key(key))
+	require.True(t, ok, "attribute %s not found", key)
+	assert.Equal(t, expected, v.AsString())
+}
+
 func TestOtelMiddleware_ChatCompletion(t *testing.T) {
 	sr := setupTestTracer(t)
 
@@ -351,3 +422,208 @@ func TestOtelMiddleware_AzurePath(t *testing.T) {
 	assertAttribute(t, attrs, "gen_ai.operation.name", "chat")
 	assertAttribute(t, attrs, "gen_ai.provider.name", "azure")
 }
+
+func TestOtelMiddleware_Suppressed(t *testing.T) {
+	sr := setupTestTracer(t)
+
+	middleware := OtelMiddleware()
+
+	ctx := runtime.Suppress(context.Background(), "OPENAI")
+	req, _ := http.NewRequestWithContext(ctx,
+		"POST",
+		"http://api.openai.com/v1/chat/completions",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"gpt-4"}`))),
+	)
+
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{}`))),
+		}, nil
+	}
+
+	resp, err := middleware(req, next)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	assert.Empty(t, sr.Ended(), "suppressed context should skip instrumentation")
+}
+
+func TestOtelMiddleware_SuppressesHTTPClient(t *testing.T) {
+	setupTestTracer(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.openai.com/v1/chat/completions",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"gpt-4"}`))),
+	)
+
+	var nextCtx context.Context
+	next := func(r *http.Request) (*http.Response, error) {
+		nextCtx = r.Context()
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{}`))),
+		}, nil
+	}
+
+	_, err := middleware(req, next)
+	require.NoError(t, err)
+
+	require.NotNil(t, nextCtx)
+	assert.True(t, runtime.IsSuppressed(nextCtx, "NETHTTP"), "the HTTP client span should be suppressed below the GenAI span")
+}
+
+func TestOtelMiddleware_Metrics(t *testing.T) {
+	setupTestTracer(t)
+	reader := setupTestMeter(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.openai.com/v1/chat/completions",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"gpt-4","temperature":0.7}`))),
+	)
+
+	respBody := `{"id":"chatcmpl-123","model":"gpt-4-0613","choices":[{"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":20,"total_tokens":30}}`
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(respBody))),
+		}, nil
+	}
+
+	_, err := middleware(req, next)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{"input": 10, "output": 20}, tokenUsage(t, reader))
+
+	dp := operationDuration(t, reader)
+	assert.Equal(t, uint64(1), dp.Count)
+	assertMetricAttribute(t, dp.Attributes, "gen_ai.operation.name", "chat")
+	assertMetricAttribute(t, dp.Attributes, "gen_ai.provider.name", "openai")
+	assertMetricAttribute(t, dp.Attributes, "gen_ai.request.model", "gpt-4")
+	assertMetricAttribute(t, dp.Attributes, "gen_ai.response.model", "gpt-4-0613")
+	_, ok := dp.Attributes.Value("gen_ai.request.temperature")
+	assert.False(t, ok, "request parameters are not metric attributes")
+}
+
+func TestOtelMiddleware_Metrics_Embedding(t *testing.T) {
+	setupTestTracer(t)
+	reader := setupTestMeter(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.openai.com/v1/embeddings",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"text-embedding-ada-002","input":"hello world"}`))),
+	)
+
+	respBody := `{"model":"text-embedding-ada-002","usage":{"prompt_tokens":2,"total_tokens":2}}`
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(respBody))),
+		}, nil
+	}
+
+	_, err := middleware(req, next)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{"input": 2}, tokenUsage(t, reader))
+}
+
+func TestOtelMiddleware_Metrics_Streaming(t *testing.T) {
+	setupTestTracer(t)
+	reader := setupTestMeter(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.openai.com/v1/chat/completions",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"gpt-4","stream":true}`))),
+	)
+
+	streamData := "data: {\"id\":\"chatcmpl-stream\",\"model\":\"gpt-4\",\"choices\":[{\"delta\":{\"content\":\"Hello\"},\"finish_reason\":\"stop\"}],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":2,\"total_tokens\":7}}\n\ndata: [DONE]\n\n"
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(streamData))),
+		}, nil
+	}
+
+	resp, err := middleware(req, next)
+	require.NoError(t, err)
+
+	_, ok := collectMetric(t, reader, "gen_ai.client.operation.duration")
+	assert.False(t, ok, "streaming metrics are recorded once the stream ends")
+
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, map[string]int64{"input": 5, "output": 2}, tokenUsage(t, reader))
+	dp := operationDuration(t, reader)
+	assertMetricAttribute(t, dp.Attributes, "gen_ai.response.model", "gpt-4")
+}
+
+func TestOtelMiddleware_Metrics_HTTPError(t *testing.T) {
+	setupTestTracer(t)
+	reader := setupTestMeter(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.openai.com/v1/chat/completions",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"gpt-4"}`))),
+	)
+
+	next := func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 429,
+			Status:     "429 Too Many Requests",
+			Body:       io.NopCloser(bytes.NewReader(nil)),
+		}, nil
+	}
+
+	_, err := middleware(req, next)
+	require.NoError(t, err)
+
+	assert.Nil(t, tokenUsage(t, reader), "failed calls report no token usage")
+	dp := operationDuration(t, reader)
+	assertMetricAttribute(t, dp.Attributes, "error.type", "429 Too Many Requests")
+}
+
+func TestOtelMiddleware_Metrics_NextError(t *testing.T) {
+	setupTestTracer(t)
+	reader := setupTestMeter(t)
+
+	middleware := OtelMiddleware()
+
+	req, _ := http.NewRequest(
+		"POST",
+		"http://api.openai.com/v1/chat/completions",
+		io.NopCloser(bytes.NewReader([]byte(`{"model":"gpt-4"}`))),
+	)
+
+	next := func(r *http.Request) (*http.Response, error) {
+		return nil, assert.AnError
+	}
+
+	_, err := middleware(req, next)
+	require.Error(t, err)
+
+	dp := operationDuration(t, reader)
+	assertMetricAttribute(t, dp.Attributes, "error.type", "*errors.errorString")
+}
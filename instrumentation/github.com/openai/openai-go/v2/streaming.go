@@ -13,6 +13,7 @@ import (
 
 	"go.opentelemetry.io/otel/trace"
 
+	"go.opentelemetry.io/otelc/instrumentation/genai"
 	"go.opentelemetry.io/otelc/instrumentation/github.com/openai/openai-go/v2/semconv"
 )
 
@@ -34,6 +35,8 @@ type streamingReader struct {
 	opName        string
 	provider      string
 	op            operationType
+	ctx           context.Context
+	content       *genai.ChatCapture
 	done          atomic.Bool
 }
 
@@ -43,7 +46,7 @@ func newStreamingReader(
 	start time.Time,
 	model, opName, provider string,
 	op operationType,
-	_ context.Context,
+	ctx context.Context,
 ) *streamingReader {
 	return &streamingReader{
 		reader:   body,
@@ -53,6 +56,7 @@ func newStreamingReader(
 		opName:   opName,
 		provider: provider,
 		op:       op,
+		ctx:      ctx,
 	}
 }
 
@@ -105,6 +109,14 @@ func (r *streamingReader) finalize() {
 	}
 
 	r.span.End()
+
+	// Usage is only streamed when the request sets
+	// stream_options.include_usage; without it only the duration is recorded.
+	endCall(r.ctx, r.start, r.op, responseUsage{
+		model:        r.responseModel,
+		inputTokens:  r.inputTokens,
+		outputTokens: r.outputTokens,
+	}, r.content, metricAttributes(r.opName, r.provider, r.model))
 }
 
 func (r *streamingReader) processSSELines() {
@@ -170,6 +182,7 @@ func (r *streamingReader) processChunk(payload []byte) {
 	case opCompletion:
 		r.processCompletionChunk(payload)
 	}
+	r.content.AddChunk(payload)
 }
 
 func (r *streamingReader) processChatChunk(payload []byte) {
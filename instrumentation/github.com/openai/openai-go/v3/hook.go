@@ -8,8 +8,13 @@ import (
 
 	"github.com/openai/openai-go/v3/option"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log"
+	logglobal "go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 	"go.opentelemetry.io/otel/trace"
 
+	"go.opentelemetry.io/otelc/instrumentation/genai"
 	"go.opentelemetry.io/otelc/pkg/hook"
 	"go.opentelemetry.io/otelc/pkg/runtime"
 )
@@ -17,12 +22,18 @@ import (
 const (
 	instrumentationName = "go.opentelemetry.io/otelc/instrumentation/github.com/openai/openai-go/v3"
 	instrumentationKey  = "OPENAI"
+
+	// httpInstrumentationKey is the instrumentation key of the net/http
+	// instrumentation, suppressed below the GenAI spans.
+	httpInstrumentationKey = "NETHTTP"
 )
 
 var (
-	logger   = runtime.Logger()
-	tracer   trace.Tracer
-	initOnce sync.Once
+	logger      = runtime.Logger()
+	tracer      trace.Tracer
+	metrics     *genai.Metrics
+	eventLogger log.Logger
+	initOnce    sync.Once
 )
 
 type openaiEnabler struct{}
@@ -33,12 +44,34 @@ func (o openaiEnabler) Enable() bool {
 
 var enabler = openaiEnabler{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, enabler)
+}
+
 func initInstrumentation() {
 	initOnce.Do(func() {
+		version := runtime.ModuleVersion()
 		tracer = otel.GetTracerProvider().Tracer(
 			instrumentationName,
-			trace.WithInstrumentationVersion(runtime.ModuleVersion()),
+			trace.WithInstrumentationVersion(version),
+		)
+		meter := otel.GetMeterProvider().Meter(
+			instrumentationName,
+			metric.WithInstrumentationVersion(version),
+			metric.WithSchemaURL(semconv.SchemaURL),
 		)
+
+		eventLogger = logglobal.GetLoggerProvider().Logger(
+			instrumentationName,
+			log.WithInstrumentationVersion(version),
+			log.WithSchemaURL(semconv.SchemaURL),
+		)
+
+		var err error
+		metrics, err = genai.NewMetrics(meter)
+		if err != nil {
+			logger.Error("failed to create GenAI metrics", "error", err)
+		}
 		logger.Info("OpenAI v3 instrumentation initialized")
 	})
 }
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
+	"go.opentelemetry.io/otelc/instrumentation/genai"
 	"go.opentelemetry.io/otelc/instrumentation/github.com/openai/openai-go/v3/semconv"
 	"go.opentelemetry.io/otelc/pkg/runtime"
 )
@@ -82,6 +84,19 @@ func classifyOperation(path string) operationType {
 	return opUnknown
 }
 
+// newContentCapture returns the content capture of a call, or nil when
+// capture is disabled or the operation carries no messages.
+func newContentCapture(cfg genai.ContentConfig, op operationType, requestBody []byte) *genai.ChatCapture {
+	switch op {
+	case opChat:
+		return genai.NewChatCapture(cfg, requestBody)
+	case opCompletion:
+		return genai.NewCompletionCapture(cfg, requestBody)
+	default:
+		return nil
+	}
+}
+
 func operationName(op operationType) string {
 	switch op {
 	case opChat:
@@ -97,9 +112,14 @@ func operationName(op operationType) string {
 
 // OtelMiddleware returns an HTTP middleware that creates spans for OpenAI API
 // calls following GenAI semantic conventions.
+//
+// When OTEL_INSTRUMENTATION_GENAI_CAPTURE_MESSAGE_CONTENT is "true", the
+// messages of every chat and completion call are also emitted as a
+// gen_ai.client.inference.operation.details log event.
 func OtelMiddleware() func(*http.Request, func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	contentCfg := genai.ContentConfigFromEnv()
 	return func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
-		if req.Body == nil {
+		if req.Body == nil || runtime.IsSpanSuppressed(req.Context(), instrumentationKey, trace.SpanKindClient) {
 			return next(req)
 		}
 
@@ -145,13 +165,16 @@ func OtelMiddleware() func(*http.Request, func(*http.Request) (*http.Response, e
 			semconv.GenAIProviderName(provider),
 		}
 		spanAttrs = append(baseAttrs, spanAttrs...)
+		metricAttrs := metricAttributes(opName, provider, model)
+		content := newContentCapture(contentCfg, op, bodyBytes)
 
 		ctx := req.Context()
 		ctx, span := tracer.Start(ctx, spanName,
 			trace.WithSpanKind(trace.SpanKindClient),
 			trace.WithAttributes(spanAttrs...),
 		)
-		ctx = runtime.SuppressHTTPClientInstrumentation(ctx)
+		// The GenAI span replaces the span of the underlying HTTP request.
+		ctx = runtime.Suppress(ctx, httpInstrumentationKey)
 		req = req.WithContext(ctx)
 
 		resp, err := next(req)
@@ -159,6 +182,8 @@ func OtelMiddleware() func(*http.Request, func(*http.Request) (*http.Response, e
 			span.SetStatus(codes.Error, err.Error())
 			span.RecordError(err)
 			span.End()
+			endCall(ctx, start, op, responseUsage{}, content,
+				append(metricAttrs, attribute.String("error.type", fmt.Sprintf("%T", err))))
 			return resp, err
 		}
 
@@ -166,6 +191,8 @@ func OtelMiddleware() func(*http.Request, func(*http.Request) (*http.Response, e
 			span.SetStatus(codes.Error, resp.Status)
 			span.SetAttributes(attribute.String("error.type", resp.Status))
 			span.End()
+			endCall(ctx, start, op, responseUsage{}, content,
+				append(metricAttrs, attribute.String("error.type", resp.Status)))
 			return resp, nil
 		}
 
@@ -174,9 +201,11 @@ func OtelMiddleware() func(*http.Request, func(*http.Request) (*http.Response, e
 
 		if isStreaming {
 			span.SetAttributes(semconv.GenAIRequestIsStream(true))
-			resp.Body = newStreamingReader(resp.Body, span, start, model, opName, provider, op, ctx)
+			streaming := newStreamingReader(resp.Body, span, start, model, opName, provider, op, ctx)
+			streaming.content = content
+			resp.Body = streaming
 		} else {
-			handleNonStreamingResponse(ctx, resp, span, start, op)
+			handleNonStreamingResponse(ctx, resp, span, start, op, metricAttrs, content)
 		}
 
 		return resp, nil
@@ -184,13 +213,19 @@ func OtelMiddleware() func(*http.Request, func(*http.Request) (*http.Response, e
 }
 
 func handleNonStreamingResponse(
-	_ context.Context,
+	ctx context.Context,
 	resp *http.Response,
 	span trace.Span,
-	_ time.Time,
+	start time.Time,
 	op operationType,
+	metricAttrs []attribute.KeyValue,
+	content *genai.ChatCapture,
 ) {
-	defer span.End()
+	var usage responseUsage
+	defer func() {
+		span.End()
+		endCall(ctx, start, op, usage, content, metricAttrs)
+	}()
 
 	// Read a bounded preview for parsing, but reassemble the full body for callers.
 	var buf bytes.Buffer
@@ -207,11 +242,57 @@ func handleNonStreamingResponse(
 
 	switch op {
 	case opChat:
-		parseChatResponse(bodyBytes, span)
+		usage = parseChatResponse(bodyBytes, span)
 	case opCompletion:
-		parseCompletionResponse(bodyBytes, span)
+		usage = parseCompletionResponse(bodyBytes, span)
 	case opEmbedding:
-		parseEmbeddingResponse(bodyBytes, span)
+		usage = parseEmbeddingResponse(bodyBytes, span)
+	}
+	content.AddResponse(bodyBytes)
+}
+
+// responseUsage is the part of a response recorded on the GenAI metrics.
+type responseUsage struct {
+	model        string
+	inputTokens  int64
+	outputTokens int64
+}
+
+// metricAttributes returns the attributes shared by the GenAI metrics of an
+// operation. Per-request attributes such as the sampling parameters are left
+// out to keep metric cardinality bounded.
+func metricAttributes(opName, provider, model string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.GenAIOperationName(opName),
+		semconv.GenAIProviderName(provider),
+		semconv.GenAIRequestModel(model),
+	}
+}
+
+// endCall records the duration of an operation started at start and, when
+// the response reported it, its token usage, then emits the captured content
+// of the call. Embeddings produce no output tokens, so only their input
+// tokens are recorded.
+func endCall(
+	ctx context.Context,
+	start time.Time,
+	op operationType,
+	usage responseUsage,
+	content *genai.ChatCapture,
+	attrs []attribute.KeyValue,
+) {
+	if usage.model != "" {
+		attrs = append(attrs[:len(attrs):len(attrs)], semconv.GenAIResponseModel(usage.model))
+	}
+	metrics.RecordOperationDuration(ctx, time.Since(start), attrs)
+	content.Emit(ctx, eventLogger, attrs)
+
+	if usage.inputTokens == 0 && usage.outputTokens == 0 {
+		return
+	}
+	metrics.RecordTokenUsage(ctx, genai.GenAITokenTypeInput, usage.inputTokens, attrs)
+	if op != opEmbedding {
+		metrics.RecordTokenUsage(ctx, genai.GenAITokenTypeOutput, usage.outputTokens, attrs)
 	}
 }
 
@@ -281,7 +362,7 @@ func parseEmbeddingRequest(body []byte) (string, []attribute.KeyValue) {
 	return req.Model, nil
 }
 
-func parseChatResponse(body []byte, span trace.Span) {
+func parseChatResponse(body []byte, span trace.Span) responseUsage {
 	var resp struct {
 		ID      string `json:"id"`
 		Model   string `json:"model"`
@@ -295,7 +376,7 @@ func parseChatResponse(body []byte, span trace.Span) {
 		} `json:"usage"`
 	}
 	if err := json.Unmarshal(body, &resp); err != nil {
-		return
+		return responseUsage{}
 	}
 
 	var reasons []string
@@ -313,9 +394,14 @@ func parseChatResponse(body []byte, span trace.Span) {
 		semconv.GenAIUsageOutputTokens(resp.Usage.CompletionTokens),
 		semconv.GenAIUsageTotalTokens(resp.Usage.TotalTokens),
 	)
+	return responseUsage{
+		model:        resp.Model,
+		inputTokens:  resp.Usage.PromptTokens,
+		outputTokens: resp.Usage.CompletionTokens,
+	}
 }
 
-func parseCompletionResponse(body []byte, span trace.Span) {
+func parseCompletionResponse(body []byte, span trace.Span) responseUsage {
 	var resp struct {
 		ID      string `json:"id"`
 		Model   string `json:"model"`
@@ -329,7 +415,7 @@ func parseCompletionResponse(body []byte, span trace.Span) {
 		} `json:"usage"`
 	}
 	if err := json.Unmarshal(body, &resp); err != nil {
-		return
+		return responseUsage{}
 	}
 
 	var reasons []string
@@ -347,9 +433,14 @@ func parseCompletionResponse(body []byte, span trace.Span) {
 		semconv.GenAIUsageOutputTokens(resp.Usage.CompletionTokens),
 		semconv.GenAIUsageTotalTokens(resp.Usage.TotalTokens),
 	)
+	return responseUsage{
+		model:        resp.Model,
+		inputTokens:  resp.Usage.PromptTokens,
+		outputTokens: resp.Usage.CompletionTokens,
+	}
 }
 
-func parseEmbeddingResponse(body []byte, span trace.Span) {
+func parseEmbeddingResponse(body []byte, span trace.Span) responseUsage {
 	var resp struct {
 		Model string `json:"model"`
 		Usage struct {
@@ -358,7 +449,7 @@ func parseEmbeddingResponse(body []byte, span trace.Span) {
 		} `json:"usage"`
 	}
 	if err := json.Unmarshal(body, &resp); err != nil {
-		return
+		return responseUsage{}
 	}
 
 	span.SetAttributes(
@@ -366,4 +457,5 @@ func parseEmbeddingResponse(body []byte, span trace.Span) {
 		semconv.GenAIUsageInputTokens(resp.Usage.PromptTokens),
 		semconv.GenAIUsageTotalTokens(resp.Usage.TotalTokens),
 	)
+	return responseUsage{model: resp.Model, inputTokens: resp.Usage.PromptTokens}
 }
@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package amqp091
+
+import (
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// headerCarrier adapts an amqp.Table of message headers to the OpenTelemetry
+// TextMapCarrier interface so trace context can be propagated through AMQP
+// message headers.
+type headerCarrier struct {
+	headers amqp.Table
+}
+
+// Get returns the value of the header matching key, or "" if absent or not a
+// string.
+func (c headerCarrier) Get(key string) string {
+	value, _ := c.headers[key].(string)
+	return value
+}
+
+// Set replaces any existing header with key.
+func (c headerCarrier) Set(key, value string) {
+	c.headers[key] = value
+}
+
+// Keys lists the header keys carried by this carrier.
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.headers))
+	for key := range c.headers {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package amqp091
+
+import (
+	"context"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+// -----------------------------------------------------------------------------
+// Consumer: (*amqp.Channel).Consume(queue, consumer, ...) and
+// (*amqp.Channel).ConsumeWithContext(ctx, queue, consumer, ...)
+// -----------------------------------------------------------------------------
+
+// BeforeConsume remembers the consumed queue for AfterConsume.
+func BeforeConsume(
+	ictx hook.HookContext,
+	_ *amqp.Channel,
+	queue, _ string,
+	_, _, _, _ bool,
+	_ amqp.Table,
+) {
+	if !rabbitmqEnabler.Enable() {
+		logger.Debug("RabbitMQ instrumentation disabled")
+		return
+	}
+	initInstrumentation()
+
+	ictx.SetData(queue)
+}
+
+// AfterConsume replaces the delivery channel returned to the application with
+// one that emits a receive span per delivery.
+func AfterConsume(ictx hook.HookContext, deliveries <-chan amqp.Delivery, err error) {
+	queue, ok := ictx.GetData().(string)
+	if !ok || err != nil || deliveries == nil {
+		return
+	}
+	ictx.SetReturnVal(0, traceDeliveries(queue, deliveries))
+}
+
+// BeforeConsumeWithContext mirrors BeforeConsume for consumers cancelled
+// through a context.
+func BeforeConsumeWithContext(
+	ictx hook.HookContext,
+	ch *amqp.Channel,
+	ctx context.Context,
+	queue, consumer string,
+	autoAck, exclusive, noLocal, noWait bool,
+	args amqp.Table,
+) {
+	if runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindConsumer) {
+		return
+	}
+	BeforeConsume(ictx, ch, queue, consumer, autoAck, exclusive, noLocal, noWait, args)
+}
+
+// AfterConsumeWithContext mirrors AfterConsume.
+func AfterConsumeWithContext(ictx hook.HookContext, deliveries <-chan amqp.Delivery, err error) {
+	AfterConsume(ictx, deliveries, err)
+}
+
+// traceDeliveries forwards the deliveries of a consumer, emitting a receive
+// span for each one as it is handed to the application. The returned channel
+// is closed once the consumer's channel is.
+func traceDeliveries(queue string, deliveries <-chan amqp.Delivery) <-chan amqp.Delivery {
+	out := make(chan amqp.Delivery)
+	go func() {
+		defer close(out)
+		for d := range deliveries {
+			receive(queue, &d)
+			out <- d
+		}
+	}()
+	return out
+}
+
+// receive emits the receive span for d and replaces the trace context in its
+// headers with the span's, so ExtractContext continues the trace below the
+// receive span.
+//
+// The span is parented on the producer context carried in the message headers
+// and also links to it, so backends that only follow links still connect the
+// consumer to the producer.
+func receive(queue string, d *amqp.Delivery) {
+	if d.Headers == nil {
+		d.Headers = amqp.Table{}
+	}
+	carrier := headerCarrier{headers: d.Headers}
+
+	req := amqpRequest{
+		destination:   queue,
+		operation:     operationReceive,
+		routingKey:    d.RoutingKey,
+		messageID:     d.MessageId,
+		correlationID: d.CorrelationId,
+		deliveryTag:   d.DeliveryTag,
+		bodySize:      len(d.Body),
+	}
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(req.traceAttrs()...),
+	}
+	parent := propagator.Extract(context.Background(), carrier)
+	if sc := trace.SpanContextFromContext(parent); sc.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+	ctx, span := tracer.Start(parent, req.spanName(), opts...)
+	propagator.Inject(ctx, carrier)
+	span.End()
+
+	if consumedMessages.Inst() != nil {
+		consumedMessages.AddSet(ctx, 1, attribute.NewSet(req.metricAttrs(nil)...))
+	}
+}
+
+// ExtractContext extracts the trace context from a delivery's headers and
+// returns a context.Context that carries the propagated span context.
+//
+// For deliveries read from an instrumented Consume channel, the headers carry
+// the receive span, so spans created with the returned context are its
+// children:
+//
+//	for d := range deliveries {
+//		ctx := otelcamqp.ExtractContext(d)
+//		// spans created with ctx will be children of the receive span.
+//	}
+func ExtractContext(d amqp.Delivery) context.Context {
+	initInstrumentation()
+	if d.Headers == nil {
+		return context.Background()
+	}
+	return propagator.Extract(context.Background(), headerCarrier{headers: d.Headers})
+}
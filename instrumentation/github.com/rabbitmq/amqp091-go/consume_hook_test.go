@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package amqp091
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+// consume runs the Consume hooks the way the injected trampoline would around
+// a consumer whose deliveries are sent on the returned channel, and returns
+// the delivery channel handed to the application.
+func consume(t *testing.T, queue string) (chan amqp.Delivery, <-chan amqp.Delivery) {
+	t.Helper()
+	src := make(chan amqp.Delivery)
+	ictx := hooktest.NewMockHookContext((*amqp.Channel)(nil), queue, "", false, false, false, false, amqp.Table(nil))
+	ictx.ReturnVals = []interface{}{(<-chan amqp.Delivery)(src), nil}
+	BeforeConsume(ictx, nil, queue, "", false, false, false, false, nil)
+	AfterConsume(ictx, src, nil)
+
+	deliveries, ok := ictx.GetReturnVal(0).(<-chan amqp.Delivery)
+	require.True(t, ok)
+	return src, deliveries
+}
+
+func next(t *testing.T, deliveries <-chan amqp.Delivery) amqp.Delivery {
+	t.Helper()
+	select {
+	case d, ok := <-deliveries:
+		require.True(t, ok, "delivery channel closed")
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery not forwarded")
+		return amqp.Delivery{}
+	}
+}
+
+func TestConsume_ContinuesProducerTrace(t *testing.T) {
+	sr, reader := setupTest(t)
+
+	ictx, sent := beforePublish(t, context.Background(), "orders", "orders.created", amqp.Publishing{})
+	AfterPublishWithContext(ictx, nil)
+	producer := sr.Ended()[0]
+
+	src, deliveries := consume(t, "billing")
+	go func() {
+		src <- amqp.Delivery{
+			Headers:     sent.Headers,
+			Exchange:    "orders",
+			RoutingKey:  "orders.created",
+			DeliveryTag: 7,
+			Body:        []byte("hello"),
+		}
+	}()
+	d := next(t, deliveries)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	span := spans[1]
+	assert.Equal(t, "billing receive", span.Name())
+	assert.Equal(t, trace.SpanKindConsumer, span.SpanKind())
+	assert.Equal(t, producer.SpanContext().SpanID(), span.Parent().SpanID())
+	require.Len(t, span.Links(), 1)
+	assert.Equal(t, producer.SpanContext().SpanID(), span.Links()[0].SpanContext.SpanID())
+
+	attrs := attrMap(span.Attributes())
+	assert.Equal(t, "rabbitmq", attrs[semconv.MessagingSystemKey].AsString())
+	assert.Equal(t, "receive", attrs[semconv.MessagingOperationTypeKey].AsString())
+	assert.Equal(t, "billing", attrs[semconv.MessagingDestinationNameKey].AsString())
+	assert.Equal(t, "orders.created", attrs[semconv.MessagingRabbitMQDestinationRoutingKeyKey].AsString())
+	assert.Equal(t, int64(7), attrs[semconv.MessagingRabbitMQMessageDeliveryTagKey].AsInt64())
+
+	// The application continues the trace below the receive span.
+	assert.Equal(t, span.SpanContext().SpanID(), trace.SpanContextFromContext(ExtractContext(d)).SpanID())
+	assert.Equal(t, "hello", string(d.Body))
+
+	m, ok := collectMetric(t, reader, "messaging.client.consumed.messages")
+	require.True(t, ok)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+
+	close(src)
+	select {
+	case _, ok := <-deliveries:
+		assert.False(t, ok, "delivery channel must be closed with the consumer's")
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery channel not closed")
+	}
+}
+
+func TestConsume_WithoutTraceContext(t *testing.T) {
+	sr, _ := setupTest(t)
+
+	src, deliveries := consume(t, "billing")
+	go func() { src <- amqp.Delivery{} }()
+	d := next(t, deliveries)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.False(t, spans[0].Parent().IsValid())
+	assert.Empty(t, spans[0].Links())
+	assert.Contains(t, d.Headers, "traceparent")
+}
+
+func TestConsume_Disabled(t *testing.T) {
+	sr, _ := setupTest(t)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "rabbitmq")
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "")
+
+	src := make(chan amqp.Delivery, 1)
+	ictx := hooktest.NewMockHookContext((*amqp.Channel)(nil), "billing", "", false, false, false, false, amqp.Table(nil))
+	ictx.ReturnVals = []interface{}{(<-chan amqp.Delivery)(src), nil}
+	BeforeConsume(ictx, nil, "billing", "", false, false, false, false, nil)
+	AfterConsume(ictx, src, nil)
+
+	deliveries, ok := ictx.GetReturnVal(0).(<-chan amqp.Delivery)
+	require.True(t, ok)
+	assert.Equal(t, (<-chan amqp.Delivery)(src), deliveries)
+	assert.Empty(t, sr.Ended())
+}
+
+func TestConsumeWithContext_Suppressed(t *testing.T) {
+	sr, _ := setupTest(t)
+
+	ctx := runtime.Suppress(context.Background(), "RABBITMQ")
+	src := make(chan amqp.Delivery, 1)
+	ictx := hooktest.NewMockHookContext((*amqp.Channel)(nil), ctx, "billing", "", false, false, false, false, amqp.Table(nil))
+	ictx.ReturnVals = []interface{}{(<-chan amqp.Delivery)(src), nil}
+	BeforeConsumeWithContext(ictx, nil, ctx, "billing", "", false, false, false, false, nil)
+	AfterConsumeWithContext(ictx, src, nil)
+
+	deliveries, ok := ictx.GetReturnVal(0).(<-chan amqp.Delivery)
+	require.True(t, ok)
+	assert.Equal(t, (<-chan amqp.Delivery)(src), deliveries)
+	assert.Empty(t, sr.Ended())
+}
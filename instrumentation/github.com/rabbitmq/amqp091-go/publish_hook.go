@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package amqp091 instruments the RabbitMQ client
+// github.com/rabbitmq/amqp091-go. Published messages get a producer span whose
+// trace context travels in the message headers, and every delivery read from
+// the channel returned by Consume gets a receive span continuing the trace of
+// the producer.
+package amqp091
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	otelsemconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/messagingconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+const (
+	instrumentationName = "go.opentelemetry.io/otelc/instrumentation/github.com/rabbitmq/amqp091-go"
+	instrumentationKey  = "RABBITMQ"
+
+	// publishMsgParamIndex is the index of the message in
+	// (*Channel).PublishWithContext(ctx, exchange, key, mandatory, immediate,
+	// msg), after the receiver.
+	publishMsgParamIndex = 6
+)
+
+// rabbitmqEnablerImpl controls whether the RabbitMQ instrumentation is enabled.
+type rabbitmqEnablerImpl struct{}
+
+func (rabbitmqEnablerImpl) Enable() bool {
+	return runtime.Instrumented(instrumentationKey)
+}
+
+var rabbitmqEnabler = rabbitmqEnablerImpl{}
+
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, rabbitmqEnabler)
+	runtime.RegisterUnsuppressible(instrumentationKey, "Channel.Consume")
+}
+
+var (
+	logger     = runtime.Logger()
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	meter      metric.Meter
+	initOnce   sync.Once
+
+	// Metrics
+	sentMessages      messagingconv.ClientSentMessages
+	operationDuration messagingconv.ClientOperationDuration
+	consumedMessages  messagingconv.ClientConsumedMessages
+)
+
+func initInstrumentation() {
+	initOnce.Do(func() {
+		version := runtime.ModuleVersion()
+		tracer = otel.GetTracerProvider().Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(version),
+		)
+		propagator = otel.GetTextMapPropagator()
+		meter = otel.GetMeterProvider().Meter(
+			instrumentationName,
+			metric.WithInstrumentationVersion(version),
+			metric.WithSchemaURL(otelsemconv.SchemaURL),
+		)
+
+		var err error
+		sentMessages, err = messagingconv.NewClientSentMessages(meter)
+		if err != nil {
+			logger.Error("failed to create sent messages metric", "error", err)
+		}
+		operationDuration, err = messagingconv.NewClientOperationDuration(meter)
+		if err != nil {
+			logger.Error("failed to create operation duration metric", "error", err)
+		}
+		consumedMessages, err = messagingconv.NewClientConsumedMessages(meter)
+		if err != nil {
+			logger.Error("failed to create consumed messages metric", "error", err)
+		}
+
+		logger.Info("RabbitMQ (rabbitmq/amqp091-go) instrumentation initialized")
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Producer: (*amqp.Channel).PublishWithContext(ctx, exchange, key, mandatory,
+// immediate, msg) and its PublishWithDeferredConfirmWithContext counterpart
+// -----------------------------------------------------------------------------
+
+type publishData struct {
+	ctx   context.Context
+	span  trace.Span
+	req   amqpRequest
+	start time.Time
+}
+
+// BeforePublishWithContext starts a producer span and hands a copy of the
+// message whose headers carry the span's trace context to the original call.
+// The headers of the caller are never modified: they are commonly shared by
+// every message published.
+func BeforePublishWithContext(
+	ictx hook.HookContext,
+	_ *amqp.Channel,
+	ctx context.Context,
+	exchange, key string,
+	_, _ bool,
+	msg amqp.Publishing,
+) {
+	if !rabbitmqEnabler.Enable() {
+		logger.Debug("RabbitMQ instrumentation disabled")
+		return
+	}
+	if ctx == nil || runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindProducer) {
+		return
+	}
+	initInstrumentation()
+
+	destination := exchange
+	if destination == "" {
+		destination = defaultExchange
+	}
+	req := amqpRequest{
+		destination:   destination,
+		operation:     operationPublish,
+		routingKey:    key,
+		messageID:     msg.MessageId,
+		correlationID: msg.CorrelationId,
+		bodySize:      len(msg.Body),
+	}
+	start := time.Now()
+	spanCtx, span := tracer.Start(ctx, req.spanName(),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(req.traceAttrs()...),
+	)
+
+	headers := make(amqp.Table, len(msg.Headers)+2)
+	maps.Copy(headers, msg.Headers)
+	propagator.Inject(spanCtx, headerCarrier{headers: headers})
+	msg.Headers = headers
+	ictx.SetParam(publishMsgParamIndex, msg)
+	ictx.SetData(&publishData{ctx: spanCtx, span: span, req: req, start: start})
+}
+
+// AfterPublishWithContext ends the producer span started by
+// BeforePublishWithContext.
+func AfterPublishWithContext(ictx hook.HookContext, err error) {
+	endPublish(ictx, err)
+}
+
+// BeforePublishWithDeferredConfirmWithContext mirrors BeforePublishWithContext
+// for publishers waiting on publisher confirms.
+func BeforePublishWithDeferredConfirmWithContext(
+	ictx hook.HookContext,
+	ch *amqp.Channel,
+	ctx context.Context,
+	exchange, key string,
+	mandatory, immediate bool,
+	msg amqp.Publishing,
+) {
+	BeforePublishWithContext(ictx, ch, ctx, exchange, key, mandatory, immediate, msg)
+}
+
+// AfterPublishWithDeferredConfirmWithContext ends the producer span started by
+// BeforePublishWithDeferredConfirmWithContext. The span covers the publish
+// only, not the wait for the confirmation.
+func AfterPublishWithDeferredConfirmWithContext(ictx hook.HookContext, _ *amqp.DeferredConfirmation, err error) {
+	endPublish(ictx, err)
+}
+
+// endPublish ends the producer span and records the publish metrics.
+func endPublish(ictx hook.HookContext, err error) {
+	data, ok := ictx.GetData().(*publishData)
+	if !ok || data == nil {
+		return
+	}
+	if err != nil {
+		data.span.RecordError(err)
+		data.span.SetStatus(codes.Error, err.Error())
+	}
+	data.span.End()
+
+	set := attribute.NewSet(data.req.metricAttrs(err)...)
+	if operationDuration.Inst() != nil {
+		operationDuration.RecordSet(data.ctx, time.Since(data.start).Seconds(), set)
+	}
+	if err == nil && sentMessages.Inst() != nil {
+		sentMessages.AddSet(data.ctx, 1, set)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package amqp091
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/messagingconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+func setupTest(t *testing.T) (*tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "rabbitmq")
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	// Consume initOnce so initInstrumentation() becomes a no-op and does not
+	// overwrite the tracer/propagator/instruments we install below.
+	initOnce.Do(func() {})
+	tracer = tp.Tracer("test")
+	propagator = propagation.TraceContext{}
+	meter = mp.Meter("test")
+	var err error
+	sentMessages, err = messagingconv.NewClientSentMessages(meter)
+	require.NoError(t, err)
+	operationDuration, err = messagingconv.NewClientOperationDuration(meter)
+	require.NoError(t, err)
+	consumedMessages, err = messagingconv.NewClientConsumedMessages(meter)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		_ = mp.Shutdown(context.Background())
+		initOnce = sync.Once{}
+		tracer = nil
+		propagator = nil
+		meter = nil
+		sentMessages = messagingconv.ClientSentMessages{}
+		operationDuration = messagingconv.ClientOperationDuration{}
+		consumedMessages = messagingconv.ClientConsumedMessages{}
+	})
+	return sr, reader
+}
+
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) (metricdata.Metrics, bool) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+func attrMap(attrs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(attrs))
+	for _, kv := range attrs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+// beforePublish runs BeforePublishWithContext the way the injected trampoline
+// would and returns the hook context and the message the publish receives.
+func beforePublish(
+	t *testing.T,
+	ctx context.Context,
+	exchange, key string,
+	msg amqp.Publishing,
+) (*hooktest.MockHookContext, amqp.Publishing) {
+	t.Helper()
+	ictx := hooktest.NewMockHookContext((*amqp.Channel)(nil), ctx, exchange, key, false, false, msg)
+	BeforePublishWithContext(ictx, nil, ctx, exchange, key, false, false, msg)
+	sent, ok := ictx.GetParam(publishMsgParamIndex).(amqp.Publishing)
+	require.True(t, ok)
+	return ictx, sent
+}
+
+func TestPublishWithContext(t *testing.T) {
+	sr, reader := setupTest(t)
+
+	parentCtx, parent := tracer.Start(context.Background(), "parent")
+	shared := amqp.Table{"order-id": "42"}
+	ictx, sent := beforePublish(t, parentCtx, "orders", "orders.created", amqp.Publishing{
+		Headers:       shared,
+		MessageId:     "m-1",
+		CorrelationId: "c-1",
+		Body:          []byte("hello"),
+	})
+	AfterPublishWithContext(ictx, nil)
+	parent.End()
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, "orders publish", span.Name())
+	assert.Equal(t, trace.SpanKindProducer, span.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+
+	attrs := attrMap(span.Attributes())
+	assert.Equal(t, "rabbitmq", attrs[semconv.MessagingSystemKey].AsString())
+	assert.Equal(t, "publish", attrs[semconv.MessagingOperationNameKey].AsString())
+	assert.Equal(t, "send", attrs[semconv.MessagingOperationTypeKey].AsString())
+	assert.Equal(t, "orders", attrs[semconv.MessagingDestinationNameKey].AsString())
+	assert.Equal(t, "orders.created", attrs[semconv.MessagingRabbitMQDestinationRoutingKeyKey].AsString())
+	assert.Equal(t, "m-1", attrs[semconv.MessagingMessageIDKey].AsString())
+	assert.Equal(t, "c-1", attrs[semconv.MessagingMessageConversationIDKey].AsString())
+	assert.Equal(t, int64(5), attrs[semconv.MessagingMessageBodySizeKey].AsInt64())
+
+	assert.Equal(t, "42", sent.Headers["order-id"])
+	sc := trace.SpanContextFromContext(
+		propagator.Extract(context.Background(), headerCarrier{headers: sent.Headers}))
+	assert.Equal(t, span.SpanContext().SpanID(), sc.SpanID())
+	assert.Equal(t, amqp.Table{"order-id": "42"}, shared, "caller headers must not be modified")
+	require.NoError(t, sent.Headers.Validate())
+
+	m, ok := collectMetric(t, reader, "messaging.client.sent.messages")
+	require.True(t, ok)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+	_, hasRoutingKey := sum.DataPoints[0].Attributes.Value(semconv.MessagingRabbitMQDestinationRoutingKeyKey)
+	assert.False(t, hasRoutingKey)
+
+	_, ok = collectMetric(t, reader, "messaging.client.operation.duration")
+	assert.True(t, ok)
+}
+
+func TestPublishWithContext_DefaultExchange(t *testing.T) {
+	sr, _ := setupTest(t)
+
+	ictx, sent := beforePublish(t, context.Background(), "", "jobs", amqp.Publishing{})
+	AfterPublishWithContext(ictx, nil)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "amq.default publish", spans[0].Name())
+	assert.Contains(t, sent.Headers, "traceparent")
+}
+
+func TestPublishWithDeferredConfirmWithContext_Error(t *testing.T) {
+	sr, reader := setupTest(t)
+
+	msg := amqp.Publishing{Body: []byte("hello")}
+	ictx := hooktest.NewMockHookContext((*amqp.Channel)(nil), context.Background(), "orders", "", false, false, msg)
+	BeforePublishWithDeferredConfirmWithContext(ictx, nil, context.Background(), "orders", "", false, false, msg)
+	AfterPublishWithDeferredConfirmWithContext(ictx, nil, amqp.ErrClosed)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+
+	_, ok := collectMetric(t, reader, "messaging.client.sent.messages")
+	assert.False(t, ok, "failed publishes are not counted as sent")
+
+	m, ok := collectMetric(t, reader, "messaging.client.operation.duration")
+	require.True(t, ok)
+	hist, ok := m.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+	errType, ok := hist.DataPoints[0].Attributes.Value(semconv.ErrorTypeKey)
+	require.True(t, ok)
+	assert.Equal(t, "*amqp091.Error", errType.AsString())
+}
+
+func TestPublishWithContext_Disabled(t *testing.T) {
+	sr, _ := setupTest(t)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "rabbitmq")
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "")
+
+	_, sent := beforePublish(t, context.Background(), "orders", "", amqp.Publishing{})
+
+	assert.Nil(t, sent.Headers)
+	assert.Empty(t, sr.Ended())
+}
+
+func TestPublishWithContext_Suppressed(t *testing.T) {
+	sr, _ := setupTest(t)
+
+	ctx := runtime.Suppress(context.Background(), runtime.SpanKindKey(trace.SpanKindProducer))
+	_, sent := beforePublish(t, ctx, "orders", "", amqp.Publishing{})
+
+	assert.Nil(t, sent.Headers)
+	assert.Empty(t, sr.Ended())
+}
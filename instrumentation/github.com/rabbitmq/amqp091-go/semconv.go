@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package amqp091
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+
+	httpsemconv "go.opentelemetry.io/otelc/instrumentation/net/http/semconv"
+)
+
+// defaultExchange is the destination name of messages published to the
+// default exchange, whose name is empty.
+const defaultExchange = "amq.default"
+
+// amqpOperation identifies the messaging operation performed by the client.
+type amqpOperation string
+
+const (
+	// operationPublish is the operation name for publishing a message.
+	operationPublish amqpOperation = "publish"
+	// operationReceive is the operation name for a delivery handed to the
+	// application by a consumer.
+	operationReceive amqpOperation = "receive"
+)
+
+// amqpRequest carries the information needed to build the semantic convention
+// attributes for a single RabbitMQ client operation.
+type amqpRequest struct {
+	// destination is the exchange a message is published to, or the queue
+	// it is consumed from.
+	destination string
+	// operation is the messaging operation.
+	operation amqpOperation
+	// routingKey is the routing key of the message.
+	routingKey string
+	// messageID is the message-id property of the message.
+	messageID string
+	// correlationID is the correlation-id property of the message.
+	correlationID string
+	// deliveryTag is the delivery tag of a delivery (receive only).
+	deliveryTag uint64
+	// bodySize is the size of the message body in bytes.
+	bodySize int
+}
+
+// spanName builds the span name from the destination and the operation.
+func (req amqpRequest) spanName() string {
+	return req.destination + " " + string(req.operation)
+}
+
+// traceAttrs returns the span attributes of the operation.
+func (req amqpRequest) traceAttrs() []attribute.KeyValue {
+	attrs := req.baseAttrs()
+	if req.routingKey != "" {
+		attrs = append(attrs, semconv.MessagingRabbitMQDestinationRoutingKey(req.routingKey))
+	}
+	if req.messageID != "" {
+		attrs = append(attrs, semconv.MessagingMessageID(req.messageID))
+	}
+	if req.correlationID != "" {
+		attrs = append(attrs, semconv.MessagingMessageConversationID(req.correlationID))
+	}
+	if req.deliveryTag > 0 {
+		attrs = append(attrs, semconv.MessagingRabbitMQMessageDeliveryTag(int(req.deliveryTag)))
+	}
+	if req.bodySize > 0 {
+		attrs = append(attrs, semconv.MessagingMessageBodySize(req.bodySize))
+	}
+	return attrs
+}
+
+// metricAttrs returns the metric attributes of the operation. Per-message
+// attributes such as the routing key are left out to keep metric cardinality
+// bounded; error.type is added when err is non-nil.
+func (req amqpRequest) metricAttrs(err error) []attribute.KeyValue {
+	attrs := req.baseAttrs()
+	if err != nil {
+		attrs = append(attrs, httpsemconv.ErrorType(err))
+	}
+	return attrs
+}
+
+// baseAttrs returns the attributes shared by spans and metrics.
+func (req amqpRequest) baseAttrs() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystemRabbitMQ,
+		semconv.MessagingOperationName(string(req.operation)),
+		semconv.MessagingDestinationName(req.destination),
+	}
+	switch req.operation {
+	case operationPublish:
+		attrs = append(attrs, semconv.MessagingOperationTypeSend)
+	case operationReceive:
+		attrs = append(attrs, semconv.MessagingOperationTypeReceive)
+	}
+	return attrs
+}
@@ -24,24 +24,37 @@ func (g redisClientEnabler) Enable() bool {
 
 var redisEnabler = redisClientEnabler{}
 
-func afterNewRedisClientV9(ictx hook.HookContext, client *redis.Client) {
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, redisEnabler)
+}
+
+// instrumentClient adds the tracing hook to client and reports the metrics of
+// its connection pool. Ring and cluster nodes are created through NewClient
+// and announced again by OnNewNode, so a client is only instrumented once.
+func instrumentClient(client *redis.Client) {
+	if client == nil || !trackPool(client) {
+		return
+	}
 	client.AddHook(newOtelRedisHook(client.Options().Addr))
+	if redisEnabler.Enable() {
+		initInstrumentation()
+	}
+}
+
+func afterNewRedisClientV9(ictx hook.HookContext, client *redis.Client) {
+	instrumentClient(client)
 }
 
 func afterNewFailOverRedisClientV9(call hook.HookContext, client *redis.Client) {
-	client.AddHook(newOtelRedisHook(client.Options().Addr))
+	instrumentClient(client)
 }
 
 func afterNewRingClientV9(call hook.HookContext, client *redis.Ring) {
-	client.OnNewNode(func(rdb *redis.Client) {
-		rdb.AddHook(newOtelRedisHook(rdb.Options().Addr))
-	})
+	client.OnNewNode(instrumentClient)
 }
 
 func afterNewClusterClientV9(call hook.HookContext, client *redis.ClusterClient) {
-	client.OnNewNode(func(rdb *redis.Client) {
-		rdb.AddHook(newOtelRedisHook(rdb.Options().Addr))
-	})
+	client.OnNewNode(instrumentClient)
 }
 
 func afterNewSentinelClientV9(call hook.HookContext, client *redis.SentinelClient) {
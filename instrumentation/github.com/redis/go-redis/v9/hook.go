@@ -15,24 +15,48 @@ import (
 	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	otelsemconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/dbconv"
 	"go.opentelemetry.io/otel/trace"
 
-	"go.opentelemetry.io/otelc/instrumentation/github.com/redis/go-redis/v9/semconv"
+	"go.opentelemetry.io/otelc/instrumentation/github.com/redis/semconv"
 	"go.opentelemetry.io/otelc/pkg/runtime"
 )
 
 var (
 	logger   = runtime.Logger()
 	tracer   trace.Tracer
+	meter    metric.Meter
 	initOnce sync.Once
+
+	// Metrics
+	operationDuration dbconv.ClientOperationDuration
 )
 
 func initInstrumentation() {
 	initOnce.Do(func() {
+		version := runtime.ModuleVersion()
 		tracer = otel.GetTracerProvider().Tracer(
 			instrumentationName,
-			trace.WithInstrumentationVersion(runtime.ModuleVersion()),
+			trace.WithInstrumentationVersion(version),
+		)
+		meter = otel.GetMeterProvider().Meter(
+			instrumentationName,
+			metric.WithInstrumentationVersion(version),
+			metric.WithSchemaURL(otelsemconv.SchemaURL),
 		)
+
+		var err error
+		operationDuration, err = dbconv.NewClientOperationDuration(meter)
+		if err != nil {
+			logger.Error("failed to create operation duration metric", "error", err)
+		}
+
+		if err = registerPoolMetrics(meter); err != nil {
+			logger.Error("failed to create connection pool metrics", "error", err)
+		}
+
 		logger.Info("Redis v9 client instrumentation initialized")
 	})
 }
@@ -53,6 +77,9 @@ func (o *otelRedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
 			logger.Debug("Redis Client instrumentation disabled")
 			return next(ctx, cmd)
 		}
+		if runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient) {
+			return next(ctx, cmd)
+		}
 		initInstrumentation()
 		fullName := cmd.FullName()
 		request := semconv.RedisRequest{
@@ -65,6 +92,7 @@ func (o *otelRedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
 
 		// Start span
 		spanName := request.FullName
+		start := time.Now()
 		ctx, span := tracer.Start(ctx,
 			spanName,
 			trace.WithSpanKind(trace.SpanKindClient),
@@ -76,6 +104,7 @@ func (o *otelRedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
 		if err != nil && !errors.Is(err, redis.Nil) {
 			span.SetStatus(codes.Error, err.Error())
 		}
+		recordOperation(ctx, request, start, err)
 		return err
 	}
 }
@@ -86,6 +115,9 @@ func (o *otelRedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redi
 			logger.Debug("Redis Client instrumentation disabled")
 			return next(ctx, cmds)
 		}
+		if runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient) {
+			return next(ctx, cmds)
+		}
 		initInstrumentation()
 
 		summary := ""
@@ -112,6 +144,7 @@ func (o *otelRedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redi
 
 		// Start span
 		spanName := request.FullName
+		start := time.Now()
 		ctx, span := tracer.Start(ctx,
 			spanName,
 			trace.WithSpanKind(trace.SpanKindClient),
@@ -123,6 +156,8 @@ func (o *otelRedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redi
 		if err != nil && !errors.Is(err, redis.Nil) {
 			span.SetStatus(codes.Error, err.Error())
 		}
+		// A pipeline is recorded as a single operation.
+		recordOperation(ctx, request, start, err)
 		return err
 	}
 }
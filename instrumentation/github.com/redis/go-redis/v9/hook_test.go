@@ -18,6 +18,8 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"go.opentelemetry.io/otelc/pkg/runtime"
 )
 
 func setupTestTracer(t *testing.T) *tracetest.SpanRecorder {
@@ -251,6 +253,27 @@ func TestProcessHook_Disabled(t *testing.T) {
 	assert.Len(t, spans, 0, "no spans should be created when instrumentation is disabled")
 }
 
+func TestProcessHook_Suppressed(t *testing.T) {
+	initOnce = *new(sync.Once)
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+
+	sr := setupTestTracer(t)
+
+	hook := newOtelRedisHook("localhost:6379")
+	called := false
+	processHook := hook.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		called = true
+		return nil
+	})
+
+	ctx := runtime.Suppress(context.Background(), "REDIS")
+	cmd := redis.NewCmd(ctx, "get", "mykey")
+	require.NoError(t, processHook(ctx, cmd))
+
+	assert.True(t, called)
+	assert.Empty(t, sr.Ended(), "no spans should be created for a suppressed context")
+}
+
 func TestProcessPipelineHook_CreatesSpan(t *testing.T) {
 	initOnce = *new(sync.Once)
 	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v9
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+)
+
+// setupTestMeter installs an in-memory meter provider and resets the
+// instrumentation so that the next hook call creates its instruments on it.
+func setupTestMeter(t *testing.T) *sdkmetric.ManualReader {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	otel.SetMeterProvider(mp)
+	initOnce = *new(sync.Once)
+	t.Cleanup(func() {
+		_ = mp.Shutdown(context.Background())
+		initOnce = *new(sync.Once)
+		poolsMu.Lock()
+		pools = nil
+		poolsMu.Unlock()
+	})
+	return reader
+}
+
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) (metricdata.Metrics, bool) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+// gaugeValues maps the value of the given attribute to the data point value of
+// an int64 sum.
+func gaugeValues(t *testing.T, m metricdata.Metrics, key attribute.Key) map[string]int64 {
+	t.Helper()
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok, "%s is not an int64 sum", m.Name)
+	values := make(map[string]int64)
+	for _, dp := range sum.DataPoints {
+		v, _ := dp.Attributes.Value(key)
+		values[v.AsString()] = dp.Value
+	}
+	return values
+}
+
+// serverError is a Redis server error reply.
+type serverError string
+
+func (e serverError) Error() string { return string(e) }
+func (serverError) RedisError()     {}
+
+func TestProcessHook_RecordsOperationDuration(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+	setupTestTracer(t)
+	reader := setupTestMeter(t)
+
+	hook := newOtelRedisHook("localhost:6379")
+	ok := hook.ProcessHook(func(context.Context, redis.Cmder) error { return nil })
+	miss := hook.ProcessHook(func(context.Context, redis.Cmder) error { return redis.Nil })
+	failed := hook.ProcessHook(func(context.Context, redis.Cmder) error {
+		return serverError("WRONGTYPE Operation against a key holding the wrong kind of value")
+	})
+
+	require.NoError(t, ok(context.Background(), redis.NewCmd(context.Background(), "get", "k")))
+	require.ErrorIs(t, miss(context.Background(), redis.NewCmd(context.Background(), "get", "k")), redis.Nil)
+	require.Error(t, failed(context.Background(), redis.NewCmd(context.Background(), "incr", "k")))
+
+	m, found := collectMetric(t, reader, "db.client.operation.duration")
+	require.True(t, found)
+	hist, isHist := m.Data.(metricdata.Histogram[float64])
+	require.True(t, isHist)
+
+	counts := make(map[string]uint64)
+	for _, dp := range hist.DataPoints {
+		op, _ := dp.Attributes.Value("db.operation.name")
+		errType, _ := dp.Attributes.Value("error.type")
+		counts[op.AsString()+"/"+errType.AsString()] += dp.Count
+
+		assert.False(t, dp.Attributes.HasValue("db.query.text"))
+		addr, _ := dp.Attributes.Value("server.address")
+		assert.Equal(t, "localhost", addr.AsString())
+	}
+	// redis.Nil is a cache miss, not a failure.
+	assert.Equal(t, map[string]uint64{"get/": 2, "incr/WRONGTYPE": 1}, counts)
+}
+
+func TestProcessPipelineHook_RecordsOperationDuration(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+	setupTestTracer(t)
+	reader := setupTestMeter(t)
+
+	hook := newOtelRedisHook("localhost:6379")
+	pipeline := hook.ProcessPipelineHook(func(context.Context, []redis.Cmder) error {
+		return errors.New("i/o timeout")
+	})
+	cmds := []redis.Cmder{
+		redis.NewCmd(context.Background(), "set", "a", "1"),
+		redis.NewCmd(context.Background(), "set", "b", "2"),
+	}
+	require.Error(t, pipeline(context.Background(), cmds))
+
+	m, found := collectMetric(t, reader, "db.client.operation.duration")
+	require.True(t, found)
+	hist := m.Data.(metricdata.Histogram[float64])
+	require.Len(t, hist.DataPoints, 1, "a pipeline is a single operation")
+	dp := hist.DataPoints[0]
+	assert.Equal(t, uint64(1), dp.Count)
+	op, _ := dp.Attributes.Value("db.operation.name")
+	assert.Equal(t, "pipeline", op.AsString())
+	errType, _ := dp.Attributes.Value("error.type")
+	assert.Equal(t, "*errors.errorString", errType.AsString())
+}
+
+func TestTrackPool_ReportsPoolMetrics(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+	reader := setupTestMeter(t)
+
+	client := redis.NewClient(&redis.Options{
+		Addr:         "localhost:6379",
+		PoolSize:     7,
+		MaxIdleConns: 3,
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	afterNewRedisClientV9(hooktest.NewMockHookContext(), client)
+
+	m, found := collectMetric(t, reader, "db.client.connection.count")
+	require.True(t, found)
+	assert.Equal(t, map[string]int64{"idle": 0, "used": 0},
+		gaugeValues(t, m, "db.client.connection.state"))
+
+	m, found = collectMetric(t, reader, "db.client.connection.max")
+	require.True(t, found)
+	assert.Equal(t, map[string]int64{"localhost:6379": 7},
+		gaugeValues(t, m, "db.client.connection.pool.name"))
+
+	m, found = collectMetric(t, reader, "db.client.connection.idle.max")
+	require.True(t, found)
+	assert.Equal(t, map[string]int64{"localhost:6379": 3},
+		gaugeValues(t, m, "db.client.connection.pool.name"))
+
+	_, found = collectMetric(t, reader, "db.client.connection.timeouts")
+	assert.True(t, found)
+}
+
+func TestTrackPool_Disabled(t *testing.T) {
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "redis")
+	reader := setupTestMeter(t)
+
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	t.Cleanup(func() { _ = client.Close() })
+	afterNewRedisClientV9(hooktest.NewMockHookContext(), client)
+
+	_, found := collectMetric(t, reader, "db.client.connection.count")
+	assert.False(t, found)
+}
+
+func TestTrackPool_InstrumentsClientOnce(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+	reader := setupTestMeter(t)
+
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379", PoolSize: 5})
+	t.Cleanup(func() { _ = client.Close() })
+	// A ring node is instrumented by NewClient and again by OnNewNode.
+	afterNewRedisClientV9(hooktest.NewMockHookContext(), client)
+	instrumentClient(client)
+
+	m, found := collectMetric(t, reader, "db.client.connection.max")
+	require.True(t, found)
+	assert.Equal(t, map[string]int64{"localhost:6379": 5},
+		gaugeValues(t, m, "db.client.connection.pool.name"))
+}
+
+func TestSnapshotPools_AggregatesAndDropsCollected(t *testing.T) {
+	setupTestMeter(t)
+
+	stats := func(total, idle uint32) func() *redis.PoolStats {
+		return func() *redis.PoolStats {
+			return &redis.PoolStats{TotalConns: total, IdleConns: idle, Timeouts: 1}
+		}
+	}
+	poolsMu.Lock()
+	pools = []*poolSource{
+		{name: "a:6379", stats: stats(4, 1), max: 10},
+		{name: "a:6379", stats: stats(2, 2), max: 10},
+		{name: "b:6379", stats: func() *redis.PoolStats { return nil }, max: 10},
+	}
+	poolsMu.Unlock()
+
+	usage := snapshotPools()
+	require.Len(t, usage, 1)
+	assert.Equal(t, &poolUsage{idle: 3, used: 3, timeouts: 2, max: 20}, usage["a:6379"])
+
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	assert.Len(t, pools, 2, "the collected client is forgotten")
+}
+
+func TestRedisErrorType(t *testing.T) {
+	assert.Equal(t, "ERR", redisErrorType(serverError("ERR unknown command")))
+	assert.Equal(t, "NOAUTH", redisErrorType(serverError("NOAUTH")))
+	assert.Equal(t, "context.deadlineExceededError", redisErrorType(context.DeadlineExceeded))
+}
@@ -0,0 +1,261 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package rueidis
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redis/rueidis"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	otelsemconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/dbconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/github.com/redis/semconv"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+var (
+	logger   = runtime.Logger()
+	tracer   trace.Tracer
+	meter    metric.Meter
+	initOnce sync.Once
+
+	// Metrics
+	operationDuration dbconv.ClientOperationDuration
+)
+
+func initInstrumentation() {
+	initOnce.Do(func() {
+		version := runtime.ModuleVersion()
+		tracer = otel.GetTracerProvider().Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(version),
+		)
+		meter = otel.GetMeterProvider().Meter(
+			instrumentationName,
+			metric.WithInstrumentationVersion(version),
+			metric.WithSchemaURL(otelsemconv.SchemaURL),
+		)
+
+		var err error
+		operationDuration, err = dbconv.NewClientOperationDuration(meter)
+		if err != nil {
+			logger.Error("failed to create operation duration metric", "error", err)
+		}
+
+		logger.Info("rueidis client instrumentation initialized")
+	})
+}
+
+// otelClient traces the commands sent through a rueidis.Client. Commands are
+// read before they are sent, as rueidis recycles them once they complete.
+// Streaming commands (DoStream, DoMultiStream) pass through untraced.
+type otelClient struct {
+	rueidis.Client
+	addr string
+}
+
+func newOtelClient(client rueidis.Client, addr string) *otelClient {
+	return &otelClient{
+		Client: client,
+		addr:   addr,
+	}
+}
+
+func (c *otelClient) Do(ctx context.Context, cmd rueidis.Completed) rueidis.RedisResult {
+	return doCommand(ctx, c.addr, cmd.Commands(), func(ctx context.Context) rueidis.RedisResult {
+		return c.Client.Do(ctx, cmd)
+	})
+}
+
+func (c *otelClient) DoMulti(ctx context.Context, multi ...rueidis.Completed) []rueidis.RedisResult {
+	names := make([]string, len(multi))
+	for i := range multi {
+		names[i] = commandName(multi[i].Commands())
+	}
+	return doPipeline(ctx, c.addr, names, func(ctx context.Context) []rueidis.RedisResult {
+		return c.Client.DoMulti(ctx, multi...)
+	})
+}
+
+func (c *otelClient) DoCache(ctx context.Context, cmd rueidis.Cacheable, ttl time.Duration) rueidis.RedisResult {
+	return doCommand(ctx, c.addr, cmd.Commands(), func(ctx context.Context) rueidis.RedisResult {
+		return c.Client.DoCache(ctx, cmd, ttl)
+	})
+}
+
+func (c *otelClient) DoMultiCache(ctx context.Context, multi ...rueidis.CacheableTTL) []rueidis.RedisResult {
+	names := make([]string, len(multi))
+	for i := range multi {
+		names[i] = commandName(multi[i].Cmd.Commands())
+	}
+	return doPipeline(ctx, c.addr, names, func(ctx context.Context) []rueidis.RedisResult {
+		return c.Client.DoMultiCache(ctx, multi...)
+	})
+}
+
+func (c *otelClient) Dedicated(fn func(rueidis.DedicatedClient) error) error {
+	return c.Client.Dedicated(func(client rueidis.DedicatedClient) error {
+		return fn(&otelDedicatedClient{DedicatedClient: client, addr: c.addr})
+	})
+}
+
+func (c *otelClient) Dedicate() (rueidis.DedicatedClient, func()) {
+	client, cancel := c.Client.Dedicate()
+	return &otelDedicatedClient{DedicatedClient: client, addr: c.addr}, cancel
+}
+
+// otelDedicatedClient traces the commands sent over a dedicated connection.
+type otelDedicatedClient struct {
+	rueidis.DedicatedClient
+	addr string
+}
+
+func (c *otelDedicatedClient) Do(ctx context.Context, cmd rueidis.Completed) rueidis.RedisResult {
+	return doCommand(ctx, c.addr, cmd.Commands(), func(ctx context.Context) rueidis.RedisResult {
+		return c.DedicatedClient.Do(ctx, cmd)
+	})
+}
+
+func (c *otelDedicatedClient) DoMulti(ctx context.Context, multi ...rueidis.Completed) []rueidis.RedisResult {
+	names := make([]string, len(multi))
+	for i := range multi {
+		names[i] = commandName(multi[i].Commands())
+	}
+	return doPipeline(ctx, c.addr, names, func(ctx context.Context) []rueidis.RedisResult {
+		return c.DedicatedClient.DoMulti(ctx, multi...)
+	})
+}
+
+func doCommand(
+	ctx context.Context,
+	addr string,
+	commands []string,
+	do func(context.Context) rueidis.RedisResult,
+) rueidis.RedisResult {
+	if !redisEnabler.Enable() {
+		logger.Debug("Redis Client instrumentation disabled")
+		return do(ctx)
+	}
+	if runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient) {
+		return do(ctx)
+	}
+	initInstrumentation()
+
+	request := semconv.RedisRequest{
+		Endpoint:  addr,
+		FullName:  commandName(commands),
+		Statement: getRueidisStatement(commands),
+	}
+	ctx, span, start := startOperation(ctx, request)
+	defer span.End()
+
+	resp := do(ctx)
+	endOperation(ctx, span, request, start, resp.Error())
+	return resp
+}
+
+func doPipeline(
+	ctx context.Context,
+	addr string,
+	names []string,
+	do func(context.Context) []rueidis.RedisResult,
+) []rueidis.RedisResult {
+	if !redisEnabler.Enable() {
+		logger.Debug("Redis Client instrumentation disabled")
+		return do(ctx)
+	}
+	if runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient) {
+		return do(ctx)
+	}
+	initInstrumentation()
+
+	summary := ""
+	summaryNames := names
+	if len(summaryNames) > 10 {
+		summaryNames = summaryNames[:10]
+	}
+	for _, name := range summaryNames {
+		summary += name + "/"
+	}
+	if len(names) > 10 {
+		summary += "..."
+	}
+	request := semconv.RedisRequest{
+		Endpoint:  addr,
+		FullName:  "pipeline",
+		Statement: "pipeline " + summary,
+	}
+	ctx, span, start := startOperation(ctx, request)
+	defer span.End()
+
+	resps := do(ctx)
+	// A pipeline is recorded as a single operation that fails with its first
+	// failed command.
+	var err error
+	for _, resp := range resps {
+		if respErr := resp.Error(); respErr != nil && !rueidis.IsRedisNil(respErr) {
+			err = respErr
+			break
+		}
+	}
+	endOperation(ctx, span, request, start, err)
+	return resps
+}
+
+func startOperation(ctx context.Context, request semconv.RedisRequest) (context.Context, trace.Span, time.Time) {
+	// Get trace attributes from semconv
+	attrs := semconv.RedisClientRequestTraceAttrs(request)
+
+	// Start span
+	start := time.Now()
+	ctx, span := tracer.Start(ctx,
+		request.FullName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, span, start
+}
+
+func endOperation(ctx context.Context, span trace.Span, request semconv.RedisRequest, start time.Time, err error) {
+	if err != nil && !rueidis.IsRedisNil(err) {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	recordOperation(ctx, request, start, err)
+}
+
+// commandName returns the lower-cased command name, as go-redis reports it.
+func commandName(commands []string) string {
+	if len(commands) == 0 {
+		return ""
+	}
+	return strings.ToLower(commands[0])
+}
+
+func getRueidisStatement(commands []string) string {
+	b := make([]byte, 0, 64)
+
+	for i, arg := range commands {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		switch {
+		case i == 0:
+			b = append(b, strings.ToLower(arg)...)
+		case utf8.ValidString(arg):
+			b = append(b, arg...)
+		default:
+			b = append(b, "<string>"...)
+		}
+	}
+
+	return string(b)
+}
@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package rueidis
+
+import (
+	"github.com/redis/rueidis"
+
+	"go.opentelemetry.io/otelc/pkg/hook"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+const (
+	instrumentationName = "go.opentelemetry.io/otelc/instrumentation/github.com/redis/rueidis"
+	instrumentationKey  = "REDIS"
+)
+
+// redisClientEnabler controls whether client instrumentation is enabled
+type redisClientEnabler struct{}
+
+func (g redisClientEnabler) Enable() bool {
+	return runtime.Instrumented(instrumentationKey)
+}
+
+var redisEnabler = redisClientEnabler{}
+
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, redisEnabler)
+}
+
+// beforeNewClient keeps the first address the client bootstraps from, which
+// is reported as the server of its commands.
+func beforeNewClient(ictx hook.HookContext, option rueidis.ClientOption) {
+	if len(option.InitAddress) > 0 {
+		ictx.SetData(option.InitAddress[0])
+	}
+}
+
+// afterNewClient replaces the returned client with one that traces its
+// commands. rueidis has no hook registry like go-redis, so the client is
+// wrapped instead.
+func afterNewClient(ictx hook.HookContext, client rueidis.Client, err error) {
+	if err != nil || client == nil {
+		return
+	}
+	addr, _ := ictx.GetData().(string)
+	ictx.SetReturnVal(0, newOtelClient(client, addr))
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package rueidis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+)
+
+func TestRedisClientEnabler(t *testing.T) {
+	tests := []struct {
+		name     string
+		setupEnv func(t *testing.T)
+		expected bool
+	}{
+		{
+			name: "enabled explicitly",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+			},
+			expected: true,
+		},
+		{
+			name: "disabled explicitly",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "redis")
+			},
+			expected: false,
+		},
+		{
+			name: "default enabled when no env set",
+			setupEnv: func(t *testing.T) {
+				// No environment variables set - should be enabled by default
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupEnv(t)
+
+			enabler := redisClientEnabler{}
+			assert.Equal(t, tt.expected, enabler.Enable())
+		})
+	}
+}
+
+func TestNewClientHooks_WrapClient(t *testing.T) {
+	server := startRedisServer(t)
+	client, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{server.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	ictx := hooktest.NewMockHookContext()
+	ictx.ReturnVals = []interface{}{client, nil}
+	beforeNewClient(ictx, rueidis.ClientOption{InitAddress: []string{server.Addr(), "other:6379"}})
+	afterNewClient(ictx, client, nil)
+
+	wrapped, ok := ictx.GetReturnVal(0).(*otelClient)
+	require.True(t, ok)
+	assert.Equal(t, server.Addr(), wrapped.addr)
+	assert.Same(t, client, wrapped.Client)
+}
+
+func TestNewClientHooks_Error(t *testing.T) {
+	ictx := hooktest.NewMockHookContext()
+	ictx.ReturnVals = []interface{}{nil, errors.New("dial failed")}
+	beforeNewClient(ictx, rueidis.ClientOption{})
+	afterNewClient(ictx, nil, errors.New("dial failed"))
+
+	assert.Nil(t, ictx.GetReturnVal(0))
+}
@@ -0,0 +1,216 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package rueidis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+func startRedisServer(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	return miniredis.RunT(t)
+}
+
+// setupTest installs in-memory tracer and meter providers and returns a traced
+// client connected to a fresh server.
+func setupTest(t *testing.T) (*otelClient, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	otel.SetTracerProvider(tp)
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	otel.SetMeterProvider(mp)
+	initOnce = *new(sync.Once)
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		_ = mp.Shutdown(context.Background())
+		initOnce = *new(sync.Once)
+	})
+
+	server := startRedisServer(t)
+	client, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{server.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return newOtelClient(client, "localhost:6379"), sr, reader
+}
+
+func spanAttrs(span sdktrace.ReadOnlySpan) map[string]interface{} {
+	attrs := make(map[string]interface{})
+	for _, attr := range span.Attributes() {
+		attrs[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	return attrs
+}
+
+func TestDo_CreatesSpan(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+	client, sr, _ := setupTest(t)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	require.NoError(t, client.Do(ctx, client.B().Set().Key("mykey").Value("myvalue").Build()).Error())
+	parent.End()
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, "set", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+
+	attrs := spanAttrs(span)
+	assert.Equal(t, "redis", attrs["db.system.name"])
+	assert.Equal(t, "set", attrs["db.operation.name"])
+	assert.Equal(t, "set mykey myvalue", attrs["db.query.text"])
+	assert.Equal(t, "localhost", attrs["server.address"])
+	assert.Equal(t, int64(6379), attrs["server.port"])
+}
+
+func TestDo_RecordsError(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+	client, sr, _ := setupTest(t)
+
+	ctx := context.Background()
+	require.NoError(t, client.Do(ctx, client.B().Set().Key("k").Value("v").Build()).Error())
+	err := client.Do(ctx, client.B().Lpush().Key("k").Element("v").Build()).Error()
+	require.Error(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Contains(t, spans[1].Status().Description, "WRONGTYPE")
+}
+
+func TestDo_RedisNilNotError(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+	client, sr, _ := setupTest(t)
+
+	err := client.Do(context.Background(), client.B().Get().Key("missing").Build()).Error()
+	require.True(t, rueidis.IsRedisNil(err))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestDo_Disabled(t *testing.T) {
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "redis")
+	client, sr, _ := setupTest(t)
+
+	require.NoError(t, client.Do(context.Background(), client.B().Ping().Build()).Error())
+
+	assert.Empty(t, sr.Ended(), "no spans should be created when instrumentation is disabled")
+}
+
+func TestDo_Suppressed(t *testing.T) {
+	client, sr, _ := setupTest(t)
+
+	ctx := runtime.Suppress(context.Background(), "REDIS")
+	require.NoError(t, client.Do(ctx, client.B().Ping().Build()).Error())
+	require.NoError(t, client.DoMulti(ctx, client.B().Ping().Build())[0].Error())
+
+	assert.Empty(t, sr.Ended(), "no spans should be created for a suppressed context")
+}
+
+func TestDoMulti_CreatesSpan(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+	client, sr, _ := setupTest(t)
+
+	cmds := make(rueidis.Commands, 0, 12)
+	for range 11 {
+		cmds = append(cmds, client.B().Set().Key("k").Value("v").Build())
+	}
+	cmds = append(cmds, client.B().Lpush().Key("k").Element("v").Build())
+	resps := client.DoMulti(context.Background(), cmds...)
+	require.Len(t, resps, 12)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1, "a pipeline is a single span")
+	span := spans[0]
+	assert.Equal(t, "pipeline", span.Name())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Status().Description, "WRONGTYPE")
+	assert.Equal(t, "pipeline set/set/set/set/set/set/set/set/set/set/...",
+		spanAttrs(span)["db.query.text"])
+}
+
+func TestDedicated_CreatesSpans(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+	client, sr, _ := setupTest(t)
+
+	err := client.Dedicated(func(c rueidis.DedicatedClient) error {
+		return c.Do(context.Background(), c.B().Ping().Build()).Error()
+	})
+	require.NoError(t, err)
+
+	dedicated, cancel := client.Dedicate()
+	defer cancel()
+	require.NoError(t, dedicated.Do(context.Background(), dedicated.B().Ping().Build()).Error())
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "ping", spans[0].Name())
+	assert.Equal(t, "ping", spans[1].Name())
+}
+
+func TestDo_RecordsOperationDuration(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "redis")
+	client, _, reader := setupTest(t)
+
+	ctx := context.Background()
+	require.NoError(t, client.Do(ctx, client.B().Set().Key("k").Value("v").Build()).Error())
+	require.Error(t, client.Do(ctx, client.B().Lpush().Key("k").Element("v").Build()).Error())
+	require.True(t, rueidis.IsRedisNil(client.Do(ctx, client.B().Get().Key("missing").Build()).Error()))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	m := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "db.client.operation.duration", m.Name)
+	hist, ok := m.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+
+	counts := make(map[string]uint64)
+	for _, dp := range hist.DataPoints {
+		op, _ := dp.Attributes.Value("db.operation.name")
+		errType, _ := dp.Attributes.Value("error.type")
+		counts[op.AsString()+"/"+errType.AsString()] += dp.Count
+		assert.False(t, dp.Attributes.HasValue(attribute.Key("db.query.text")))
+	}
+	// A nil reply is a cache miss, not a failure.
+	assert.Equal(t, map[string]uint64{"set/": 1, "lpush/WRONGTYPE": 1, "get/": 1}, counts)
+}
+
+func TestGetRueidisStatement(t *testing.T) {
+	assert.Equal(t, "set k v", getRueidisStatement([]string{"SET", "k", "v"}))
+	assert.Equal(t, "set k <string>", getRueidisStatement([]string{"SET", "k", string([]byte{0xff, 0xfe})}))
+	assert.Empty(t, getRueidisStatement(nil))
+}
+
+func TestRedisErrorType(t *testing.T) {
+	assert.Equal(t, "*errors.errorString", redisErrorType(errors.New("i/o timeout")))
+	assert.Equal(t, "context.deadlineExceededError", redisErrorType(context.DeadlineExceeded))
+}
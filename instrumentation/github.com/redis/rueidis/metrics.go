@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package rueidis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/rueidis"
+	"go.opentelemetry.io/otel/attribute"
+
+	"go.opentelemetry.io/otelc/instrumentation/github.com/redis/semconv"
+)
+
+// recordOperation records db.client.operation.duration for a command or
+// pipeline that started at start. rueidis does not expose the state of its
+// connection pools, so no connection metrics are reported.
+func recordOperation(ctx context.Context, req semconv.RedisRequest, start time.Time, err error) {
+	errType := ""
+	if err != nil && !rueidis.IsRedisNil(err) {
+		errType = redisErrorType(err)
+	}
+	attrs := semconv.RedisClientRequestMetricAttrs(req, errType)
+	operationDuration.RecordSet(ctx, time.Since(start).Seconds(), attribute.NewSet(attrs...))
+}
+
+// redisErrorType returns the error prefix of a Redis server error, such as
+// "WRONGTYPE" or "ERR", and the Go type of any other error.
+func redisErrorType(err error) string {
+	if redisErr, ok := rueidis.IsRedisErr(err); ok {
+		msg := redisErr.Error()
+		if prefix, _, found := strings.Cut(msg, " "); found {
+			return prefix
+		}
+		return msg
+	}
+	return fmt.Sprintf("%T", err)
+}
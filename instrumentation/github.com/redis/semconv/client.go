@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package semconv builds the attributes shared by the Redis client
+// instrumentations (go-redis v8 and v9, rueidis).
+package semconv
+
+import (
+	"net"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+type RedisRequest struct {
+	Endpoint  string
+	FullName  string
+	Statement string
+}
+
+// RedisClientRequestTraceAttrs returns trace attributes for a Redis client request.
+func RedisClientRequestTraceAttrs(req RedisRequest) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemNameRedis,
+		semconv.DBOperationName(req.FullName),
+	}
+	attrs = append(attrs, serverAttrs(req.Endpoint)...)
+	return append(attrs,
+		semconv.NetworkTransportTCP,
+		semconv.DBQueryText(req.Statement),
+	)
+}
+
+// RedisClientRequestMetricAttrs returns metric attributes for a Redis client
+// request. The query text is left out to keep the cardinality bounded.
+// errorType is empty for successful requests.
+func RedisClientRequestMetricAttrs(req RedisRequest, errorType string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemNameRedis,
+		semconv.DBOperationName(req.FullName),
+	}
+	attrs = append(attrs, serverAttrs(req.Endpoint)...)
+	if errorType != "" {
+		attrs = append(attrs, semconv.ErrorTypeKey.String(errorType))
+	}
+	return attrs
+}
+
+// RedisPoolMetricAttrs returns the attributes identifying a connection pool.
+func RedisPoolMetricAttrs(poolName string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.DBSystemNameRedis,
+		semconv.DBClientConnectionPoolName(poolName),
+	}
+}
+
+func serverAttrs(endpoint string) []attribute.KeyValue {
+	host, portStr, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return []attribute.KeyValue{semconv.ServerAddress(endpoint)}
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServerAddress(host)}
+	if port, convErr := strconv.Atoi(portStr); convErr == nil && port > 0 {
+		attrs = append(attrs, semconv.ServerPort(port))
+	}
+	return attrs
+}
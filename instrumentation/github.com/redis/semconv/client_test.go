@@ -169,3 +169,39 @@ func TestRedisClientRequestTraceAttrs_ContainsNetworkTransportTCP(t *testing.T)
 	}
 	assert.True(t, found, "should contain network.transport=tcp attribute")
 }
+
+func TestRedisClientRequestMetricAttrs(t *testing.T) {
+	req := RedisRequest{
+		Endpoint:  "localhost:6379",
+		FullName:  "get",
+		Statement: "get secret-key",
+	}
+
+	attrMap := make(map[string]interface{})
+	for _, attr := range RedisClientRequestMetricAttrs(req, "") {
+		attrMap[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	assert.Equal(t, map[string]interface{}{
+		"db.system.name":    "redis",
+		"db.operation.name": "get",
+		"server.address":    "localhost",
+		"server.port":       int64(6379),
+	}, attrMap, "the query text must not be a metric attribute")
+
+	attrMap = make(map[string]interface{})
+	for _, attr := range RedisClientRequestMetricAttrs(req, "WRONGTYPE") {
+		attrMap[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	assert.Equal(t, "WRONGTYPE", attrMap["error.type"])
+}
+
+func TestRedisPoolMetricAttrs(t *testing.T) {
+	attrMap := make(map[string]interface{})
+	for _, attr := range RedisPoolMetricAttrs("localhost:6379") {
+		attrMap[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	assert.Equal(t, map[string]interface{}{
+		"db.system.name":                 "redis",
+		"db.client.connection.pool.name": "localhost:6379",
+	}, attrMap)
+}
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/github.com/segmentio/kafka-go/semconv"
+	"go.opentelemetry.io/otelc/pkg/hook"
+)
+
+// maxBatchLinks caps the number of producer links recorded on a batch span.
+const maxBatchLinks = 128
+
+// batches maps a *kafka.Batch returned by an instrumented ReadBatch call to its
+// *batchData. Entries are removed when the batch is closed.
+//
+// Only (*kafka.Conn).ReadBatch is hooked, not ReadBatchWith: kafka.Reader
+// fetches through ReadBatchWith internally, and those messages are already
+// covered by the Reader hooks.
+var batches sync.Map
+
+// batchData accumulates the messages read from a batch. The receive span is
+// only created when the batch is closed, with an explicit start timestamp, so
+// it never sits open on the goroutine-local span stack while user code reads
+// the batch.
+type batchData struct {
+	parent    context.Context
+	endpoint  string
+	partition int
+	start     time.Time
+
+	mu    sync.Mutex
+	topic string
+	count int
+	links []trace.Link
+}
+
+func (d *batchData) add(msg *kafka.Message) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.topic == "" {
+		d.topic = msg.Topic
+	}
+	d.count++
+	if len(d.links) < maxBatchLinks {
+		if link, ok := producerLink(msg); ok {
+			d.links = append(d.links, link)
+		}
+	}
+}
+
+func (d *batchData) end(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	req := semconv.KafkaRequest{
+		Endpoint:          d.endpoint,
+		Destination:       d.topic,
+		Operation:         semconv.KafkaOperationReceive,
+		Partition:         d.partition,
+		HasPartition:      true,
+		BatchMessageCount: d.count,
+	}
+	ctx, span := tracer.Start(d.parent,
+		spanName(d.topic, semconv.KafkaOperationReceive),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithTimestamp(d.start),
+		trace.WithLinks(d.links...),
+		trace.WithAttributes(semconv.KafkaRequestTraceAttrs(req)...),
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+
+	// The messages read before a failure were still delivered
+	recordConsumed(ctx, req, d.count, err)
+}
+
+// -----------------------------------------------------------------------------
+// Consumer: (*kafka.Conn).ReadBatch(minBytes, maxBytes)
+// -----------------------------------------------------------------------------
+
+// BeforeReadBatch records the call start time.
+func BeforeReadBatch(ictx hook.HookContext, c *kafka.Conn, _ int, _ int) {
+	if !kafkaEnabler.Enable() {
+		logger.Debug("Kafka consumer instrumentation disabled")
+		return
+	}
+	// ReadBatch takes no context, so the batch span is a root span and
+	// runtime.Suppress cannot apply to it.
+	if c == nil {
+		return
+	}
+	initInstrumentation()
+
+	endpoint := ""
+	if addr := c.RemoteAddr(); addr != nil {
+		endpoint = addr.String()
+	}
+	ictx.SetData(&batchData{parent: context.Background(), endpoint: endpoint, start: time.Now()})
+}
+
+// AfterReadBatch registers the returned batch so its messages are counted as
+// they are read.
+func AfterReadBatch(ictx hook.HookContext, batch *kafka.Batch) {
+	data, ok := ictx.GetData().(*batchData)
+	if !ok || data == nil || batch == nil {
+		return
+	}
+	data.partition = batch.Partition()
+	batches.Store(batch, data)
+}
+
+// The Batch hooks run once per message, including for batches read internally
+// by kafka.Reader, so they deliberately skip the enabler check: only batches
+// registered by AfterReadBatch carry batchData.
+
+// BeforeBatchReadMessage looks up the batch state for the after hook.
+func BeforeBatchReadMessage(ictx hook.HookContext, batch *kafka.Batch) {
+	if data, ok := batches.Load(batch); ok {
+		ictx.SetData(data)
+	}
+}
+
+// AfterBatchReadMessage counts a successfully read message.
+func AfterBatchReadMessage(ictx hook.HookContext, msg kafka.Message, err error) {
+	data, ok := ictx.GetData().(*batchData)
+	if !ok || err != nil {
+		return
+	}
+	data.add(&msg)
+}
+
+// BeforeBatchClose unregisters the batch so a repeated Close is a no-op.
+func BeforeBatchClose(ictx hook.HookContext, batch *kafka.Batch) {
+	if data, ok := batches.LoadAndDelete(batch); ok {
+		ictx.SetData(data)
+	}
+}
+
+// AfterBatchClose emits the batch receive span. Close reports nil once the
+// batch was fully consumed, so any error here is a real read failure.
+func AfterBatchClose(ictx hook.HookContext, err error) {
+	data, ok := ictx.GetData().(*batchData)
+	if !ok {
+		return
+	}
+	data.end(err)
+}
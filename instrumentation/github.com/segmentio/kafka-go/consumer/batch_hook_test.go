@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+)
+
+// readBatch simulates the hooks firing around (*kafka.Conn).ReadBatch. The
+// Conn and Batch are never used for I/O, so zero values are enough.
+func readBatch(t *testing.T) *kafka.Batch {
+	t.Helper()
+	batch := &kafka.Batch{}
+	ictx := hooktest.NewMockHookContext()
+	ictx.SetData(&batchData{parent: context.Background(), endpoint: "localhost:9092", start: time.Now()})
+	AfterReadBatch(ictx, batch)
+	t.Cleanup(func() { batches.Delete(batch) })
+	return batch
+}
+
+func readFromBatch(batch *kafka.Batch, msg kafka.Message, err error) {
+	ictx := hooktest.NewMockHookContext(batch)
+	BeforeBatchReadMessage(ictx, batch)
+	AfterBatchReadMessage(ictx, msg, err)
+}
+
+func closeBatch(batch *kafka.Batch, err error) {
+	ictx := hooktest.NewMockHookContext(batch)
+	BeforeBatchClose(ictx, batch)
+	AfterBatchClose(ictx, err)
+}
+
+func TestReadBatch_SpanOnClose(t *testing.T) {
+	sr := setupTest(t)
+	metrics := setupMetrics(t)
+	sc, headers := producerHeaders(t, "0102030405060708090a0b0c0d0e0f10", "0102030405060708")
+
+	batch := readBatch(t)
+	readFromBatch(batch, kafka.Message{Topic: "orders", Headers: headers}, nil)
+	readFromBatch(batch, kafka.Message{Topic: "orders"}, nil)
+	assert.Empty(t, sr.Ended(), "span must not be emitted before Close")
+
+	closeBatch(batch, nil)
+	// A second Close must not emit another span.
+	closeBatch(batch, nil)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "orders receive", span.Name())
+	assert.Equal(t, trace.SpanKindConsumer, span.SpanKind())
+	m := spanAttrs(span)
+	assert.Equal(t, int64(2), m["messaging.batch.message_count"])
+	assert.Equal(t, "0", m["messaging.destination.partition.id"])
+	assert.Equal(t, "localhost", m["server.address"])
+	require.Len(t, span.Links(), 1)
+	assert.Equal(t, sc.SpanID(), span.Links()[0].SpanContext.SpanID())
+
+	_, ok := collectMetric(t, metrics, "messaging.client.consumed.messages")
+	assert.True(t, ok)
+}
+
+func TestReadBatch_Error(t *testing.T) {
+	sr := setupTest(t)
+
+	batch := readBatch(t)
+	readFromBatch(batch, kafka.Message{}, errors.New("broken pipe"))
+	closeBatch(batch, errors.New("broken pipe"))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "receive", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	_, hasCount := spanAttrs(spans[0])["messaging.batch.message_count"]
+	assert.False(t, hasCount)
+}
+
+func TestReadBatch_ErrorTypeOnConsumedMessages(t *testing.T) {
+	setupTest(t)
+	metrics := setupMetrics(t)
+
+	batch := readBatch(t)
+	readFromBatch(batch, kafka.Message{Topic: "orders"}, nil)
+	readFromBatch(batch, kafka.Message{}, errors.New("broken pipe"))
+	closeBatch(batch, errors.New("broken pipe"))
+
+	consumed, ok := collectMetric(t, metrics, "messaging.client.consumed.messages")
+	require.True(t, ok)
+	sum, ok := consumed.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+	errType, ok := sum.DataPoints[0].Attributes.Value("error.type")
+	require.True(t, ok)
+	assert.Equal(t, "*errors.errorString", errType.AsString())
+}
+
+func TestBatchHooks_IgnoreUnregisteredBatches(t *testing.T) {
+	sr := setupTest(t)
+
+	// Batches read internally by kafka.Reader never pass through ReadBatch.
+	batch := &kafka.Batch{}
+	readFromBatch(batch, kafka.Message{Topic: "orders"}, nil)
+	closeBatch(batch, nil)
+
+	assert.Empty(t, sr.Ended())
+}
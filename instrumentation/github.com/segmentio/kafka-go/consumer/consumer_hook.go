@@ -10,8 +10,12 @@ import (
 
 	kafka "github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
+	otelsemconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/messagingconv"
 	"go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/otelc/instrumentation/github.com/segmentio/kafka-go/semconv"
@@ -34,24 +38,90 @@ func (kafkaEnablerImpl) Enable() bool {
 
 var kafkaEnabler = kafkaEnablerImpl{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, kafkaEnabler)
+	runtime.RegisterUnsuppressible(instrumentationKey, "Conn.ReadBatch")
+}
+
 var (
 	logger     = runtime.Logger()
 	tracer     trace.Tracer
 	propagator propagation.TextMapPropagator
+	meter      metric.Meter
 	initOnce   sync.Once
+
+	// Metrics
+	consumedMessages messagingconv.ClientConsumedMessages
+	processDuration  messagingconv.ProcessDuration
 )
 
 func initInstrumentation() {
 	initOnce.Do(func() {
+		version := runtime.ModuleVersion()
 		tracer = otel.GetTracerProvider().Tracer(
 			instrumentationName,
-			trace.WithInstrumentationVersion(runtime.ModuleVersion()),
+			trace.WithInstrumentationVersion(version),
 		)
 		propagator = otel.GetTextMapPropagator()
+		meter = otel.GetMeterProvider().Meter(
+			instrumentationName,
+			metric.WithInstrumentationVersion(version),
+			metric.WithSchemaURL(otelsemconv.SchemaURL),
+		)
+
+		var err error
+		consumedMessages, err = messagingconv.NewClientConsumedMessages(meter)
+		if err != nil {
+			logger.Error("failed to create consumed messages metric", "error", err)
+		}
+		processDuration, err = messagingconv.NewProcessDuration(meter)
+		if err != nil {
+			logger.Error("failed to create process duration metric", "error", err)
+		}
+
 		logger.Info("Kafka (segmentio/kafka-go) consumer instrumentation initialized")
 	})
 }
 
+// recordConsumed adds n to messaging.client.consumed.messages, with the
+// error.type of err when the operation that delivered the messages failed.
+func recordConsumed(ctx context.Context, req semconv.KafkaRequest, n int, err error) {
+	if n <= 0 || consumedMessages.Inst() == nil {
+		return
+	}
+	set := attribute.NewSet(semconv.KafkaRequestMetricAttrs(req, err)...)
+	consumedMessages.AddSet(ctx, int64(n), set)
+}
+
+// producerLink returns a link to the producer span whose context is carried in
+// the message headers, and whether such a context was found.
+func producerLink(msg *kafka.Message) (trace.Link, bool) {
+	sc := trace.SpanContextFromContext(
+		propagator.Extract(context.Background(), headerCarrier{headers: &msg.Headers}),
+	)
+	if !sc.IsValid() {
+		return trace.Link{}, false
+	}
+	return trace.Link{SpanContext: sc}, true
+}
+
+// spanName builds a messaging span name from the destination and operation,
+// falling back to the operation alone when the destination is unknown.
+func spanName(topic string, op semconv.KafkaOperation) string {
+	if topic == "" {
+		return string(op)
+	}
+	return topic + " " + string(op)
+}
+
+// readMessageKey marks the context that ReadMessage hands down to its inner
+// FetchMessage and CommitMessages calls, so those are not traced twice.
+type readMessageKey struct{}
+
+func inReadMessage(ctx context.Context) bool {
+	return ctx != nil && ctx.Value(readMessageKey{}) != nil
+}
+
 // headerCarrier adapts a slice of kafka.Header to the OpenTelemetry
 // TextMapCarrier interface so trace context can be propagated through Kafka
 // message headers.
@@ -90,41 +160,54 @@ func (c headerCarrier) Keys() []string {
 }
 
 // -----------------------------------------------------------------------------
-// Consumer: (*kafka.Reader).ReadMessage(ctx)
+// Consumer: (*kafka.Reader).ReadMessage(ctx) and (*kafka.Reader).FetchMessage(ctx)
 // -----------------------------------------------------------------------------
 
 type consumerData struct {
 	ctx      context.Context
+	reader   *kafka.Reader
 	endpoint string
 	topic    string
 	groupID  string
 	start    time.Time
 }
 
+func newConsumerData(r *kafka.Reader, ctx context.Context) *consumerData {
+	cfg := r.Config()
+	endpoint := ""
+	if len(cfg.Brokers) > 0 {
+		endpoint = cfg.Brokers[0]
+	}
+	return &consumerData{
+		ctx:      ctx,
+		reader:   r,
+		endpoint: endpoint,
+		topic:    cfg.Topic,
+		groupID:  cfg.GroupID,
+		start:    time.Now(),
+	}
+}
+
 // BeforeReadMessage captures the reader configuration and the call start time so
 // AfterReadMessage can build an accurate consumer span once the message arrives.
+//
+// ReadMessage is implemented on top of FetchMessage and CommitMessages; the
+// context passed down to them is marked so their own hooks stay silent and the
+// read produces a single receive span.
 func BeforeReadMessage(ictx hook.HookContext, r *kafka.Reader, ctx context.Context) {
 	if !kafkaEnabler.Enable() {
 		logger.Debug("Kafka consumer instrumentation disabled")
 		return
 	}
-	if r == nil {
+	if r == nil || runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindConsumer) {
 		return
 	}
 	initInstrumentation()
 
-	cfg := r.Config()
-	endpoint := ""
-	if len(cfg.Brokers) > 0 {
-		endpoint = cfg.Brokers[0]
+	ictx.SetData(newConsumerData(r, ctx))
+	if ctx != nil {
+		ictx.SetParam(1, context.WithValue(ctx, readMessageKey{}, true))
 	}
-	ictx.SetData(&consumerData{
-		ctx:      ctx,
-		endpoint: endpoint,
-		topic:    cfg.Topic,
-		groupID:  cfg.GroupID,
-		start:    time.Now(),
-	})
 }
 
 // AfterReadMessage creates a consumer span that links to the producer via the
@@ -139,7 +222,44 @@ func AfterReadMessage(ictx hook.HookContext, msg kafka.Message, err error) {
 	if !ok || data == nil {
 		return
 	}
+	data.receive(&msg, err)
+}
+
+// BeforeFetchMessage mirrors BeforeReadMessage for readers that commit offsets
+// explicitly with CommitMessages.
+func BeforeFetchMessage(ictx hook.HookContext, r *kafka.Reader, ctx context.Context) {
+	if !kafkaEnabler.Enable() {
+		logger.Debug("Kafka consumer instrumentation disabled")
+		return
+	}
+	if r == nil || inReadMessage(ctx) || runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindConsumer) {
+		return
+	}
+	initInstrumentation()
+
+	ictx.SetData(newConsumerData(r, ctx))
+}
+
+// AfterFetchMessage creates the receive span for a fetched message and, for
+// consumer groups, remembers when the message was handed to the application
+// so the matching CommitMessages call can record its processing duration.
+func AfterFetchMessage(ictx hook.HookContext, msg kafka.Message, err error) {
+	data, ok := ictx.GetData().(*consumerData)
+	if !ok || data == nil {
+		return
+	}
+	data.receive(&msg, err)
+	if err == nil && data.groupID != "" {
+		trackerFor(data.reader).fetched(&msg, time.Now())
+	}
+}
 
+// receive emits the receive span and the consumed messages metric for msg.
+//
+// The span is parented on the producer context extracted from the message
+// headers and also links to it, so backends that only follow links still
+// connect the consumer to the producer.
+func (data *consumerData) receive(msg *kafka.Message, err error) {
 	topic := msg.Topic
 	if topic == "" {
 		topic = data.topic
@@ -163,23 +283,152 @@ func AfterReadMessage(ictx hook.HookContext, msg kafka.Message, err error) {
 		HasPartition:    err == nil,
 		HasOffset:       err == nil,
 	}
-	_, span := tracer.Start(parent, topic+" receive",
+	opts := []trace.SpanStartOption{
 		trace.WithSpanKind(trace.SpanKindConsumer),
 		trace.WithTimestamp(data.start),
 		trace.WithAttributes(semconv.KafkaRequestTraceAttrs(req)...),
-	)
+	}
+	if link, ok := producerLink(msg); ok {
+		opts = append(opts, trace.WithLinks(link))
+	}
+	ctx, span := tracer.Start(parent, spanName(topic, semconv.KafkaOperationReceive), opts...)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 	}
 	span.End()
+
+	// A failed read delivers no message, so only its span records the error
+	if err == nil {
+		recordConsumed(ctx, req, 1, nil)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Consumer: (*kafka.Reader).CommitMessages(ctx, msgs...)
+// -----------------------------------------------------------------------------
+
+type commitData struct {
+	ctx    context.Context
+	reader *kafka.Reader
+	span   trace.Span
+	req    semconv.KafkaRequest
+	msgs   []kafka.Message
+}
+
+// BeforeCommitMessages starts a settle span for the offset commit. The span is
+// a child of the caller's context and links to the producer context of every
+// committed message.
+func BeforeCommitMessages(
+	ictx hook.HookContext,
+	r *kafka.Reader,
+	ctx context.Context,
+	msgs ...kafka.Message,
+) {
+	if !kafkaEnabler.Enable() {
+		logger.Debug("Kafka consumer instrumentation disabled")
+		return
+	}
+	if r == nil || len(msgs) == 0 || inReadMessage(ctx) ||
+		runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient) {
+		return
+	}
+	initInstrumentation()
+
+	data := newConsumerData(r, ctx)
+	// Name the span after the topic only when every message shares it.
+	topic := msgs[0].Topic
+	for i := range msgs {
+		if msgs[i].Topic != topic {
+			topic = ""
+			break
+		}
+	}
+	if topic == "" && len(msgs) == 1 {
+		topic = data.topic
+	}
+
+	req := semconv.KafkaRequest{
+		Endpoint:          data.endpoint,
+		Destination:       topic,
+		Operation:         semconv.KafkaOperationCommit,
+		ConsumerGroupID:   data.groupID,
+		BatchMessageCount: len(msgs),
+	}
+	links := make([]trace.Link, 0, len(msgs))
+	for i := range msgs {
+		if link, ok := producerLink(&msgs[i]); ok {
+			links = append(links, link)
+		}
+	}
+
+	parent := ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	spanCtx, span := tracer.Start(parent, spanName(topic, semconv.KafkaOperationCommit),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithLinks(links...),
+		trace.WithAttributes(semconv.KafkaRequestTraceAttrs(req)...),
+	)
+	ictx.SetData(&commitData{ctx: spanCtx, reader: r, span: span, req: req, msgs: msgs})
+}
+
+// AfterCommitMessages ends the settle span and records
+// messaging.process.duration for every message the commit settles. A failed
+// commit records the durations with its error.type and keeps the messages
+// tracked, so that a retried commit records them again.
+func AfterCommitMessages(ictx hook.HookContext, err error) {
+	data, ok := ictx.GetData().(*commitData)
+	if !ok || data == nil {
+		return
+	}
+	if err != nil {
+		data.span.RecordError(err)
+		data.span.SetStatus(codes.Error, err.Error())
+	}
+	data.span.End()
+
+	if processDuration.Inst() == nil {
+		return
+	}
+	tracker := trackerFor(data.reader)
+	now := time.Now()
+	for i := range data.msgs {
+		msg := &data.msgs[i]
+		req := semconv.KafkaRequest{
+			Endpoint:        data.req.Endpoint,
+			Destination:     msg.Topic,
+			Operation:       semconv.KafkaOperationProcess,
+			ConsumerGroupID: data.req.ConsumerGroupID,
+			Partition:       msg.Partition,
+			HasPartition:    true,
+		}
+		set := attribute.NewSet(semconv.KafkaRequestMetricAttrs(req, err)...)
+		fetchTimes := tracker.settle
+		if err != nil {
+			fetchTimes = tracker.peek
+		}
+		for _, fetchedAt := range fetchTimes(msg) {
+			processDuration.RecordSet(data.ctx, now.Sub(fetchedAt).Seconds(), set)
+		}
+	}
+}
+
+// BeforeReaderClose drops the fetch bookkeeping kept for the reader.
+func BeforeReaderClose(_ hook.HookContext, r *kafka.Reader) {
+	if r == nil {
+		return
+	}
+	fetchTrackers.Delete(r)
 }
 
 // ExtractContext extracts the trace context from a Kafka message's headers
 // and returns a context.Context that carries the propagated span context.
 //
-// Use this with the message returned by (*kafka.Reader).ReadMessage to
-// continue the trace in downstream message-processing code:
+// Use this with the message returned by (*kafka.Reader).ReadMessage or
+// (*kafka.Reader).FetchMessage to continue the trace in downstream
+// message-processing code:
 //
 //	msg, err := r.ReadMessage(ctx)
 //	ctx = consumer.ExtractContext(msg)
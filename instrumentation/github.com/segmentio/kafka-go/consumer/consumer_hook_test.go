@@ -8,17 +8,22 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	kafka "github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/messagingconv"
 	"go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+	"go.opentelemetry.io/otelc/pkg/runtime"
 )
 
 // setupTest wires the package-level tracer/propagator to an in-memory span
@@ -46,6 +51,62 @@ func setupTest(t *testing.T) *tracetest.SpanRecorder {
 	return sr
 }
 
+// setupMetrics installs the consumer instruments on an in-memory meter
+// provider. It must be called after setupTest.
+func setupMetrics(t *testing.T) *sdkmetric.ManualReader {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	meter = mp.Meter("test")
+	var err error
+	consumedMessages, err = messagingconv.NewClientConsumedMessages(meter)
+	require.NoError(t, err)
+	processDuration, err = messagingconv.NewProcessDuration(meter)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = mp.Shutdown(context.Background())
+		meter = nil
+		consumedMessages = messagingconv.ClientConsumedMessages{}
+		processDuration = messagingconv.ProcessDuration{}
+	})
+	return reader
+}
+
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) (metricdata.Metrics, bool) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+// producerHeaders returns message headers carrying a sampled remote span
+// context with the given IDs.
+func producerHeaders(t *testing.T, traceID, spanID string) (trace.SpanContext, []kafka.Header) {
+	t.Helper()
+	tid, err := trace.TraceIDFromHex(traceID)
+	require.NoError(t, err)
+	sid, err := trace.SpanIDFromHex(spanID)
+	require.NoError(t, err)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	var headers []kafka.Header
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), sc), headerCarrier{headers: &headers})
+	return sc, headers
+}
+
 func spanAttrs(span sdktrace.ReadOnlySpan) map[string]interface{} {
 	m := make(map[string]interface{})
 	for _, a := range span.Attributes() {
@@ -155,6 +216,23 @@ func TestReadMessage_Disabled(t *testing.T) {
 	assert.Empty(t, sr.Ended())
 }
 
+func TestReadMessage_Suppressed(t *testing.T) {
+	sr := setupTest(t)
+
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: []string{"localhost:9092"},
+		Topic:   "orders",
+	})
+	t.Cleanup(func() { _ = r.Close() })
+
+	ctx := runtime.Suppress(context.Background(), "KAFKA")
+	ictx := hooktest.NewMockHookContext(r, ctx)
+	BeforeReadMessage(ictx, r, ctx)
+	AfterReadMessage(ictx, kafka.Message{Topic: "orders"}, nil)
+
+	assert.Empty(t, sr.Ended())
+}
+
 // TestExtractContext verifies that ExtractContext correctly extracts the trace
 // context from a Kafka message's headers and returns a context that carries the
 // propagated span context.
@@ -196,3 +274,203 @@ func TestExtractContext(t *testing.T) {
 	assert.True(t, extractedSc.IsSampled())
 	assert.True(t, extractedSc.IsRemote())
 }
+
+func TestReadMessage_AddsProducerLink(t *testing.T) {
+	sr := setupTest(t)
+	sc, headers := producerHeaders(t, "0102030405060708090a0b0c0d0e0f10", "0102030405060708")
+
+	r := kafka.NewReader(kafka.ReaderConfig{Brokers: []string{"localhost:9092"}, Topic: "orders"})
+	t.Cleanup(func() { _ = r.Close() })
+
+	ictx := hooktest.NewMockHookContext(r, context.Background())
+	BeforeReadMessage(ictx, r, context.Background())
+	AfterReadMessage(ictx, kafka.Message{Topic: "orders", Headers: headers}, nil)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	require.Len(t, spans[0].Links(), 1)
+	assert.Equal(t, sc.SpanID(), spans[0].Links()[0].SpanContext.SpanID())
+}
+
+func TestReadMessage_SuppressesInnerFetchAndCommit(t *testing.T) {
+	sr := setupTest(t)
+
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: []string{"localhost:9092"},
+		Topic:   "orders",
+		GroupID: "workers",
+	})
+	t.Cleanup(func() { _ = r.Close() })
+
+	ictx := hooktest.NewMockHookContext(r, context.Background())
+	BeforeReadMessage(ictx, r, context.Background())
+
+	// ReadMessage passes its (rewritten) context down to FetchMessage and
+	// CommitMessages.
+	innerCtx, ok := ictx.GetParam(1).(context.Context)
+	require.True(t, ok)
+	msg := kafka.Message{Topic: "orders", Partition: 1, Offset: 5}
+
+	fetchCtx := hooktest.NewMockHookContext(r, innerCtx)
+	BeforeFetchMessage(fetchCtx, r, innerCtx)
+	AfterFetchMessage(fetchCtx, msg, nil)
+	commitCtx := hooktest.NewMockHookContext(r, innerCtx, msg)
+	BeforeCommitMessages(commitCtx, r, innerCtx, msg)
+	AfterCommitMessages(commitCtx, nil)
+
+	AfterReadMessage(ictx, msg, nil)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "orders receive", spans[0].Name())
+	assert.Equal(t, "workers", spanAttrs(spans[0])["messaging.consumer.group.name"])
+}
+
+func TestFetchAndCommit(t *testing.T) {
+	sr := setupTest(t)
+	metrics := setupMetrics(t)
+	sc, headers := producerHeaders(t, "0102030405060708090a0b0c0d0e0f10", "0102030405060708")
+
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: []string{"localhost:9092"},
+		Topic:   "orders",
+		GroupID: "workers",
+	})
+	t.Cleanup(func() { _ = r.Close() })
+
+	msgs := []kafka.Message{
+		{Topic: "orders", Partition: 2, Offset: 10, Value: []byte("a"), Headers: headers},
+		{Topic: "orders", Partition: 2, Offset: 11, Value: []byte("b")},
+	}
+	for _, msg := range msgs {
+		ictx := hooktest.NewMockHookContext(r, context.Background())
+		BeforeFetchMessage(ictx, r, context.Background())
+		AfterFetchMessage(ictx, msg, nil)
+	}
+
+	// Committing the highest offset settles every earlier message too.
+	ictx := hooktest.NewMockHookContext(r, context.Background(), msgs[1])
+	BeforeCommitMessages(ictx, r, context.Background(), msgs[1])
+	AfterCommitMessages(ictx, nil)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+
+	fetched := spans[0]
+	assert.Equal(t, "orders receive", fetched.Name())
+	assert.Equal(t, sc.TraceID(), fetched.Parent().TraceID())
+	assert.Equal(t, "workers", spanAttrs(fetched)["messaging.consumer.group.name"])
+
+	commit := spans[2]
+	assert.Equal(t, "orders commit", commit.Name())
+	assert.Equal(t, trace.SpanKindClient, commit.SpanKind())
+	m := spanAttrs(commit)
+	assert.Equal(t, "commit", m["messaging.operation.name"])
+	assert.Equal(t, "settle", m["messaging.operation.type"])
+	assert.Equal(t, "workers", m["messaging.consumer.group.name"])
+
+	consumed, ok := collectMetric(t, metrics, "messaging.client.consumed.messages")
+	require.True(t, ok)
+	sum, ok := consumed.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(2), sum.DataPoints[0].Value)
+
+	duration, ok := collectMetric(t, metrics, "messaging.process.duration")
+	require.True(t, ok)
+	hist, ok := duration.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+	assert.Equal(t, uint64(2), hist.DataPoints[0].Count)
+	group, _ := hist.DataPoints[0].Attributes.Value("messaging.consumer.group.name")
+	assert.Equal(t, "workers", group.AsString())
+	assert.Empty(t, trackerFor(r).pending)
+}
+
+func TestCommitMessages_FailureRecordsErrorType(t *testing.T) {
+	setupTest(t)
+	metrics := setupMetrics(t)
+
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: []string{"localhost:9092"},
+		Topic:   "orders",
+		GroupID: "workers",
+	})
+	t.Cleanup(func() { _ = r.Close() })
+
+	msg := kafka.Message{Topic: "orders", Partition: 1, Offset: 5}
+	ictx := hooktest.NewMockHookContext(r, context.Background())
+	BeforeFetchMessage(ictx, r, context.Background())
+	AfterFetchMessage(ictx, msg, nil)
+
+	commit := func(err error) {
+		ictx := hooktest.NewMockHookContext(r, context.Background(), msg)
+		BeforeCommitMessages(ictx, r, context.Background(), msg)
+		AfterCommitMessages(ictx, err)
+	}
+	commit(errors.New("rebalance in progress"))
+	// The failed commit keeps the message tracked for the retry
+	assert.NotEmpty(t, trackerFor(r).pending)
+	commit(nil)
+	assert.Empty(t, trackerFor(r).pending)
+
+	duration, ok := collectMetric(t, metrics, "messaging.process.duration")
+	require.True(t, ok)
+	hist, ok := duration.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 2)
+	var failed int
+	for _, dp := range hist.DataPoints {
+		assert.Equal(t, uint64(1), dp.Count)
+		if errType, ok := dp.Attributes.Value("error.type"); ok {
+			assert.Equal(t, "*errors.errorString", errType.AsString())
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed)
+}
+
+func TestCommitMessages_LinksAndError(t *testing.T) {
+	sr := setupTest(t)
+	scA, headersA := producerHeaders(t, "0102030405060708090a0b0c0d0e0f10", "0102030405060708")
+	scB, headersB := producerHeaders(t, "1112131415161718191a1b1c1d1e1f20", "1112131415161718")
+
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: []string{"localhost:9092"},
+		GroupID: "workers",
+		Topic:   "orders",
+	})
+	t.Cleanup(func() { _ = r.Close() })
+
+	msgs := []kafka.Message{
+		{Topic: "orders", Headers: headersA},
+		{Topic: "payments", Headers: headersB},
+	}
+	ictx := hooktest.NewMockHookContext(r, context.Background(), msgs)
+	BeforeCommitMessages(ictx, r, context.Background(), msgs...)
+	AfterCommitMessages(ictx, errors.New("rebalance in progress"))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	// Messages from several topics leave the destination unnamed.
+	assert.Equal(t, "commit", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, int64(2), spanAttrs(spans[0])["messaging.batch.message_count"])
+	links := spans[0].Links()
+	require.Len(t, links, 2)
+	assert.Equal(t, scA.SpanID(), links[0].SpanContext.SpanID())
+	assert.Equal(t, scB.SpanID(), links[1].SpanContext.SpanID())
+}
+
+func TestFetchTracker_Bounded(t *testing.T) {
+	tracker := &fetchTracker{pending: make(map[partitionKey]map[int64]time.Time)}
+	now := time.Now()
+	for i := range maxTrackedMessages + 10 {
+		tracker.fetched(&kafka.Message{Topic: "orders", Offset: int64(i)}, now)
+	}
+	assert.Equal(t, maxTrackedMessages, tracker.size)
+
+	settled := tracker.settle(&kafka.Message{Topic: "orders", Offset: 99})
+	assert.Len(t, settled, 100)
+	assert.Equal(t, maxTrackedMessages-100, tracker.size)
+}
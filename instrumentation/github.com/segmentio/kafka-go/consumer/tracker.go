@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"sync"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// maxTrackedMessages bounds the fetch bookkeeping kept per reader, so
+// applications that fetch from a consumer group without ever committing do
+// not grow it without limit. Messages fetched past the limit are simply not
+// reported in messaging.process.duration.
+const maxTrackedMessages = 4096
+
+// fetchTrackers maps a *kafka.Reader to its *fetchTracker. Entries are
+// removed when the reader is closed.
+var fetchTrackers sync.Map
+
+type partitionKey struct {
+	topic     string
+	partition int
+}
+
+// fetchTracker remembers when each uncommitted message was fetched.
+type fetchTracker struct {
+	mu      sync.Mutex
+	size    int
+	pending map[partitionKey]map[int64]time.Time
+}
+
+func trackerFor(r *kafka.Reader) *fetchTracker {
+	if t, ok := fetchTrackers.Load(r); ok {
+		return t.(*fetchTracker)
+	}
+	t, _ := fetchTrackers.LoadOrStore(r, &fetchTracker{
+		pending: make(map[partitionKey]map[int64]time.Time),
+	})
+	return t.(*fetchTracker)
+}
+
+func (t *fetchTracker) fetched(msg *kafka.Message, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.size >= maxTrackedMessages {
+		return
+	}
+	key := partitionKey{topic: msg.Topic, partition: msg.Partition}
+	offsets := t.pending[key]
+	if offsets == nil {
+		offsets = make(map[int64]time.Time)
+		t.pending[key] = offsets
+	}
+	if _, ok := offsets[msg.Offset]; !ok {
+		offsets[msg.Offset] = at
+		t.size++
+	}
+}
+
+// settle forgets every message of msg's partition up to and including its
+// offset, since committing an offset commits all earlier ones too, and returns
+// their fetch times.
+func (t *fetchTracker) settle(msg *kafka.Message) []time.Time {
+	return t.collect(msg, true)
+}
+
+// peek returns the fetch times settle would return without forgetting the
+// messages, for a commit that failed and may be retried.
+func (t *fetchTracker) peek(msg *kafka.Message) []time.Time {
+	return t.collect(msg, false)
+}
+
+func (t *fetchTracker) collect(msg *kafka.Message, remove bool) []time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := partitionKey{topic: msg.Topic, partition: msg.Partition}
+	offsets := t.pending[key]
+	var settled []time.Time
+	for offset, at := range offsets {
+		if offset <= msg.Offset {
+			settled = append(settled, at)
+			if remove {
+				delete(offsets, offset)
+				t.size--
+			}
+		}
+	}
+	if len(offsets) == 0 {
+		delete(t.pending, key)
+	}
+	return settled
+}
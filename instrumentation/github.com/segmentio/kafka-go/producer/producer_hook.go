@@ -34,6 +34,10 @@ func (kafkaEnablerImpl) Enable() bool {
 
 var kafkaEnabler = kafkaEnablerImpl{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, kafkaEnabler)
+}
+
 var (
 	logger     = runtime.Logger()
 	tracer     trace.Tracer
@@ -109,6 +113,9 @@ func BeforeWriteMessages(
 	if w == nil || len(msgs) == 0 {
 		return
 	}
+	if runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindProducer) {
+		return
+	}
 	initInstrumentation()
 
 	endpoint := ""
@@ -19,6 +19,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+	"go.opentelemetry.io/otelc/pkg/runtime"
 )
 
 // setupTest wires the package-level tracer/propagator to an in-memory span
@@ -127,6 +128,21 @@ func TestWriteMessages_Disabled(t *testing.T) {
 	assert.Nil(t, ictx.GetData())
 }
 
+func TestWriteMessages_Suppressed(t *testing.T) {
+	sr := setupTest(t)
+
+	w := &kafka.Writer{Addr: kafka.TCP("localhost:9092"), Topic: "orders"}
+	msgs := []kafka.Message{{Value: []byte("hello")}}
+	ctx := runtime.Suppress(context.Background(), runtime.SpanKindKey(trace.SpanKindProducer))
+
+	ictx := hooktest.NewMockHookContext(w, ctx, msgs)
+	BeforeWriteMessages(ictx, w, ctx, msgs...)
+	AfterWriteMessages(ictx, nil)
+
+	assert.Empty(t, sr.Ended())
+	assert.Empty(t, msgs[0].Headers)
+}
+
 func TestHeaderCarrier_SetGetKeys(t *testing.T) {
 	var headers []kafka.Header
 	hc := headerCarrier{headers: &headers}
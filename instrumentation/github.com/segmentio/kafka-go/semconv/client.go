@@ -9,6 +9,8 @@ import (
 
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+
+	httpsemconv "go.opentelemetry.io/otelc/instrumentation/net/http/semconv"
 )
 
 // KafkaOperation identifies the messaging operation performed by the client.
@@ -19,6 +21,12 @@ const (
 	KafkaOperationSend KafkaOperation = "send"
 	// KafkaOperationReceive is the operation name for consuming messages.
 	KafkaOperationReceive KafkaOperation = "receive"
+	// KafkaOperationCommit is the operation name for committing consumer
+	// group offsets, a settle operation in messaging semantic conventions.
+	KafkaOperationCommit KafkaOperation = "commit"
+	// KafkaOperationProcess is the operation name used for the processing
+	// time between fetching a message and committing its offset.
+	KafkaOperationProcess KafkaOperation = "process"
 )
 
 // KafkaRequest carries the information needed to build the semantic convention
@@ -28,7 +36,7 @@ type KafkaRequest struct {
 	Endpoint string
 	// Destination is the Kafka topic.
 	Destination string
-	// Operation is the messaging operation (send or receive).
+	// Operation is the messaging operation (send, receive, commit or process).
 	Operation KafkaOperation
 	// ConsumerGroupID is the consumer group name (consumer side only).
 	ConsumerGroupID string
@@ -46,12 +54,52 @@ type KafkaRequest struct {
 	HasPartition bool
 	// HasOffset indicates whether Offset holds a meaningful value.
 	HasOffset bool
+	// BatchMessageCount is the number of messages covered by a batch
+	// operation. Only emitted when greater than zero.
+	BatchMessageCount int
 }
 
 // KafkaRequestTraceAttrs returns the trace attributes for a Kafka client
 // operation. Optional attributes are only included when they carry a
 // meaningful value to avoid cluttering spans with empty attributes.
 func KafkaRequestTraceAttrs(req KafkaRequest) []attribute.KeyValue {
+	attrs := baseAttrs(req)
+
+	if req.MessageKey != "" {
+		attrs = append(attrs, semconv.MessagingKafkaMessageKey(req.MessageKey))
+	}
+	if req.MessageBodySize > 0 {
+		attrs = append(attrs, semconv.MessagingMessageBodySize(req.MessageBodySize))
+	}
+	if req.HasPartition {
+		attrs = append(attrs, semconv.MessagingDestinationPartitionID(strconv.Itoa(req.Partition)))
+	}
+	if req.HasOffset {
+		attrs = append(attrs, semconv.MessagingKafkaOffset(int(req.Offset)))
+	}
+	if req.BatchMessageCount > 0 {
+		attrs = append(attrs, semconv.MessagingBatchMessageCount(req.BatchMessageCount))
+	}
+
+	return attrs
+}
+
+// KafkaRequestMetricAttrs returns the metric attributes for a Kafka client
+// operation. Per-message attributes such as the key and offset are left out to
+// keep metric cardinality bounded; error.type is added when err is non-nil.
+func KafkaRequestMetricAttrs(req KafkaRequest, err error) []attribute.KeyValue {
+	attrs := baseAttrs(req)
+	if req.HasPartition {
+		attrs = append(attrs, semconv.MessagingDestinationPartitionID(strconv.Itoa(req.Partition)))
+	}
+	if err != nil {
+		attrs = append(attrs, httpsemconv.ErrorType(err))
+	}
+	return attrs
+}
+
+// baseAttrs returns the attributes shared by spans and metrics.
+func baseAttrs(req KafkaRequest) []attribute.KeyValue {
 	attrs := []attribute.KeyValue{
 		semconv.MessagingSystemKafka,
 		semconv.MessagingOperationName(string(req.Operation)),
@@ -63,6 +111,10 @@ func KafkaRequestTraceAttrs(req KafkaRequest) []attribute.KeyValue {
 		attrs = append(attrs, semconv.MessagingOperationTypeSend)
 	case KafkaOperationReceive:
 		attrs = append(attrs, semconv.MessagingOperationTypeReceive)
+	case KafkaOperationCommit:
+		attrs = append(attrs, semconv.MessagingOperationTypeSettle)
+	case KafkaOperationProcess:
+		attrs = append(attrs, semconv.MessagingOperationTypeProcess)
 	}
 
 	if req.Endpoint != "" {
@@ -80,18 +132,5 @@ func KafkaRequestTraceAttrs(req KafkaRequest) []attribute.KeyValue {
 	if req.ConsumerGroupID != "" {
 		attrs = append(attrs, semconv.MessagingConsumerGroupName(req.ConsumerGroupID))
 	}
-	if req.MessageKey != "" {
-		attrs = append(attrs, semconv.MessagingKafkaMessageKey(req.MessageKey))
-	}
-	if req.MessageBodySize > 0 {
-		attrs = append(attrs, semconv.MessagingMessageBodySize(req.MessageBodySize))
-	}
-	if req.HasPartition {
-		attrs = append(attrs, semconv.MessagingDestinationPartitionID(strconv.Itoa(req.Partition)))
-	}
-	if req.HasOffset {
-		attrs = append(attrs, semconv.MessagingKafkaOffset(int(req.Offset)))
-	}
-
 	return attrs
 }
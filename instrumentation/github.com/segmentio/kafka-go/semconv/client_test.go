@@ -4,6 +4,7 @@
 package semconv
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -104,3 +105,55 @@ func TestKafkaRequestTraceAttrs_EndpointWithoutPort(t *testing.T) {
 	_, hasPort := m["server.port"]
 	assert.False(t, hasPort)
 }
+
+func TestKafkaRequestTraceAttrs_Commit(t *testing.T) {
+	attrs := KafkaRequestTraceAttrs(KafkaRequest{
+		Endpoint:          "localhost:9092",
+		Destination:       "orders",
+		Operation:         KafkaOperationCommit,
+		ConsumerGroupID:   "workers",
+		BatchMessageCount: 3,
+	})
+	m := attrMap(attrs)
+
+	assert.Equal(t, "commit", m["messaging.operation.name"].AsString())
+	assert.Equal(t, "settle", m["messaging.operation.type"].AsString())
+	assert.Equal(t, "workers", m["messaging.consumer.group.name"].AsString())
+	assert.Equal(t, int64(3), m["messaging.batch.message_count"].AsInt64())
+}
+
+func TestKafkaRequestMetricAttrs(t *testing.T) {
+	req := KafkaRequest{
+		Endpoint:        "localhost:9092",
+		Destination:     "orders",
+		Operation:       KafkaOperationReceive,
+		ConsumerGroupID: "workers",
+		MessageKey:      "order-1",
+		MessageBodySize: 64,
+		Partition:       2,
+		Offset:          7,
+		HasPartition:    true,
+		HasOffset:       true,
+	}
+	m := attrMap(KafkaRequestMetricAttrs(req, nil))
+
+	assert.Equal(t, "kafka", m["messaging.system"].AsString())
+	assert.Equal(t, "receive", m["messaging.operation.name"].AsString())
+	assert.Equal(t, "orders", m["messaging.destination.name"].AsString())
+	assert.Equal(t, "workers", m["messaging.consumer.group.name"].AsString())
+	assert.Equal(t, "2", m["messaging.destination.partition.id"].AsString())
+	assert.Equal(t, "localhost", m["server.address"].AsString())
+	// High-cardinality per-message attributes are never used on metrics.
+	for _, key := range []string{
+		"messaging.kafka.message.key",
+		"messaging.message.body.size",
+		"messaging.kafka.offset",
+		"error.type",
+	} {
+		_, ok := m[key]
+		assert.Falsef(t, ok, "expected %q to be omitted", key)
+	}
+
+	m = attrMap(KafkaRequestMetricAttrs(req, errors.New("boom")))
+	assert.Equal(t, "*errors.errorString", m["error.type"].AsString())
+}
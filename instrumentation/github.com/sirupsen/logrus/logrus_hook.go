@@ -25,6 +25,10 @@ func (l logEnabler) Enable() bool {
 
 var enabler = logEnabler{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, enabler)
+}
+
 var (
 	hookInitMu    sync.Mutex
 	hookInitMap   = make(map[*logrus.Logger]bool)
@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fasthttp
+
+import (
+	"github.com/valyala/fasthttp"
+)
+
+// headerCarrier adapts a fasthttp.RequestHeader to the OpenTelemetry
+// TextMapCarrier interface so trace context can be propagated through the
+// request headers.
+type headerCarrier struct {
+	header *fasthttp.RequestHeader
+}
+
+// Get returns the value of the header matching key, or "" if absent.
+func (c headerCarrier) Get(key string) string {
+	return string(c.header.Peek(key))
+}
+
+// Set replaces any existing value of the header with key.
+func (c headerCarrier) Set(key, value string) {
+	c.header.Set(key, value)
+}
+
+// Keys lists the header keys carried by this carrier.
+func (c headerCarrier) Keys() []string {
+	var keys []string
+	for key := range c.header.All() {
+		keys = append(keys, string(key))
+	}
+	return keys
+}
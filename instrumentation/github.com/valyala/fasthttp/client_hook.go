@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fasthttp instruments the HTTP client and server of
+// github.com/valyala/fasthttp, which do not go through net/http. Client
+// requests get a client span whose trace context travels in the request
+// headers, and server requests are handled inside a server span continuing
+// the trace of the caller. The spans carry the attributes of the net/http
+// instrumentation.
+package fasthttp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/net/http/semconv"
+	"go.opentelemetry.io/otelc/pkg/hook"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+const (
+	instrumentationName = "go.opentelemetry.io/otelc/instrumentation/github.com/valyala/fasthttp"
+	instrumentationKey  = "FASTHTTP"
+
+	// responseParamIndex is the index of the response in
+	// (*HostClient).Do(req, resp), after the receiver.
+	responseParamIndex = 2
+)
+
+// fasthttpEnablerImpl controls whether the fasthttp instrumentation is enabled.
+type fasthttpEnablerImpl struct{}
+
+func (fasthttpEnablerImpl) Enable() bool {
+	return runtime.Instrumented(instrumentationKey)
+}
+
+var fasthttpEnabler = fasthttpEnablerImpl{}
+
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, fasthttpEnabler)
+	runtime.RegisterUnsuppressible(instrumentationKey, "HostClient.Do", "Server.Serve")
+}
+
+var (
+	logger     = runtime.Logger()
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	initOnce   sync.Once
+)
+
+func initInstrumentation() {
+	initOnce.Do(func() {
+		tracer = otel.GetTracerProvider().Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(runtime.ModuleVersion()),
+		)
+		propagator = otel.GetTextMapPropagator()
+		logger.Info("fasthttp instrumentation initialized")
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Client: (*fasthttp.HostClient).Do(req, resp)
+//
+// Client.Do, LBClient.Do and the Get/Post helpers all send their requests
+// through a HostClient, once per redirect. Retries of an idempotent request
+// share its span.
+// -----------------------------------------------------------------------------
+
+// BeforeHostClientDo starts a client span and injects its trace context into
+// the request headers.
+func BeforeHostClientDo(ictx hook.HookContext, _ *fasthttp.HostClient, req *fasthttp.Request, _ *fasthttp.Response) {
+	if !fasthttpEnabler.Enable() {
+		logger.Debug("fasthttp instrumentation disabled")
+		return
+	}
+	if req == nil {
+		return
+	}
+	initInstrumentation()
+
+	r := clientRequest(req)
+	// fasthttp requests carry no context: the parent span, if any, is the
+	// caller's, found through the goroutine-local storage.
+	ctx, span := tracer.Start(context.Background(), r.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPClientRequestTraceAttrs(r)...),
+	)
+	propagator.Inject(ctx, headerCarrier{header: &req.Header})
+	ictx.SetData(span)
+}
+
+// AfterHostClientDo ends the client span started by BeforeHostClientDo. The
+// response status is unknown when the caller passed no response.
+func AfterHostClientDo(ictx hook.HookContext, err error) {
+	span, ok := ictx.GetData().(trace.Span)
+	if !ok || span == nil {
+		return
+	}
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(semconv.HTTPClientErrorType(err))
+		return
+	}
+	resp, ok := ictx.GetParam(responseParamIndex).(*fasthttp.Response)
+	if !ok || resp == nil {
+		return
+	}
+	res := &http.Response{StatusCode: resp.StatusCode()}
+	span.SetAttributes(semconv.HTTPClientResponseTraceAttrs(res)...)
+	if code, desc := semconv.HTTPClientStatus(res.StatusCode); code != codes.Unset {
+		span.SetStatus(code, desc)
+	}
+}
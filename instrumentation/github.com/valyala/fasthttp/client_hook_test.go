@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fasthttp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+)
+
+func setupTest(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "fasthttp")
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	// Consume initOnce so initInstrumentation() becomes a no-op and does not
+	// overwrite the tracer/propagator we install below.
+	initOnce.Do(func() {})
+	tracer = tp.Tracer("test")
+	propagator = propagation.TraceContext{}
+
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		initOnce = sync.Once{}
+		tracer = nil
+		propagator = nil
+	})
+	return sr
+}
+
+// serve serves s on an in-memory listener and returns a client dialing it.
+func serve(t *testing.T, s *fasthttp.Server) *fasthttp.HostClient {
+	t.Helper()
+	ln := fasthttputil.NewInmemoryListener()
+	go func() { _ = s.Serve(ln) }()
+	t.Cleanup(func() { _ = s.Shutdown() })
+
+	return &fasthttp.HostClient{
+		Addr: "example.com:8080",
+		Dial: func(string) (net.Conn, error) { return ln.Dial() },
+	}
+}
+
+func attrMap(attrs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(attrs))
+	for _, kv := range attrs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+// do runs the client hooks around hc.Do the way the injected trampoline
+// would.
+func do(hc *fasthttp.HostClient, req *fasthttp.Request, resp *fasthttp.Response) error {
+	ictx := hooktest.NewMockHookContext(hc, req, resp)
+	BeforeHostClientDo(ictx, hc, req, resp)
+	err := hc.Do(req, resp)
+	AfterHostClientDo(ictx, err)
+	return err
+}
+
+func TestHostClientDo(t *testing.T) {
+	sr := setupTest(t)
+
+	var traceparent string
+	hc := serve(t, &fasthttp.Server{Handler: func(ctx *fasthttp.RequestCtx) {
+		traceparent = string(ctx.Request.Header.Peek("traceparent"))
+		ctx.SetBodyString("ok")
+	}})
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI("http://example.com:8080/orders?id=1")
+	req.Header.SetUserAgent("test-agent")
+	require.NoError(t, do(hc, req, resp))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, codes.Unset, span.Status().Code)
+
+	attrs := attrMap(span.Attributes())
+	assert.Equal(t, "GET", attrs[semconv.HTTPRequestMethodKey].AsString())
+	assert.Equal(t, "http://example.com:8080/orders?id=1", attrs[semconv.URLFullKey].AsString())
+	assert.Equal(t, "example.com", attrs[semconv.ServerAddressKey].AsString())
+	assert.Equal(t, int64(8080), attrs[semconv.ServerPortKey].AsInt64())
+	assert.Equal(t, "http", attrs[semconv.URLSchemeKey].AsString())
+	assert.Equal(t, "1.1", attrs[semconv.NetworkProtocolVersionKey].AsString())
+	assert.Equal(t, "test-agent", attrs[semconv.UserAgentOriginalKey].AsString())
+	assert.Equal(t, int64(200), attrs[semconv.HTTPResponseStatusCodeKey].AsInt64())
+
+	sc := span.SpanContext()
+	assert.Equal(t, "00-"+sc.TraceID().String()+"-"+sc.SpanID().String()+"-01", traceparent)
+}
+
+func TestHostClientDo_ErrorStatus(t *testing.T) {
+	sr := setupTest(t)
+
+	hc := serve(t, &fasthttp.Server{Handler: func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+	}})
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI("http://example.com:8080/orders")
+	req.Header.SetMethod(fasthttp.MethodPost)
+	require.NoError(t, do(hc, req, resp))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "POST", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	attrs := attrMap(spans[0].Attributes())
+	assert.Equal(t, int64(503), attrs[semconv.HTTPResponseStatusCodeKey].AsInt64())
+	assert.Equal(t, "503", attrs[semconv.ErrorTypeKey].AsString())
+}
+
+func TestHostClientDo_Error(t *testing.T) {
+	sr := setupTest(t)
+
+	errDial := errors.New("dial failed")
+	hc := &fasthttp.HostClient{
+		Addr: "example.com:8080",
+		Dial: func(string) (net.Conn, error) { return nil, errDial },
+	}
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI("http://example.com:8080/orders")
+	require.Error(t, do(hc, req, nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	attrs := attrMap(spans[0].Attributes())
+	assert.Equal(t, "*errors.errorString", attrs[semconv.ErrorTypeKey].AsString())
+	_, hasStatus := attrs[semconv.HTTPResponseStatusCodeKey]
+	assert.False(t, hasStatus)
+}
+
+func TestHostClientDo_Disabled(t *testing.T) {
+	sr := setupTest(t)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "fasthttp")
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "")
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI("http://example.com:8080/orders")
+	ictx := hooktest.NewMockHookContext(nil, req, nil)
+	BeforeHostClientDo(ictx, nil, req, nil)
+	AfterHostClientDo(ictx, nil)
+
+	assert.Empty(t, req.Header.Peek("traceparent"))
+	assert.Empty(t, sr.Ended())
+}
@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fasthttp
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+
+	"github.com/valyala/fasthttp"
+)
+
+// The net/http semconv package builds the span attributes from an
+// *http.Request. The functions below describe a fasthttp request with the
+// few *http.Request fields it reads, so fasthttp spans carry exactly the
+// attributes of net/http spans.
+
+// clientRequest returns the *http.Request describing req, sent by a client.
+func clientRequest(req *fasthttp.Request) *http.Request {
+	uri := req.URI()
+	r := &http.Request{
+		Method: string(req.Header.Method()),
+		URL: &url.URL{
+			Scheme:   string(uri.Scheme()),
+			Host:     string(uri.Host()),
+			Path:     string(uri.Path()),
+			RawQuery: string(uri.QueryString()),
+		},
+		Proto:  string(req.Header.Protocol()),
+		Header: http.Header{},
+	}
+	if ua := req.Header.UserAgent(); len(ua) > 0 {
+		r.Header.Set("User-Agent", string(ua))
+	}
+	return r
+}
+
+// serverRequest returns the *http.Request describing the request received
+// by a server in ctx.
+func serverRequest(ctx *fasthttp.RequestCtx) *http.Request {
+	uri := ctx.URI()
+	r := &http.Request{
+		Method: string(ctx.Method()),
+		URL: &url.URL{
+			Path:     string(uri.Path()),
+			RawQuery: string(uri.QueryString()),
+		},
+		Proto:      string(ctx.Request.Header.Protocol()),
+		Host:       string(ctx.Host()),
+		RemoteAddr: ctx.RemoteAddr().String(),
+		Header:     http.Header{},
+	}
+	if ua := ctx.UserAgent(); len(ua) > 0 {
+		r.Header.Set("User-Agent", string(ua))
+	}
+	if xff := ctx.Request.Header.Peek("X-Forwarded-For"); len(xff) > 0 {
+		r.Header.Set("X-Forwarded-For", string(xff))
+	}
+	if ctx.IsTLS() {
+		// Only the presence of the connection state is read.
+		r.TLS = &tls.ConnectionState{}
+	}
+	return r
+}
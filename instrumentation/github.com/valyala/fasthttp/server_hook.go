@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fasthttp
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/instrumentation/net/http/semconv"
+	"go.opentelemetry.io/otelc/pkg/hook"
+)
+
+// serverContextKey is the user value key of the context carrying the server
+// span of a request, as returned by tracer.Start.
+type serverContextKey struct{}
+
+// -----------------------------------------------------------------------------
+// Server: (*fasthttp.Server).Serve(ln) and (*fasthttp.Server).ServeConn(c)
+//
+// ListenAndServe, ListenAndServeTLS and the package-level helpers all serve
+// through Serve. The request handler of the server is wrapped once, before it
+// handles its first request.
+// -----------------------------------------------------------------------------
+
+// instrumentedServers maps a *fasthttp.Server to the *sync.Once wrapping its
+// handler, so servers serving several listeners or connections are only
+// wrapped once.
+var instrumentedServers sync.Map
+
+// BeforeServe wraps the request handler of s before it serves ln.
+func BeforeServe(ictx hook.HookContext, s *fasthttp.Server, _ net.Listener) {
+	instrumentServer(s)
+}
+
+// BeforeServeConn wraps the request handler of s before it serves c.
+func BeforeServeConn(ictx hook.HookContext, s *fasthttp.Server, _ net.Conn) {
+	instrumentServer(s)
+}
+
+func instrumentServer(s *fasthttp.Server) {
+	if !fasthttpEnabler.Enable() {
+		logger.Debug("fasthttp instrumentation disabled")
+		return
+	}
+	if s == nil {
+		return
+	}
+	initInstrumentation()
+
+	// Every Serve and ServeConn call waits for the handler to be wrapped
+	// before it serves, so no request reads s.Handler while it is written.
+	once, _ := instrumentedServers.LoadOrStore(s, &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		if s.Handler != nil {
+			s.Handler = wrapHandler(s.Handler)
+		}
+	})
+}
+
+// wrapHandler returns a handler running handler inside a server span.
+func wrapHandler(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		r := serverRequest(ctx)
+		parent := propagator.Extract(context.Background(), headerCarrier{header: &ctx.Request.Header})
+		spanCtx, span := tracer.Start(parent, semconv.HTTPServerSpanName(r.Method, ""),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerRequestTraceAttrs("", r)...),
+		)
+		ctx.SetUserValue(serverContextKey{}, spanCtx)
+		defer endServerSpan(ctx, span)
+
+		handler(ctx)
+	}
+}
+
+// endServerSpan records the response status of the request handled with ctx
+// and ends its server span.
+func endServerSpan(ctx *fasthttp.RequestCtx, span trace.Span) {
+	statusCode := ctx.Response.StatusCode()
+	span.SetAttributes(semconv.HTTPServerResponseTraceAttrs(statusCode, 0)...)
+	if code, desc := semconv.HTTPServerStatus(statusCode); code != codes.Unset {
+		span.SetStatus(code, desc)
+	}
+	span.End()
+}
+
+// RequestContext returns a context carrying the server span of the request
+// handled with ctx, and the baggage propagated by the caller, for the handler
+// to continue its trace. It returns ctx itself when the request is not traced.
+func RequestContext(ctx *fasthttp.RequestCtx) context.Context {
+	spanCtx, ok := ctx.UserValue(serverContextKey{}).(context.Context)
+	if !ok {
+		return ctx
+	}
+	traced := trace.ContextWithSpan(ctx, trace.SpanFromContext(spanCtx))
+	return baggage.ContextWithBaggage(traced, baggage.FromContext(spanCtx))
+}
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fasthttp
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+)
+
+// instrumentedServer returns a server for handler whose handler was wrapped
+// by BeforeServe the way the injected trampoline would.
+func instrumentedServer(handler fasthttp.RequestHandler) *fasthttp.Server {
+	s := &fasthttp.Server{Handler: handler}
+	BeforeServe(hooktest.NewMockHookContext(s, nil), s, nil)
+	return s
+}
+
+func get(t *testing.T, hc *fasthttp.HostClient, uri string, header map[string]string) int {
+	t.Helper()
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI(uri)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	require.NoError(t, hc.Do(req, resp))
+	return resp.StatusCode()
+}
+
+func TestServe_ContinuesCallerTrace(t *testing.T) {
+	sr := setupTest(t)
+
+	var handlerSpan trace.SpanContext
+	s := instrumentedServer(func(ctx *fasthttp.RequestCtx) {
+		handlerSpan = trace.SpanContextFromContext(RequestContext(ctx))
+	})
+	hc := serve(t, s)
+
+	_, parent := tracer.Start(context.Background(), "parent")
+	psc := parent.SpanContext()
+	parent.End()
+	traceparent := "00-" + psc.TraceID().String() + "-" + psc.SpanID().String() + "-01"
+	assert.Equal(t, fasthttp.StatusOK, get(t, hc, "http://example.com:8080/orders?id=1", map[string]string{
+		"traceparent":     traceparent,
+		"User-Agent":      "test-agent",
+		"X-Forwarded-For": "10.0.0.1, 10.0.0.2",
+	}))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	span := spans[1]
+	assert.Equal(t, "GET", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, psc.TraceID(), span.SpanContext().TraceID())
+	assert.Equal(t, psc.SpanID(), span.Parent().SpanID())
+	assert.True(t, span.Parent().IsRemote())
+	assert.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID())
+
+	attrs := attrMap(span.Attributes())
+	assert.Equal(t, "GET", attrs[semconv.HTTPRequestMethodKey].AsString())
+	assert.Equal(t, "example.com", attrs[semconv.ServerAddressKey].AsString())
+	assert.Equal(t, int64(8080), attrs[semconv.ServerPortKey].AsInt64())
+	assert.Equal(t, "http", attrs[semconv.URLSchemeKey].AsString())
+	assert.Equal(t, "/orders", attrs[semconv.URLPathKey].AsString())
+	assert.Equal(t, "id=1", attrs[semconv.URLQueryKey].AsString())
+	assert.Equal(t, "1.1", attrs[semconv.NetworkProtocolVersionKey].AsString())
+	assert.Equal(t, "test-agent", attrs[semconv.UserAgentOriginalKey].AsString())
+	assert.Equal(t, "10.0.0.1", attrs[semconv.ClientAddressKey].AsString())
+	assert.Equal(t, int64(200), attrs[semconv.HTTPResponseStatusCodeKey].AsInt64())
+}
+
+func TestServe_ServerError(t *testing.T) {
+	sr := setupTest(t)
+
+	s := instrumentedServer(func(ctx *fasthttp.RequestCtx) {
+		ctx.Error("boom", fasthttp.StatusInternalServerError)
+	})
+	hc := serve(t, s)
+	assert.Equal(t, fasthttp.StatusInternalServerError, get(t, hc, "http://example.com:8080/", nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	attrs := attrMap(spans[0].Attributes())
+	assert.Equal(t, "500", attrs[semconv.ErrorTypeKey].AsString())
+}
+
+func TestServe_ClientErrorIsNotSpanError(t *testing.T) {
+	sr := setupTest(t)
+
+	s := instrumentedServer(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusNotFound)
+	})
+	hc := serve(t, s)
+	assert.Equal(t, fasthttp.StatusNotFound, get(t, hc, "http://example.com:8080/missing", nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestServe_WrapsHandlerOnce(t *testing.T) {
+	sr := setupTest(t)
+
+	s := instrumentedServer(func(*fasthttp.RequestCtx) {})
+	// A second listener or a connection served directly must not wrap the
+	// handler again.
+	BeforeServeConn(hooktest.NewMockHookContext(s, nil), s, nil)
+	hc := serve(t, s)
+	get(t, hc, "http://example.com:8080/", nil)
+
+	assert.Len(t, sr.Ended(), 1)
+}
+
+func TestServe_ConcurrentServeWaitsForWrappedHandler(t *testing.T) {
+	sr := setupTest(t)
+
+	s := &fasthttp.Server{Handler: func(*fasthttp.RequestCtx) {}}
+	handlers := make(chan fasthttp.RequestHandler, 8)
+	var wg sync.WaitGroup
+	for range cap(handlers) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			BeforeServeConn(hooktest.NewMockHookContext(s, nil), s, nil)
+			// fasthttp reads the handler as soon as the hook returns.
+			handlers <- s.Handler
+		}()
+	}
+	wg.Wait()
+	close(handlers)
+
+	hc := serve(t, s)
+	get(t, hc, "http://example.com:8080/", nil)
+	assert.Len(t, sr.Ended(), 1)
+	for h := range handlers {
+		assert.Equal(t, reflect.ValueOf(s.Handler).Pointer(), reflect.ValueOf(h).Pointer())
+	}
+}
+
+func TestServe_RequestContextCarriesBaggage(t *testing.T) {
+	setupTest(t)
+	propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+	var tenant string
+	var traced bool
+	s := instrumentedServer(func(ctx *fasthttp.RequestCtx) {
+		reqCtx := RequestContext(ctx)
+		traced = trace.SpanContextFromContext(reqCtx).IsValid()
+		tenant = baggage.FromContext(reqCtx).Member("tenant").Value()
+	})
+	hc := serve(t, s)
+	get(t, hc, "http://example.com:8080/", map[string]string{"baggage": "tenant=acme"})
+
+	assert.True(t, traced)
+	assert.Equal(t, "acme", tenant)
+}
+
+func TestServe_Disabled(t *testing.T) {
+	sr := setupTest(t)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "fasthttp")
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "")
+
+	var traced bool
+	s := instrumentedServer(func(ctx *fasthttp.RequestCtx) {
+		traced = trace.SpanContextFromContext(RequestContext(ctx)).IsValid()
+	})
+	hc := serve(t, s)
+	get(t, hc, "http://example.com:8080/", nil)
+
+	assert.False(t, traced)
+	assert.Empty(t, sr.Ended())
+}
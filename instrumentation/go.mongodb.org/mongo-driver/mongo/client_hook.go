@@ -6,8 +6,10 @@ package mongodb
 import (
 	"context"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otelc/pkg/hook"
 	"go.opentelemetry.io/otelc/pkg/runtime"
 )
@@ -24,13 +26,17 @@ func (g mongoEnabler) Enable() bool {
 
 var enabler = mongoEnabler{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, enabler)
+}
+
 // BeforeConnect intercepts mongo.Connect and injects the OTel command monitor
 func BeforeConnect(ictx hook.HookContext, ctx context.Context, opts ...*options.ClientOptions) {
 	if !enabler.Enable() {
 		return
 	}
 
-	monitor := otelmongo.NewMonitor()
+	monitor := newMonitor()
 
 	// If no options were provided, create a default options struct
 	if len(opts) == 0 {
@@ -56,7 +62,7 @@ func BeforeNewClient(ictx hook.HookContext, opts ...*options.ClientOptions) {
 		return
 	}
 
-	monitor := otelmongo.NewMonitor()
+	monitor := newMonitor()
 
 	// If no options were provided, create a default options struct
 	if len(opts) == 0 {
@@ -75,3 +81,18 @@ func BeforeNewClient(ictx hook.HookContext, opts ...*options.ClientOptions) {
 	// Explicitly set parameter to ensure otelc compiles and applies it
 	ictx.SetParam(0, opts)
 }
+
+// newMonitor returns the OTel command monitor, skipping the commands run with
+// a context suppressing the instrumentation. The monitor ignores the
+// completion of commands it did not start a span for.
+func newMonitor() *event.CommandMonitor {
+	monitor := otelmongo.NewMonitor()
+	started := monitor.Started
+	monitor.Started = func(ctx context.Context, evt *event.CommandStartedEvent) {
+		if runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient) {
+			return
+		}
+		started(ctx, evt)
+	}
+	return monitor
+}
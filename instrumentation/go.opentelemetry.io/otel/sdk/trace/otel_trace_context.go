@@ -22,6 +22,9 @@ func registerTraceAndSpanIDFunc(f func() (string, string))
 //go:linkname registerSpanFromGLSFunc go.opentelemetry.io/otelc/pkg/runtime.RegisterSpanFromGLSFunc
 func registerSpanFromGLSFunc(f func() trace.Span)
 
+//go:linkname recordGLSSpanStack go.opentelemetry.io/otelc/pkg/runtime.RecordGLSSpanStack
+func recordGLSSpanStack(depth int, overflow bool)
+
 const defaultGLSMaxSpans = 1000
 
 // maxSpanStates bounds lifecycle bookkeeping. Evicted states are marked ended,
@@ -224,9 +227,11 @@ func setTraceContext(tc *traceContext) {
 
 func traceContextAddSpan(span trace.Span) {
 	tc := getOrInitTraceContext()
-	if tc.add(span) {
+	added := tc.add(span)
+	if added {
 		setTraceContext(tc)
 	}
+	recordGLSSpanStack(tc.n, !added)
 }
 
 func GetTraceAndSpanID() (string, string) {
@@ -106,6 +106,10 @@ func (g grpcClientEnabler) Enable() bool {
 
 var clientEnabler = grpcClientEnabler{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, clientEnabler)
+}
+
 // BeforeNewClient hooks before grpc.NewClient (v1.63+)
 func BeforeNewClient(ictx hook.HookContext, target string, opts ...grpc.DialOption) {
 	if !clientEnabler.Enable() {
@@ -169,20 +173,32 @@ type gRPCContextKey struct{}
 type gRPCContext struct {
 	inMessages    int64
 	outMessages   int64
+	events        int64
+	streaming     atomic.Bool
+	name          string
 	metricAttrs   []attribute.KeyValue
 	metricAttrSet attribute.Set
 }
 
-type clientStatsHandler struct{}
+type clientStatsHandler struct {
+	messages grpcsemconv.MessageConfig
+	filter   grpcsemconv.MethodFilter
+}
 
 func newClientStatsHandler() stats.Handler {
-	return &clientStatsHandler{}
+	return &clientStatsHandler{
+		messages: grpcsemconv.MessageConfigFromEnv(),
+		filter:   grpcsemconv.MethodFilterFromEnv(),
+	}
 }
 
 // TagRPC is called at the beginning of an RPC to create a context
 func (h *clientStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
-	// Skip instrumentation for OTLP exporter endpoints to prevent infinite recursion
-	if grpcsemconv.IsOTELExporterPath(info.FullMethodName) {
+	// Skip instrumentation for OTLP exporter endpoints to prevent infinite
+	// recursion, for the methods excluded by the user, and for suppressed
+	// contexts
+	if grpcsemconv.IsOTELExporterPath(info.FullMethodName) || h.filter.Excluded(info.FullMethodName) ||
+		runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindClient) {
 		return ctx
 	}
 
@@ -202,6 +218,7 @@ func (h *clientStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo)
 
 	// Store gRPC context for metrics
 	gctx := &gRPCContext{
+		name:          name,
 		metricAttrs:   attrs,
 		metricAttrSet: attribute.NewSet(attrs...),
 	}
@@ -213,23 +230,25 @@ func (h *clientStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo)
 func (h *clientStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
 	span := trace.SpanFromContext(ctx)
 	gctx, _ := ctx.Value(gRPCContextKey{}).(*gRPCContext)
+	if gctx == nil {
+		// TagRPC skipped this RPC, so the span in ctx, if any, is not ours
+		return
+	}
 
 	switch rs := rs.(type) {
 	case *stats.Begin:
-		// RPC started
+		gctx.streaming.Store(rs.IsClientStream || rs.IsServerStream)
 	case *stats.OutPayload:
-		if gctx != nil {
-			atomic.AddInt64(&gctx.outMessages, 1)
-			if clientRequestSize != nil {
-				clientRequestSize.RecordSet(ctx, int64(rs.Length), gctx.metricAttrSet)
-			}
+		id := atomic.AddInt64(&gctx.outMessages, 1)
+		h.recordMessage(ctx, gctx, true, id, rs.CompressedLength, rs.Length, rs.SentTime)
+		if clientRequestSize != nil {
+			clientRequestSize.RecordSet(ctx, int64(rs.Length), gctx.metricAttrSet)
 		}
 	case *stats.InPayload:
-		if gctx != nil {
-			atomic.AddInt64(&gctx.inMessages, 1)
-			if clientResponseSize != nil {
-				clientResponseSize.RecordSet(ctx, int64(rs.Length), gctx.metricAttrSet)
-			}
+		id := atomic.AddInt64(&gctx.inMessages, 1)
+		h.recordMessage(ctx, gctx, false, id, rs.CompressedLength, rs.Length, rs.RecvTime)
+		if clientResponseSize != nil {
+			clientResponseSize.RecordSet(ctx, int64(rs.Length), gctx.metricAttrSet)
 		}
 	case *stats.OutHeader:
 		// Add server address attributes
@@ -260,28 +279,64 @@ func (h *clientStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
 		}
 
 		// Record metrics
-		if gctx != nil {
-			metricAttrs := make([]attribute.KeyValue, 0, len(gctx.metricAttrs)+1)
-			metricAttrs = append(metricAttrs, gctx.metricAttrs...)
-			metricAttrs = append(metricAttrs, statusAttr)
-			recordOpts := []metric.RecordOption{metric.WithAttributeSet(attribute.NewSet(metricAttrs...))}
+		metricAttrs := make([]attribute.KeyValue, 0, len(gctx.metricAttrs)+1)
+		metricAttrs = append(metricAttrs, gctx.metricAttrs...)
+		metricAttrs = append(metricAttrs, statusAttr)
+		recordOpts := []metric.RecordOption{metric.WithAttributeSet(attribute.NewSet(metricAttrs...))}
 
-			// Use floating point division for higher precision (instead of Milliseconds method)
-			duration := float64(rs.EndTime.Sub(rs.BeginTime)) / float64(time.Millisecond)
+		// Use floating point division for higher precision (instead of Milliseconds method)
+		duration := float64(rs.EndTime.Sub(rs.BeginTime)) / float64(time.Millisecond)
 
-			if clientDuration.Inst() != nil {
-				clientDuration.Inst().Record(ctx, duration, recordOpts...)
-			}
-			if clientRequestsPerRPC.Inst() != nil {
-				clientRequestsPerRPC.Inst().Record(ctx, atomic.LoadInt64(&gctx.outMessages), recordOpts...)
-			}
-			if clientResponsesPerRPC.Inst() != nil {
-				clientResponsesPerRPC.Inst().Record(ctx, atomic.LoadInt64(&gctx.inMessages), recordOpts...)
-			}
+		if clientDuration.Inst() != nil {
+			clientDuration.Inst().Record(ctx, duration, recordOpts...)
+		}
+		if clientRequestsPerRPC.Inst() != nil {
+			clientRequestsPerRPC.Inst().Record(ctx, atomic.LoadInt64(&gctx.outMessages), recordOpts...)
+		}
+		if clientResponsesPerRPC.Inst() != nil {
+			clientResponsesPerRPC.Inst().Record(ctx, atomic.LoadInt64(&gctx.inMessages), recordOpts...)
 		}
 	}
 }
 
+// recordMessage adds an rpc.message event for a sent or received message to
+// the RPC span, up to the configured limit, and creates a child span for the
+// message when message spans are enabled and the RPC is streaming.
+func (h *clientStatsHandler) recordMessage(
+	ctx context.Context,
+	gctx *gRPCContext,
+	sent bool,
+	id int64,
+	compressedSize, uncompressedSize int,
+	at time.Time,
+) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	attrs := grpcsemconv.MessageAttrs(sent, id, compressedSize, uncompressedSize)
+	if atomic.AddInt64(&gctx.events, 1) <= h.messages.EventsLimit {
+		span.AddEvent(grpcsemconv.MessageEventName, trace.WithTimestamp(at), trace.WithAttributes(attrs...))
+	}
+
+	if h.messages.Spans && gctx.streaming.Load() {
+		name := gctx.name + " receive"
+		if sent {
+			name = gctx.name + " send"
+		}
+		_, msgSpan := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithTimestamp(at),
+			trace.WithAttributes(attrs...),
+		)
+		msgSpan.End(trace.WithTimestamp(at))
+	}
+}
+
 // TagConn is called when a new connection is established
 func (h *clientStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
 	return ctx
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
@@ -488,3 +489,115 @@ func TestClientStatsHandler_OTELExporterFiltering(t *testing.T) {
 		})
 	}
 }
+
+// setupMessageTest points the tracer at an in-memory exporter and returns a
+// handler built from the current environment.
+func setupMessageTest(t *testing.T) (stats.Handler, *tracetest.InMemoryExporter) {
+	t.Helper()
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "grpc")
+	initInstrumentation()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	oldTracer := tracer
+	tracer = tp.Tracer(instrumentationName)
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		tracer = oldTracer
+	})
+	return newClientStatsHandler(), exporter
+}
+
+func TestClientStatsHandler_MessageEvents(t *testing.T) {
+	t.Setenv("OTEL_GO_GRPC_MESSAGE_EVENTS_LIMIT", "3")
+	handler, exporter := setupMessageTest(t)
+
+	ctx := handler.TagRPC(t.Context(), &stats.RPCTagInfo{FullMethodName: "/events.Stream/Subscribe"})
+	handler.HandleRPC(ctx, &stats.Begin{IsClientStream: true, IsServerStream: true})
+	handler.HandleRPC(ctx, &stats.OutPayload{Length: 40, CompressedLength: 12, SentTime: time.Now()})
+	for range 3 {
+		handler.HandleRPC(ctx, &stats.InPayload{Length: 7, CompressedLength: 7, RecvTime: time.Now()})
+	}
+	handler.HandleRPC(ctx, &stats.End{BeginTime: time.Now(), EndTime: time.Now()})
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1, "message spans are off by default")
+	events := spans[0].Events
+	require.Len(t, events, 3, "events are capped by OTEL_GO_GRPC_MESSAGE_EVENTS_LIMIT")
+
+	assert.Equal(t, "rpc.message", events[0].Name)
+	assert.Contains(t, events[0].Attributes, attribute.String("rpc.message.type", "SENT"))
+	assert.Contains(t, events[0].Attributes, attribute.Int("rpc.message.id", 1))
+	assert.Contains(t, events[0].Attributes, attribute.Int("rpc.message.compressed_size", 12))
+	assert.Contains(t, events[0].Attributes, attribute.Int("rpc.message.uncompressed_size", 40))
+	assert.Contains(t, events[2].Attributes, attribute.String("rpc.message.type", "RECEIVED"))
+	assert.Contains(t, events[2].Attributes, attribute.Int("rpc.message.id", 2))
+}
+
+func TestClientStatsHandler_MessageSpans(t *testing.T) {
+	t.Setenv("OTEL_GO_GRPC_MESSAGE_SPANS_ENABLED", "true")
+	handler, exporter := setupMessageTest(t)
+
+	// Unary RPCs get no message spans.
+	ctx := handler.TagRPC(t.Context(), &stats.RPCTagInfo{FullMethodName: "/events.Stream/Get"})
+	handler.HandleRPC(ctx, &stats.Begin{})
+	handler.HandleRPC(ctx, &stats.OutPayload{Length: 1, SentTime: time.Now()})
+	handler.HandleRPC(ctx, &stats.End{BeginTime: time.Now(), EndTime: time.Now()})
+	require.Len(t, exporter.GetSpans(), 1)
+	exporter.Reset()
+
+	ctx = handler.TagRPC(t.Context(), &stats.RPCTagInfo{FullMethodName: "/events.Stream/Subscribe"})
+	handler.HandleRPC(ctx, &stats.Begin{IsServerStream: true})
+	handler.HandleRPC(ctx, &stats.OutPayload{Length: 1, SentTime: time.Now()})
+	handler.HandleRPC(ctx, &stats.InPayload{Length: 2, RecvTime: time.Now()})
+	handler.HandleRPC(ctx, &stats.End{BeginTime: time.Now(), EndTime: time.Now()})
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	rpcSpan := spans[2]
+	assert.Equal(t, "events.Stream/Subscribe", rpcSpan.Name)
+	assert.Equal(t, "events.Stream/Subscribe send", spans[0].Name)
+	assert.Equal(t, "events.Stream/Subscribe receive", spans[1].Name)
+	for _, span := range spans[:2] {
+		assert.Equal(t, rpcSpan.SpanContext.SpanID(), span.Parent.SpanID())
+		assert.Equal(t, trace.SpanKindInternal, span.SpanKind)
+	}
+	assert.Contains(t, spans[1].Attributes, attribute.Int("rpc.message.uncompressed_size", 2))
+}
+
+func TestClientStatsHandler_ExcludedMethods(t *testing.T) {
+	t.Setenv("OTEL_GO_GRPC_EXCLUDED_METHODS", "/grpc.health.v1.Health/Check")
+	handler, exporter := setupMessageTest(t)
+
+	// The caller's span must survive an excluded RPC made under it
+	parentCtx, parent := tracer.Start(t.Context(), "parent")
+	ctx := handler.TagRPC(parentCtx, &stats.RPCTagInfo{FullMethodName: "/grpc.health.v1.Health/Check"})
+	handler.HandleRPC(ctx, &stats.OutPayload{Length: 10, SentTime: time.Now()})
+	handler.HandleRPC(ctx, &stats.End{BeginTime: time.Now(), EndTime: time.Now()})
+	assert.True(t, parent.IsRecording(), "excluded RPC must not end the caller's span")
+	assert.Empty(t, exporter.GetSpans())
+
+	ctx = handler.TagRPC(parentCtx, &stats.RPCTagInfo{FullMethodName: "/grpc.health.v1.Health/Watch"})
+	handler.HandleRPC(ctx, &stats.End{BeginTime: time.Now(), EndTime: time.Now()})
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "grpc.health.v1.Health/Watch", spans[0].Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+}
+
+func TestClientStatsHandler_Suppressed(t *testing.T) {
+	handler, exporter := setupMessageTest(t)
+
+	parentCtx, parent := tracer.Start(t.Context(), "parent")
+	ctx := runtime.Suppress(parentCtx, runtime.SpanKindKey(trace.SpanKindClient))
+	ctx = handler.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/helloworld.Greeter/SayHello"})
+	handler.HandleRPC(ctx, &stats.End{BeginTime: time.Now(), EndTime: time.Now()})
+	assert.True(t, parent.IsRecording(), "suppressed RPC must not end the caller's span")
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "parent", spans[0].Name)
+}
@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package semconv
+
+import (
+	"os"
+	"strings"
+)
+
+// envExcludedMethods lists the RPC methods the client and server handlers do
+// not instrument.
+const envExcludedMethods = "OTEL_GO_GRPC_EXCLUDED_METHODS"
+
+// MethodFilter tells which RPC methods are excluded from instrumentation. The
+// zero value excludes nothing.
+type MethodFilter struct {
+	methods  map[string]struct{}
+	services map[string]struct{}
+}
+
+// MethodFilterFromEnv reads the excluded methods from
+// OTEL_GO_GRPC_EXCLUDED_METHODS, a comma-separated list of full method names
+// such as "grpc.health.v1.Health/Check". The leading slash is optional and an
+// entry of the form "package.Service/*" excludes every method of the service.
+func MethodFilterFromEnv() MethodFilter {
+	return NewMethodFilter(strings.Split(os.Getenv(envExcludedMethods), ","))
+}
+
+// NewMethodFilter returns a MethodFilter excluding the given methods, in the
+// format accepted by MethodFilterFromEnv. Empty entries are ignored.
+func NewMethodFilter(excluded []string) MethodFilter {
+	var f MethodFilter
+	for _, entry := range excluded {
+		entry = strings.TrimPrefix(strings.TrimSpace(entry), "/")
+		if entry == "" {
+			continue
+		}
+		if service, ok := strings.CutSuffix(entry, "/*"); ok {
+			if f.services == nil {
+				f.services = make(map[string]struct{})
+			}
+			f.services[service] = struct{}{}
+			continue
+		}
+		if f.methods == nil {
+			f.methods = make(map[string]struct{})
+		}
+		f.methods[entry] = struct{}{}
+	}
+	return f
+}
+
+// Excluded returns true if fullMethod, in the "/package.Service/Method" form
+// passed to stats handlers, must not be instrumented.
+func (f MethodFilter) Excluded(fullMethod string) bool {
+	if len(f.methods) == 0 && len(f.services) == 0 {
+		return false
+	}
+	method := strings.TrimPrefix(fullMethod, "/")
+	if _, ok := f.methods[method]; ok {
+		return true
+	}
+	if i := strings.LastIndexByte(method, '/'); i >= 0 {
+		_, ok := f.services[method[:i]]
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package semconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodFilterFromEnv(t *testing.T) {
+	t.Setenv(envExcludedMethods,
+		" grpc.health.v1.Health/Check , /grpc.reflection.v1.ServerReflection/*,,")
+	f := MethodFilterFromEnv()
+
+	tests := []struct {
+		fullMethod string
+		excluded   bool
+	}{
+		{"/grpc.health.v1.Health/Check", true},
+		{"/grpc.health.v1.Health/Watch", false},
+		{"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", true},
+		{"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", false},
+		{"/helloworld.Greeter/SayHello", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fullMethod, func(t *testing.T) {
+			assert.Equal(t, tt.excluded, f.Excluded(tt.fullMethod))
+		})
+	}
+}
+
+func TestMethodFilter_Empty(t *testing.T) {
+	t.Setenv(envExcludedMethods, "")
+	assert.False(t, MethodFilterFromEnv().Excluded("/grpc.health.v1.Health/Check"))
+	assert.False(t, MethodFilter{}.Excluded("/helloworld.Greeter/SayHello"))
+}
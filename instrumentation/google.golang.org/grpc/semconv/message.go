@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package semconv
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const (
+	// MessageEventName is the name of the span event recorded for every
+	// message sent or received on an RPC.
+	MessageEventName = "rpc.message"
+
+	// envMessageEventsLimit caps the rpc.message events recorded on one RPC
+	// span. 0 disables the events.
+	envMessageEventsLimit = "OTEL_GO_GRPC_MESSAGE_EVENTS_LIMIT"
+	// envMessageSpans turns on a child span per message of streaming RPCs.
+	envMessageSpans = "OTEL_GO_GRPC_MESSAGE_SPANS_ENABLED"
+
+	// defaultMessageEventsLimit matches the SDK default span event count
+	// limit, past which events would be dropped anyway.
+	defaultMessageEventsLimit = 128
+)
+
+// MessageConfig controls how individual RPC messages are recorded.
+type MessageConfig struct {
+	// EventsLimit is the maximum number of rpc.message events recorded on
+	// one RPC span. Messages past the limit are only counted in metrics.
+	EventsLimit int64
+	// Spans creates a child span for every message of a streaming RPC, so
+	// that long-lived streams show each message without the events limit.
+	Spans bool
+}
+
+// MessageConfigFromEnv reads the MessageConfig from the environment. Unset or
+// unparsable values fall back to the defaults: up to 128 events per RPC and no
+// message spans.
+func MessageConfigFromEnv() MessageConfig {
+	cfg := MessageConfig{EventsLimit: defaultMessageEventsLimit}
+	if v, err := strconv.ParseInt(strings.TrimSpace(os.Getenv(envMessageEventsLimit)), 10, 64); err == nil && v >= 0 {
+		cfg.EventsLimit = v
+	}
+	if v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(envMessageSpans))); err == nil {
+		cfg.Spans = v
+	}
+	return cfg
+}
+
+// MessageAttrs returns the attributes of a sent or received message. id is
+// the 1-based sequence number of the message in its direction.
+func MessageAttrs(sent bool, id int64, compressedSize, uncompressedSize int) []attribute.KeyValue {
+	typ := semconv.RPCMessageTypeReceived
+	if sent {
+		typ = semconv.RPCMessageTypeSent
+	}
+	return []attribute.KeyValue{
+		typ,
+		semconv.RPCMessageID(int(id)),
+		semconv.RPCMessageCompressedSize(compressedSize),
+		semconv.RPCMessageUncompressedSize(uncompressedSize),
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package semconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestMessageConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    string
+		spans    string
+		expected MessageConfig
+	}{
+		{
+			name:     "defaults",
+			expected: MessageConfig{EventsLimit: 128},
+		},
+		{
+			name:     "custom limit and spans",
+			limit:    "10",
+			spans:    "true",
+			expected: MessageConfig{EventsLimit: 10, Spans: true},
+		},
+		{
+			name:     "events disabled",
+			limit:    "0",
+			expected: MessageConfig{EventsLimit: 0},
+		},
+		{
+			name:     "invalid values fall back to defaults",
+			limit:    "-1",
+			spans:    "sometimes",
+			expected: MessageConfig{EventsLimit: 128},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envMessageEventsLimit, tt.limit)
+			t.Setenv(envMessageSpans, tt.spans)
+			assert.Equal(t, tt.expected, MessageConfigFromEnv())
+		})
+	}
+}
+
+func TestMessageAttrs(t *testing.T) {
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("rpc.message.type", "SENT"),
+		attribute.Int("rpc.message.id", 1),
+		attribute.Int("rpc.message.compressed_size", 12),
+		attribute.Int("rpc.message.uncompressed_size", 40),
+	}, MessageAttrs(true, 1, 12, 40))
+
+	attrs := MessageAttrs(false, 3, 0, 7)
+	assert.Equal(t, attribute.String("rpc.message.type", "RECEIVED"), attrs[0])
+	assert.Equal(t, attribute.Int("rpc.message.id", 3), attrs[1])
+}
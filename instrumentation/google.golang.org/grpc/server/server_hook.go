@@ -5,6 +5,8 @@ package server
 
 import (
 	"context"
+	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -30,6 +32,10 @@ const (
 	instrumentationName = "go.opentelemetry.io/otelc/instrumentation/google.golang.org/grpc"
 	instrumentationKey  = "GRPC"
 	optionsParamIndex   = 0
+
+	// otelgrpcPackage is the package of the stats handlers created by
+	// otelgrpc.NewServerHandler.
+	otelgrpcPackage = "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 )
 
 type int64Hist interface {
@@ -43,6 +49,10 @@ var (
 	meter      metric.Meter
 	initOnce   sync.Once
 
+	// otelOptions holds the server options created by grpc.StatsHandler with
+	// an OpenTelemetry stats handler, until a grpc.NewServer call takes them.
+	otelOptions sync.Map
+
 	// Metrics
 	serverDuration        rpcconv.ServerDuration
 	serverRequestSize     int64Hist
@@ -104,6 +114,10 @@ func (g grpcServerEnabler) Enable() bool {
 
 var serverEnabler = grpcServerEnabler{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, serverEnabler)
+}
+
 // BeforeNewServer hooks before grpc.NewServer to inject stats handler
 func BeforeNewServer(ictx hook.HookContext, opts ...grpc.ServerOption) {
 	if !serverEnabler.Enable() {
@@ -111,6 +125,11 @@ func BeforeNewServer(ictx hook.HookContext, opts ...grpc.ServerOption) {
 		return
 	}
 
+	if takeOTelStatsHandler(opts) {
+		logger.Debug("BeforeNewServer skipped, server already has an OpenTelemetry stats handler")
+		return
+	}
+
 	initInstrumentation()
 
 	logger.Debug("BeforeNewServer called")
@@ -129,25 +148,87 @@ func AfterNewServer(ictx hook.HookContext, server *grpc.Server) {
 	logger.Debug("AfterNewServer called")
 }
 
+// BeforeStatsHandler hooks before grpc.StatsHandler to tell whether the
+// handler already produces OpenTelemetry telemetry
+func BeforeStatsHandler(ictx hook.HookContext, h stats.Handler) {
+	if !serverEnabler.Enable() {
+		return
+	}
+	ictx.SetData(isOTelStatsHandler(h))
+}
+
+// AfterStatsHandler hooks after grpc.StatsHandler to remember the options
+// carrying an OpenTelemetry stats handler, so that BeforeNewServer does not
+// add a second one
+func AfterStatsHandler(ictx hook.HookContext, opt grpc.ServerOption) {
+	if otelHandler, _ := ictx.GetData().(bool); !otelHandler || opt == nil {
+		return
+	}
+	if !reflect.ValueOf(opt).Comparable() {
+		return
+	}
+	otelOptions.Store(opt, struct{}{})
+}
+
+// isOTelStatsHandler returns true for the stats handlers of otelgrpc.
+func isOTelStatsHandler(h stats.Handler) bool {
+	if h == nil {
+		return false
+	}
+	t := reflect.TypeOf(h)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.PkgPath() == otelgrpcPackage || strings.HasPrefix(t.PkgPath(), otelgrpcPackage+"/")
+}
+
+// takeOTelStatsHandler reports whether opts carry an OpenTelemetry stats
+// handler, and forgets those options so that otelOptions does not keep them
+// alive past the server they configure.
+func takeOTelStatsHandler(opts []grpc.ServerOption) bool {
+	found := false
+	for _, opt := range opts {
+		if opt == nil || !reflect.ValueOf(opt).Comparable() {
+			continue
+		}
+		if _, ok := otelOptions.LoadAndDelete(opt); ok {
+			found = true
+		}
+	}
+	return found
+}
+
 type gRPCContextKey struct{}
 
 type gRPCContext struct {
 	inMessages    int64
 	outMessages   int64
+	events        int64
+	streaming     atomic.Bool
+	name          string
 	metricAttrs   []attribute.KeyValue
 	metricAttrSet attribute.Set
 }
 
-type serverStatsHandler struct{}
+type serverStatsHandler struct {
+	messages grpcsemconv.MessageConfig
+	filter   grpcsemconv.MethodFilter
+}
 
 func newServerStatsHandler() stats.Handler {
-	return &serverStatsHandler{}
+	return &serverStatsHandler{
+		messages: grpcsemconv.MessageConfigFromEnv(),
+		filter:   grpcsemconv.MethodFilterFromEnv(),
+	}
 }
 
 // TagRPC is called at the beginning of an RPC to create a context
 func (h *serverStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
-	// Skip instrumentation for OTLP exporter endpoints to prevent infinite recursion
-	if grpcsemconv.IsOTELExporterPath(info.FullMethodName) {
+	// Skip instrumentation for OTLP exporter endpoints to prevent infinite
+	// recursion, for the methods excluded by the user, and for suppressed
+	// contexts
+	if grpcsemconv.IsOTELExporterPath(info.FullMethodName) || h.filter.Excluded(info.FullMethodName) ||
+		runtime.IsSpanSuppressed(ctx, instrumentationKey, trace.SpanKindServer) {
 		return ctx
 	}
 
@@ -167,6 +248,7 @@ func (h *serverStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo)
 
 	// Store gRPC context for metrics
 	gctx := &gRPCContext{
+		name:          name,
 		metricAttrs:   attrs,
 		metricAttrSet: attribute.NewSet(attrs...),
 	}
@@ -178,23 +260,25 @@ func (h *serverStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo)
 func (h *serverStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
 	span := trace.SpanFromContext(ctx)
 	gctx, _ := ctx.Value(gRPCContextKey{}).(*gRPCContext)
+	if gctx == nil {
+		// TagRPC skipped this RPC, so the span in ctx, if any, is not ours
+		return
+	}
 
 	switch rs := rs.(type) {
 	case *stats.Begin:
-		// RPC started
+		gctx.streaming.Store(rs.IsClientStream || rs.IsServerStream)
 	case *stats.InPayload:
-		if gctx != nil {
-			atomic.AddInt64(&gctx.inMessages, 1)
-			if serverRequestSize != nil {
-				serverRequestSize.RecordSet(ctx, int64(rs.Length), gctx.metricAttrSet)
-			}
+		id := atomic.AddInt64(&gctx.inMessages, 1)
+		h.recordMessage(ctx, gctx, false, id, rs.CompressedLength, rs.Length, rs.RecvTime)
+		if serverRequestSize != nil {
+			serverRequestSize.RecordSet(ctx, int64(rs.Length), gctx.metricAttrSet)
 		}
 	case *stats.OutPayload:
-		if gctx != nil {
-			atomic.AddInt64(&gctx.outMessages, 1)
-			if serverResponseSize != nil {
-				serverResponseSize.RecordSet(ctx, int64(rs.Length), gctx.metricAttrSet)
-			}
+		id := atomic.AddInt64(&gctx.outMessages, 1)
+		h.recordMessage(ctx, gctx, true, id, rs.CompressedLength, rs.Length, rs.SentTime)
+		if serverResponseSize != nil {
+			serverResponseSize.RecordSet(ctx, int64(rs.Length), gctx.metricAttrSet)
 		}
 	case *stats.OutHeader:
 		// Add peer address attributes
@@ -225,28 +309,64 @@ func (h *serverStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
 		}
 
 		// Record metrics
-		if gctx != nil {
-			metricAttrs := make([]attribute.KeyValue, 0, len(gctx.metricAttrs)+1)
-			metricAttrs = append(metricAttrs, gctx.metricAttrs...)
-			metricAttrs = append(metricAttrs, statusAttr)
-			recordOpts := []metric.RecordOption{metric.WithAttributeSet(attribute.NewSet(metricAttrs...))}
+		metricAttrs := make([]attribute.KeyValue, 0, len(gctx.metricAttrs)+1)
+		metricAttrs = append(metricAttrs, gctx.metricAttrs...)
+		metricAttrs = append(metricAttrs, statusAttr)
+		recordOpts := []metric.RecordOption{metric.WithAttributeSet(attribute.NewSet(metricAttrs...))}
 
-			// Use floating point division for higher precision (instead of Milliseconds method)
-			duration := float64(rs.EndTime.Sub(rs.BeginTime)) / float64(time.Millisecond)
+		// Use floating point division for higher precision (instead of Milliseconds method)
+		duration := float64(rs.EndTime.Sub(rs.BeginTime)) / float64(time.Millisecond)
 
-			if serverDuration.Inst() != nil {
-				serverDuration.Inst().Record(ctx, duration, recordOpts...)
-			}
-			if serverRequestsPerRPC.Inst() != nil {
-				serverRequestsPerRPC.Inst().Record(ctx, atomic.LoadInt64(&gctx.inMessages), recordOpts...)
-			}
-			if serverResponsesPerRPC.Inst() != nil {
-				serverResponsesPerRPC.Inst().Record(ctx, atomic.LoadInt64(&gctx.outMessages), recordOpts...)
-			}
+		if serverDuration.Inst() != nil {
+			serverDuration.Inst().Record(ctx, duration, recordOpts...)
+		}
+		if serverRequestsPerRPC.Inst() != nil {
+			serverRequestsPerRPC.Inst().Record(ctx, atomic.LoadInt64(&gctx.inMessages), recordOpts...)
+		}
+		if serverResponsesPerRPC.Inst() != nil {
+			serverResponsesPerRPC.Inst().Record(ctx, atomic.LoadInt64(&gctx.outMessages), recordOpts...)
 		}
 	}
 }
 
+// recordMessage adds an rpc.message event for a sent or received message to
+// the RPC span, up to the configured limit, and creates a child span for the
+// message when message spans are enabled and the RPC is streaming.
+func (h *serverStatsHandler) recordMessage(
+	ctx context.Context,
+	gctx *gRPCContext,
+	sent bool,
+	id int64,
+	compressedSize, uncompressedSize int,
+	at time.Time,
+) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	attrs := grpcsemconv.MessageAttrs(sent, id, compressedSize, uncompressedSize)
+	if atomic.AddInt64(&gctx.events, 1) <= h.messages.EventsLimit {
+		span.AddEvent(grpcsemconv.MessageEventName, trace.WithTimestamp(at), trace.WithAttributes(attrs...))
+	}
+
+	if h.messages.Spans && gctx.streaming.Load() {
+		name := gctx.name + " receive"
+		if sent {
+			name = gctx.name + " send"
+		}
+		_, msgSpan := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithTimestamp(at),
+			trace.WithAttributes(attrs...),
+		)
+		msgSpan.End(trace.WithTimestamp(at))
+	}
+}
+
 // TagConn is called when a new connection is established
 func (h *serverStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
 	return ctx
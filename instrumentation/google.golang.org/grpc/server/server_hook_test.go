@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
@@ -345,3 +346,102 @@ func TestServerStatsHandler_OTELExporterFiltering(t *testing.T) {
 		})
 	}
 }
+
+func TestServerStatsHandler_MessageEventsAndSpans(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "grpc")
+	t.Setenv("OTEL_GO_GRPC_MESSAGE_SPANS_ENABLED", "true")
+	initInstrumentation()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	oldTracer := tracer
+	tracer = tp.Tracer(instrumentationName)
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		tracer = oldTracer
+	})
+
+	handler := newServerStatsHandler()
+	ctx := handler.TagRPC(t.Context(), &stats.RPCTagInfo{FullMethodName: "/events.Stream/Publish"})
+	handler.HandleRPC(ctx, &stats.Begin{IsClientStream: true})
+	handler.HandleRPC(ctx, &stats.InPayload{Length: 30, CompressedLength: 10, RecvTime: time.Now()})
+	handler.HandleRPC(ctx, &stats.InPayload{Length: 20, CompressedLength: 20, RecvTime: time.Now()})
+	handler.HandleRPC(ctx, &stats.OutPayload{Length: 5, SentTime: time.Now()})
+	handler.HandleRPC(ctx, &stats.End{BeginTime: time.Now(), EndTime: time.Now()})
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 4)
+	rpcSpan := spans[3]
+	assert.Equal(t, "events.Stream/Publish", rpcSpan.Name)
+	require.Len(t, rpcSpan.Events, 3)
+	assert.Equal(t, "rpc.message", rpcSpan.Events[1].Name)
+	assert.Contains(t, rpcSpan.Events[1].Attributes, attribute.String("rpc.message.type", "RECEIVED"))
+	assert.Contains(t, rpcSpan.Events[1].Attributes, attribute.Int("rpc.message.id", 2))
+	assert.Contains(t, rpcSpan.Events[2].Attributes, attribute.String("rpc.message.type", "SENT"))
+	assert.Contains(t, rpcSpan.Events[2].Attributes, attribute.Int("rpc.message.id", 1))
+
+	assert.Equal(t, "events.Stream/Publish receive", spans[0].Name)
+	assert.Equal(t, "events.Stream/Publish send", spans[2].Name)
+	assert.Equal(t, rpcSpan.SpanContext.SpanID(), spans[0].Parent.SpanID())
+}
+
+func TestBeforeNewServer_SkipsExistingOTelStatsHandler(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "grpc")
+
+	// grpc.StatsHandler(otelgrpc.NewServerHandler()) as seen by the hooks
+	otelOpt := grpc.StatsHandler(&serverStatsHandler{})
+	ictx := hooktest.NewMockHookContext()
+	ictx.SetData(true)
+	AfterStatsHandler(ictx, otelOpt)
+	t.Cleanup(func() { otelOptions.Delete(otelOpt) })
+
+	opts := []grpc.ServerOption{grpc.MaxRecvMsgSize(1024), otelOpt}
+	ictx = hooktest.NewMockHookContext(opts)
+	BeforeNewServer(ictx, opts...)
+	assert.Equal(t, opts, ictx.GetParam(0), "options must be left untouched")
+	_, kept := otelOptions.Load(otelOpt)
+	assert.False(t, kept, "the option is forgotten once the server took it")
+
+	// Handlers of other packages, including this one, are not otelgrpc handlers
+	ictx = hooktest.NewMockHookContext()
+	BeforeStatsHandler(ictx, newServerStatsHandler())
+	assert.Equal(t, false, ictx.GetData())
+	AfterStatsHandler(ictx, grpc.StatsHandler(newServerStatsHandler()))
+
+	opts = []grpc.ServerOption{grpc.StatsHandler(newServerStatsHandler())}
+	ictx = hooktest.NewMockHookContext(opts)
+	BeforeNewServer(ictx, opts...)
+	newOpts, ok := ictx.GetParam(0).([]grpc.ServerOption)
+	require.True(t, ok)
+	assert.Len(t, newOpts, 2, "Expected stats handler to be added")
+}
+
+func TestServerStatsHandler_ExcludedMethods(t *testing.T) {
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "grpc")
+	t.Setenv("OTEL_GO_GRPC_EXCLUDED_METHODS", "grpc.health.v1.Health/Check,grpc.reflection.v1.ServerReflection/*")
+	initInstrumentation()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	oldTracer := tracer
+	tracer = tp.Tracer(instrumentationName)
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		tracer = oldTracer
+	})
+
+	handler := newServerStatsHandler()
+	for _, method := range []string{
+		"/grpc.health.v1.Health/Check",
+		"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+		"/helloworld.Greeter/SayHello",
+	} {
+		ctx := handler.TagRPC(t.Context(), &stats.RPCTagInfo{FullMethodName: method})
+		handler.HandleRPC(ctx, &stats.InPayload{Length: 10, RecvTime: time.Now()})
+		handler.HandleRPC(ctx, &stats.End{BeginTime: time.Now(), EndTime: time.Now()})
+	}
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "helloworld.Greeter/SayHello", spans[0].Name)
+}
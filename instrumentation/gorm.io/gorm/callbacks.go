@@ -0,0 +1,211 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gorm instruments the ORM gorm.io/gorm. Every ORM operation runs
+// inside a span named after the operation and the table, such as
+// "gorm.create users". The statements the operation sends through
+// database/sql are traced by the database/sql instrumentation as children of
+// that span.
+package gorm
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	httpsemconv "go.opentelemetry.io/otelc/instrumentation/net/http/semconv"
+	"go.opentelemetry.io/otelc/pkg/hook"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+const (
+	instrumentationName = "go.opentelemetry.io/otelc/instrumentation/gorm.io/gorm"
+	instrumentationKey  = "GORM"
+
+	// operationDataKey is the statement instance key of the operationData of
+	// the running operation.
+	operationDataKey = "otelc:operation"
+)
+
+// gormEnablerImpl controls whether the GORM instrumentation is enabled.
+type gormEnablerImpl struct{}
+
+func (gormEnablerImpl) Enable() bool {
+	return runtime.Instrumented(instrumentationKey)
+}
+
+var gormEnabler = gormEnablerImpl{}
+
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, gormEnabler)
+}
+
+var (
+	logger   = runtime.Logger()
+	tracer   trace.Tracer
+	initOnce sync.Once
+)
+
+func initInstrumentation() {
+	initOnce.Do(func() {
+		tracer = otel.GetTracerProvider().Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(runtime.ModuleVersion()),
+		)
+		logger.Info("GORM instrumentation initialized")
+	})
+}
+
+// AfterOpen registers the tracing callbacks on the *gorm.DB returned by
+// gorm.Open.
+func AfterOpen(ictx hook.HookContext, db *gorm.DB, err error) {
+	if !gormEnabler.Enable() {
+		logger.Debug("GORM instrumentation disabled")
+		return
+	}
+	if err != nil || db == nil {
+		return
+	}
+	initInstrumentation()
+
+	registerCallbacks(db)
+}
+
+// registerer is the part of a gorm callback used to register a function.
+type registerer interface {
+	Register(name string, fn func(*gorm.DB)) error
+}
+
+// registerCallbacks registers, for every kind of ORM operation, a callback
+// starting the operation span before all the others and one ending it after
+// all the others, so the span covers the whole operation, including the
+// hooks of the models and the implicit transaction.
+func registerCallbacks(db *gorm.DB) {
+	cb := db.Callback()
+	register := func(operation string, before, after registerer) {
+		if err := before.Register("otelc:before_"+operation, beforeOperation(operation)); err != nil {
+			logger.Error("failed to register GORM callback", "operation", operation, "error", err)
+			return
+		}
+		if err := after.Register("otelc:after_"+operation, afterOperation); err != nil {
+			logger.Error("failed to register GORM callback", "operation", operation, "error", err)
+		}
+	}
+	register("create", cb.Create().Before("*"), cb.Create().After("*"))
+	register("query", cb.Query().Before("*"), cb.Query().After("*"))
+	register("update", cb.Update().Before("*"), cb.Update().After("*"))
+	register("delete", cb.Delete().Before("*"), cb.Delete().After("*"))
+	register("row", cb.Row().Before("*"), cb.Row().After("*"))
+	register("raw", cb.Raw().Before("*"), cb.Raw().After("*"))
+}
+
+// operationData is the span of a running ORM operation, and the statement
+// context it replaced.
+type operationData struct {
+	span   trace.Span
+	parent context.Context
+}
+
+// beforeOperation returns the callback starting the span of operation. The
+// span becomes the parent of the statements the operation executes through
+// the statement context.
+func beforeOperation(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement == nil {
+			return
+		}
+		parent := db.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		if runtime.IsSpanSuppressed(parent, instrumentationKey, trace.SpanKindInternal) {
+			return
+		}
+		table := db.Statement.Table
+		ctx, span := tracer.Start(parent, spanName(operation, table),
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(operationAttrs(db, operation, table)...),
+		)
+		db.InstanceSet(operationDataKey, &operationData{span: span, parent: db.Statement.Context})
+		db.Statement.Context = ctx
+	}
+}
+
+// afterOperation ends the span started by beforeOperation and restores the
+// statement context.
+func afterOperation(db *gorm.DB) {
+	if db.Statement == nil {
+		return
+	}
+	v, ok := db.InstanceGet(operationDataKey)
+	if !ok {
+		return
+	}
+	data, ok := v.(*operationData)
+	if !ok || data == nil {
+		return
+	}
+	db.Statement.Context = data.parent
+
+	span := data.span
+	defer span.End()
+	// The table of statements built from a model is only known once the
+	// operation parsed it.
+	if table := db.Statement.Table; table != "" {
+		span.SetAttributes(semconv.DBCollectionName(table))
+	}
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(httpsemconv.ErrorType(err))
+	}
+}
+
+// spanName returns the name of the span of operation on table, such as
+// "gorm.create users".
+func spanName(operation, table string) string {
+	if table == "" {
+		return "gorm." + operation
+	}
+	return "gorm." + operation + " " + table
+}
+
+// operationAttrs returns the attributes of the span of operation on table.
+func operationAttrs(db *gorm.DB, operation, table string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.DBOperationName(operation),
+		dbSystem(db),
+	}
+	if table != "" {
+		attrs = append(attrs, semconv.DBCollectionName(table))
+	}
+	return attrs
+}
+
+// dbSystem returns the db.system.name attribute of the dialector of db.
+func dbSystem(db *gorm.DB) attribute.KeyValue {
+	if db.Dialector == nil {
+		return semconv.DBSystemNameOtherSQL
+	}
+	switch db.Dialector.Name() {
+	case "mysql":
+		return semconv.DBSystemNameMySQL
+	case "postgres":
+		return semconv.DBSystemNamePostgreSQL
+	case "sqlite":
+		return semconv.DBSystemNameSQLite
+	case "sqlserver":
+		return semconv.DBSystemNameMicrosoftSQLServer
+	case "clickhouse":
+		return semconv.DBSystemNameClickHouse
+	default:
+		return semconv.DBSystemNameOtherSQL
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gorm
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+
+	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+	"go.opentelemetry.io/otelc/pkg/runtime"
+)
+
+type order struct {
+	ID    uint
+	Total int
+}
+
+func setupTest(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "gorm")
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	// Consume initOnce so initInstrumentation() becomes a no-op and does not
+	// overwrite the tracer we install below.
+	initOnce.Do(func() {})
+	tracer = tp.Tracer("test")
+
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		initOnce = sync.Once{}
+		tracer = nil
+	})
+	return sr
+}
+
+// open opens a dry run *gorm.DB and runs AfterOpen on it the way the injected
+// trampoline would.
+func open(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+	AfterOpen(hooktest.NewMockHookContext(tests.DummyDialector{}), db, nil)
+	return db
+}
+
+func attrMap(attrs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(attrs))
+	for _, kv := range attrs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestOperationSpans(t *testing.T) {
+	sr := setupTest(t)
+	db := open(t)
+
+	parentCtx, parent := tracer.Start(context.Background(), "parent")
+	tx := db.WithContext(parentCtx)
+	require.NoError(t, tx.Create(&order{Total: 42}).Error)
+	var o order
+	require.NoError(t, tx.Where("total > ?", 10).Find(&o).Error)
+	require.NoError(t, tx.Model(&order{ID: 1}).Update("total", 7).Error)
+	require.NoError(t, tx.Exec("VACUUM").Error)
+	parent.End()
+
+	spans := sr.Ended()
+	require.Len(t, spans, 5)
+	names := make([]string, 0, len(spans))
+	for _, span := range spans[:4] {
+		names = append(names, span.Name())
+		assert.Equal(t, trace.SpanKindInternal, span.SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Equal(t, codes.Unset, span.Status().Code)
+	}
+	assert.Equal(t, []string{"gorm.create orders", "gorm.query orders", "gorm.update orders", "gorm.raw"}, names)
+
+	attrs := attrMap(spans[0].Attributes())
+	assert.Equal(t, "create", attrs[semconv.DBOperationNameKey].AsString())
+	assert.Equal(t, "orders", attrs[semconv.DBCollectionNameKey].AsString())
+	assert.Equal(t, semconv.DBSystemNameOtherSQL.Value, attrs[semconv.DBSystemNameKey])
+
+	_, hasTable := attrMap(spans[3].Attributes())[semconv.DBCollectionNameKey]
+	assert.False(t, hasTable)
+}
+
+func TestOperationSpans_StatementContext(t *testing.T) {
+	sr := setupTest(t)
+	db := open(t)
+
+	// The statements of the operation, which database/sql traces, run with
+	// the operation span in their context.
+	var stmtSpan trace.SpanContext
+	require.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:capture", func(db *gorm.DB) {
+		stmtSpan = trace.SpanContextFromContext(db.Statement.Context)
+	}))
+
+	ctx := context.Background()
+	tx := db.WithContext(ctx)
+	require.NoError(t, tx.Create(&order{Total: 42}).Error)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, spans[0].SpanContext().SpanID(), stmtSpan.SpanID())
+	assert.Equal(t, ctx, tx.Statement.Context, "statement context must be restored")
+}
+
+func TestOperationSpans_Error(t *testing.T) {
+	sr := setupTest(t)
+	db := open(t)
+
+	err := db.Delete(&order{}).Error
+	require.ErrorIs(t, err, gorm.ErrMissingWhereClause)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "gorm.delete orders", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, attrMap(spans[0].Attributes()), semconv.ErrorTypeKey)
+}
+
+func TestOperationSpans_Suppressed(t *testing.T) {
+	sr := setupTest(t)
+	db := open(t)
+
+	ctx := runtime.Suppress(context.Background(), "GORM")
+	require.NoError(t, db.WithContext(ctx).Create(&order{Total: 42}).Error)
+
+	assert.Empty(t, sr.Ended())
+}
+
+func TestAfterOpen_Disabled(t *testing.T) {
+	sr := setupTest(t)
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "gorm")
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "")
+
+	db := open(t)
+	require.NoError(t, db.Create(&order{Total: 42}).Error)
+
+	assert.Empty(t, sr.Ended())
+	assert.Nil(t, db.Callback().Create().Get("otelc:before_create"))
+}
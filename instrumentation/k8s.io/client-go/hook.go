@@ -37,6 +37,11 @@ func (g k8SClientGoEnabler) Enable() bool {
 
 var k8SEnabler = k8SClientGoEnabler{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, k8SEnabler)
+	runtime.RegisterUnsuppressible(instrumentationKey, "informer event handling")
+}
+
 func initInstrumentation() {
 	initOnce.Do(func() {
 		tracer = otel.GetTracerProvider().Tracer(
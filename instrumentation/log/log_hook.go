@@ -25,6 +25,10 @@ func (l logEnabler) Enable() bool {
 
 var enabler = logEnabler{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, enabler)
+}
+
 func BeforeLogOutput(
 	ictx hook.HookContext,
 	logger *log.Logger,
@@ -24,6 +24,10 @@ func (l logEnabler) Enable() bool {
 
 var enabler = logEnabler{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, enabler)
+}
+
 func AfterSlogNewRecord(ictx hook.HookContext, r slog.Record) {
 	if !enabler.Enable() {
 		return
@@ -53,13 +53,17 @@ func (n netHttpClientEnabler) Enable() bool {
 
 var clientEnabler = netHttpClientEnabler{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, clientEnabler)
+}
+
 func BeforeRoundTrip(ictx hook.HookContext, transport *http.Transport, req *http.Request) {
 	if !clientEnabler.Enable() {
 		logger.Debug("HTTP client instrumentation disabled")
 		return
 	}
 
-	if runtime.IsHTTPClientInstrumentationSuppressed(req.Context()) {
+	if runtime.IsSpanSuppressed(req.Context(), instrumentationKey, trace.SpanKindClient) {
 		return
 	}
 
@@ -22,6 +22,7 @@ import (
 
 	"go.opentelemetry.io/otelc/pkg/hook"
 	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+	"go.opentelemetry.io/otelc/pkg/runtime"
 )
 
 func setupTestTracer(t *testing.T) (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
@@ -84,6 +85,30 @@ func TestBeforeRoundTrip(t *testing.T) {
 			},
 			expectSpan: false,
 		},
+		{
+			name: "instrumentation suppressed",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "nethttp")
+			},
+			setupRequest: func() *http.Request {
+				ctx := runtime.Suppress(context.Background(), "NETHTTP")
+				req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/path", nil)
+				return req
+			},
+			expectSpan: false,
+		},
+		{
+			name: "client spans suppressed",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "nethttp")
+			},
+			setupRequest: func() *http.Request {
+				ctx := runtime.Suppress(context.Background(), runtime.SpanKindKey(trace.SpanKindClient))
+				req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com/path", nil)
+				return req
+			},
+			expectSpan: false,
+		},
 		{
 			name: "POST request",
 			setupEnv: func(t *testing.T) {
@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"reflect"
 	"strconv"
 	"strings"
 
@@ -203,20 +202,7 @@ func (HTTPClient) ResponseTraceAttrs(resp *http.Response) []attribute.KeyValue {
 
 // ErrorType returns the error.type attribute for a given error.
 func (HTTPClient) ErrorType(err error) attribute.KeyValue {
-	t := reflect.TypeOf(err)
-	var value string
-	if t.PkgPath() == "" && t.Name() == "" {
-		// Likely a builtin type.
-		value = t.String()
-	} else {
-		value = fmt.Sprintf("%s.%s", t.PkgPath(), t.Name())
-	}
-
-	if value == "" {
-		return semconv.ErrorTypeOther
-	}
-
-	return semconv.ErrorTypeKey.String(value)
+	return ErrorType(err)
 }
 
 // method returns the HTTP method attribute and optional original method attribute.
@@ -6,8 +6,10 @@
 package semconv
 
 import (
+	"fmt"
 	"net"
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -116,6 +118,26 @@ var MethodLookup = map[string]attribute.KeyValue{
 	"QUERY":            upstream.HTTPRequestMethodKey.String("QUERY"),
 }
 
+// ErrorType returns the error.type attribute for err, using the fully
+// qualified type name of the error. Instrumentations of other libraries use it
+// as well, for errors that carry no more specific type.
+func ErrorType(err error) attribute.KeyValue {
+	t := reflect.TypeOf(err)
+	var value string
+	if t.PkgPath() == "" && t.Name() == "" {
+		// Likely a builtin type.
+		value = t.String()
+	} else {
+		value = fmt.Sprintf("%s.%s", t.PkgPath(), t.Name())
+	}
+
+	if value == "" {
+		return upstream.ErrorTypeOther
+	}
+
+	return upstream.ErrorTypeKey.String(value)
+}
+
 // HandleErr reports errors to the OTel error handler.
 func HandleErr(err error) {
 	if err != nil {
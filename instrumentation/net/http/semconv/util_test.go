@@ -4,6 +4,8 @@
 package semconv
 
 import (
+	"errors"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -165,3 +167,9 @@ func TestServerClientIP(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorType(t *testing.T) {
+	assert.Equal(t, "*errors.errorString", ErrorType(errors.New("boom")).Value.AsString())
+	assert.Equal(t, "syscall.Errno", ErrorType(syscall.ENOENT).Value.AsString())
+	assert.Equal(t, "error.type", string(ErrorType(errors.New("boom")).Key))
+}
@@ -52,11 +52,18 @@ func (n netHttpServerEnabler) Enable() bool {
 
 var serverEnabler = netHttpServerEnabler{}
 
+func init() {
+	runtime.RegisterInstrumentation(instrumentationKey, serverEnabler)
+}
+
 func BeforeServeHTTP(ictx hook.HookContext, recv interface{}, w http.ResponseWriter, r *http.Request) {
 	if !serverEnabler.Enable() {
 		logger.Debug("HTTP server instrumentation disabled")
 		return
 	}
+	if runtime.IsSpanSuppressed(r.Context(), instrumentationKey, trace.SpanKindServer) {
+		return
+	}
 
 	initInstrumentation()
 
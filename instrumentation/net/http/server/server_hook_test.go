@@ -21,6 +21,7 @@ import (
 
 	"go.opentelemetry.io/otelc/pkg/hook"
 	"go.opentelemetry.io/otelc/pkg/hook/hooktest"
+	"go.opentelemetry.io/otelc/pkg/runtime"
 )
 
 func setupTestTracer(t *testing.T) (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
@@ -70,6 +71,18 @@ func TestBeforeServeHTTP(t *testing.T) {
 			},
 			expectSpan: false,
 		},
+		{
+			name: "server spans suppressed",
+			setupEnv: func(t *testing.T) {
+				t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "nethttp")
+			},
+			setupRequest: func() *http.Request {
+				req := httptest.NewRequest("GET", "http://example.com/path", nil)
+				ctx := runtime.Suppress(req.Context(), runtime.SpanKindKey(trace.SpanKindServer))
+				return req.WithContext(ctx)
+			},
+			expectSpan: false,
+		},
 		{
 			name: "POST request",
 			setupEnv: func(t *testing.T) {
@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"context"
+	"maps"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// hookPanicLimitEnv sets how many panics of one rule's hooks trip its
+	// circuit breaker.
+	hookPanicLimitEnv     = "OTEL_GO_HOOK_PANIC_LIMIT"
+	defaultHookPanicLimit = 10
+
+	hookPanicsMetric = "otelc.hook.panics"
+)
+
+var (
+	hookPanicLimit = sync.OnceValue(func() int64 {
+		return parseHookPanicLimit(os.Getenv(hookPanicLimitEnv))
+	})
+	hookPanicCounter = sync.OnceValue(newHookPanicCounter)
+
+	// hookPanics counts the panics of every rule, keyed by rule identity.
+	hookPanics sync.Map // map[string]*atomic.Int64
+
+	// disabledHooks is the set of rules whose breaker tripped. Trampolines
+	// read it on every call, including from the Go runtime package, so reads
+	// never lock: writers copy the set under disabledHooksMu and swap it in.
+	disabledHooks   atomic.Pointer[map[string]struct{}]
+	disabledHooksMu sync.Mutex
+)
+
+func newHookPanicCounter() metric.Int64Counter {
+	counter, err := selfMeter().Int64Counter(hookPanicsMetric,
+		metric.WithDescription("Number of panics recovered from instrumentation hooks"),
+		metric.WithUnit("{panic}"))
+	if err != nil {
+		Logger().Warn("failed to create hook panic counter", "error", err)
+	}
+	return counter
+}
+
+// parseHookPanicLimit parses the value of OTEL_GO_HOOK_PANIC_LIMIT. Zero
+// disables the circuit breaker; an unset or invalid value selects the default.
+func parseHookPanicLimit(value string) int64 {
+	if value == "" {
+		return defaultHookPanicLimit
+	}
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || limit < 0 {
+		Logger().Warn("invalid hook panic limit, using the default",
+			"env", hookPanicLimitEnv, "value", value, "default", defaultHookPanicLimit)
+		return defaultHookPanicLimit
+	}
+	return limit
+}
+
+// hookEnabled reports whether the hooks of rule still run. It returns false
+// once they panicked OTEL_GO_HOOK_PANIC_LIMIT times, turning the
+// instrumentation into a no-op.
+func hookEnabled(rule string) bool {
+	disabled := disabledHooks.Load()
+	if disabled == nil {
+		return true
+	}
+	_, tripped := (*disabled)[rule]
+	return !tripped
+}
+
+// HookPanicked records a panic recovered from hook, a hook function of rule.
+// It logs the panic with its stack, counts it in the otelc.hook.panics metric
+// and trips the circuit breaker of rule once the limit is reached.
+//
+// Trampolines call it from the deferred recover around every hook invocation.
+func HookPanicked(rule, hook string, recovered any) {
+	// Reporting must never panic back into the instrumented function.
+	defer func() {
+		if rec := recover(); rec != nil {
+			println("failed to report hook panic", hook)
+		}
+	}()
+
+	value, _ := hookPanics.LoadOrStore(rule, new(atomic.Int64))
+	count := value.(*atomic.Int64).Add(1)
+
+	Logger().Error("recovered panic in instrumentation hook",
+		"rule", rule,
+		"hook", hook,
+		"panic", recovered,
+		"count", count,
+		"stack", string(debug.Stack()))
+	if counter := hookPanicCounter(); counter != nil {
+		counter.Add(context.Background(), 1, hookAttributes(rule, hook))
+	}
+
+	if limit := hookPanicLimit(); limit > 0 && count == limit {
+		disableHook(rule)
+		Logger().Error("disabled instrumentation hook after repeated panics",
+			"rule", rule, "hook", hook, "panics", count, "limit_env", hookPanicLimitEnv)
+	}
+}
+
+// disableHook trips the circuit breaker of rule.
+func disableHook(rule string) {
+	disabledHooksMu.Lock()
+	defer disabledHooksMu.Unlock()
+	next := map[string]struct{}{rule: {}}
+	if current := disabledHooks.Load(); current != nil {
+		maps.Copy(next, *current)
+	}
+	disabledHooks.Store(&next)
+}
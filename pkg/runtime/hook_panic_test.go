@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// resetHookPanics clears the breaker state and sets the panic limit.
+func resetHookPanics(t *testing.T, limit int64) {
+	t.Helper()
+	reset := func() {
+		hookPanics.Clear()
+		disabledHooks.Store(nil)
+	}
+	reset()
+	prev := hookPanicLimit
+	hookPanicLimit = func() int64 { return limit }
+	t.Cleanup(func() {
+		reset()
+		hookPanicLimit = prev
+	})
+}
+
+func TestParseHookPanicLimit(t *testing.T) {
+	assert.Equal(t, int64(defaultHookPanicLimit), parseHookPanicLimit(""))
+	assert.Equal(t, int64(3), parseHookPanicLimit("3"))
+	assert.Equal(t, int64(0), parseHookPanicLimit("0"))
+	assert.Equal(t, int64(defaultHookPanicLimit), parseHookPanicLimit("-1"))
+	assert.Equal(t, int64(defaultHookPanicLimit), parseHookPanicLimit("many"))
+}
+
+func TestHookPanicked_CircuitBreaker(t *testing.T) {
+	resetHookPanics(t, 3)
+
+	for range 2 {
+		HookPanicked("rule1", "BeforeServeHTTP", errors.New("boom"))
+		assert.True(t, hookEnabled("rule1"))
+	}
+	HookPanicked("rule1", "AfterServeHTTP", "boom")
+	assert.False(t, hookEnabled("rule1"), "the breaker trips at the limit")
+	assert.True(t, hookEnabled("rule2"), "other rules keep running")
+
+	HookPanicked("rule2", "BeforeQuery", nil)
+	assert.True(t, hookEnabled("rule2"))
+	HookPanicked("rule1", "BeforeServeHTTP", "boom")
+	assert.False(t, hookEnabled("rule1"))
+}
+
+func TestHookPanicked_NoLimit(t *testing.T) {
+	resetHookPanics(t, 0)
+
+	for range 2 * defaultHookPanicLimit {
+		HookPanicked("rule1", "BeforeServeHTTP", "boom")
+	}
+	assert.True(t, hookEnabled("rule1"), "a zero limit disables the breaker")
+}
+
+func TestHookPanicked_Metric(t *testing.T) {
+	reader := setupSelfMeter(t)
+	resetHookPanics(t, 0)
+
+	HookPanicked("rule1", "BeforeServeHTTP", "boom")
+	HookPanicked("rule1", "BeforeServeHTTP", "boom")
+	HookPanicked("rule2", "AfterQuery", "boom")
+
+	m, found := collectSelfMetric(t, reader, hookPanicsMetric)
+	require.True(t, found)
+	assert.Equal(t, map[string]int64{"BeforeServeHTTP": 2, "AfterQuery": 1}, sumByAttribute(t, m, hookNameAttribute))
+	for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+		assert.False(t, dp.Attributes.HasValue(hookRuleAttribute), "the rule identity is opt-in")
+	}
+}
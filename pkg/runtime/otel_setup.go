@@ -70,7 +70,19 @@ func SetupOTelSDK() {
 //  3. If neither is set, all instrumentations are enabled by default
 //
 // The instrumentationName should be lowercase (e.g., "nethttp", "grpc").
+//
+// Calls returning false are counted in the otelc.instrumentation.disabled
+// metric.
 func Instrumented(instrumentationName string) bool {
+	if !instrumented(instrumentationName) {
+		countDisabled(instrumentationName)
+		return false
+	}
+	return true
+}
+
+// instrumented implements Instrumented without counting disabled calls.
+func instrumented(instrumentationName string) bool {
 	name := strings.ToLower(instrumentationName)
 
 	// Check if specific instrumentations are enabled
@@ -0,0 +1,249 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// selfScope is the instrumentation scope of the metrics otelc reports
+	// about itself.
+	selfScope = "go.opentelemetry.io/otelc"
+
+	// selfMetricsKey is the instrumentation key that turns the self metrics
+	// off, as in OTEL_GO_DISABLED_INSTRUMENTATIONS=selfmetrics.
+	selfMetricsKey = "selfmetrics"
+
+	// hookRuleAttributeEnv adds the rule identity to the hook metrics. Every
+	// rule of an instrumentation is a separate series, so it is opt-in.
+	hookRuleAttributeEnv = "OTEL_GO_HOOK_RULE_ATTRIBUTE_ENABLED"
+
+	hookDurationMetric          = "otelc.hook.duration"
+	glsDepthMetric              = "otelc.gls.span_stack.depth"
+	glsOverflowsMetric          = "otelc.gls.span_stack.overflows"
+	suppressedMetric            = "otelc.instrumentation.suppressed"
+	disabledMetric              = "otelc.instrumentation.disabled"
+	hookRuleAttribute           = "otelc.hook.rule"
+	hookNameAttribute           = "otelc.hook.name"
+	instrumentationKeyAttribute = "otelc.instrumentation.key"
+)
+
+// selfInstruments are the instruments of the metrics otelc reports about
+// itself. A nil instrument failed to be created and is not recorded.
+type selfInstruments struct {
+	hookDuration metric.Float64Histogram
+	glsDepth     metric.Int64Histogram
+	glsOverflows metric.Int64Counter
+	suppressed   metric.Int64Counter
+	disabled     metric.Int64Counter
+}
+
+var (
+	// selfMetrics returns the self instruments, or nil when they are turned
+	// off through the instrumentation environment variables.
+	selfMetrics = sync.OnceValue(newSelfInstruments)
+
+	// epoch is the origin of the hook start times HookStart returns. Durations
+	// are computed from the monotonic clock reading it carries.
+	epoch = time.Now()
+
+	// hookOptions caches the measurement options of every hook, keyed by
+	// rule and hook name, so recording a hook invocation does not allocate.
+	hookOptions sync.Map // map[[2]string]hookOption
+
+	// hookPackageKeys maps the import path of a package of hooks to its
+	// instrumentation key, see RegisterInstrumentation. hookPackagesVersion
+	// counts the registrations, so options cached for a hook of a package
+	// that registered since are recomputed.
+	hookPackageKeys     sync.Map // map[string]string
+	hookPackagesVersion atomic.Int64
+
+	hookRuleAttributeEnabled = sync.OnceValue(func() bool {
+		return os.Getenv(hookRuleAttributeEnv) == "true"
+	})
+)
+
+// hookOption is a cached measurement option of a hook and the registration
+// count it was computed at.
+type hookOption struct {
+	opt     metric.MeasurementOption
+	version int64
+}
+
+// selfMeter returns the meter of the metrics otelc reports about itself. It
+// goes through the global provider, so instruments created before the SDK is
+// set up still export once it is.
+func selfMeter() metric.Meter {
+	return otel.GetMeterProvider().Meter(selfScope, metric.WithInstrumentationVersion(ModuleVersion()))
+}
+
+func newSelfInstruments() *selfInstruments {
+	if !instrumented(selfMetricsKey) {
+		Logger().Debug("otelc self metrics disabled via environment variable")
+		return nil
+	}
+	meter := selfMeter()
+	warn := func(name string, err error) {
+		if err != nil {
+			Logger().Warn("failed to create otelc self metric", "metric", name, "error", err)
+		}
+	}
+	var (
+		inst selfInstruments
+		err  error
+	)
+	inst.hookDuration, err = meter.Float64Histogram(hookDurationMetric,
+		metric.WithDescription("Duration of instrumentation hook invocations"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(
+			0.000001, 0.000005, 0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1))
+	warn(hookDurationMetric, err)
+	inst.glsDepth, err = meter.Int64Histogram(glsDepthMetric,
+		metric.WithDescription("Depth of the goroutine-local span stack after a span is pushed"),
+		metric.WithUnit("{span}"),
+		metric.WithExplicitBucketBoundaries(1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024))
+	warn(glsDepthMetric, err)
+	inst.glsOverflows, err = meter.Int64Counter(glsOverflowsMetric,
+		metric.WithDescription("Number of spans not pushed to a goroutine-local span stack that reached OTEL_GLS_MAX_SPANS"),
+		metric.WithUnit("{span}"))
+	warn(glsOverflowsMetric, err)
+	inst.suppressed, err = meter.Int64Counter(suppressedMetric,
+		metric.WithDescription("Number of instrumentation calls skipped because the context suppresses them"),
+		metric.WithUnit("{call}"))
+	warn(suppressedMetric, err)
+	inst.disabled, err = meter.Int64Counter(disabledMetric,
+		metric.WithDescription("Number of instrumentation calls skipped because the instrumentation is disabled by environment variable"),
+		metric.WithUnit("{call}"))
+	warn(disabledMetric, err)
+	return &inst
+}
+
+// RegisterInstrumentation records that the hooks declared in the package of
+// enabler, the enabler type of an instrumentation, belong to the
+// instrumentation key. The key labels their otelc.hook.duration and
+// otelc.hook.panics metrics as otelc.instrumentation.key, which hooks of
+// packages that did not register lack.
+//
+// Instrumentation packages call it from init.
+func RegisterInstrumentation(key string, enabler any) {
+	t := reflect.TypeOf(enabler)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.PkgPath() == "" {
+		return
+	}
+	hookPackageKeys.Store(t.PkgPath(), strings.ToLower(key))
+	hookPackagesVersion.Add(1)
+}
+
+// hookPackage returns the import path of the package declaring hook, the
+// qualified name of a hook function.
+func hookPackage(hook string) string {
+	if dot := strings.LastIndexByte(hook, '.'); dot >= 0 {
+		return hook[:dot]
+	}
+	return hook
+}
+
+// hookAttributes returns the measurement option identifying hook, a hook
+// function of rule: its instrumentation key and name, and the rule identity
+// when OTEL_GO_HOOK_RULE_ATTRIBUTE_ENABLED is set.
+func hookAttributes(rule, hook string) metric.MeasurementOption {
+	key := [2]string{rule, hook}
+	version := hookPackagesVersion.Load()
+	if cached, ok := hookOptions.Load(key); ok {
+		if c := cached.(hookOption); c.version == version {
+			return c.opt
+		}
+	}
+	attrs := []attribute.KeyValue{attribute.String(hookNameAttribute, hook)}
+	if ikey, ok := hookPackageKeys.Load(hookPackage(hook)); ok {
+		attrs = append(attrs, attribute.String(instrumentationKeyAttribute, ikey.(string)))
+	}
+	if hookRuleAttributeEnabled() {
+		attrs = append(attrs, attribute.String(hookRuleAttribute, rule))
+	}
+	opt := metric.WithAttributeSet(attribute.NewSet(attrs...))
+	hookOptions.Store(key, hookOption{opt: opt, version: version})
+	return opt
+}
+
+// HookStart reports whether the hooks of rule still run, see HookPanicked, and
+// returns the start time of the invocation to pass to HookEnd.
+//
+// Trampolines call it before every hook invocation; rule is the identity otelc
+// derives from the rule definition at build time. An After hook whose Before
+// hook ran is invoked even if the rule was disabled since, so the start time is
+// returned either way.
+func HookStart(rule string) (int64, bool) {
+	var start int64
+	if selfMetrics() != nil {
+		start = int64(time.Since(epoch))
+	}
+	return start, hookEnabled(rule)
+}
+
+// HookEnd records the duration of an invocation of hook, a hook function of
+// rule, that started at start as returned by HookStart. The count of the
+// otelc.hook.duration histogram is the number of invocations.
+//
+// Trampolines call it once the hook returns or panics.
+func HookEnd(rule, hook string, start int64) {
+	inst := selfMetrics()
+	if inst == nil || inst.hookDuration == nil {
+		return
+	}
+	elapsed := time.Duration(int64(time.Since(epoch)) - start)
+	inst.hookDuration.Record(context.Background(), elapsed.Seconds(), hookAttributes(rule, hook))
+}
+
+// RecordGLSSpanStack records the depth of the goroutine-local span stack after
+// a span is pushed to it, or an overflow when the stack is full and the span
+// was not pushed. The GLS instrumentation of the OpenTelemetry SDK calls it.
+func RecordGLSSpanStack(depth int, overflow bool) {
+	inst := selfMetrics()
+	if inst == nil {
+		return
+	}
+	ctx := context.Background()
+	if overflow {
+		if inst.glsOverflows != nil {
+			inst.glsOverflows.Add(ctx, 1)
+		}
+		return
+	}
+	if inst.glsDepth != nil {
+		inst.glsDepth.Record(ctx, int64(depth))
+	}
+}
+
+// countSuppressed counts a call of the instrumentation key suppressed by its
+// context.
+func countSuppressed(key string) {
+	if inst := selfMetrics(); inst != nil && inst.suppressed != nil {
+		inst.suppressed.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String(instrumentationKeyAttribute, strings.ToLower(key))))
+	}
+}
+
+// countDisabled counts a call of the instrumentation key disabled by the
+// instrumentation environment variables.
+func countDisabled(key string) {
+	if inst := selfMetrics(); inst != nil && inst.disabled != nil {
+		inst.disabled.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String(instrumentationKeyAttribute, strings.ToLower(key))))
+	}
+}
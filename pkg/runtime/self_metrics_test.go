@@ -0,0 +1,221 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// setupSelfMeter installs an in-memory meter provider and resets the self
+// instruments so that they are created on it.
+func setupSelfMeter(t *testing.T) *sdkmetric.ManualReader {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	otel.SetMeterProvider(mp)
+	reset := func() {
+		selfMetrics = sync.OnceValue(newSelfInstruments)
+		hookPanicCounter = sync.OnceValue(newHookPanicCounter)
+	}
+	reset()
+	t.Cleanup(func() {
+		_ = mp.Shutdown(context.Background())
+		reset()
+	})
+	return reader
+}
+
+// collectSelfMetric returns the metric name of the otelc scope.
+func collectSelfMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) (metricdata.Metrics, bool) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		if sm.Scope.Name != selfScope {
+			continue
+		}
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+// sumByAttribute maps the value of attribute key to the data point value of an
+// int64 sum.
+func sumByAttribute(t *testing.T, m metricdata.Metrics, key string) map[string]int64 {
+	t.Helper()
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok, "%s is not an int64 sum", m.Name)
+	values := make(map[string]int64)
+	for _, dp := range sum.DataPoints {
+		v, _ := dp.Attributes.Value(attribute.Key(key))
+		values[v.AsString()] = dp.Value
+	}
+	return values
+}
+
+func TestHookStartEnd(t *testing.T) {
+	reader := setupSelfMeter(t)
+	resetHookPanics(t, 1)
+
+	for range 3 {
+		start, ok := HookStart("rule1")
+		require.True(t, ok)
+		HookEnd("rule1", "example.com/hook.BeforeFoo", start)
+	}
+	start, ok := HookStart("rule2")
+	require.True(t, ok)
+	HookEnd("rule2", "example.com/hook.AfterBar", start)
+
+	HookPanicked("rule2", "example.com/hook.AfterBar", "boom")
+	_, ok = HookStart("rule2")
+	assert.False(t, ok, "the hooks of a tripped rule do not run")
+
+	m, found := collectSelfMetric(t, reader, hookDurationMetric)
+	require.True(t, found)
+	assert.Equal(t, "s", m.Unit)
+	hist, ok := m.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	counts := make(map[string]uint64)
+	for _, dp := range hist.DataPoints {
+		hook, _ := dp.Attributes.Value(hookNameAttribute)
+		counts[hook.AsString()] = dp.Count
+		assert.GreaterOrEqual(t, dp.Sum, 0.0)
+		assert.False(t, dp.Attributes.HasValue(hookRuleAttribute), "the rule identity is opt-in")
+	}
+	assert.Equal(t, map[string]uint64{
+		"example.com/hook.BeforeFoo": 3,
+		"example.com/hook.AfterBar":  1,
+	}, counts)
+}
+
+// testEnabler stands in for the enabler type of an instrumentation declaring
+// hooks in this package.
+type testEnabler struct{}
+
+// resetHookAttributes clears the registered instrumentations and the cached
+// hook attributes.
+func resetHookAttributes(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		hookPackageKeys.Clear()
+		hookOptions.Clear()
+		hookRuleAttributeEnabled = sync.OnceValue(func() bool {
+			return os.Getenv(hookRuleAttributeEnv) == "true"
+		})
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestHookAttributes_InstrumentationKey(t *testing.T) {
+	resetHookAttributes(t)
+
+	hook := "go.opentelemetry.io/otelc/pkg/runtime.BeforeRoundTrip"
+	set := func() attribute.Set {
+		cfg := metric.NewAddConfig([]metric.AddOption{hookAttributes("rule1", hook)})
+		return cfg.Attributes()
+	}
+	attrs := set()
+	assert.False(t, attrs.HasValue(instrumentationKeyAttribute), "hooks of unregistered packages have no key")
+
+	RegisterInstrumentation("NETHTTP", &testEnabler{})
+	attrs = set()
+	key, _ := attrs.Value(instrumentationKeyAttribute)
+	assert.Equal(t, "nethttp", key.AsString(), "a registration after the first call still applies")
+	name, _ := attrs.Value(hookNameAttribute)
+	assert.Equal(t, hook, name.AsString())
+	assert.False(t, attrs.HasValue(hookRuleAttribute))
+
+	t.Setenv(hookRuleAttributeEnv, "true")
+	resetHookAttributes(t)
+	RegisterInstrumentation("NETHTTP", testEnabler{})
+	attrs = set()
+	rule, _ := attrs.Value(hookRuleAttribute)
+	assert.Equal(t, "rule1", rule.AsString())
+}
+
+func TestHookPackage(t *testing.T) {
+	assert.Equal(t, "go.opentelemetry.io/otelc/instrumentation/github.com/nats-io/nats.go",
+		hookPackage("go.opentelemetry.io/otelc/instrumentation/github.com/nats-io/nats.go.BeforePublish"))
+	assert.Equal(t, "main", hookPackage("main.BeforeFoo"))
+}
+
+func TestRecordGLSSpanStack(t *testing.T) {
+	reader := setupSelfMeter(t)
+
+	RecordGLSSpanStack(1, false)
+	RecordGLSSpanStack(2, false)
+	RecordGLSSpanStack(2, true)
+
+	m, found := collectSelfMetric(t, reader, glsDepthMetric)
+	require.True(t, found)
+	hist, ok := m.Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+	assert.Equal(t, uint64(2), hist.DataPoints[0].Count)
+	assert.Equal(t, int64(3), hist.DataPoints[0].Sum)
+
+	m, found = collectSelfMetric(t, reader, glsOverflowsMetric)
+	require.True(t, found)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+}
+
+func TestSuppressedAndDisabledMetrics(t *testing.T) {
+	reader := setupSelfMeter(t)
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "")
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "redis")
+
+	assert.False(t, Instrumented("REDIS"))
+	assert.False(t, Instrumented("redis"))
+	assert.True(t, Instrumented("nethttp"))
+
+	ctx := Suppress(context.Background(), "NETHTTP")
+	assert.True(t, IsSuppressed(ctx, "NETHTTP"))
+	assert.False(t, IsSuppressed(ctx, "GRPC"))
+	assert.True(t, IsSpanSuppressed(ctx, "NETHTTP", 0))
+
+	m, found := collectSelfMetric(t, reader, disabledMetric)
+	require.True(t, found)
+	assert.Equal(t, map[string]int64{"redis": 2}, sumByAttribute(t, m, instrumentationKeyAttribute))
+
+	m, found = collectSelfMetric(t, reader, suppressedMetric)
+	require.True(t, found)
+	assert.Equal(t, map[string]int64{"nethttp": 2}, sumByAttribute(t, m, instrumentationKeyAttribute),
+		"IsSpanSuppressed counts a call once")
+}
+
+func TestSelfMetricsDisabled(t *testing.T) {
+	reader := setupSelfMeter(t)
+	t.Setenv("OTEL_GO_ENABLED_INSTRUMENTATIONS", "")
+	t.Setenv("OTEL_GO_DISABLED_INSTRUMENTATIONS", "selfmetrics,redis")
+
+	start, ok := HookStart("rule1")
+	assert.True(t, ok)
+	assert.Zero(t, start)
+	HookEnd("rule1", "example.com/hook.BeforeFoo", start)
+	RecordGLSSpanStack(1, false)
+	assert.False(t, Instrumented("redis"))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	assert.Empty(t, rm.ScopeMetrics)
+}
@@ -3,22 +3,144 @@
 
 package runtime
 
-import "context"
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
 
 type contextKey struct{}
 
-var suppressHTTPClientKey = contextKey{}
+var suppressedKey = contextKey{}
+
+// netHTTPKey is the instrumentation key of the net/http instrumentation.
+const netHTTPKey = "NETHTTP"
+
+// spanKindKeyPrefix prefixes the keys returned by SpanKindKey, so they cannot
+// collide with an instrumentation key.
+const spanKindKeyPrefix = "span_kind/"
+
+// unsuppressible maps the lowercase instrumentation keys to the operations
+// registered by RegisterUnsuppressible.
+var (
+	unsuppressibleMu sync.Mutex
+	unsuppressible   = map[string][]string{}
+)
+
+// suppressed is the set of suppressed keys a context carries. It is never
+// modified once stored in a context.
+type suppressed map[string]struct{}
+
+// Suppress returns a context in which the instrumentations identified by keys
+// create no telemetry. A key is either an instrumentation key, the one used
+// in OTEL_GO_ENABLED_INSTRUMENTATIONS and OTEL_GO_DISABLED_INSTRUMENTATIONS,
+// or a span kind key returned by SpanKindKey. Keys are case-insensitive, and
+// add to the keys ctx already suppresses.
+//
+// Use this from higher-level instrumentations that already create a more
+// specific span than the instrumentation of the library they are built on,
+// e.g. GenAI over HTTP or an ORM over database/sql.
+//
+// Only hooks given a context by the instrumented call can honor it.
+// Unsuppressible lists the operations of the linked instrumentations that are
+// not given one.
+func Suppress(ctx context.Context, keys ...string) context.Context {
+	if len(keys) == 0 {
+		return ctx
+	}
+	parent, _ := ctx.Value(suppressedKey).(suppressed)
+	set := make(suppressed, len(parent)+len(keys))
+	for k := range parent {
+		set[k] = struct{}{}
+	}
+	for _, k := range keys {
+		set[strings.ToLower(k)] = struct{}{}
+	}
+	return context.WithValue(ctx, suppressedKey, set)
+}
+
+// IsSuppressed reports whether ctx suppresses key, as set by Suppress. Calls
+// returning true are counted in the otelc.instrumentation.suppressed metric.
+func IsSuppressed(ctx context.Context, key string) bool {
+	if !isSuppressed(ctx, key) {
+		return false
+	}
+	countSuppressed(key)
+	return true
+}
+
+func isSuppressed(ctx context.Context, key string) bool {
+	if ctx == nil {
+		return false
+	}
+	set, _ := ctx.Value(suppressedKey).(suppressed)
+	if len(set) == 0 {
+		return false
+	}
+	_, ok := set[strings.ToLower(key)]
+	return ok
+}
+
+// RegisterUnsuppressible records that the operations of the instrumentation
+// identified by key start their spans without a caller context, so Suppress
+// cannot apply to them.
+//
+// Instrumentation packages call it from init.
+func RegisterUnsuppressible(key string, operations ...string) {
+	key = strings.ToLower(key)
+	unsuppressibleMu.Lock()
+	defer unsuppressibleMu.Unlock()
+	for _, op := range operations {
+		if !slices.Contains(unsuppressible[key], op) {
+			unsuppressible[key] = append(unsuppressible[key], op)
+		}
+	}
+}
+
+// Unsuppressible returns the operations Suppress cannot apply to, by the
+// lowercase key of the linked instrumentation registering them.
+func Unsuppressible() map[string][]string {
+	unsuppressibleMu.Lock()
+	defer unsuppressibleMu.Unlock()
+	ops := make(map[string][]string, len(unsuppressible))
+	for k, v := range unsuppressible {
+		ops[k] = slices.Clone(v)
+	}
+	return ops
+}
+
+// SpanKindKey returns the key suppressing every instrumentation creating spans
+// of kind.
+func SpanKindKey(kind trace.SpanKind) string {
+	return spanKindKeyPrefix + kind.String()
+}
+
+// IsSpanSuppressed reports whether ctx suppresses the instrumentation
+// identified by key, or the spans of kind. Instrumentations check it before
+// starting a span of kind.
+func IsSpanSuppressed(ctx context.Context, key string, kind trace.SpanKind) bool {
+	if !isSuppressed(ctx, key) && !isSuppressed(ctx, SpanKindKey(kind)) {
+		return false
+	}
+	countSuppressed(key)
+	return true
+}
 
 // SuppressHTTPClientInstrumentation returns a context that signals the net/http
-// client hook to skip span creation. Use this from higher-level instrumentations
-// (e.g., GenAI) that already create a more specific span.
+// client hook to skip span creation.
+//
+// Deprecated: Use Suppress with the "NETHTTP" key instead.
 func SuppressHTTPClientInstrumentation(ctx context.Context) context.Context {
-	return context.WithValue(ctx, suppressHTTPClientKey, true)
+	return Suppress(ctx, netHTTPKey)
 }
 
 // IsHTTPClientInstrumentationSuppressed reports whether the context carries the
 // suppression flag set by SuppressHTTPClientInstrumentation.
+//
+// Deprecated: Use IsSuppressed with the "NETHTTP" key instead.
 func IsHTTPClientInstrumentationSuppressed(ctx context.Context) bool {
-	v, _ := ctx.Value(suppressHTTPClientKey).(bool)
-	return v
+	return IsSuppressed(ctx, netHTTPKey)
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestHTTPClientInstrumentationSuppression(t *testing.T) {
@@ -23,3 +24,45 @@ func TestHTTPClientInstrumentationSuppression(t *testing.T) {
 	// Suppression does not leak back into the original context.
 	assert.False(t, IsHTTPClientInstrumentationSuppressed(ctx))
 }
+
+func TestSuppress(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, IsSuppressed(ctx, "REDIS"))
+	assert.Equal(t, ctx, Suppress(ctx), "no keys leaves the context unchanged")
+
+	redis := Suppress(ctx, "REDIS")
+	assert.True(t, IsSuppressed(redis, "REDIS"))
+	assert.True(t, IsSuppressed(redis, "redis"), "keys are case-insensitive")
+	assert.False(t, IsSuppressed(redis, "DATABASE"))
+
+	// Keys add to the ones the parent context suppresses, without changing it.
+	both := Suppress(redis, "database")
+	assert.True(t, IsSuppressed(both, "REDIS"))
+	assert.True(t, IsSuppressed(both, "DATABASE"))
+	assert.False(t, IsSuppressed(redis, "DATABASE"))
+}
+
+func TestSuppress_SpanKind(t *testing.T) {
+	ctx := Suppress(context.Background(), SpanKindKey(trace.SpanKindClient))
+
+	assert.True(t, IsSpanSuppressed(ctx, "REDIS", trace.SpanKindClient))
+	assert.True(t, IsSpanSuppressed(ctx, "DATABASE", trace.SpanKindClient))
+	assert.False(t, IsSpanSuppressed(ctx, "NETHTTP", trace.SpanKindServer))
+	assert.False(t, IsSuppressed(ctx, "REDIS"))
+
+	ctx = Suppress(context.Background(), "NETHTTP")
+	assert.True(t, IsSpanSuppressed(ctx, "NETHTTP", trace.SpanKindServer))
+	assert.False(t, IsSpanSuppressed(ctx, "GRPC", trace.SpanKindServer))
+}
+
+func TestUnsuppressible(t *testing.T) {
+	RegisterUnsuppressible("TEST_UNSUPPRESSIBLE", "Publish", "Request")
+	RegisterUnsuppressible("test_unsuppressible", "Publish")
+
+	ops := Unsuppressible()
+	assert.Equal(t, []string{"Publish", "Request"}, ops["test_unsuppressible"])
+
+	// The returned map is a copy.
+	ops["test_unsuppressible"][0] = "Consume"
+	assert.Equal(t, []string{"Publish", "Request"}, Unsuppressible()["test_unsuppressible"])
+}
@@ -19,6 +19,7 @@ var (
 	addr   = flag.String("addr", "http://localhost:8080", "The Anthropic API base URL")
 	apiKey = flag.String("api-key", "test-key", "The API key")
 	model  = flag.String("model", "claude-sonnet-4-5", "The model to use")
+	stream = flag.Bool("stream", false, "Stream the response")
 )
 
 func main() {
@@ -29,15 +30,34 @@ func main() {
 		option.WithAPIKey(*apiKey),
 	)
 
-	message, err := client.Messages.New(context.Background(), anthropic.MessageNewParams{
+	params := anthropic.MessageNewParams{
 		Model:     anthropic.Model(*model),
 		MaxTokens: 1024,
 		Messages: []anthropic.MessageParam{
 			anthropic.NewUserMessage(anthropic.NewTextBlock("Say hello in one word")),
 		},
-	})
-	if err != nil {
-		log.Fatalf("failed to create message: %v", err)
+	}
+
+	var message anthropic.Message
+	if *stream {
+		s := client.Messages.NewStreaming(context.Background(), params)
+		for s.Next() {
+			if err := message.Accumulate(s.Current()); err != nil {
+				log.Fatalf("failed to accumulate event: %v", err)
+			}
+		}
+		if err := s.Err(); err != nil {
+			log.Fatalf("failed to stream message: %v", err)
+		}
+		if err := s.Close(); err != nil {
+			log.Fatalf("failed to close stream: %v", err)
+		}
+	} else {
+		resp, err := client.Messages.New(context.Background(), params)
+		if err != nil {
+			log.Fatalf("failed to create message: %v", err)
+		}
+		message = *resp
 	}
 
 	for _, block := range message.Content {
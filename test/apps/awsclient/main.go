@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package main provides a minimal AWS SDK for Go v2 client for integration
+// testing. It talks to an in-process HTTP stand-in for SQS, so no AWS account
+// or emulator is needed: one message is sent to a queue and the trace context
+// the stand-in received in the message attributes is printed.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+var queue = flag.String("queue", "orders", "SQS queue name")
+
+// newFakeSQS answers every SQS call with a sent message and reports the
+// traceparent message attribute of each request on received.
+func newFakeSQS(received chan<- string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			MessageAttributes map[string]struct{ StringValue string }
+		}
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		received <- body.MessageAttributes["traceparent"].StringValue
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		_, _ = io.WriteString(w, `{"MessageId":"msg-1"}`)
+	}))
+}
+
+func main() {
+	flag.Parse()
+
+	received := make(chan string, 1)
+	server := newFakeSQS(received)
+	defer server.Close()
+
+	ctx := context.Background()
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion("us-east-1"),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("key", "secret", "")),
+		config.WithBaseEndpoint(server.URL),
+	)
+	if err != nil {
+		log.Fatalf("failed to load AWS config: %v", err)
+	}
+
+	client := sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+		// The stand-in does not compute message checksums
+		o.DisableMessageChecksumValidation = true
+	})
+	_, err = client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(fmt.Sprintf("%s/123456789012/%s", server.URL, *queue)),
+		MessageBody: aws.String("order-1"),
+	})
+	if err != nil {
+		log.Fatalf("failed to send message: %v", err)
+	}
+
+	slog.Info("sent message", "traceparent", <-received)
+}
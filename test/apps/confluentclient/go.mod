@@ -0,0 +1,5 @@
+module go.opentelemetry.io/otelc/test/apps/confluentclient
+
+go 1.25.0
+
+require github.com/confluentinc/confluent-kafka-go/v2 v2.11.1
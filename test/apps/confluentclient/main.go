@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package main provides a minimal confluent-kafka-go client for integration
+// testing. It talks to librdkafka's in-process mock cluster, so no real Kafka
+// is needed: a producer sends one message, then a consumer reads it back.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+var topic = flag.String("topic", "orders", "kafka topic")
+
+const group = "workers"
+
+func produce(servers string) {
+	p, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": servers})
+	if err != nil {
+		log.Fatalf("failed to create producer: %v", err)
+	}
+	defer p.Close()
+
+	deliveryChan := make(chan kafka.Event, 1)
+	err = p.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: topic, Partition: kafka.PartitionAny},
+		Key:            []byte("order-1"),
+		Value:          []byte("hello confluent"),
+	}, deliveryChan)
+	if err != nil {
+		log.Fatalf("failed to produce message: %v", err)
+	}
+	msg := (<-deliveryChan).(*kafka.Message)
+	if msg.TopicPartition.Error != nil {
+		log.Fatalf("failed to deliver message: %v", msg.TopicPartition.Error)
+	}
+	slog.Info("produced message", "topic", *topic,
+		"partition", msg.TopicPartition.Partition, "offset", msg.TopicPartition.Offset)
+}
+
+func consume(servers string) {
+	c, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": servers,
+		"group.id":          group,
+		"auto.offset.reset": "earliest",
+	})
+	if err != nil {
+		log.Fatalf("failed to create consumer: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.Subscribe(*topic, nil); err != nil {
+		log.Fatalf("failed to subscribe: %v", err)
+	}
+	msg, err := c.ReadMessage(30 * time.Second)
+	if err != nil {
+		log.Fatalf("failed to read message: %v", err)
+	}
+	slog.Info("consumed message", "topic", *msg.TopicPartition.Topic,
+		"key", string(msg.Key), "offset", msg.TopicPartition.Offset)
+}
+
+func main() {
+	flag.Parse()
+
+	cluster, err := kafka.NewMockCluster(1)
+	if err != nil {
+		log.Fatalf("failed to start mock cluster: %v", err)
+	}
+	defer cluster.Close()
+	if err := cluster.CreateTopic(*topic, 1, 1); err != nil {
+		log.Fatalf("failed to create topic: %v", err)
+	}
+
+	produce(cluster.BootstrapServers())
+	consume(cluster.BootstrapServers())
+	fmt.Println("done")
+}
@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package main provides a minimal fasthttp client for integration testing. It
+// sends one request to an in-process fasthttp server, so both the client and
+// the server side of the request are traced.
+package main
+
+import (
+	"flag"
+	"log"
+	"log/slog"
+	"net"
+
+	"github.com/valyala/fasthttp"
+)
+
+var name = flag.String("name", "world", "The name to greet")
+
+func main() {
+	flag.Parse()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	srv := &fasthttp.Server{Handler: func(ctx *fasthttp.RequestCtx) {
+		ctx.SetBodyString("Hello " + string(ctx.QueryArgs().Peek("name")))
+	}}
+	go func() {
+		if err := srv.Serve(ln); err != nil {
+			log.Printf("server stopped: %v", err)
+		}
+	}()
+	defer func() { _ = srv.Shutdown() }()
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI("http://" + ln.Addr().String() + "/hello?name=" + *name)
+
+	var client fasthttp.Client
+	if err = client.Do(req, resp); err != nil {
+		log.Fatalf("request failed: %v", err)
+	}
+	slog.Info("received response", "status", resp.StatusCode(), "body", string(resp.Body()))
+}
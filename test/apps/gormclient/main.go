@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package main provides a minimal GORM client for integration testing.
+// GORM runs over database/sql with a custom in-memory driver to avoid
+// external dependencies.
+// This client is designed to be instrumented with the otelc compile-time tool.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type User struct {
+	ID   uint
+	Name string
+}
+
+func init() {
+	sql.Register("testdb", &testDriver{})
+}
+
+func main() {
+	sqlDB, err := sql.Open("testdb", "user:pass@tcp(127.0.0.1:3306)/shop")
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
+	}
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		Logger:                 logger.Discard,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		log.Fatalf("failed to open gorm: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := db.WithContext(ctx).Create(&User{Name: "alice"}).Error; err != nil {
+		log.Fatalf("failed to create: %v", err)
+	}
+	var users []User
+	if err := db.WithContext(ctx).Where("name = ?", "alice").Find(&users).Error; err != nil {
+		log.Fatalf("failed to query: %v", err)
+	}
+
+	slog.Info("gorm operations completed successfully", "users", len(users))
+}
+
+// =============================================================================
+// Minimal in-memory SQL driver. It does not store data; it returns canned
+// responses.
+// =============================================================================
+
+type testDriver struct{}
+
+func (d *testDriver) Open(name string) (driver.Conn, error) {
+	return &testConn{}, nil
+}
+
+type testConn struct{}
+
+func (c *testConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare not supported")
+}
+
+func (c *testConn) Close() error {
+	return nil
+}
+
+func (c *testConn) Begin() (driver.Tx, error) {
+	return &testTx{}, nil
+}
+
+func (c *testConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return &testRows{
+		columns: []string{"id", "name"},
+		data: [][]driver.Value{
+			{int64(1), "alice"},
+		},
+	}, nil
+}
+
+func (c *testConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return &testResult{}, nil
+}
+
+type testResult struct{}
+
+func (r *testResult) LastInsertId() (int64, error) {
+	return 1, nil
+}
+
+func (r *testResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+type testTx struct{}
+
+func (t *testTx) Commit() error {
+	return nil
+}
+
+func (t *testTx) Rollback() error {
+	return nil
+}
+
+type testRows struct {
+	columns []string
+	data    [][]driver.Value
+	pos     int
+}
+
+func (r *testRows) Columns() []string {
+	return r.columns
+}
+
+func (r *testRows) Close() error {
+	return nil
+}
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.data) {
+		return io.EOF
+	}
+	copy(dest, r.data[r.pos])
+	r.pos++
+	return nil
+}
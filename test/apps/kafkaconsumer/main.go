@@ -20,6 +20,7 @@ import (
 var (
 	topic = flag.String("topic", "orders", "kafka topic")
 	seed  = flag.Bool("seed", true, "seed a message before reading (disable for cross-process E2E tests)")
+	group = flag.String("group", "", "consumer group; when set, messages are fetched and committed explicitly")
 )
 
 func brokers() []string {
@@ -43,6 +44,11 @@ func main() {
 		writeMessage(ctx, "order-1", "hello kafka")
 	}
 
+	if *group != "" {
+		fetchAndCommit(ctx)
+		return
+	}
+
 	r := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:     brokers(),
 		Topic:       *topic,
@@ -59,6 +65,28 @@ func main() {
 	slog.Info("consumed message", "topic", *topic, "key", string(msg.Key), "offset", msg.Offset)
 }
 
+// fetchAndCommit consumes one message as a member of a consumer group, using
+// the at-least-once FetchMessage + CommitMessages pattern.
+func fetchAndCommit(ctx context.Context) {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers(),
+		Topic:       *topic,
+		GroupID:     *group,
+		StartOffset: kafka.FirstOffset,
+		MaxWait:     500 * time.Millisecond,
+	})
+	defer r.Close()
+
+	msg, err := r.FetchMessage(ctx)
+	if err != nil {
+		log.Fatalf("failed to fetch message: %v", err)
+	}
+	if err := r.CommitMessages(ctx, msg); err != nil {
+		log.Fatalf("failed to commit message: %v", err)
+	}
+	slog.Info("consumed message", "topic", *topic, "group", *group, "key", string(msg.Key), "offset", msg.Offset)
+}
+
 // ensureTopic creates the topic up front (best-effort) so a single
 // WriteMessages call succeeds and emits exactly one producer span. CreateTopics
 // is not instrumented, so it adds no spans of its own.
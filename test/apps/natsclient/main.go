@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package main provides a minimal NATS client for integration testing. It
+// talks to an in-process NATS server, so no broker is needed: one message is
+// published to a subject and received by a subscription, and one request is
+// answered by a responder.
+package main
+
+import (
+	"flag"
+	"log"
+	"log/slog"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+var subject = flag.String("subject", "orders", "NATS subject")
+
+func main() {
+	flag.Parse()
+
+	srv, err := server.NewServer(&server.Options{
+		Host:   "127.0.0.1",
+		Port:   server.RANDOM_PORT,
+		NoLog:  true,
+		NoSigs: true,
+	})
+	if err != nil {
+		log.Fatalf("failed to create NATS server: %v", err)
+	}
+	srv.Start()
+	defer srv.Shutdown()
+	if !srv.ReadyForConnections(10 * time.Second) {
+		log.Fatal("NATS server not ready")
+	}
+
+	closed := make(chan struct{})
+	nc, err := nats.Connect(srv.ClientURL(), nats.ClosedHandler(func(*nats.Conn) {
+		close(closed)
+	}))
+	if err != nil {
+		log.Fatalf("failed to connect: %v", err)
+	}
+
+	received := make(chan string, 1)
+	if _, err = nc.Subscribe(*subject, func(msg *nats.Msg) {
+		received <- msg.Header.Get("traceparent")
+	}); err != nil {
+		log.Fatalf("failed to subscribe: %v", err)
+	}
+	if _, err = nc.Subscribe("prices", func(msg *nats.Msg) {
+		_ = msg.Respond([]byte("10"))
+	}); err != nil {
+		log.Fatalf("failed to subscribe: %v", err)
+	}
+
+	if err = nc.Publish(*subject, []byte("order-1")); err != nil {
+		log.Fatalf("failed to publish: %v", err)
+	}
+	select {
+	case traceparent := <-received:
+		slog.Info("received message", "traceparent", traceparent)
+	case <-time.After(10 * time.Second):
+		log.Fatal("message not received")
+	}
+
+	reply, err := nc.Request("prices", []byte("apple"), 10*time.Second)
+	if err != nil {
+		log.Fatalf("request failed: %v", err)
+	}
+	slog.Info("received reply", "data", string(reply.Data))
+
+	// Draining waits for the handlers to return, so their spans end before
+	// the program exits.
+	if err = nc.Drain(); err != nil {
+		log.Fatalf("failed to drain: %v", err)
+	}
+	<-closed
+}
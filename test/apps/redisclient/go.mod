@@ -2,9 +2,15 @@ module go.opentelemetry.io/otelc/test/apps/redisclient
 
 go 1.25.0
 
-require github.com/redis/go-redis/v9 v9.21.0
+require (
+	github.com/go-redis/redis/v8 v8.11.5
+	github.com/redis/go-redis/v9 v9.21.0
+	github.com/redis/rueidis v1.0.78
+)
 
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	go.uber.org/atomic v1.11.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
 )
@@ -11,39 +11,114 @@ import (
 	"log"
 	"log/slog"
 
+	redisv8 "github.com/go-redis/redis/v8"
 	"github.com/redis/go-redis/v9"
+	"github.com/redis/rueidis"
 )
 
-var addr = flag.String("addr", "localhost:6379", "The Redis server address")
+var (
+	addr   = flag.String("addr", "localhost:6379", "The Redis server address")
+	client = flag.String("client", "v9", "The Redis client library: v9, v8 or rueidis")
+)
+
+// kv is the subset of Redis commands exercised by every client.
+type kv interface {
+	Set(ctx context.Context, key, value string) error
+	Get(ctx context.Context, key string) (string, error)
+	Del(ctx context.Context, key string) error
+	Close() error
+}
 
 func main() {
 	flag.Parse()
 
 	ctx := context.Background()
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr: *addr,
-	})
+	var rdb kv
+	switch *client {
+	case "v9":
+		rdb = goRedisV9{redis.NewClient(&redis.Options{Addr: *addr})}
+	case "v8":
+		rdb = goRedisV8{redisv8.NewClient(&redisv8.Options{Addr: *addr})}
+	case "rueidis":
+		c, err := rueidis.NewClient(rueidis.ClientOption{
+			InitAddress:  []string{*addr},
+			DisableCache: true,
+		})
+		if err != nil {
+			log.Fatalf("failed to create rueidis client: %v", err)
+		}
+		rdb = rueidisClient{c}
+	default:
+		log.Fatalf("unknown client %q", *client)
+	}
 	defer rdb.Close()
 
 	// SET command
-	err := rdb.Set(ctx, "testkey", "testvalue", 0).Err()
+	err := rdb.Set(ctx, "testkey", "testvalue")
 	if err != nil {
 		log.Fatalf("failed to set key: %v", err)
 	}
 	slog.Info("SET", "key", "testkey", "value", "testvalue")
 
 	// GET command
-	val, err := rdb.Get(ctx, "testkey").Result()
+	val, err := rdb.Get(ctx, "testkey")
 	if err != nil {
 		log.Fatalf("failed to get key: %v", err)
 	}
 	slog.Info("GET", "key", "testkey", "value", val)
 
 	// DEL command
-	err = rdb.Del(ctx, "testkey").Err()
+	err = rdb.Del(ctx, "testkey")
 	if err != nil {
 		log.Fatalf("failed to del key: %v", err)
 	}
 	slog.Info("DEL", "key", "testkey")
 }
+
+type goRedisV9 struct{ *redis.Client }
+
+func (c goRedisV9) Set(ctx context.Context, key, value string) error {
+	return c.Client.Set(ctx, key, value, 0).Err()
+}
+
+func (c goRedisV9) Get(ctx context.Context, key string) (string, error) {
+	return c.Client.Get(ctx, key).Result()
+}
+
+func (c goRedisV9) Del(ctx context.Context, key string) error {
+	return c.Client.Del(ctx, key).Err()
+}
+
+type goRedisV8 struct{ *redisv8.Client }
+
+func (c goRedisV8) Set(ctx context.Context, key, value string) error {
+	return c.Client.Set(ctx, key, value, 0).Err()
+}
+
+func (c goRedisV8) Get(ctx context.Context, key string) (string, error) {
+	return c.Client.Get(ctx, key).Result()
+}
+
+func (c goRedisV8) Del(ctx context.Context, key string) error {
+	return c.Client.Del(ctx, key).Err()
+}
+
+type rueidisClient struct{ rueidis.Client }
+
+func (c rueidisClient) Set(ctx context.Context, key, value string) error {
+	return c.Do(ctx, c.B().Set().Key(key).Value(value).Build()).Error()
+}
+
+func (c rueidisClient) Get(ctx context.Context, key string) (string, error) {
+	return c.Do(ctx, c.B().Get().Key(key).Build()).ToString()
+}
+
+func (c rueidisClient) Del(ctx context.Context, key string) error {
+	return c.Do(ctx, c.B().Del().Key(key).Build()).Error()
+}
+
+func (c rueidisClient) Close() error {
+	c.Client.Close()
+	return nil
+}
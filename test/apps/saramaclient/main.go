@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package main provides a minimal IBM/sarama client for integration testing.
+// It talks to sarama's in-process mock broker, so no real Kafka is needed: a
+// SyncProducer sends one message, then a consumer group reads one back.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+var topic = flag.String("topic", "orders", "kafka topic")
+
+const group = "workers"
+
+// reporter satisfies sarama.TestReporter outside of tests.
+type reporter struct{}
+
+func (reporter) Error(args ...any)                 { log.Print(args...) }
+func (reporter) Errorf(format string, args ...any) { log.Printf(format, args...) }
+func (reporter) Fatal(args ...any)                 { log.Fatal(args...) }
+func (reporter) Fatalf(format string, args ...any) { log.Fatalf(format, args...) }
+func (reporter) Helper()                           {}
+
+type handler struct {
+	consumed chan *sarama.ConsumerMessage
+}
+
+func (*handler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (*handler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+func (h *handler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		session.MarkMessage(msg, "")
+		h.consumed <- msg
+		return nil
+	}
+	return nil
+}
+
+func newBroker() *sarama.MockBroker {
+	t := reporter{}
+	broker := sarama.NewMockBroker(t, 0)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(*topic, 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t).
+			SetError(*topic, 0, sarama.ErrNoError),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset(*topic, 0, sarama.OffsetOldest, 0).
+			SetOffset(*topic, 0, sarama.OffsetNewest, 1),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, group, broker),
+		"HeartbeatRequest": sarama.NewMockHeartbeatResponse(t),
+		"JoinGroupRequest": sarama.NewMockJoinGroupResponse(t).
+			SetGroupProtocol(sarama.RangeBalanceStrategyName),
+		"SyncGroupRequest": sarama.NewMockSyncGroupResponse(t).SetMemberAssignment(
+			&sarama.ConsumerGroupMemberAssignment{
+				Topics: map[string][]int32{*topic: {0}},
+			}),
+		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+			SetOffset(group, *topic, 0, 0, "", sarama.ErrNoError).
+			SetError(sarama.ErrNoError),
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			SetMessageWithKey(*topic, 0, 0, sarama.StringEncoder("order-1"), sarama.StringEncoder("hello sarama")),
+		"LeaveGroupRequest": sarama.NewMockLeaveGroupResponse(t),
+	})
+	return broker
+}
+
+func newConfig() *sarama.Config {
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_0_0_0
+	// The mock broker does not answer ApiVersions requests.
+	config.ApiVersionsRequest = false
+	config.Producer.Return.Successes = true
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.Offsets.AutoCommit.Enable = false
+	return config
+}
+
+func produce(addr string) {
+	producer, err := sarama.NewSyncProducer([]string{addr}, newConfig())
+	if err != nil {
+		log.Fatalf("failed to create producer: %v", err)
+	}
+	defer producer.Close()
+
+	partition, offset, err := producer.SendMessage(&sarama.ProducerMessage{
+		Topic: *topic,
+		Key:   sarama.StringEncoder("order-1"),
+		Value: sarama.StringEncoder("hello sarama"),
+	})
+	if err != nil {
+		log.Fatalf("failed to send message: %v", err)
+	}
+	slog.Info("produced message", "topic", *topic, "partition", partition, "offset", offset)
+}
+
+func consume(addr string) {
+	cg, err := sarama.NewConsumerGroup([]string{addr}, group, newConfig())
+	if err != nil {
+		log.Fatalf("failed to create consumer group: %v", err)
+	}
+	defer cg.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	h := &handler{consumed: make(chan *sarama.ConsumerMessage, 1)}
+	errCh := make(chan error, 1)
+	go func() { errCh <- cg.Consume(ctx, []string{*topic}, h) }()
+
+	select {
+	case msg := <-h.consumed:
+		slog.Info("consumed message", "topic", msg.Topic, "key", string(msg.Key), "offset", msg.Offset)
+	case err := <-errCh:
+		log.Fatalf("consume ended without a message: %v", err)
+	case <-ctx.Done():
+		log.Fatal("timed out waiting for a message")
+	}
+	cancel()
+	<-errCh
+}
+
+func main() {
+	flag.Parse()
+
+	broker := newBroker()
+	defer broker.Close()
+
+	produce(broker.Addr())
+	consume(broker.Addr())
+	fmt.Println("done")
+}
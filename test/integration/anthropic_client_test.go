@@ -24,6 +24,7 @@ func TestAnthropicClient(t *testing.T) {
 		model         string
 		cacheRead     int64
 		cacheCreation int64
+		stream        bool
 	}{
 		{
 			name:  "messages",
@@ -35,6 +36,12 @@ func TestAnthropicClient(t *testing.T) {
 			cacheRead:     7,
 			cacheCreation: 3,
 		},
+		{
+			name:      "messages_streaming",
+			model:     "claude-sonnet-4-5",
+			cacheRead: 7,
+			stream:    true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -46,6 +53,7 @@ func TestAnthropicClient(t *testing.T) {
 				fmt.Sprintf("-addr=%s", server.URL),
 				"-api-key=test-key",
 				fmt.Sprintf("-model=%s", tc.model),
+				fmt.Sprintf("-stream=%t", tc.stream),
 			)
 
 			// Anthropic's input_tokens excludes cache tokens; the
@@ -77,13 +85,17 @@ func TestAnthropicClient(t *testing.T) {
 			if tc.cacheCreation > 0 {
 				testutil.RequireAttribute(t, span, "gen_ai.usage.cache_creation.input_tokens", tc.cacheCreation)
 			}
+			if tc.stream {
+				testutil.RequireAttribute(t, span, "gen_ai.request.is_stream", true)
+			}
 		})
 	}
 }
 
 // startMockAnthropicServer creates a mock Anthropic API server for testing.
 // Non-zero cache token values are included in the response usage to exercise
-// the prompt-cache attribute path.
+// the prompt-cache attribute path. Streaming requests are answered with an
+// event stream that splits the usage across message_start and message_delta.
 func startMockAnthropicServer(t *testing.T, cacheRead, cacheCreation int64) *httptest.Server {
 	t.Helper()
 
@@ -91,7 +103,8 @@ func startMockAnthropicServer(t *testing.T, cacheRead, cacheCreation int64) *htt
 	mux.HandleFunc("/v1/messages", func(w http.ResponseWriter, r *http.Request) {
 		// Parse model from request body
 		var reqBody struct {
-			Model string `json:"model"`
+			Model  string `json:"model"`
+			Stream bool   `json:"stream"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -109,6 +122,11 @@ func startMockAnthropicServer(t *testing.T, cacheRead, cacheCreation int64) *htt
 			usage["cache_creation_input_tokens"] = cacheCreation
 		}
 
+		if reqBody.Stream {
+			writeMockAnthropicStream(t, w, reqBody.Model, usage)
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		resp := map[string]any{
 			"id":    "msg-test-123",
@@ -134,3 +152,60 @@ func startMockAnthropicServer(t *testing.T, cacheRead, cacheCreation int64) *htt
 	t.Cleanup(server.Close)
 	return server
 }
+
+// writeMockAnthropicStream writes a Messages API event stream. message_start
+// reports the input usage and message_delta the final output token count.
+func writeMockAnthropicStream(t *testing.T, w http.ResponseWriter, model string, usage map[string]any) {
+	t.Helper()
+
+	startUsage := map[string]any{"output_tokens": 1}
+	for k, v := range usage {
+		if k != "output_tokens" {
+			startUsage[k] = v
+		}
+	}
+	events := []struct {
+		name string
+		data map[string]any
+	}{
+		{"message_start", map[string]any{
+			"type": "message_start",
+			"message": map[string]any{
+				"id":          "msg-test-123",
+				"type":        "message",
+				"role":        "assistant",
+				"model":       model,
+				"content":     []any{},
+				"stop_reason": nil,
+				"usage":       startUsage,
+			},
+		}},
+		{"content_block_start", map[string]any{
+			"type":          "content_block_start",
+			"index":         0,
+			"content_block": map[string]any{"type": "text", "text": ""},
+		}},
+		{"content_block_delta", map[string]any{
+			"type":  "content_block_delta",
+			"index": 0,
+			"delta": map[string]any{"type": "text_delta", "text": "Hello!"},
+		}},
+		{"content_block_stop", map[string]any{"type": "content_block_stop", "index": 0}},
+		{"message_delta", map[string]any{
+			"type":  "message_delta",
+			"delta": map[string]any{"stop_reason": "end_turn", "stop_sequence": nil},
+			"usage": map[string]any{"output_tokens": usage["output_tokens"]},
+		}},
+		{"message_stop", map[string]any{"type": "message_stop"}},
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, e := range events {
+		data, err := json.Marshal(e.data)
+		if err != nil {
+			t.Errorf("failed to encode event: %v", err)
+			return
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, data)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"go.opentelemetry.io/otelc/test/testutil"
+)
+
+func TestAWSClient(t *testing.T) {
+	t.Parallel()
+	testutil.Build(t, "", "awsclient", "go", "build", "-a")
+
+	// The app runs against an in-process HTTP stand-in for SQS, so no AWS
+	// account or emulator is needed.
+	f := testutil.NewTestFixture(t)
+	out := f.Run("awsclient", "-queue=orders")
+	require.Contains(t, out, "sent message")
+
+	span := testutil.RequireSpan(t, f.Traces(),
+		func(s ptrace.Span) bool { return s.Name() == "SQS.SendMessage" },
+	)
+	require.Equal(t, ptrace.SpanKindClient, span.Kind())
+	require.NotEqual(t, ptrace.StatusCodeError, span.Status().Code())
+
+	attrs := testutil.Attrs(span)
+	require.Equal(t, "aws_sqs", attrs["messaging.system"])
+	require.Equal(t, "SQS/SendMessage", attrs["rpc.method"])
+	require.Equal(t, "us-east-1", attrs["aws.region"])
+
+	// The stand-in received the context of the span in the message attributes
+	require.Contains(t, out, span.TraceID().String()+"-"+span.SpanID().String())
+}
@@ -39,7 +39,7 @@ func TestDBClient(t *testing.T) {
 		f.Run("dbclient", "-op=exec")
 
 		span := f.RequireSingleSpan()
-		require.Equal(t, "INSERT", span.Name())
+		require.Equal(t, "INSERT users", span.Name())
 		testutil.RequireDBClientSemconv(t, span,
 			"INSERT",
 			"INSERT INTO users (name, email) VALUES (?, ?)",
@@ -53,8 +53,17 @@ func TestDBClient(t *testing.T) {
 
 		f.Run("dbclient", "-op=query")
 
-		span := f.RequireSingleSpan()
-		require.Equal(t, "SELECT", span.Name())
+		// QueryContext -> rows iteration = 2 spans
+		spans := testutil.AllSpans(f.Traces())
+		require.Len(t, spans, 2, "Expected query and rows spans")
+
+		span := testutil.RequireSpan(t, f.Traces(), testutil.HasName("SELECT users"))
+		testutil.RequireAttribute(t, span, string(semconv.DBCollectionNameKey), "users")
+		testutil.RequireAttribute(t, span, string(semconv.DBQuerySummaryKey), "SELECT users")
+
+		rowsSpan := testutil.RequireSpan(t, f.Traces(), testutil.HasName("SELECT users rows"))
+		require.Equal(t, span.SpanID(), rowsSpan.ParentSpanID())
+		testutil.RequireAttribute(t, rowsSpan, string(semconv.DBResponseReturnedRowsKey), int64(1))
 		testutil.RequireDBClientSemconv(t, span,
 			"SELECT",
 			"SELECT id, name FROM users WHERE name = ?",
@@ -73,8 +82,12 @@ func TestDBClient(t *testing.T) {
 		require.GreaterOrEqual(t, len(spans), 1, "Expected at least 1 span from prepared statement query")
 
 		// Find the query span from stmt.QueryContext
-		stmtSpan := testutil.RequireSpan(t, f.Traces(), testutil.IsClient)
-		require.Equal(t, "SELECT", stmtSpan.Name())
+		stmtSpan := testutil.RequireSpan(t, f.Traces(), testutil.IsClient, testutil.HasName("SELECT users"))
+
+		// The rows are closed without being iterated.
+		rowsSpan := testutil.RequireSpan(t, f.Traces(), testutil.HasName("SELECT users rows"))
+		require.Equal(t, stmtSpan.SpanID(), rowsSpan.ParentSpanID())
+		testutil.RequireAttribute(t, rowsSpan, string(semconv.DBResponseReturnedRowsKey), int64(0))
 	})
 
 	t.Run("Transaction", func(t *testing.T) {
@@ -96,7 +109,7 @@ func TestDBClient(t *testing.T) {
 			testutil.IsClient,
 			testutil.HasAttribute("db.operation.name", "INSERT"),
 		)
-		require.Equal(t, "INSERT", execSpan.Name())
+		require.Equal(t, "INSERT orders", execSpan.Name())
 
 		commitSpan := testutil.RequireSpan(t, f.Traces(),
 			testutil.IsClient,
@@ -114,16 +127,18 @@ func TestDBClient(t *testing.T) {
 			"-op=all",
 		)
 
-		// "all" operation produces 7 spans:
+		// "all" operation produces 9 spans:
 		//   PING (PingContext)
-		//   INSERT (ExecContext)
-		//   SELECT (QueryContext)
-		//   SELECT (Stmt.QueryContext via PrepareContext)
+		//   INSERT users (ExecContext)
+		//   SELECT users (QueryContext)
+		//   SELECT users rows (Rows iteration)
+		//   SELECT users (Stmt.QueryContext via PrepareContext)
+		//   SELECT users rows (Rows.Close)
 		//   START (BeginTx)
-		//   INSERT (Tx.ExecContext)
+		//   INSERT orders (Tx.ExecContext)
 		//   COMMIT (Tx.Commit)
 		spans := testutil.AllSpans(f.Traces())
-		require.GreaterOrEqual(t, len(spans), 7, "Expected at least 7 spans")
+		require.GreaterOrEqual(t, len(spans), 9, "Expected at least 9 spans")
 
 		// For "testdb" driver, parseDSN returns an error (unknown driver),
 		// so beforeOpenInstrumentation falls back to "unknown" as the endpoint.
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package test
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otelc/test/testutil"
+)
+
+func TestFastHTTPClient(t *testing.T) {
+	t.Parallel()
+	testutil.Build(t, "", "fasthttpclient", "go", "build", "-a")
+
+	// The app sends its request to an in-process fasthttp server, so both
+	// sides of the request are traced.
+	f := testutil.NewTestFixture(t)
+	out := f.Run("fasthttpclient", "-name=world")
+	require.Contains(t, out, "Hello world")
+
+	traces := f.Traces()
+	client := testutil.RequireSpan(t, traces, testutil.IsClient, testutil.HasName("GET"))
+	urlFull := client.Attributes().AsRaw()["url.full"].(string)
+	require.Contains(t, urlFull, "/hello?name=world")
+	port := serverPort(t, urlFull)
+	testutil.RequireHTTPClientSemconv(t, client, "GET", urlFull, "127.0.0.1", 200, port, "1.1", "http")
+
+	server := testutil.RequireSpan(t, traces, testutil.IsServer, testutil.HasName("GET"))
+	testutil.RequireHTTPServerSemconv(t, server, "GET", "/hello", "http", 200, port,
+		"127.0.0.1", "fasthttp", "1.1", "127.0.0.1")
+	require.Equal(t, client.TraceID(), server.TraceID())
+	require.Equal(t, client.SpanID(), server.ParentSpanID())
+}
+
+// serverPort returns the port of the server requested at urlFull.
+func serverPort(t *testing.T, urlFull string) int64 {
+	t.Helper()
+	u, err := url.Parse(urlFull)
+	require.NoError(t, err)
+	_, p, err := net.SplitHostPort(u.Host)
+	require.NoError(t, err)
+	port, err := strconv.ParseInt(p, 10, 64)
+	require.NoError(t, err)
+	return port
+}
@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+
+	"go.opentelemetry.io/otelc/test/testutil"
+)
+
+func TestGORMClient(t *testing.T) {
+	t.Parallel()
+	testutil.Build(t, "", "gormclient", "go", "build", "-a")
+	f := testutil.NewTestFixture(t)
+
+	f.Run("gormclient")
+
+	create := testutil.RequireSpan(t, f.Traces(), testutil.HasName("gorm.create users"))
+	testutil.RequireAttribute(t, create, string(semconv.DBOperationNameKey), "create")
+	testutil.RequireAttribute(t, create, string(semconv.DBCollectionNameKey), "users")
+	testutil.RequireAttribute(t, create, string(semconv.DBSystemNameKey), "mysql")
+
+	insert := testutil.RequireSpan(t, f.Traces(), testutil.IsClient, testutil.HasName("INSERT `users`"))
+	require.Equal(t, create.SpanID(), insert.ParentSpanID(), "database/sql span must be a child of the GORM span")
+
+	query := testutil.RequireSpan(t, f.Traces(), testutil.HasName("gorm.query users"))
+	testutil.RequireAttribute(t, query, string(semconv.DBCollectionNameKey), "users")
+
+	selectSpan := testutil.RequireSpan(t, f.Traces(), testutil.IsClient, testutil.HasName("SELECT `users`"))
+	require.Equal(t, query.SpanID(), selectSpan.ParentSpanID(), "database/sql span must be a child of the GORM span")
+}
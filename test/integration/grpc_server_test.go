@@ -65,6 +65,24 @@ func TestGRPCServer(t *testing.T) {
 		})
 	}
 
+	t.Run("excluded methods", func(t *testing.T) {
+		f := testutil.NewTestFixture(t)
+		f.SetEnv("OTEL_GO_GRPC_EXCLUDED_METHODS", "greeter.Greeter/SayHello")
+		port := testutil.FreePort(t)
+		addr := fmt.Sprintf("localhost:%d", port)
+
+		f.Start("grpcserver", fmt.Sprintf("-port=%d", port))
+		testutil.WaitForTCP(t, addr)
+
+		client := NewGRPCClient(t, addr)
+		client.SayHello(t, "ExcludedUser")
+		client.SayHelloStream(t, "StreamUser", 1)
+		testutil.WaitForSpanFlush(t)
+
+		span := f.RequireSingleSpan()
+		testutil.RequireGRPCServerSemconv(t, span, "greeter.Greeter", "SayHelloStream", 0)
+	})
+
 	// This test verifies that telemetry is properly flushed
 	// when the server receives SIGINT, using the batch span processor.
 	// This test validates that the signal-based shutdown handler in the instrumentation
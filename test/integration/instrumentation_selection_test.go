@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,9 +26,10 @@ func TestExplicitInstrumentationSelection(t *testing.T) {
 
 	testutil.Build(t, "", "gincustom", "go", "build", "-a")
 
-	// Verify .otelc-build/matched.json only contains net/http, custom gin instrumentation
+	// Verify the matched rules in .otelc-build only contains net/http, custom gin instrumentation
 	// but not the built-in gin instrumentation:
-	matched := filepath.Join("../", "apps", "gincustom", ".otelc-build", "matched.json")
+	matched := filepath.Join("../", "apps", "gincustom", ".otelc-build",
+		"matched."+runtime.GOOS+"_"+runtime.GOARCH+".json")
 	require.FileExists(t, matched)
 
 	matchedData, readErr := os.ReadFile(matched)
@@ -73,6 +73,37 @@ func TestKafkaClient(t *testing.T) {
 		require.Equal(t, "0", attrs["messaging.destination.partition.id"])
 		require.Contains(t, attrs, "messaging.kafka.offset")
 	})
+
+	t.Run("FetchAndCommit", func(t *testing.T) {
+		f := testutil.NewTestFixture(t)
+		f.SetEnv("KAFKA_BROKERS", strings.Join(brokers, ","))
+
+		out := f.Run("kafkaconsumer", "-topic=payments", "-group=workers")
+		require.Contains(t, out, "consumed message")
+
+		receive := testutil.RequireSpan(t, f.Traces(),
+			func(s ptrace.Span) bool { return s.Kind() == ptrace.SpanKindConsumer },
+		)
+		require.Equal(t, "payments receive", receive.Name())
+		// The seeded message carries the producer context, so the receive
+		// span links back to the producer span.
+		require.Equal(t, 1, receive.Links().Len())
+
+		attrs := testutil.Attrs(receive)
+		require.Equal(t, "workers", attrs["messaging.consumer.group.name"])
+		require.Equal(t, "0", attrs["messaging.destination.partition.id"])
+
+		commit := testutil.RequireSpan(t, f.Traces(),
+			func(s ptrace.Span) bool { return s.Name() == "payments commit" },
+		)
+		require.Equal(t, ptrace.SpanKindClient, commit.Kind())
+		require.NotEqual(t, ptrace.StatusCodeError, commit.Status().Code())
+
+		attrs = testutil.Attrs(commit)
+		require.Equal(t, "commit", attrs["messaging.operation.name"])
+		require.Equal(t, "settle", attrs["messaging.operation.type"])
+		require.Equal(t, "workers", attrs["messaging.consumer.group.name"])
+	})
 }
 
 func startKafkaContainer(t *testing.T) []string {
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"go.opentelemetry.io/otelc/test/testutil"
+)
+
+func TestNATSClient(t *testing.T) {
+	t.Parallel()
+	testutil.Build(t, "", "natsclient", "go", "build", "-a")
+
+	// The app runs against an in-process NATS server, so no broker is needed.
+	f := testutil.NewTestFixture(t)
+	out := f.Run("natsclient", "-subject=orders")
+	require.Contains(t, out, "received reply")
+
+	traces := f.Traces()
+	producer := testutil.RequireSpan(t, traces, testutil.HasName("orders publish"))
+	require.Equal(t, ptrace.SpanKindProducer, producer.Kind())
+	attrs := testutil.Attrs(producer)
+	require.Equal(t, "nats", attrs["messaging.system"])
+	require.Equal(t, "send", attrs["messaging.operation.type"])
+	require.Equal(t, "orders", attrs["messaging.destination.name"])
+	require.Equal(t, "127.0.0.1", attrs["server.address"])
+
+	consumer := testutil.RequireSpan(t, traces, testutil.HasName("orders process"))
+	require.Equal(t, ptrace.SpanKindConsumer, consumer.Kind())
+	require.Equal(t, producer.TraceID(), consumer.TraceID())
+	require.Equal(t, producer.SpanID(), consumer.ParentSpanID())
+
+	// The headers seen by the subscription handler carry the context of the
+	// process span, so the handler continues the trace below it.
+	require.Contains(t, out, consumer.TraceID().String()+"-"+consumer.SpanID().String())
+
+	request := testutil.RequireSpan(t, traces, testutil.HasName("prices request"))
+	require.Equal(t, ptrace.SpanKindClient, request.Kind())
+	require.NotEqual(t, ptrace.StatusCodeError, request.Status().Code())
+
+	// The request message is published below the request span, and the
+	// responder processes it in the same trace.
+	published := testutil.RequireSpan(t, traces, testutil.HasName("prices publish"))
+	require.Equal(t, request.SpanID(), published.ParentSpanID())
+	responder := testutil.RequireSpan(t, traces, testutil.HasName("prices process"))
+	require.Equal(t, request.TraceID(), responder.TraceID())
+}
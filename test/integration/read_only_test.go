@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package test
+
+import (
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otelc/test/testutil"
+)
+
+func TestReadOnlyBuild(t *testing.T) {
+	otelcPath, err := testutil.OtelcPath()
+	require.NoError(t, err)
+	absoluteOtelcPath, err := filepath.Abs(otelcPath)
+	require.NoError(t, err)
+
+	workspace := t.TempDir()
+	moduleDir := filepath.Join(workspace, "module")
+	require.NoError(t, os.MkdirAll(moduleDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "go.mod"), []byte(preparedGoMod), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.go"), []byte(preparedMainSource), 0o644))
+	makeReadOnly(t, moduleDir)
+	before := snapshotTree(t, moduleDir)
+
+	name := "app"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	output := filepath.Join(workspace, "out", name)
+	cmd := exec.CommandContext(t.Context(), absoluteOtelcPath, "--read-only", "go", "build", "-o", output, ".")
+	cmd.Dir = moduleDir
+	cmd.Env = append(preparedPlainBuildEnvironment(preparedBuildBaseEnvironment(), filepath.Join(workspace, "gocache")),
+		"OTELC_WORK_DIR="+filepath.Join(workspace, "work"))
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "otelc --read-only go build failed:\n%s", out)
+
+	require.Equal(t, before, snapshotTree(t, moduleDir), "read-only build modified the module")
+
+	server := StartHTTPServerWithResponse(t, http.StatusOK, `{"message":"Hello"}`)
+	fixture := testutil.NewTestFixture(t, testutil.WithAppsDir(workspace))
+	fixture.Run("out", "-addr="+server.URL)
+	span := fixture.RequireSingleSpan()
+	testutil.RequireHTTPClientSemconv(
+		t,
+		span,
+		http.MethodGet,
+		server.URL+"/hello",
+		"127.0.0.1",
+		http.StatusOK,
+		server.Port(),
+		"1.1",
+		"http",
+	)
+}
+
+// makeReadOnly removes write permissions from dir and everything in it, and
+// restores them on cleanup so the temp dir can be removed.
+func makeReadOnly(t *testing.T, dir string) {
+	t.Helper()
+	chmodTree(t, dir, 0o444, 0o555)
+	t.Cleanup(func() { chmodTree(t, dir, 0o644, 0o755) })
+}
+
+func chmodTree(t *testing.T, dir string, fileMode, dirMode os.FileMode) {
+	t.Helper()
+	// Directories are changed after their contents: walk the tree first.
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			dirs = append(dirs, path)
+			return nil
+		}
+		return os.Chmod(path, fileMode)
+	})
+	require.NoError(t, err)
+	for i := len(dirs) - 1; i >= 0; i-- {
+		require.NoError(t, os.Chmod(dirs[i], dirMode))
+	}
+}
+
+// snapshotTree returns the content of every file under dir by relative path.
+func snapshotTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[rel] = string(content)
+		return nil
+	})
+	require.NoError(t, err)
+	return files
+}
@@ -19,10 +19,20 @@ func TestRedisClient(t *testing.T) {
 	testutil.Build(t, "", "redisclient", "go", "build", "-a")
 
 	testCases := []struct {
-		name string
+		name   string
+		client string
 	}{
 		{
-			name: "basic",
+			name:   "basic",
+			client: "v9",
+		},
+		{
+			name:   "go-redis v8",
+			client: "v8",
+		},
+		{
+			name:   "rueidis",
+			client: "rueidis",
 		},
 	}
 
@@ -31,7 +41,7 @@ func TestRedisClient(t *testing.T) {
 			f := testutil.NewTestFixture(t)
 			server := StartRedisServer(t)
 
-			output := f.Run("redisclient", "-addr="+server.Addr())
+			output := f.Run("redisclient", "-addr="+server.Addr(), "-client="+tc.client)
 			require.Contains(t, output, "testvalue")
 
 			spans := testutil.AllSpans(f.Traces())
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"go.opentelemetry.io/otelc/test/testutil"
+)
+
+func TestSaramaClient(t *testing.T) {
+	t.Parallel()
+	testutil.Build(t, "", "saramaclient", "go", "build", "-a")
+
+	// The app runs against sarama's in-process mock broker, so no Kafka
+	// container is needed. The mock broker serves canned fetch responses
+	// without headers, so the receive span is not linked to the producer.
+	f := testutil.NewTestFixture(t)
+	out := f.Run("saramaclient", "-topic=orders")
+	require.Contains(t, out, "consumed message")
+
+	producer := testutil.RequireSpan(t, f.Traces(),
+		func(s ptrace.Span) bool { return s.Kind() == ptrace.SpanKindProducer },
+	)
+	require.Equal(t, "orders send", producer.Name())
+	require.NotEqual(t, ptrace.StatusCodeError, producer.Status().Code())
+
+	attrs := testutil.Attrs(producer)
+	require.Equal(t, "kafka", attrs["messaging.system"])
+	require.Equal(t, "send", attrs["messaging.operation.name"])
+	require.Equal(t, "orders", attrs["messaging.destination.name"])
+	require.Equal(t, "order-1", attrs["messaging.kafka.message.key"])
+	require.Equal(t, "0", attrs["messaging.destination.partition.id"])
+	require.Contains(t, attrs, "messaging.kafka.offset")
+
+	receive := testutil.RequireSpan(t, f.Traces(),
+		func(s ptrace.Span) bool { return s.Kind() == ptrace.SpanKindConsumer },
+	)
+	require.Equal(t, "orders receive", receive.Name())
+	require.NotEqual(t, ptrace.StatusCodeError, receive.Status().Code())
+
+	attrs = testutil.Attrs(receive)
+	require.Equal(t, "receive", attrs["messaging.operation.name"])
+	require.Equal(t, "orders", attrs["messaging.destination.name"])
+	require.Equal(t, "workers", attrs["messaging.consumer.group.name"])
+	require.Equal(t, "0", attrs["messaging.destination.partition.id"])
+}
@@ -168,7 +168,7 @@ func (b *preparedBuildCase) setup(args ...string) {
 	require.NoError(b.t, err, "otelc setup failed:\n%s", output)
 	b.prepared = true
 
-	matchedPath := filepath.Join(b.moduleDir, ".otelc-build", "matched.json")
+	matchedPath := filepath.Join(b.moduleDir, ".otelc-build", "matched."+runtime.GOOS+"_"+runtime.GOARCH+".json")
 	matched, err := os.ReadFile(matchedPath)
 	require.NoError(b.t, err)
 	require.Contains(b.t, string(matched), "/instrumentation/net/http/client")
@@ -0,0 +1,100 @@
+# Copyright The OpenTelemetry Authors
+# SPDX-License-Identifier: Apache-2.0
+
+"""Instruments rules_go builds with otelc.
+
+otelc_go_sdk wraps a Go SDK so that its compiler runs through
+`otelc instrument-package`, which instruments every package as it is compiled.
+Register the result with rules_go's go_wrap_sdk. See docs/bazel.md.
+"""
+
+# Files of the wrapped SDK that belong to the repository, not the SDK tree
+_SKIPPED = ["BUILD", "BUILD.bazel", "WORKSPACE", "WORKSPACE.bazel", "REPO.bazel"]
+
+_COMPILE = """#!/bin/sh
+# Generated by otelc_go_sdk: compiles through otelc instrument-package.
+set -e
+tooldir=$(cd "$(dirname "$0")" && pwd)
+# Compile actions run in parallel; each extracts the instrumentation bundle
+# into a work directory of its own.
+workdir=$(mktemp -d)
+trap 'rm -rf "$workdir"' EXIT
+OTELC_WORK_DIR="$workdir" "$tooldir/otelc/otelc" {global_flags} instrument-package {flags} -- "$tooldir/otelc/compile" "$@"
+"""
+
+def _link_children(rctx, src, dst, skip = []):
+    for child in src.readdir():
+        if child.basename not in skip:
+            rctx.symlink(child, dst + "/" + child.basename)
+
+def _otelc_go_sdk_impl(rctx):
+    goroot = rctx.path(rctx.attr.go_sdk).dirname
+    _link_children(rctx, goroot, ".", skip = _SKIPPED + ["pkg"])
+    _link_children(rctx, goroot.get_child("pkg"), "pkg", skip = ["tool"])
+
+    global_flags = []
+    flags = []
+    tool_dirs = goroot.get_child("pkg").get_child("tool").readdir()
+    for tool_dir in tool_dirs:
+        if not tool_dir.get_child("compile").exists:
+            continue
+        dst = "pkg/tool/" + tool_dir.basename
+        _link_children(rctx, tool_dir, dst, skip = ["compile"])
+        # The go command checks the name the compiler prints for -V=full
+        rctx.symlink(tool_dir.get_child("compile"), dst + "/otelc/compile")
+
+        # Everything otelc reads lives under the tool directory, which rules_go
+        # declares as an input of every compile action.
+        rctx.symlink(rctx.attr.otelc, dst + "/otelc/otelc")
+        rules = []
+        for i, rule in enumerate(rctx.attr.rules):
+            name = "otelc/rules/%d/%s" % (i, rule.name.split("/")[-1])
+            rctx.symlink(rule, dst + "/" + name)
+            rules.append("$tooldir/" + name)
+        global_flags = ["--rules", ",".join(rules)] if rules else []
+
+        flags = []
+        for i, (hook, importpath) in enumerate(rctx.attr.hooks.items()):
+            name = "otelc/hooks/%d" % i
+            rctx.symlink(rctx.path(hook).dirname, dst + "/" + name)
+            flags += ["--hook", "%s=$tooldir/%s" % (importpath, name)]
+        for module, version in sorted(rctx.attr.module_versions.items()):
+            flags += ["--module-version", "%s=%s" % (module, version)]
+
+        rctx.file(dst + "/compile", _COMPILE.format(
+            global_flags = " ".join(['"%s"' % f for f in global_flags]),
+            flags = " ".join(['"%s"' % f for f in flags]),
+        ), executable = True)
+
+    rctx.file("BUILD.bazel", 'exports_files(["ROOT"])\n')
+
+otelc_go_sdk = repository_rule(
+    implementation = _otelc_go_sdk_impl,
+    doc = "A copy of a Go SDK whose compiler instruments packages with otelc. " +
+          "Pass its ROOT file to go_wrap_sdk as root_file.",
+    attrs = {
+        "go_sdk": attr.label(
+            mandatory = True,
+            allow_single_file = True,
+            doc = "The ROOT file of the Go SDK to wrap, such as @go_sdk//:ROOT.",
+        ),
+        "otelc": attr.label(
+            mandatory = True,
+            allow_single_file = True,
+            doc = "The otelc binary for the host platform.",
+        ),
+        "rules": attr.label_list(
+            allow_files = [".yml", ".yaml"],
+            doc = "Rule files, as for --rules. Empty selects the rules embedded in otelc.",
+        ),
+        "hooks": attr.label_keyed_string_dict(
+            allow_files = True,
+            doc = "A file in the source directory of each hook package the rules " +
+                  "use, mapped to the import path of the package. The hook packages " +
+                  "of the embedded rules need no entry.",
+        ),
+        "module_versions": attr.string_dict(
+            doc = "Module paths mapped to their versions, for rules with a version range.",
+        ),
+    },
+)
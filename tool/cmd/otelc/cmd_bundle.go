@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/urfave/cli/v3"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/internal/setup"
+)
+
+//nolint:gochecknoglobals // Implementation of a CLI command
+var commandBundle = cli.Command{
+	Name:        "bundle",
+	Description: "Create and verify rule bundles, for builds with --bundle that need no network access",
+	Before:      addLoggerPhaseAttribute,
+	Commands: []*cli.Command{
+		&commandBundleCreate,
+		&commandBundleVerify,
+	},
+}
+
+//nolint:gochecknoglobals // Implementation of a CLI command
+var commandBundleCreate = cli.Command{
+	Name: "create",
+	Description: "Package instrumentation modules, their rules and their dependencies into a versioned archive. " +
+		"Modules are directories or embedded instrumentation module paths; none selects all embedded instrumentation.",
+	ArgsUsage: "[module directories or paths]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      "output",
+			Aliases:   []string{"o"},
+			Usage:     "The path of the bundle archive",
+			TakesFile: true,
+			Required:  true,
+		},
+		&cli.StringFlag{
+			Name:     "version",
+			Usage:    "The version of the bundle",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:      "key",
+			Usage:     "The PEM PKCS #8 Ed25519 private key to sign the bundle with",
+			TakesFile: true,
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		output := cmd.String("output")
+		manifest, err := setup.CreateBundle(ctx, setup.BundleOptions{
+			Output:  output,
+			Version: cmd.String("version"),
+			Modules: cmd.Args().Slice(),
+			Key:     cmd.String("key"),
+		})
+		if err != nil {
+			return err
+		}
+		if err = printBundle(cmd.Root().Writer, output, manifest); err != nil {
+			return err
+		}
+		written := []string{output + setup.BundleChecksumExt}
+		if cmd.IsSet("key") {
+			written = append(written, output+setup.BundleSignatureExt)
+		}
+		for _, file := range written {
+			if _, err = fmt.Fprintf(cmd.Root().Writer, "wrote %s\n", file); err != nil {
+				return ex.Wrapf(err, "failed to print bundle")
+			}
+		}
+		return nil
+	},
+}
+
+//nolint:gochecknoglobals // Implementation of a CLI command
+var commandBundleVerify = cli.Command{
+	Name:        "verify",
+	Description: "Check a rule bundle against its checksum, its module hashes and, given a key, its signature",
+	ArgsUsage:   "bundle",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      "key",
+			Usage:     "The PEM Ed25519 public key the bundle must be signed with",
+			TakesFile: true,
+		},
+	},
+	Action: func(_ context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != 1 {
+			return ex.Newf("expected one bundle, got %d arguments", cmd.Args().Len())
+		}
+		bundle := cmd.Args().First()
+		manifest, err := setup.VerifyBundle(bundle, cmd.String("key"))
+		if err != nil {
+			return err
+		}
+		return printBundle(cmd.Root().Writer, bundle, manifest)
+	},
+}
+
+func printBundle(w io.Writer, bundle string, manifest *setup.BundleManifest) error {
+	_, err := fmt.Fprintf(w, "%s: version %s, created by otelc %s\n", bundle, manifest.Version, manifest.OtelcVersion)
+	for _, m := range manifest.Modules {
+		if err != nil {
+			break
+		}
+		_, err = fmt.Fprintf(w, "  %s %s\n", m.Path, m.Hash)
+	}
+	if err != nil {
+		return ex.Wrapf(err, "failed to print bundle")
+	}
+	return nil
+}
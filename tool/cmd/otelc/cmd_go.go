@@ -11,7 +11,7 @@ import (
 //nolint:gochecknoglobals // Implementation of a CLI command
 var commandGo = cli.Command{
 	Name:            "go",
-	Description:     "Invoke the go toolchain with toolexec mode",
+	Description:     "Invoke the go toolchain with toolexec mode. build, install, run and test are instrumented; vet and generate run on the original sources",
 	ArgsUsage:       "[go toolchain flags]",
 	SkipFlagParsing: true,
 	Before:          addLoggerPhaseAttribute,
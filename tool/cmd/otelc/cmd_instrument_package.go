@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/internal/setup"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+//nolint:gochecknoglobals // Implementation of a CLI command
+var commandInstrumentPackage = cli.Command{
+	Name: "instrument-package",
+	Description: "Instrument one package as a build system action, without the go command. " +
+		"Given source files, write the instrumented package to --out and print the files to compile. " +
+		"Given a compile command after --, run it on the instrumented sources.",
+	ArgsUsage: "[source files | -- compile command]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "importpath",
+			Usage: "The import path of the package",
+		},
+		&cli.StringFlag{
+			Name:      "importcfg",
+			Usage:     "The importcfg of the compile, listing every package the instrumentation imports",
+			TakesFile: true,
+		},
+		&cli.StringFlag{
+			Name:  "version",
+			Usage: "The module version of the package, matched against rule versions",
+		},
+		&cli.StringMapFlag{
+			Name:  "module-version",
+			Usage: "The version of a module, as path=version (repeatable), for packages without --version",
+		},
+		&cli.StringFlag{
+			Name:      "out",
+			Usage:     "The directory the instrumented files are written to",
+			TakesFile: true,
+		},
+		&cli.StringMapFlag{
+			Name:  "hook",
+			Usage: "The source directory of a hook package, as importpath=dir (repeatable)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "root-module",
+			Usage: "A module path the $root rule target expands to (repeatable)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "tags",
+			Usage: "A build tag of the compile, for where.file.build rules (repeatable)",
+		},
+	},
+	Before: addLoggerPhaseAttribute,
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		opts := setup.PackageOptions{
+			ImportPath:     cmd.String("importpath"),
+			Version:        cmd.String("version"),
+			ModuleVersions: cmd.StringMap("module-version"),
+			Sources:        cmd.Args().Slice(),
+			ImportCfg:      cmd.String("importcfg"),
+			Rules:          cmd.String("rules"),
+			Hooks:          cmd.StringMap("hook"),
+			RootModules:    cmd.StringSlice("root-module"),
+			Tags:           cmd.StringSlice("tags"),
+			OutDir:         cmd.String("out"),
+		}
+		if cmd.Args().Present() && !util.IsGoFile(cmd.Args().First()) {
+			return setup.InstrumentCompile(ctx, opts, cmd.Args().Slice())
+		}
+		for _, flag := range []string{"importpath", "importcfg", "out"} {
+			if !cmd.IsSet(flag) {
+				return ex.Newf("--%s is required when instrumenting source files", flag)
+			}
+		}
+		sources, err := setup.InstrumentPackage(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, source := range sources {
+			if _, err = fmt.Fprintln(cmd.Root().Writer, source); err != nil {
+				return ex.Wrap(err)
+			}
+		}
+		return nil
+	},
+}
@@ -15,6 +15,7 @@ import (
 
 	"go.opentelemetry.io/otelc/tool/ex"
 	"go.opentelemetry.io/otelc/tool/internal/profile"
+	"go.opentelemetry.io/otelc/tool/internal/report"
 	"go.opentelemetry.io/otelc/tool/util"
 )
 
@@ -42,6 +43,12 @@ func main() {
 				Usage:   "Enable debug mode",
 				Value:   false,
 			},
+			&cli.BoolFlag{
+				Name:    "read-only",
+				Sources: cli.EnvVars(util.EnvOtelcReadOnly),
+				Usage:   "Build without writing to the source tree, using -modfile and -overlay",
+				Value:   false,
+			},
 			&cli.StringFlag{
 				Name:      "rules",
 				Aliases:   []string{"rules"},
@@ -49,6 +56,41 @@ func main() {
 				TakesFile: true,
 				Value:     "",
 			},
+			&cli.StringFlag{
+				Name:      "bundle",
+				Sources:   cli.EnvVars(util.EnvOtelcBundle),
+				Usage:     "Build with the instrumentation of a rule bundle, without network access",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:      "bundle-key",
+				Sources:   cli.EnvVars(util.EnvOtelcBundleKey),
+				Usage:     "The PEM Ed25519 public key the --bundle must be signed with",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:    "bundle-insecure",
+				Sources: cli.EnvVars(util.EnvOtelcBundleInsecure),
+				Usage:   "Build with a --bundle without --bundle-key, checking only its checksum",
+				Value:   false,
+			},
+			&cli.BoolFlag{
+				Name:    "strict",
+				Sources: cli.EnvVars(util.EnvOtelcStrict),
+				Usage:   "Fail the build when a pinned instrumentation matches nothing or a call site is skipped",
+				Value:   false,
+			},
+			&cli.StringFlag{
+				Name:      "report",
+				Sources:   cli.EnvVars(report.EnvReport),
+				Usage:     "Write a machine-readable report of the instrumented build to this file",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:    "report-format",
+				Sources: cli.EnvVars(report.EnvReportFormat),
+				Usage:   "The format of the --report: json or sarif (default: sarif for .sarif files, json otherwise)",
+			},
 			&cli.StringFlag{
 				Name:    "profile-path",
 				Sources: cli.EnvVars(profile.EnvProfilePath),
@@ -80,6 +122,8 @@ func main() {
 			&commandGo,
 			&commandCleanup,
 			&commandToolexec,
+			&commandInstrumentPackage,
+			&commandBundle,
 			&commandVersion,
 		},
 		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
@@ -101,7 +145,15 @@ func main() {
 			if err != nil {
 				return ctx, err
 			}
-			return initStats(ctx, cmd)
+			ctx, err = initStats(ctx, cmd)
+			if err != nil {
+				return ctx, err
+			}
+			ctx, err = initStrict(ctx, cmd)
+			if err != nil {
+				return ctx, err
+			}
+			return initReport(ctx, cmd)
 		},
 		After: func(ctx context.Context, cmd *cli.Command) error {
 			return ex.Join(stopProfiling(ctx, cmd), closeLogger(ctx))
@@ -133,6 +185,11 @@ func initLogger(ctx context.Context, cmd *cli.Command) (context.Context, error)
 		if workDir == "" {
 			return ctx, nil
 		}
+	} else if cmd.Bool("read-only") && !cmd.IsSet("work-dir") && os.Getenv(util.EnvOtelcWorkDir) == "" {
+		// A read-only build must not create .otelc-build in the source tree
+		// either. Keying the directory on the source directory keeps builds of
+		// different projects apart and lets `otelc --read-only cleanup` find it.
+		workDir = filepath.Join(os.TempDir(), "otelc-"+util.CRC32(workDir))
 	}
 
 	if setErr := os.Setenv(util.EnvOtelcWorkDir, workDir); setErr != nil {
@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v3"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/internal/report"
+)
+
+// initReport resolves the path and format of the build report if --report is
+// set. It sets OTELC_REPORT and OTELC_REPORT_FORMAT so child toolexec
+// processes, which run in other directories, record into the same report.
+func initReport(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	path := cmd.String("report")
+	if path == "" {
+		return ctx, nil
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return ctx, ex.Wrapf(err, "resolve report path")
+	}
+	format, err := report.FormatFor(path, cmd.String("report-format"))
+	if err != nil {
+		return ctx, err
+	}
+
+	if err = os.Setenv(report.EnvReport, path); err != nil {
+		return ctx, ex.Wrapf(err, "set %s", report.EnvReport)
+	}
+	if err = os.Setenv(report.EnvReportFormat, format); err != nil {
+		return ctx, ex.Wrapf(err, "set %s", report.EnvReportFormat)
+	}
+	return ctx, nil
+}
@@ -0,0 +1,31 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+// initStrict enables strict mode if --strict is set. It sets OTELC_STRICT to
+// "1" so child toolexec processes fail on skipped call sites too, and unsets
+// it otherwise, so that OTELC_STRICT=false does not read as enabled.
+func initStrict(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	if !cmd.Bool("strict") {
+		if err := os.Unsetenv(util.EnvOtelcStrict); err != nil {
+			return ctx, ex.Wrapf(err, "unset %s", util.EnvOtelcStrict)
+		}
+		return ctx, nil
+	}
+
+	if err := os.Setenv(util.EnvOtelcStrict, "1"); err != nil {
+		return ctx, ex.Wrapf(err, "set %s", util.EnvOtelcStrict)
+	}
+	return ctx, nil
+}
@@ -145,6 +145,17 @@ func Join(errs ...error) error {
 	return errors.Join(errs...)
 }
 
+// ExitError reports that a program otelc ran, e.g. by `otelc go run`, exited
+// with Code. Fatal exits with the same code without printing anything, as the
+// program already reported its failure.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
 func printError(err error) {
 	e := &stackfulError{}
 	if errors.As(err, &e) {
@@ -164,6 +175,10 @@ func Fatal(err error) {
 		_, _ = fmt.Fprintln(os.Stderr, "Fatal error: unknown")
 		os.Exit(1)
 	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.Code)
+	}
 
 	type multiUnwrap interface {
 		Unwrap() []error
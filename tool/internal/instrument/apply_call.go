@@ -5,6 +5,7 @@ package instrument
 
 import (
 	"context"
+	"os"
 
 	"github.com/dave/dst"
 	"github.com/dave/dst/dstutil"
@@ -19,11 +20,13 @@ import (
 func (ip *InstrumentPhase) applyCallRule(ctx context.Context, r *rule.InstCallRule, root *dst.File) error {
 	importAliases := collectImportAliases(root)
 
-	appendModified := ip.applyCallAppendArgs(r, root, importAliases)
+	appendModified, err := ip.applyCallAppendArgs(r, root, importAliases)
+	if err != nil {
+		return err
+	}
 
 	replaceModified := false
 	if r.Replace != "" {
-		var err error
 		replaceModified, err = ip.applyCallReplace(r, root, importAliases)
 		if err != nil {
 			return err
@@ -32,7 +35,7 @@ func (ip *InstrumentPhase) applyCallRule(ctx context.Context, r *rule.InstCallRu
 
 	util.Assert(appendModified || replaceModified, "call rule did not match any call")
 
-	if err := ip.addRuleImports(ctx, root, r.Imports, r.Name); err != nil {
+	if err = ip.addRuleImports(ctx, root, r.Imports, r.Name); err != nil {
 		return err
 	}
 	ip.Info("Apply call rule", "rule", r)
@@ -43,7 +46,7 @@ func (ip *InstrumentPhase) applyCallRule(ctx context.Context, r *rule.InstCallRu
 // applyCallReplace applies replacement wrapping to all matching calls in root using a
 // two-pass approach to avoid re-matching wrapped nodes.
 // Returns true if any replacement was made.
-func (*InstrumentPhase) applyCallReplace(
+func (ip *InstrumentPhase) applyCallReplace(
 	r *rule.InstCallRule,
 	root *dst.File,
 	importAliases map[string]string,
@@ -74,6 +77,7 @@ func (*InstrumentPhase) applyCallReplace(
 			return false
 		}
 		replacements[call] = util.AssertType[dst.Expr](dst.Clone(wrapped))
+		ip.reportApplied(r, call, r.FunctionCall)
 		return true
 	})
 
@@ -102,13 +106,17 @@ func (*InstrumentPhase) applyCallReplace(
 	return true, nil
 }
 
+// applyCallAppendArgs appends r.AppendArgs to all matching calls in root.
+// Call sites the arguments cannot be appended to are skipped with a warning,
+// or fail the build for required rules and in strict mode.
+// Returns true if any call matched.
 func (ip *InstrumentPhase) applyCallAppendArgs(
 	r *rule.InstCallRule,
 	root *dst.File,
 	importAliases map[string]string,
-) bool {
+) (bool, error) {
 	if len(r.AppendArgs) == 0 {
-		return false
+		return false, nil
 	}
 
 	var matchingCalls []*dst.CallExpr
@@ -124,11 +132,18 @@ func (ip *InstrumentPhase) applyCallAppendArgs(
 	})
 	for _, call := range matchingCalls {
 		if _, err := appendCallArgs(call, r); err != nil {
+			ip.reportSkipped(r, call, err)
+			if r.Required || os.Getenv(util.EnvOtelcStrict) != "" {
+				return false, ex.Wrapf(err, "skipped call to %s at %s:%d",
+					r.FunctionCall, ip.source, ip.line(call))
+			}
 			ip.Warn("Failed to append args to call", "error", err)
+			continue
 		}
+		ip.reportApplied(r, call, r.FunctionCall)
 	}
 
-	return len(matchingCalls) > 0
+	return len(matchingCalls) > 0, nil
 }
 
 // appendCallArgs appends the expressions from r.AppendArgs to the call's argument list.
@@ -6,13 +6,17 @@ package instrument
 import (
 	"context"
 	"go/token"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/dave/dst"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/otelc/tool/internal/report"
 	"go.opentelemetry.io/otelc/tool/internal/rule"
+	"go.opentelemetry.io/otelc/tool/util"
 )
 
 // makeCallFile builds a minimal *dst.File containing a single function whose
@@ -467,8 +471,9 @@ func TestApplyCallAppendArgs_NoMatchReturnsFalse(t *testing.T) {
 
 	ip := newTestPhase()
 	importAliases := collectImportAliases(file)
-	result := ip.applyCallAppendArgs(r, file, importAliases)
+	result, err := ip.applyCallAppendArgs(r, file, importAliases)
 
+	require.NoError(t, err)
 	assert.False(t, result, "applyCallAppendArgs must return false when no calls match")
 }
 
@@ -482,3 +487,78 @@ func TestApplyCallRule_WrapFailureReturnsError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to wrap")
 }
+
+// parseCallSites parses a main package with a plain and a spread call to
+// fmt.Println, the second of which append_args without variadic_type skips.
+func parseCallSites(t *testing.T) (*InstrumentPhase, *dst.File, string) {
+	t.Helper()
+	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
+	source := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(source, []byte(`package main
+
+import "fmt"
+
+func main() {
+	args := []any{"a"}
+	fmt.Println("plain")
+	fmt.Println(args...)
+}
+`), 0o644))
+
+	ip := newTestPhase()
+	ip.compileArgs = []string{"compile", "-p", "main"}
+	ip.report = report.NewRecorder(nil)
+	root, err := ip.parseFile(source)
+	require.NoError(t, err)
+	ip.source = source
+	return ip, root, source
+}
+
+func addMarkerRule() *rule.InstCallRule {
+	return &rule.InstCallRule{
+		InstBaseRule: rule.InstBaseRule{Name: "add_marker"},
+		FunctionCall: "fmt.Println",
+		ImportPath:   "fmt",
+		FuncName:     "Println",
+		AppendArgs:   []string{`"marker"`},
+	}
+}
+
+func TestApplyCallRule_ReportsCallSites(t *testing.T) {
+	ip, root, source := parseCallSites(t)
+	require.NoError(t, ip.applyCallRule(t.Context(), addMarkerRule(), root))
+
+	got, err := ip.report.Build(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []report.Modification{{
+		Rule: "add_marker", Kind: "call", Package: "main", File: source, Line: 7, Symbol: "fmt.Println",
+	}}, got.Modifications)
+	assert.Equal(t, []report.Skipped{{
+		Rule: "add_marker", Package: "main", File: source, Line: 8,
+		Reason: "append_args on ellipsis call requires variadic_type to be set",
+	}}, got.Skipped)
+}
+
+func TestApplyCallRule_SkippedCallSiteFailsWhenRequired(t *testing.T) {
+	t.Run("required", func(t *testing.T) {
+		ip, root, source := parseCallSites(t)
+		r := addMarkerRule()
+		r.Required = true
+
+		err := ip.applyCallRule(t.Context(), r, root)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "requires variadic_type")
+		assert.Contains(t, err.Error(), source+":8")
+		// The skipped call site is still reported
+		got, err := ip.report.Build(nil)
+		require.NoError(t, err)
+		assert.Len(t, got.Skipped, 1)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		ip, root, _ := parseCallSites(t)
+		t.Setenv(util.EnvOtelcStrict, "1")
+
+		require.Error(t, ip.applyCallRule(t.Context(), addMarkerRule(), root))
+	})
+}
@@ -52,6 +52,7 @@ func (ip *InstrumentPhase) applyDeclRule(ctx context.Context, r *rule.InstDeclRu
 			return err
 		}
 		ip.Info("Apply decl rule", "rule", r)
+		ip.reportApplied(r, node, r.Identifier)
 		return nil
 	}
 
@@ -66,6 +67,7 @@ func (ip *InstrumentPhase) applyDeclRule(ctx context.Context, r *rule.InstDeclRu
 	}
 
 	ip.Info("Apply decl rule", "rule", r)
+	ip.reportApplied(r, node, r.Identifier)
 	return nil
 }
 
@@ -47,6 +47,7 @@ func (ip *InstrumentPhase) applyDirectiveRule(ctx context.Context, r *rule.InstD
 		renameReturnValues(funcDecl)
 		funcDecl.Body.List = append(stmts, funcDecl.Body.List...)
 		ip.Info("Apply directive rule", "rule", r, "func", funcDecl.Name.Name)
+		ip.reportApplied(r, funcDecl, funcDecl.Name.Name)
 	}
 	return nil
 }
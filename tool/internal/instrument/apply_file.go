@@ -13,6 +13,7 @@ import (
 
 	"go.opentelemetry.io/otelc/tool/ex"
 	"go.opentelemetry.io/otelc/tool/internal/ast"
+	"go.opentelemetry.io/otelc/tool/internal/report"
 	"go.opentelemetry.io/otelc/tool/internal/rule"
 	"go.opentelemetry.io/otelc/tool/util"
 )
@@ -68,6 +69,12 @@ func (ip *InstrumentPhase) applyFileRule(ctx context.Context, rule *rule.InstFil
 		return ex.Wrapf(err, "writing instrumented file %s", newFile)
 	}
 	ip.Info("Apply file rule", "rule", rule)
+	ip.report.AddModification(report.Modification{
+		Rule:    rule.Name,
+		Kind:    report.Kind(rule),
+		Package: ip.packagePath(),
+		File:    file,
+	})
 
 	// Add the new file as part of the source files to be compiled
 	ip.addCompileArg(newFile)
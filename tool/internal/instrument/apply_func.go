@@ -381,5 +381,6 @@ func (ip *InstrumentPhase) applyFuncRule(ctx context.Context, rule *rule.InstFun
 	}
 	ip.appliedFuncIdentities[id] = struct{}{}
 	ip.Info("Apply func rule", "rule", rule)
+	ip.reportApplied(rule, funcDecl, rule.Func)
 	return nil
 }
@@ -162,5 +162,6 @@ func (ip *InstrumentPhase) applyRawRule(ctx context.Context, rule *rule.InstRawR
 		return err
 	}
 	ip.Info("Apply raw rule", "rule", rule)
+	ip.reportApplied(rule, funcDecl, rule.Func)
 	return nil
 }
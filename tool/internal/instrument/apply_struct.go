@@ -28,5 +28,6 @@ func (ip *InstrumentPhase) applyStructRule(ctx context.Context, rule *rule.InstS
 		ast.AddStructField(structDecl, field.Name, field.Type)
 	}
 	ip.Info("Apply struct rule", "rule", rule)
+	ip.reportApplied(rule, structDecl, rule.Struct)
 	return nil
 }
@@ -10,13 +10,14 @@ import (
 
 	"go.opentelemetry.io/otelc/tool/ex"
 	"go.opentelemetry.io/otelc/tool/internal/imports"
+	"go.opentelemetry.io/otelc/tool/util"
 )
 
 // updateImportConfigForFile ensures all imports in the given file's AST are present in the importcfg.
 // This is used when adding a new file (e.g., via file rules) that has its own imports which may
 // not be in the target package's importcfg.
 func (ip *InstrumentPhase) updateImportConfigForFile(ctx context.Context, root *dst.File, ruleName string) error {
-	paths := imports.CollectPaths(ctx, root)
+	paths := imports.CollectPaths(ctx, root, util.GetBuildFlags()...)
 
 	if len(paths) == 0 {
 		return nil
@@ -45,7 +46,7 @@ func (ip *InstrumentPhase) addRuleImports(
 		return nil
 	}
 
-	resolution := imports.FindNew(ctx, root, ruleImports)
+	resolution := imports.FindNew(ctx, root, ruleImports, util.GetBuildFlags()...)
 
 	// Validate: check for alias mismatches that would break injected code
 	for ruleAlias, importPath := range ruleImports {
@@ -87,7 +88,7 @@ func (ip *InstrumentPhase) addRuleImports(
 	}
 
 	// Add import declarations to the AST
-	if err := imports.AddToFile(ctx, root, resolution.NewImports); err != nil {
+	if err := imports.AddToFile(ctx, root, resolution.NewImports, util.GetBuildFlags()...); err != nil {
 		return ex.Wrapf(err, "adding imports for %s", ruleName)
 	}
 
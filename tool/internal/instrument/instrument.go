@@ -4,6 +4,7 @@
 package instrument
 
 import (
+	"cmp"
 	"context"
 	"path/filepath"
 
@@ -74,12 +75,17 @@ func (ip *InstrumentPhase) instrument(ctx context.Context, rset *rule.InstRuleSe
 			return ex.Wrapf(err, "applying file rule %s to package %s", rule.Name, rset.PackageName)
 		}
 	}
+	sources := make(map[string]string, len(rset.CgoFileMap))
+	for source, cgoBase := range rset.CgoFileMap {
+		sources[filepath.Join(ip.workDir, cgoBase)] = source
+	}
 	for file, rules := range groupRules(ip.workDir, rset) {
 		// Group rules by file, then parse the target file once
 		root, err := ip.parseFile(file)
 		if err != nil {
 			return ex.Wrapf(err, "parsing file %s", file)
 		}
+		ip.source = cmp.Or(sources[file], file)
 
 		// Apply the rules to the target file
 		for _, r := range rules {
@@ -346,7 +346,8 @@ func writeMatchedJSON(ruleSet *rule.InstRuleSet) {
 	}
 
 	matchedJSON, _ := json.Marshal([]*rule.InstRuleSet{ruleSet})
-	matchedFile := util.GetMatchedRuleFile()
+	goos, goarch := util.ToolPlatform()
+	matchedFile := util.GetMatchedRuleFile(goos, goarch, util.ToolTags())
 	os.MkdirAll(filepath.Dir(matchedFile), 0o755)
 	util.WriteFile(matchedFile, string(matchedJSON))
 }
@@ -6,6 +6,7 @@ package instrument
 import (
 	"encoding/json"
 	"os"
+	"strings"
 
 	"go.opentelemetry.io/otelc/tool/ex"
 	"go.opentelemetry.io/otelc/tool/internal/rule"
@@ -15,12 +16,15 @@ import (
 // load loads the matched rules from the build temp directory.
 // TODO: Shared memory across all sub-processes is possible
 func (ip *InstrumentPhase) load() ([]*rule.InstRuleSet, error) {
-	f := util.GetMatchedRuleFile()
+	goos, goarch := util.ToolPlatform()
+	tags := util.ToolTags()
+	f := util.GetMatchedRuleFile(goos, goarch, tags)
 	content, err := os.ReadFile(f)
 	if os.IsNotExist(err) {
-		return nil, ex.Newf("no instrumentation configuration found (%s does not exist); "+
-			"run `otelc setup` in the module directory before building with `-toolexec`, "+
-			"or build with `otelc go build` instead", f)
+		return nil, ex.Newf("no instrumentation configuration found for %s/%s tags=%s (%s does not exist); "+
+			"run `otelc setup` in the module directory, with the GOOS, GOARCH and -tags of the build, "+
+			"before building with `-toolexec`, or build with `otelc go build` instead",
+			goos, goarch, strings.Join(tags, ","), f)
 	}
 	if err != nil {
 		return nil, ex.Wrapf(err, "failed to read file %s", f)
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package instrument
+
+import (
+	"context"
+	"path/filepath"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/internal/imports"
+	"go.opentelemetry.io/otelc/tool/internal/pkgload"
+	"go.opentelemetry.io/otelc/tool/internal/rule"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+// InstrumentPackage applies the matched rule set to the sources of one package
+// outside of a go build, the way a build system action such as a Bazel rule
+// runs it. Packages resolve from the importcfg alone, so it must already list
+// every package the injected code imports. Instrumented and generated files are
+// written to outDir; the returned sources replace the given ones in the compile.
+func InstrumentPackage(
+	ctx context.Context,
+	rset *rule.InstRuleSet,
+	sources []string,
+	importCfgPath, outDir string,
+) ([]string, error) {
+	cfg, err := imports.ParseImportCfg(importCfgPath)
+	if err != nil {
+		return nil, ex.Wrapf(err, "parsing importcfg")
+	}
+	ctx = pkgload.WithArchives(ctx, cfg.PackageFile)
+
+	// The instrument phase edits a compile command in place; only the -p flag
+	// and the source files of one are needed here.
+	args := make([]string, 0, len(sources)+2)
+	args = append(args, "-p", rset.ModulePath)
+	for _, source := range sources {
+		abs, err1 := filepath.Abs(source)
+		if err1 != nil {
+			return nil, ex.Wrap(err1)
+		}
+		args = append(args, abs)
+	}
+	ip := &InstrumentPhase{
+		logger:           util.LoggerFromContext(ctx),
+		workDir:          outDir,
+		compileArgs:      args,
+		importConfig:     cfg,
+		importConfigPath: importCfgPath,
+	}
+	ip.Info("Instrument package", "rules", rset, "sources", sources)
+	if err = ip.instrument(ctx, rset); err != nil {
+		return nil, ex.Wrapf(err, "instrumenting package %s", rset.ModulePath)
+	}
+	return ip.compileArgs[2:], nil
+}
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package instrument
+
+import (
+	"github.com/dave/dst"
+
+	"go.opentelemetry.io/otelc/tool/internal/report"
+	"go.opentelemetry.io/otelc/tool/internal/rule"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+// packagePath returns the import path of the package being compiled.
+func (ip *InstrumentPhase) packagePath() string {
+	return util.FindFlagValue(ip.compileArgs, "-p")
+}
+
+// line returns the line of node in the original source of the target file, or
+// 0 when node is not part of it.
+func (ip *InstrumentPhase) line(node dst.Node) int {
+	return max(ip.parser.FindPosition(node).Line, 0)
+}
+
+// reportApplied records that r modified node, a declaration or call of the
+// target file.
+func (ip *InstrumentPhase) reportApplied(r rule.InstRule, node dst.Node, symbol string) {
+	if ip.report == nil {
+		return
+	}
+	ip.report.AddModification(report.Modification{
+		Rule:    r.GetName(),
+		Kind:    report.Kind(r),
+		Package: ip.packagePath(),
+		File:    ip.source,
+		Line:    ip.line(node),
+		Symbol:  symbol,
+	})
+}
+
+// reportSkipped records that r left the call site node of the target file
+// unchanged, and why.
+func (ip *InstrumentPhase) reportSkipped(r rule.InstRule, node dst.Node, reason error) {
+	if ip.report == nil {
+		return
+	}
+	ip.report.AddSkipped(report.Skipped{
+		Rule:    r.GetName(),
+		Package: ip.packagePath(),
+		File:    ip.source,
+		Line:    ip.line(node),
+		Reason:  reason.Error(),
+	})
+}
@@ -42,22 +42,38 @@ func TestToolVersionLine(t *testing.T) {
 func TestMarkedToolVersion(t *testing.T) {
 	const raw = "compile version go1.26.5\n"
 
-	t.Run("no rules hash when matched.json is absent", func(t *testing.T) {
+	t.Run("no rules hash when the matched rules are absent", func(t *testing.T) {
 		t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
 
 		got := markedToolVersion(raw)
 		assert.Equal(t, "compile version go1.26.5 otelc@"+util.Version, got)
 	})
 
-	t.Run("appends a 16-hex-digit rules hash when matched.json is present", func(t *testing.T) {
+	t.Run("appends a 16-hex-digit rules hash when the matched rules are present", func(t *testing.T) {
 		workDir := t.TempDir()
 		t.Setenv(util.EnvOtelcWorkDir, workDir)
 		require.NoError(t, os.MkdirAll(filepath.Join(workDir, util.BuildTempDir), 0o755))
-		require.NoError(t, os.WriteFile(util.GetMatchedRuleFile(), []byte(`[{"module_path":"main"}]`), 0o644))
+		goos, goarch := util.ToolPlatform()
+		require.NoError(t, os.WriteFile(util.GetMatchedRuleFile(goos, goarch, util.ToolTags()),
+			[]byte(`[{"module_path":"main"}]`), 0o644))
 
 		got := markedToolVersion(raw)
 		assert.Regexp(t, `^compile version go1\.26\.5 otelc@\S+/[0-9a-f]{16}$`, got)
 	})
+
+	t.Run("strict builds hash differently", func(t *testing.T) {
+		workDir := t.TempDir()
+		t.Setenv(util.EnvOtelcWorkDir, workDir)
+		require.NoError(t, os.MkdirAll(filepath.Join(workDir, util.BuildTempDir), 0o755))
+		goos, goarch := util.ToolPlatform()
+		require.NoError(t, os.WriteFile(util.GetMatchedRuleFile(goos, goarch, util.ToolTags()),
+			[]byte(`[{"module_path":"main"}]`), 0o644))
+
+		t.Setenv(util.EnvOtelcStrict, "")
+		lenient := markedToolVersion(raw)
+		t.Setenv(util.EnvOtelcStrict, "1")
+		assert.NotEqual(t, lenient, markedToolVersion(raw))
+	})
 }
 
 func TestEnableNestedToolexec(t *testing.T) {
@@ -87,7 +103,7 @@ func TestEnableNestedToolexec(t *testing.T) {
 }
 
 func TestLoadMissingMatchedRules(t *testing.T) {
-	// Point the work dir at an empty directory: matched.json does not exist,
+	// Point the work dir at an empty directory: there are no matched rules,
 	// which is what a bare -toolexec build sees when setup never ran.
 	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
 
@@ -97,6 +113,57 @@ func TestLoadMissingMatchedRules(t *testing.T) {
 	assert.Contains(t, err.Error(), "otelc setup")
 }
 
+func TestLoadMatchedRulesOfTargetPlatform(t *testing.T) {
+	workDir := t.TempDir()
+	t.Setenv(util.EnvOtelcWorkDir, workDir)
+	t.Setenv(util.EnvOtelcBuildFlags, "")
+	t.Setenv("GOFLAGS", "")
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, util.BuildTempDir), 0o755))
+	require.NoError(t, os.WriteFile(util.GetMatchedRuleFile("linux", "amd64", nil),
+		[]byte(`[{"module_path":"example.com/linux"}]`), 0o644))
+	ip := &InstrumentPhase{logger: slog.Default()}
+
+	// The go command sets the target platform for every tool it runs
+	t.Setenv("GOOS", "linux")
+	t.Setenv("GOARCH", "amd64")
+	sets, err := ip.load()
+	require.NoError(t, err)
+	require.Len(t, sets, 1)
+	assert.Equal(t, "example.com/linux", sets[0].ModulePath)
+
+	// A cross-compile does not use the rules matched for another platform
+	t.Setenv("GOOS", "windows")
+	_, err = ip.load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "windows/amd64")
+}
+
+func TestLoadMatchedRulesOfBuildTags(t *testing.T) {
+	workDir := t.TempDir()
+	t.Setenv(util.EnvOtelcWorkDir, workDir)
+	t.Setenv(util.EnvOtelcBuildFlags, "")
+	t.Setenv("GOOS", "linux")
+	t.Setenv("GOARCH", "amd64")
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, util.BuildTempDir), 0o755))
+	require.NoError(t, os.WriteFile(util.GetMatchedRuleFile("linux", "amd64", []string{"osusergo", "netgo"}),
+		[]byte(`[{"module_path":"example.com/netgo"}]`), 0o644))
+	ip := &InstrumentPhase{logger: slog.Default()}
+
+	// A drop-in build sets its tags in GOFLAGS
+	t.Setenv("GOFLAGS", "-tags=netgo,osusergo")
+	sets, err := ip.load()
+	require.NoError(t, err)
+	require.Len(t, sets, 1)
+	assert.Equal(t, "example.com/netgo", sets[0].ModulePath)
+
+	// The -tags otelc go build forwards take precedence, and a build with
+	// other tags does not use the rules matched for these
+	t.Setenv(util.EnvOtelcBuildFlags, util.EncodeBuildFlags([]string{"-tags", "purego"}))
+	_, err = ip.load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tags=purego")
+}
+
 // versionMarkerPattern documents the exact tool-ID shape the go build cache
 // keys on, guarding against accidental format drift.
 var versionMarkerPattern = regexp.MustCompile(`otelc@[^\s/]+(/[0-9a-f]{16})?`)
@@ -22,6 +22,7 @@ import (
 	"go.opentelemetry.io/otelc/tool/internal/ast"
 	"go.opentelemetry.io/otelc/tool/internal/imports"
 	"go.opentelemetry.io/otelc/tool/internal/pkgload"
+	"go.opentelemetry.io/otelc/tool/internal/report"
 	"go.opentelemetry.io/otelc/tool/util"
 )
 
@@ -35,6 +36,9 @@ type InstrumentPhase struct {
 	importConfigPath string
 	// The target file to be instrumented
 	target *dst.File
+	// The original source of the target file, which differs from the parsed
+	// file for cgo packages
+	source string
 	// The parser for the target file
 	parser *ast.AstParser
 	// The compiling arguments for the target file
@@ -60,6 +64,8 @@ type InstrumentPhase struct {
 	// whole package because HookContext declarations accumulate into one globals
 	// file across all instrumented source files.
 	appliedFuncIdentities map[string]struct{}
+	// The build report recorder; nil when the build writes no report
+	report *report.Recorder
 }
 
 func (ip *InstrumentPhase) Info(msg string, args ...any)  { ip.logger.Info(msg, args...) }
@@ -91,7 +97,7 @@ func stripCompleteFlag(args []string) []string {
 	return args
 }
 
-func interceptCompile(ctx context.Context, args []string) ([]string, error) {
+func interceptCompile(ctx context.Context, args []string) (_ []string, err error) {
 	// Read compilation output directory
 	target := util.FindFlagValue(args, "-o")
 	util.Assert(target != "", "missing -o flag value")
@@ -105,6 +111,12 @@ func interceptCompile(ctx context.Context, args []string) ([]string, error) {
 		compileArgs:      args,
 		importConfigPath: importCfgPath,
 	}
+	if report.Enabled() {
+		ip.report = report.NewRecorder(nil)
+		defer func() {
+			err = ex.Join(err, ip.report.WriteFragment())
+		}()
+	}
 
 	// Parse existing importcfg if present
 	if importCfgPath != "" {
@@ -193,6 +205,7 @@ func (ip *InstrumentPhase) updateImportConfig(ctx context.Context, newImports ma
 	// Track added imports for the link phase
 	if err := trackAddedImports(ip.importConfig.PackageFile); err != nil {
 		ip.Warn("failed to track added imports for link phase", "error", err)
+		ip.report.AddWarning(ip.packagePath(), "failed to track added imports for link phase: "+err.Error())
 		// Non-fatal: link phase may still work if imports were already present
 	}
 
@@ -374,11 +387,17 @@ func toolVersionLine(line, rulesHash string) string {
 }
 
 // markedToolVersion turns a tool's raw `-V=full` output into the line otelc
-// reports in its place: the version with an otelc marker, plus the current
-// matched-rules hash when one exists.
+// reports in its place: the version with an otelc marker, plus the hash of the
+// rules matched for the target platform and tags when they exist.
 func markedToolVersion(rawOutput string) string {
 	var rulesHash string
-	if content, err := os.ReadFile(util.GetMatchedRuleFile()); err == nil {
+	goos, goarch := util.ToolPlatform()
+	if content, err := os.ReadFile(util.GetMatchedRuleFile(goos, goarch, util.ToolTags())); err == nil {
+		// Strict builds fail on call sites other builds skip, so they must
+		// not reuse the compiled packages of those builds
+		if os.Getenv(util.EnvOtelcStrict) != "" {
+			content = append(content, util.EnvOtelcStrict...)
+		}
 		sum := sha256.Sum256(content)
 		rulesHash = hex.EncodeToString(sum[:8])
 	}
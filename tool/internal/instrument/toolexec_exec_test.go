@@ -68,7 +68,7 @@ func TestToolexecPassesThroughNonToolCommands(t *testing.T) {
 // TestToolexecNestedGatesInstrumentation checks that the nested flag, not the
 // command shape, decides whether otelc instruments: the same compile-shaped
 // command is instrumented when nested is false (and here fails because no
-// matched.json exists) but passed straight through when nested is true.
+// matched rules exist) but passed straight through when nested is true.
 func TestToolexecNestedGatesInstrumentation(t *testing.T) {
 	ctx := util.ContextWithLogger(t.Context(), slog.Default())
 	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
@@ -80,7 +80,7 @@ func TestToolexecNestedGatesInstrumentation(t *testing.T) {
 
 	t.Run("non-nested attempts instrumentation", func(t *testing.T) {
 		err := Toolexec(ctx, compileArgs, false)
-		require.Error(t, err, "instrumentation should try to load the absent matched.json")
+		require.Error(t, err, "instrumentation should try to load the absent matched rules")
 		assert.Contains(t, err.Error(), "otelc setup")
 		assert.NoFileExists(t, marker, "the tool is not run when instrumentation fails first")
 	})
@@ -50,6 +50,7 @@ const (
 	trampolineHookContextImplType   = "HookContextImpl"
 	trampolineBeforeNamePlaceholder = `"OtelBeforeNamePlaceholder"`
 	trampolineAfterNamePlaceholder  = `"OtelAfterNamePlaceholder"`
+	trampolineRulePlaceholder       = `"OtelRulePlaceholder"`
 	trampolineBefore                = true
 	trampolineAfter                 = false
 	unsafePackageName               = "unsafe"
@@ -365,7 +366,7 @@ func (ip *InstrumentPhase) addHookDecl(t *rule.InstFuncRule, paramTypes *dst.Fie
 		},
 		Decs: dst.FuncDeclDecorations{
 			NodeDecs: ast.LineComments(
-				fmt.Sprintf("//go:linkname %s %s.%s", fnName, t.Path, fnName)),
+				fmt.Sprintf("//go:linkname %s %s.%s", fnName, util.LinknamePath(t.Path), fnName)),
 		},
 	}
 
@@ -394,9 +395,14 @@ func (ip *InstrumentPhase) renameTrampFunc(t *rule.InstFuncRule) {
 	ip.beforeTrampFunc.Name.Name = makeName(t, ip.targetFunc, trampolineBefore)
 	dst.Inspect(ip.beforeTrampFunc, func(node dst.Node) bool {
 		if basicLit, ok := node.(*dst.BasicLit); ok {
-			// Replace OtelBeforeTrampolinePlaceHolder to real hook func name
-			if basicLit.Value == trampolineBeforeNamePlaceholder {
-				basicLit.Value = strconv.Quote(t.Before)
+			// Replace OtelBeforeTrampolinePlaceHolder to qualified hook func name
+			switch basicLit.Value {
+			case trampolineBeforeNamePlaceholder:
+				basicLit.Value = strconv.Quote(t.Path + "." + t.Before)
+			case trampolineRulePlaceholder:
+				// The runtime tracks hooks by rule for its circuit breaker
+				// and self-observability metrics
+				basicLit.Value = strconv.Quote(t.Identity())
 			}
 		}
 		return true
@@ -404,8 +410,11 @@ func (ip *InstrumentPhase) renameTrampFunc(t *rule.InstFuncRule) {
 	ip.afterTrampFunc.Name.Name = makeName(t, ip.targetFunc, trampolineAfter)
 	dst.Inspect(ip.afterTrampFunc, func(node dst.Node) bool {
 		if basicLit, ok := node.(*dst.BasicLit); ok {
-			if basicLit.Value == trampolineAfterNamePlaceholder {
-				basicLit.Value = strconv.Quote(t.After)
+			switch basicLit.Value {
+			case trampolineAfterNamePlaceholder:
+				basicLit.Value = strconv.Quote(t.Path + "." + t.After)
+			case trampolineRulePlaceholder:
+				basicLit.Value = strconv.Quote(t.Identity())
 			}
 		}
 		return true
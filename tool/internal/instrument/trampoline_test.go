@@ -4,6 +4,10 @@
 package instrument
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/dave/dst"
@@ -188,3 +192,73 @@ func H1Before(ctx hook.HookContext, p1 string, p2 string) {}`,
 		})
 	}
 }
+
+// trampolineBreakerTest drives the trampoline templates the way an
+// instrumented function does, with a runtime whose breaker trips between the
+// Before and the After hook.
+const trampolineBreakerTest = `package instrument
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBreakerTripsBetweenBeforeAndAfter(t *testing.T) {
+	enabled := true
+	var ran []string
+	OtelHookStartImpl = func(string) (int64, bool) { return 0, enabled }
+	OtelHookEndImpl = func(_, hook string, _ int64) { ran = append(ran, hook) }
+
+	hookContext, _ := OtelBeforeTrampoline()
+	enabled = false
+	OtelAfterTrampoline(hookContext)
+
+	// Once tripped, Before is skipped and so is After.
+	hookContext, _ = OtelBeforeTrampoline()
+	OtelAfterTrampoline(hookContext)
+
+	if got := strings.Join(ran, " "); got != "OtelBeforeNamePlaceholder OtelAfterNamePlaceholder" {
+		t.Fatalf("hooks ran: %s", got)
+	}
+}
+
+func TestBreakerStopsAfterOnlyRule(t *testing.T) {
+	enabled := true
+	var ran []string
+	OtelHookStartImpl = func(string) (int64, bool) { return 0, enabled }
+	OtelHookEndImpl = func(_, hook string, _ int64) { ran = append(ran, hook) }
+
+	// Without a Before hook, the instrumented function builds the context
+	OtelAfterTrampoline(&HookContextImpl{})
+	enabled = false
+	OtelAfterTrampoline(&HookContextImpl{})
+
+	if got := strings.Join(ran, " "); got != "OtelAfterNamePlaceholder" {
+		t.Fatalf("hooks ran: %s", got)
+	}
+}
+`
+
+// TestTrampolineAfterRunsOnceBeforeRan checks that After still runs when the
+// breaker of its rule trips after Before ran, so spans and goroutine-local
+// state started by Before are released, that it is skipped together with
+// Before, and that the breaker stops a rule that has only an After hook.
+func TestTrampolineAfterRunsOnceBeforeRan(t *testing.T) {
+	dir := t.TempDir()
+	api := strings.Replace(templateAPI, "package hook", "package instrument", 1)
+	files := map[string]string{
+		"go.mod":          "module example.com/tramp\n\ngo 1.23\n",
+		"api.go":          api,
+		"impl.go":         templateImpl,
+		"breaker_test.go": trampolineBreakerTest,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	cmd := exec.CommandContext(t.Context(), "go", "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pkgload
+
+import (
+	"bufio"
+	"context"
+	"go/token"
+	"go/types"
+	"os"
+
+	"golang.org/x/tools/go/gcexportdata"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+)
+
+type archivesKey struct{}
+
+// WithArchives returns a context in which packages resolve only from archives,
+// the packagefile entries of an importcfg, without running the go command.
+// Build systems such as Bazel declare every dependency of a compile action up
+// front and cannot run `go list` inside it.
+func WithArchives(ctx context.Context, archives map[string]string) context.Context {
+	return context.WithValue(ctx, archivesKey{}, archives)
+}
+
+func archivesFromContext(ctx context.Context) (map[string]string, bool) {
+	archives, ok := ctx.Value(archivesKey{}).(map[string]string)
+	return archives, ok
+}
+
+// archiveFile returns the archive of importPath, failing with a hint to declare
+// the package as a dependency when the importcfg does not list it.
+func archiveFile(archives map[string]string, importPath string) (string, error) {
+	archive, ok := archives[importPath]
+	if !ok || archive == "" {
+		return "", ex.Newf("package %q is not in the importcfg; "+
+			"add it to the dependencies of the instrumented target", importPath)
+	}
+	return archive, nil
+}
+
+// archivePackageName reads the declared name of importPath from the export
+// data of its archive.
+func archivePackageName(archives map[string]string, importPath string) (string, error) {
+	// Neither has an archive; their names are fixed.
+	if importPath == "unsafe" || importPath == "C" {
+		return importPath, nil
+	}
+	archive, err := archiveFile(archives, importPath)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(archive)
+	if err != nil {
+		return "", ex.Wrapf(err, "opening archive of %s", importPath)
+	}
+	defer f.Close()
+
+	r, err := gcexportdata.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return "", ex.Wrapf(err, "reading export data of %s from %s", importPath, archive)
+	}
+	pkg, err := gcexportdata.Read(r, token.NewFileSet(), make(map[string]*types.Package), importPath)
+	if err != nil {
+		return "", ex.Wrapf(err, "decoding export data of %s from %s", importPath, archive)
+	}
+	return pkg.Name(), nil
+}
@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pkgload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithArchives(t *testing.T) {
+	// Real archives stand in for the importcfg of a build system action.
+	resolved, err := ResolveExportFiles(t.Context(), "net/http")
+	require.NoError(t, err)
+	archives := map[string]string{
+		"net/http": resolved["net/http"],
+		"net/url":  resolved["net/url"],
+	}
+	ctx := WithArchives(t.Context(), archives)
+
+	t.Run("package name from export data", func(t *testing.T) {
+		assert.Equal(t, "http", ResolvePackageName(ctx, "net/http"))
+		assert.Equal(t, "url", ResolvePackageName(ctx, "net/url"))
+		assert.Equal(t, "unsafe", ResolvePackageName(ctx, "unsafe"))
+	})
+
+	t.Run("export file of a listed package only", func(t *testing.T) {
+		files, err1 := ResolveExportFiles(ctx, "net/http")
+		require.NoError(t, err1)
+		assert.Equal(t, map[string]string{"net/http": archives["net/http"]}, files)
+	})
+
+	t.Run("unlisted package is an error", func(t *testing.T) {
+		_, err1 := ResolveExportFiles(ctx, "fmt")
+		require.Error(t, err1)
+		assert.Contains(t, err1.Error(), `package "fmt" is not in the importcfg`)
+	})
+}
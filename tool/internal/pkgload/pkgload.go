@@ -105,6 +105,14 @@ func loadDirFromBuildFlags(buildFlags []string) (string, []string, error) {
 // ResolvePackageName returns the declared package name for an import path.
 // Panics via ex.Fatalf on failure (matches existing behavior during toolexec).
 func ResolvePackageName(ctx context.Context, importPath string, buildFlags ...string) string {
+	if archives, ok := archivesFromContext(ctx); ok {
+		name, err := archivePackageName(archives, importPath)
+		if err != nil {
+			ex.Fatalf("failed to resolve package name for %s: %v", importPath, err)
+		}
+		return name
+	}
+
 	pkgs, err := LoadPackages(ctx, packages.NeedName, buildFlags, importPath)
 	if err != nil {
 		ex.Fatalf("failed to resolve package name for %s: %v", importPath, err)
@@ -127,8 +135,17 @@ func ResolvePackageName(ctx context.Context, importPath string, buildFlags ...st
 }
 
 // ResolveExportFiles returns importPath -> exportFile for a package and all
-// transitive dependencies.
+// transitive dependencies. Under WithArchives only the package itself is
+// returned: its dependencies are already known to the build system.
 func ResolveExportFiles(ctx context.Context, importPath string, buildFlags ...string) (map[string]string, error) {
+	if archives, ok := archivesFromContext(ctx); ok {
+		archive, err := archiveFile(archives, importPath)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{importPath: archive}, nil
+	}
+
 	mode := packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedExportFile
 	pkgs, err := LoadPackages(ctx, mode, buildFlags, importPath)
 	if err != nil {
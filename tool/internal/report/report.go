@@ -0,0 +1,525 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package report builds the machine-readable report of an instrumented build:
+// the rules that were loaded, the packages they matched, the modifications
+// applied to each source file, the call sites that were skipped, warnings and
+// timing.
+//
+// Setup records into a Recorder carried by the context of `otelc go`. Every
+// toolexec process that instruments a package records into a Recorder of its
+// own and writes it as a fragment to the build temp directory, which the
+// parent merges into its report when the build ends.
+package report
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/internal/rule"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+const (
+	// EnvReport is the absolute path the report is written to.
+	// Set automatically when --report is used; propagated to child processes.
+	EnvReport = "OTELC_REPORT"
+	// EnvReportFormat is the format of the report, see Formats.
+	// Set automatically when --report is used.
+	EnvReportFormat = "OTELC_REPORT_FORMAT"
+
+	// Version is the version of the report layout. Fields may be added within
+	// a version; it is bumped whenever a field is removed or changes meaning.
+	Version = 1
+
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+
+	// fragmentDir holds the fragments of toolexec processes in the build temp
+	// directory.
+	fragmentDir = "report"
+)
+
+// Formats lists the supported report formats.
+//
+//nolint:gochecknoglobals // read-only list of formats
+var Formats = []string{FormatJSON, FormatSARIF}
+
+// Report is the build report.
+type Report struct {
+	Version       int            `json:"version"`
+	OtelcVersion  string         `json:"otelc_version"`
+	Command       []string       `json:"command"`
+	Status        string         `json:"status"`
+	Error         string         `json:"error,omitempty"`
+	Target        Target         `json:"target"`
+	Rules         []Rule         `json:"rules"`
+	Packages      []Package      `json:"packages"`
+	Modifications []Modification `json:"modifications"`
+	Skipped       []Skipped      `json:"skipped"`
+	Warnings      []Warning      `json:"warnings"`
+	Timing        Timing         `json:"timing"`
+}
+
+// Target is the platform and build tags the build compiles for.
+type Target struct {
+	GOOS   string   `json:"goos"`
+	GOARCH string   `json:"goarch"`
+	Tags   []string `json:"tags"`
+}
+
+// Rule is a rule that was loaded, and where it was loaded from.
+type Rule struct {
+	Name            string `json:"name"`
+	Kind            string `json:"kind"`
+	Target          string `json:"target"`
+	Version         string `json:"version,omitempty"`
+	Source          string `json:"source,omitempty"`
+	Instrumentation string `json:"instrumentation,omitempty"`
+}
+
+// Package is a package that rules matched.
+type Package struct {
+	ImportPath string  `json:"import_path"`
+	Version    string  `json:"version,omitempty"`
+	Matches    []Match `json:"matches"`
+}
+
+// Match is a rule that matched a source file of a package. File rules match
+// the package rather than one of its files.
+type Match struct {
+	Rule string `json:"rule"`
+	Kind string `json:"kind"`
+	File string `json:"file,omitempty"`
+}
+
+// Modification is a change a rule applied to a source file of a package. Line
+// is the line of the modified declaration or call in the original file. For
+// file rules, File is the file the rule added and Line is 0.
+type Modification struct {
+	Rule    string `json:"rule"`
+	Kind    string `json:"kind"`
+	Package string `json:"package"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Symbol  string `json:"symbol,omitempty"`
+}
+
+// Skipped is a call site a matching rule left unchanged, and why.
+type Skipped struct {
+	Rule    string `json:"rule"`
+	Package string `json:"package"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// Warning is a problem that did not fail the build. Package is empty for
+// warnings about the build as a whole.
+type Warning struct {
+	Package string `json:"package,omitempty"`
+	Message string `json:"message"`
+}
+
+// Timing is the wall-clock duration of the phases of the build, in
+// milliseconds. Setup includes matching.
+type Timing struct {
+	SetupMS int64 `json:"setup_ms"`
+	MatchMS int64 `json:"match_ms"`
+	BuildMS int64 `json:"build_ms"`
+	TotalMS int64 `json:"total_ms"`
+}
+
+// Phase names a timed phase of the build.
+type Phase int
+
+const (
+	PhaseSetup Phase = iota
+	PhaseMatch
+	PhaseBuild
+)
+
+// Kind returns the kind of r as rules files spell it.
+func Kind(r rule.InstRule) string {
+	switch r.(type) {
+	case *rule.InstFuncRule:
+		return "func"
+	case *rule.InstStructRule:
+		return "struct"
+	case *rule.InstRawRule:
+		return "raw"
+	case *rule.InstCallRule:
+		return "call"
+	case *rule.InstDirectiveRule:
+		return "directive"
+	case *rule.InstDeclRule:
+		return "decl"
+	case *rule.InstFileRule:
+		return "file"
+	default:
+		util.ShouldNotReachHere()
+		return ""
+	}
+}
+
+// FormatFor returns the format of a report written to path: format when it is
+// set, otherwise sarif for a .sarif file and json for any other.
+func FormatFor(path, format string) (string, error) {
+	if format == "" {
+		if strings.EqualFold(filepath.Ext(path), ".sarif") {
+			return FormatSARIF, nil
+		}
+		return FormatJSON, nil
+	}
+	if !slices.Contains(Formats, format) {
+		return "", ex.Newf("unsupported report format %q, want one of %s", format, strings.Join(Formats, ", "))
+	}
+	return format, nil
+}
+
+// Enabled reports whether the build writes a report.
+func Enabled() bool {
+	return os.Getenv(EnvReport) != ""
+}
+
+// Recorder collects what one otelc process contributes to the report. It is
+// safe for concurrent use, and a nil Recorder records nothing, so callers need
+// not check whether a report was requested.
+type Recorder struct {
+	mu       sync.Mutex
+	start    time.Time
+	report   Report
+	packages map[string]*Package
+}
+
+// NewRecorder returns a Recorder for a build running command.
+func NewRecorder(command []string) *Recorder {
+	return &Recorder{
+		start:    time.Now(),
+		report:   Report{Command: command},
+		packages: make(map[string]*Package),
+	}
+}
+
+type recorderKey struct{}
+
+// ContextWithRecorder returns a copy of ctx containing rc.
+func ContextWithRecorder(ctx context.Context, rc *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, rc)
+}
+
+// FromContext returns the Recorder stored in ctx, or nil when the build writes
+// no report.
+func FromContext(ctx context.Context) *Recorder {
+	rc, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return rc
+}
+
+// SetTarget records the target of the build.
+func (rc *Recorder) SetTarget(goos, goarch string, tags []string) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.report.Target = Target{GOOS: goos, GOARCH: goarch, Tags: tags}
+}
+
+// AddRules records the rules that were loaded.
+func (rc *Recorder) AddRules(rules []rule.InstRule) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, r := range rules {
+		origin := r.GetOrigin()
+		rc.report.Rules = append(rc.report.Rules, Rule{
+			Name:            r.GetName(),
+			Kind:            Kind(r),
+			Target:          r.GetTarget(),
+			Version:         r.GetVersion(),
+			Source:          origin.File,
+			Instrumentation: origin.Instrumentation,
+		})
+	}
+}
+
+// AddMatches records the rules of set, which matched the package at version.
+func (rc *Recorder) AddMatches(set *rule.InstRuleSet, version string) {
+	if rc == nil {
+		return
+	}
+	matches := make([]Match, 0)
+	for _, r := range set.FileRules {
+		matches = append(matches, Match{Rule: r.GetName(), Kind: Kind(r)})
+	}
+	matches = appendMatches(matches, set.FuncRules)
+	matches = appendMatches(matches, set.StructRules)
+	matches = appendMatches(matches, set.RawRules)
+	matches = appendMatches(matches, set.CallRules)
+	matches = appendMatches(matches, set.DirectiveRules)
+	matches = appendMatches(matches, set.DeclRules)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	pkg := rc.packages[set.ModulePath]
+	if pkg == nil {
+		pkg = &Package{ImportPath: set.ModulePath, Version: version}
+		rc.packages[set.ModulePath] = pkg
+	}
+	pkg.Matches = append(pkg.Matches, matches...)
+}
+
+func appendMatches[T rule.InstRule](matches []Match, byFile map[string][]T) []Match {
+	for file, rules := range byFile {
+		for _, r := range rules {
+			matches = append(matches, Match{Rule: r.GetName(), Kind: Kind(r), File: file})
+		}
+	}
+	return matches
+}
+
+// AddModification records a change applied to a source file.
+func (rc *Recorder) AddModification(m Modification) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.report.Modifications = append(rc.report.Modifications, m)
+}
+
+// AddSkipped records a call site a matching rule left unchanged.
+func (rc *Recorder) AddSkipped(s Skipped) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.report.Skipped = append(rc.report.Skipped, s)
+}
+
+// AddWarning records a warning about pkg, or about the whole build when pkg
+// is empty.
+func (rc *Recorder) AddWarning(pkg, message string) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.report.Warnings = append(rc.report.Warnings, Warning{Package: pkg, Message: message})
+}
+
+// AddDuration records the duration of a phase of the build.
+func (rc *Recorder) AddDuration(phase Phase, d time.Duration) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	switch phase {
+	case PhaseSetup:
+		rc.report.Timing.SetupMS += d.Milliseconds()
+	case PhaseMatch:
+		rc.report.Timing.MatchMS += d.Milliseconds()
+	case PhaseBuild:
+		rc.report.Timing.BuildMS += d.Milliseconds()
+	default:
+		util.ShouldNotReachHere()
+	}
+}
+
+// snapshot returns a copy of what rc recorded so far.
+func (rc *Recorder) snapshot() Report {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	r := rc.report
+	r.Rules = slices.Clone(r.Rules)
+	r.Modifications = slices.Clone(r.Modifications)
+	r.Skipped = slices.Clone(r.Skipped)
+	r.Warnings = slices.Clone(r.Warnings)
+	r.Packages = make([]Package, 0, len(rc.packages))
+	for _, pkg := range rc.packages {
+		p := *pkg
+		p.Matches = slices.Clone(p.Matches)
+		r.Packages = append(r.Packages, p)
+	}
+	return r
+}
+
+// CleanFragments removes the fragments left by the toolexec processes of
+// earlier builds.
+func CleanFragments() error {
+	if err := os.RemoveAll(util.GetBuildTemp(fragmentDir)); err != nil {
+		return ex.Wrapf(err, "removing report fragments")
+	}
+	return nil
+}
+
+// WriteFragment writes what rc recorded to a fragment of its own, for the
+// parent otelc process to merge. Recorders that recorded nothing write none.
+func (rc *Recorder) WriteFragment() error {
+	if rc == nil {
+		return nil
+	}
+	fragment := rc.snapshot()
+	if len(fragment.Modifications) == 0 && len(fragment.Skipped) == 0 && len(fragment.Warnings) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(fragment)
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	dir := util.GetBuildTemp(fragmentDir)
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return ex.Wrap(err)
+	}
+	// Process IDs are reused during long builds, names from CreateTemp are not
+	f, err := os.CreateTemp(dir, "*.json")
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	_, err = f.Write(data)
+	return ex.Join(err, f.Close())
+}
+
+// mergeFragments adds the events of the fragments written by toolexec
+// processes to r.
+func mergeFragments(r *Report) error {
+	files, err := filepath.Glob(filepath.Join(util.GetBuildTemp(fragmentDir), "*.json"))
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	for _, file := range files {
+		data, readErr := os.ReadFile(file)
+		if readErr != nil {
+			return ex.Wrap(readErr)
+		}
+		var fragment Report
+		if err = json.Unmarshal(data, &fragment); err != nil {
+			return ex.Wrapf(err, "parsing report fragment %s", file)
+		}
+		r.Modifications = append(r.Modifications, fragment.Modifications...)
+		r.Skipped = append(r.Skipped, fragment.Skipped...)
+		r.Warnings = append(r.Warnings, fragment.Warnings...)
+	}
+	return nil
+}
+
+// Build assembles the report of a build that ended with buildErr: what rc
+// recorded, merged with the fragments of the toolexec processes, in a stable
+// order.
+func (rc *Recorder) Build(buildErr error) (*Report, error) {
+	r := rc.snapshot()
+	if err := mergeFragments(&r); err != nil {
+		return nil, err
+	}
+	r.Version = Version
+	r.OtelcVersion = util.Version
+	r.Status = StatusSucceeded
+	if buildErr != nil {
+		r.Status = StatusFailed
+		r.Error = buildErr.Error()
+	}
+	r.Timing.TotalMS = time.Since(rc.start).Milliseconds()
+	r.normalize()
+	return &r, nil
+}
+
+// normalize sorts the report and removes duplicates, so that reports of the
+// same build compare equal. Lists are never null.
+func (r *Report) normalize() {
+	if r.Command == nil {
+		r.Command = []string{}
+	}
+	if r.Target.Tags == nil {
+		r.Target.Tags = []string{}
+	}
+	// A rule file entry can expand into several rules of the same name
+	r.Rules = sortCompact(r.Rules, func(a, b Rule) int {
+		return cmp.Or(
+			cmp.Compare(a.Instrumentation, b.Instrumentation),
+			cmp.Compare(a.Source, b.Source),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Target, b.Target),
+			cmp.Compare(a.Version, b.Version),
+		)
+	})
+	for i := range r.Packages {
+		r.Packages[i].Matches = sortCompact(r.Packages[i].Matches, func(a, b Match) int {
+			return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Rule, b.Rule), cmp.Compare(a.Kind, b.Kind))
+		})
+	}
+	r.Packages = sortCompact(r.Packages, func(a, b Package) int {
+		return cmp.Compare(a.ImportPath, b.ImportPath)
+	})
+	r.Modifications = sortCompact(r.Modifications, func(a, b Modification) int {
+		return cmp.Or(
+			cmp.Compare(a.Package, b.Package),
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Rule, b.Rule),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Symbol, b.Symbol),
+		)
+	})
+	r.Skipped = sortCompact(r.Skipped, func(a, b Skipped) int {
+		return cmp.Or(
+			cmp.Compare(a.Package, b.Package),
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Rule, b.Rule),
+			cmp.Compare(a.Reason, b.Reason),
+		)
+	})
+	r.Warnings = sortCompact(r.Warnings, func(a, b Warning) int {
+		return cmp.Or(cmp.Compare(a.Package, b.Package), cmp.Compare(a.Message, b.Message))
+	})
+}
+
+// sortCompact sorts s by compare and drops the elements that compare equal to
+// their predecessor. It never returns nil.
+func sortCompact[T any](s []T, compare func(a, b T) int) []T {
+	if s == nil {
+		return []T{}
+	}
+	slices.SortFunc(s, compare)
+	return slices.CompactFunc(s, func(a, b T) bool { return compare(a, b) == 0 })
+}
+
+// Write assembles the report of a build that ended with buildErr, see Build,
+// and writes it to path in the given format.
+func (rc *Recorder) Write(path, format string, buildErr error) error {
+	r, err := rc.Build(buildErr)
+	if err != nil {
+		return err
+	}
+	var doc any = r
+	if format == FormatSARIF {
+		doc = r.sarif()
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return ex.Wrap(err)
+	}
+	if err = util.WriteFileAtomic(path, append(data, '\n')); err != nil {
+		return ex.Wrapf(err, "writing report %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otelc/tool/internal/rule"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+func TestFormatFor(t *testing.T) {
+	tests := []struct {
+		path, format string
+		want         string
+		wantErr      bool
+	}{
+		{path: "out.json", want: FormatJSON},
+		{path: "out.sarif", want: FormatSARIF},
+		{path: "OUT.SARIF", want: FormatSARIF},
+		{path: "report", want: FormatJSON},
+		{path: "out.json", format: FormatSARIF, want: FormatSARIF},
+		{path: "out.sarif", format: FormatJSON, want: FormatJSON},
+		{path: "out.xml", format: "xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := FormatFor(tt.path, tt.format)
+		if tt.wantErr {
+			require.Error(t, err, "%s %s", tt.path, tt.format)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "%s %s", tt.path, tt.format)
+	}
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var rc *Recorder
+	rc.SetTarget("linux", "amd64", nil)
+	rc.AddRules([]rule.InstRule{&rule.InstFuncRule{}})
+	rc.AddMatches(rule.NewInstRuleSet("net/http"), "")
+	rc.AddModification(Modification{})
+	rc.AddSkipped(Skipped{})
+	rc.AddWarning("", "warning")
+	rc.AddDuration(PhaseSetup, time.Second)
+	require.NoError(t, rc.WriteFragment())
+}
+
+func handlerRule(name string) *rule.InstFuncRule {
+	return &rule.InstFuncRule{
+		InstBaseRule: rule.InstBaseRule{
+			Name:   name,
+			Target: "example.com/svc",
+			Origin: rule.Origin{File: "/rules/svc.otelc.yml", Instrumentation: "example.com/instrumentation/svc"},
+		},
+		Func: "Handler",
+	}
+}
+
+func TestRecorder_Build(t *testing.T) {
+	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
+	require.NoError(t, CleanFragments())
+
+	rc := NewRecorder([]string{"go", "build", "."})
+	rc.SetTarget("linux", "arm64", []string{"netgo"})
+	// A do: sequence expands into several rules of the same name and origin
+	before, after := handlerRule("handler"), handlerRule("handler")
+	server := &rule.InstStructRule{
+		InstBaseRule: rule.InstBaseRule{Name: "server", Target: "example.com/svc"},
+		Struct:       "Server",
+	}
+	rc.AddRules([]rule.InstRule{server, before, after})
+	set := rule.NewInstRuleSet("example.com/svc")
+	set.AddFuncRule("/src/svc/handler.go", before)
+	set.AddStructRule("/src/svc/server.go", server)
+	rc.AddMatches(set, "v1.2.0")
+	rc.AddDuration(PhaseSetup, 2*time.Second)
+	rc.AddWarning("", "no instrumentation rules matched any dependencies")
+
+	// Two toolexec processes report their modifications and skipped call sites
+	toolexec := NewRecorder(nil)
+	toolexec.AddModification(Modification{
+		Rule: "server", Kind: "struct", Package: "example.com/svc", File: "/src/svc/server.go", Line: 9, Symbol: "Server",
+	})
+	toolexec.AddModification(Modification{
+		Rule: "handler", Kind: "func", Package: "example.com/svc", File: "/src/svc/handler.go", Line: 3, Symbol: "Handler",
+	})
+	require.NoError(t, toolexec.WriteFragment())
+	toolexec = NewRecorder(nil)
+	toolexec.AddSkipped(Skipped{
+		Rule: "dial", Package: "example.com/client", File: "/src/client/dial.go", Line: 12, Reason: "no variadic_type",
+	})
+	require.NoError(t, toolexec.WriteFragment())
+	// Recorders without events write no fragment
+	require.NoError(t, NewRecorder(nil).WriteFragment())
+	fragments, err := filepath.Glob(filepath.Join(util.GetBuildTemp(fragmentDir), "*.json"))
+	require.NoError(t, err)
+	assert.Len(t, fragments, 2)
+
+	got, err := rc.Build(nil)
+	require.NoError(t, err)
+	assert.Equal(t, Version, got.Version)
+	assert.Equal(t, util.Version, got.OtelcVersion)
+	assert.Equal(t, StatusSucceeded, got.Status)
+	assert.Empty(t, got.Error)
+	assert.Equal(t, Target{GOOS: "linux", GOARCH: "arm64", Tags: []string{"netgo"}}, got.Target)
+	assert.Equal(t, []Rule{
+		{Name: "server", Kind: "struct", Target: "example.com/svc"},
+		{
+			Name: "handler", Kind: "func", Target: "example.com/svc",
+			Source: "/rules/svc.otelc.yml", Instrumentation: "example.com/instrumentation/svc",
+		},
+	}, got.Rules)
+	assert.Equal(t, []Package{{
+		ImportPath: "example.com/svc",
+		Version:    "v1.2.0",
+		Matches: []Match{
+			{Rule: "handler", Kind: "func", File: "/src/svc/handler.go"},
+			{Rule: "server", Kind: "struct", File: "/src/svc/server.go"},
+		},
+	}}, got.Packages)
+	require.Len(t, got.Modifications, 2)
+	assert.Equal(t, "/src/svc/handler.go", got.Modifications[0].File)
+	assert.Equal(t, "/src/svc/server.go", got.Modifications[1].File)
+	assert.Len(t, got.Skipped, 1)
+	assert.Equal(t, []Warning{{Message: "no instrumentation rules matched any dependencies"}}, got.Warnings)
+	assert.Equal(t, int64(2000), got.Timing.SetupMS)
+
+	failed, err := rc.Build(errors.New("exit status 1"))
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Equal(t, "exit status 1", failed.Error)
+
+	// Fragments of earlier builds are removed
+	require.NoError(t, CleanFragments())
+	got, err = NewRecorder(nil).Build(nil)
+	require.NoError(t, err)
+	assert.Empty(t, got.Modifications)
+	assert.NotNil(t, got.Modifications)
+}
+
+func TestRecorder_Write(t *testing.T) {
+	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
+	rc := NewRecorder([]string{"go", "build", "."})
+	rc.AddRules([]rule.InstRule{handlerRule("handler")})
+	rc.AddModification(Modification{
+		Rule: "handler", Kind: "func", Package: "example.com/svc", File: "/src/svc/handler.go", Line: 3, Symbol: "Handler",
+	})
+	rc.AddSkipped(Skipped{
+		Rule: "handler", Package: "example.com/svc", File: "/src/svc/main.go", Line: 8, Reason: "no variadic_type",
+	})
+	rc.AddWarning("example.com/svc", "skipping package without Go files")
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "out", "report.json")
+		require.NoError(t, rc.Write(path, FormatJSON, nil))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var got Report
+		require.NoError(t, json.Unmarshal(data, &got))
+		assert.Equal(t, Version, got.Version)
+		assert.Len(t, got.Modifications, 1)
+		assert.Len(t, got.Skipped, 1)
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.sarif")
+		require.NoError(t, rc.Write(path, FormatSARIF, errors.New("exit status 1")))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var got sarifLog
+		require.NoError(t, json.Unmarshal(data, &got))
+		assert.Equal(t, "2.1.0", got.Version)
+		require.Len(t, got.Runs, 1)
+		run := got.Runs[0]
+		assert.Equal(t, "otelc", run.Tool.Driver.Name)
+		require.Len(t, run.Tool.Driver.Rules, 1)
+		assert.Equal(t, "handler", run.Tool.Driver.Rules[0].ID)
+		assert.False(t, run.Invocations[0].ExecutionSuccessful)
+		assert.Equal(t, StatusFailed, run.Properties.Status)
+
+		require.Len(t, run.Results, 3)
+		applied := run.Results[0]
+		assert.Equal(t, "handler", applied.RuleID)
+		assert.Equal(t, levelNote, applied.Level)
+		assert.Equal(t, "Applied func rule handler to Handler", applied.Message.Text)
+		require.Len(t, applied.Locations, 1)
+		assert.Equal(t, "file:///src/svc/handler.go", applied.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 3, applied.Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Equal(t, levelWarning, run.Results[1].Level)
+		assert.Equal(t, "Skipped call site: no variadic_type", run.Results[1].Message.Text)
+		assert.Equal(t, levelWarning, run.Results[2].Level)
+		assert.Empty(t, run.Results[2].Locations)
+	})
+}
+
+func TestFileURI(t *testing.T) {
+	assert.Equal(t, "file:///src/my%20app/main.go", fileURI("/src/my app/main.go"))
+	assert.Equal(t, "file:///C:/src/main.go", fileURI("C:/src/main.go"))
+}
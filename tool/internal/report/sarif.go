@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// The subset of SARIF 2.1.0 the report is written in. Rules become reporting
+// descriptors, applied modifications notes, and skipped call sites and
+// warnings warnings. Everything else goes to the properties of the run.
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	otelcURI     = "https://github.com/open-telemetry/opentelemetry-go-compile-instrumentation"
+
+	levelNote    = "note"
+	levelWarning = "warning"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+	Properties  sarifRunProps     `json:"properties"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string         `json:"id"`
+	ShortDescription sarifMessage   `json:"shortDescription"`
+	Properties       sarifRuleProps `json:"properties"`
+}
+
+// sarifRuleProps lists the loaded rules of one name: a rule file entry can
+// expand into several rules, and instrumentations may reuse names.
+type sarifRuleProps struct {
+	Definitions []Rule `json:"definitions"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool        `json:"executionSuccessful"`
+	Arguments           []string    `json:"arguments"`
+	Notifications       []sarifNote `json:"toolExecutionNotifications,omitempty"`
+	Properties          Timing      `json:"properties"`
+}
+
+type sarifNote struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId,omitempty"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations,omitempty"`
+	Properties map[string]any  `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifRunProps carries what SARIF has no place for.
+type sarifRunProps struct {
+	ReportVersion int       `json:"reportVersion"`
+	Status        string    `json:"status"`
+	Target        Target    `json:"target"`
+	Packages      []Package `json:"packages"`
+}
+
+// sarif converts the report to a SARIF log with a single run.
+func (r *Report) sarif() *sarifLog {
+	driver := sarifDriver{
+		Name:           "otelc",
+		Version:        r.OtelcVersion,
+		InformationURI: otelcURI,
+		Rules:          make([]sarifRule, 0),
+	}
+	// The ids of reporting descriptors must be unique: rules of the same name
+	// share one
+	descriptors := make(map[string]int)
+	for _, rule := range r.Rules {
+		i, ok := descriptors[rule.Name]
+		if !ok {
+			i = len(driver.Rules)
+			descriptors[rule.Name] = i
+			driver.Rules = append(driver.Rules, sarifRule{
+				ID:               rule.Name,
+				ShortDescription: sarifMessage{Text: fmt.Sprintf("%s rule for %s", rule.Kind, rule.Target)},
+			})
+		}
+		driver.Rules[i].Properties.Definitions = append(driver.Rules[i].Properties.Definitions, rule)
+	}
+
+	invocation := sarifInvocation{
+		ExecutionSuccessful: r.Status == StatusSucceeded,
+		Arguments:           r.Command,
+		Properties:          r.Timing,
+	}
+	if r.Error != "" {
+		invocation.Notifications = []sarifNote{{Level: "error", Message: sarifMessage{Text: r.Error}}}
+	}
+
+	results := make([]sarifResult, 0, len(r.Modifications)+len(r.Skipped)+len(r.Warnings))
+	for _, m := range r.Modifications {
+		text := fmt.Sprintf("Applied %s rule %s", m.Kind, m.Rule)
+		if m.Symbol != "" {
+			text += " to " + m.Symbol
+		}
+		results = append(results, sarifResult{
+			RuleID:     m.Rule,
+			Level:      levelNote,
+			Message:    sarifMessage{Text: text},
+			Locations:  sarifLocations(m.File, m.Line),
+			Properties: map[string]any{"package": m.Package},
+		})
+	}
+	for _, s := range r.Skipped {
+		results = append(results, sarifResult{
+			RuleID:     s.Rule,
+			Level:      levelWarning,
+			Message:    sarifMessage{Text: fmt.Sprintf("Skipped call site: %s", s.Reason)},
+			Locations:  sarifLocations(s.File, s.Line),
+			Properties: map[string]any{"package": s.Package},
+		})
+	}
+	for _, w := range r.Warnings {
+		result := sarifResult{Level: levelWarning, Message: sarifMessage{Text: w.Message}}
+		if w.Package != "" {
+			result.Properties = map[string]any{"package": w.Package}
+		}
+		results = append(results, result)
+	}
+
+	return &sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool:        sarifTool{Driver: driver},
+			Invocations: []sarifInvocation{invocation},
+			Results:     results,
+			Properties: sarifRunProps{
+				ReportVersion: r.Version,
+				Status:        r.Status,
+				Target:        r.Target,
+				Packages:      r.Packages,
+			},
+		}},
+	}
+}
+
+func sarifLocations(file string, line int) []sarifLocation {
+	if file == "" {
+		return nil
+	}
+	loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: fileURI(file)},
+	}}
+	if line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+	}
+	return []sarifLocation{loc}
+}
+
+// fileURI returns the file URI of an absolute path, on Windows too.
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// A drive letter: C:/src becomes file:///C:/src
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -24,6 +24,27 @@ type InstRule interface {
 	GetTarget() string   // The target module path where the rule is applied
 	GetVersion() string  // The version range of target module if available, e.g "v1.0.0,v2.0.0"
 	GetWhere() *WhereDef // Optional non-package selectors that remain after normalization
+	GetOrigin() Origin   // Where the rule was loaded from
+	IsRequired() bool    // Whether the build fails when the rule does not apply
+}
+
+// Origin tells where a rule was loaded from. It is not part of the rule
+// definition and is never serialized.
+type Origin struct {
+	// File is the rule file that defines the rule
+	File string
+	// Instrumentation is the import path of the instrumentation package that
+	// provides the rule file, empty for rules passed with --rules
+	Instrumentation string
+}
+
+// SetOrigin records where rules were loaded from.
+func SetOrigin(rules []InstRule, origin Origin) {
+	for _, r := range rules {
+		if b, ok := r.(interface{ setOrigin(o Origin) }); ok {
+			b.setOrigin(origin)
+		}
+	}
 }
 
 // FilterDef describes file predicates nested under where.file.
@@ -64,6 +85,19 @@ type FilterDef struct {
 	//   is_test: false → match only non-test builds
 	//   absent (nil)   → no filtering; the rule applies to every build
 	IsTest *bool `json:"is_test,omitempty" yaml:"is_test,omitempty"`
+
+	// Build selects source files by the target of the build: the GOOS and
+	// GOARCH it compiles for and the build tags it sets with -tags. A build
+	// predicate without any field set is rejected when the filter is built.
+	Build *BuildDef `json:"build,omitempty" yaml:"build,omitempty"`
+}
+
+// BuildDef is the where.file.build predicate. It matches when every field that
+// is set matches the target of the build.
+type BuildDef struct {
+	GOOS   string   `json:"goos,omitempty"   yaml:"goos,omitempty"`   // match builds for this operating system
+	GOARCH string   `json:"goarch,omitempty" yaml:"goarch,omitempty"` // match builds for this architecture
+	Tags   []string `json:"tags,omitempty"   yaml:"tags,omitempty"`   // match builds that set all of these tags
 }
 
 // WhereDef carries the structured where clause after package selectors have
@@ -90,11 +124,13 @@ type WhereDef struct {
 
 // InstBaseRule is the base rule for all instrumentation rules.
 type InstBaseRule struct {
-	Name    string            `json:"name,omitempty"    yaml:"name,omitempty"`
-	Target  string            `json:"target"            yaml:"target"`
-	Version string            `json:"version,omitempty" yaml:"version,omitempty"`
-	Imports map[string]string `json:"imports,omitempty" yaml:"imports,omitempty"` // map[alias]path
-	Where   *WhereDef         `json:"where,omitempty"   yaml:"where,omitempty"`
+	Name     string            `json:"name,omitempty"     yaml:"name,omitempty"`
+	Target   string            `json:"target"             yaml:"target"`
+	Version  string            `json:"version,omitempty"  yaml:"version,omitempty"`
+	Imports  map[string]string `json:"imports,omitempty"  yaml:"imports,omitempty"` // map[alias]path
+	Where    *WhereDef         `json:"where,omitempty"    yaml:"where,omitempty"`
+	Required bool              `json:"required,omitempty" yaml:"required,omitempty"` // fail the build when the rule does not apply
+	Origin   Origin            `json:"-"                  yaml:"-"`
 }
 
 func (ibr *InstBaseRule) String() string      { return ibr.Name }
@@ -102,6 +138,9 @@ func (ibr *InstBaseRule) GetName() string     { return ibr.Name }
 func (ibr *InstBaseRule) GetTarget() string   { return ibr.Target }
 func (ibr *InstBaseRule) GetVersion() string  { return ibr.Version }
 func (ibr *InstBaseRule) GetWhere() *WhereDef { return ibr.Where }
+func (ibr *InstBaseRule) GetOrigin() Origin   { return ibr.Origin }
+func (ibr *InstBaseRule) IsRequired() bool    { return ibr.Required }
+func (ibr *InstBaseRule) setOrigin(o Origin)  { ibr.Origin = o }
 
 // InstRuleSet represents a collection of instrumentation rules that apply to a
 // single Go package within a specific module. It acts as a container for rules,
@@ -205,6 +244,29 @@ func (irs *InstRuleSet) SetCgoFileMap(cgoFiles map[string]string) {
 	irs.CgoFileMap = cgoFiles
 }
 
+// AllRules returns all rules of the rule set as a flat slice.
+func (irs *InstRuleSet) AllRules() []InstRule {
+	rules := make([]InstRule, 0, len(irs.FileRules))
+	for _, r := range irs.FileRules {
+		rules = append(rules, r)
+	}
+	rules = appendRules(rules, irs.RawRules)
+	rules = appendRules(rules, irs.FuncRules)
+	rules = appendRules(rules, irs.StructRules)
+	rules = appendRules(rules, irs.CallRules)
+	rules = appendRules(rules, irs.DirectiveRules)
+	return appendRules(rules, irs.DeclRules)
+}
+
+func appendRules[T InstRule](rules []InstRule, byFile map[string][]T) []InstRule {
+	for _, rs := range byFile {
+		for _, r := range rs {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
 // AllFuncRules returns all function rules from the rule set as a flat slice.
 func (irs *InstRuleSet) AllFuncRules() []*InstFuncRule {
 	n := 0
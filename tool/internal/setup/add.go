@@ -15,6 +15,7 @@ import (
 	"go.opentelemetry.io/otelc/tool/ex"
 	"go.opentelemetry.io/otelc/tool/internal/ast"
 	"go.opentelemetry.io/otelc/tool/internal/rule"
+	"go.opentelemetry.io/otelc/tool/util"
 )
 
 const (
@@ -23,9 +24,8 @@ const (
 
 //nolint:gochecknoglobals // This is a constant
 var requiredImports = map[string]string{
-	"runtime/debug": "_otel_debug", // The getstack function depends on runtime/debug
-	"log":           "_otel_log",   // The printstack function depends on log
-	"unsafe":        "_",           // The golinkname tag depends on unsafe
+	util.OtelcPkgRoot + "/runtime": "_otel_runtime", // The hook handlers live in otelc runtime
+	"unsafe":                       "_",             // The golinkname tag depends on unsafe
 }
 
 func genImportDecl(funcRules []*rule.InstFuncRule, fileRules []*rule.InstFileRule) []dst.Decl {
@@ -49,53 +49,46 @@ func genImportDecl(funcRules []*rule.InstFuncRule, fileRules []*rule.InstFileRul
 	return importDecls
 }
 
-func genVarDecl(matched []*rule.InstFuncRule) []dst.Decl {
+// linknameTarget returns the link-time path of the package instrumented by
+// the rule set, where the trampolines declare their handler variables. The
+// linker always names the main package "main", whatever its import path.
+func linknameTarget(rs *rule.InstRuleSet) string {
+	if rs.PackageName == "main" {
+		return "main"
+	}
+	return util.LinknamePath(rs.ModulePath)
+}
+
+func genVarDecl(matched []*rule.InstRuleSet) []dst.Decl {
 	decls := make([]dst.Decl, 0, len(matched))
-	uniquePath := map[string]bool{}
+	uniqueTarget := map[string]bool{}
 	for i, m := range matched {
-		if _, ok := uniquePath[m.Path]; ok {
+		if len(m.FuncRules) == 0 {
 			continue
 		}
-		uniquePath[m.Path] = true
-		// First variable declaration
-		// //go:linkname _getstack%d %s.OtelGetStackImpl
-		// var _getstack%d = _otel_debug.Stack
-		value := ast.SelectorExpr(ast.Ident("_otel_debug"), "Stack")
-		getStackVar := ast.VarDecl(fmt.Sprintf("_getstack%d", i), value)
-		getStackVar.Decs = dst.GenDeclDecorations{
-			NodeDecs: ast.LineComments(
-				fmt.Sprintf("//go:linkname _getstack%d %s.OtelGetStackImpl", i, m.Path)),
-		}
-		// Second variable declaration
-		// //go:linkname _printstack%d %s.OtelPrintStackImpl
-		// var _printstack%d = func (bt []byte){ _otel_log.Print(string(bt)) }
-		// Build: string(bt)
-		stringCall := &dst.CallExpr{
-			Fun:  ast.Ident("string"),
-			Args: []dst.Expr{ast.Ident("bt")},
-		}
-		// Build: _otel_log.Print(string(bt))
-		printCall := &dst.CallExpr{
-			Fun:  ast.SelectorExpr(ast.Ident("_otel_log"), "Print"),
-			Args: []dst.Expr{stringCall},
-		}
-		// Build: func (bt []byte) { _otel_log.Print(string(bt)) }
-		printStackFunc := &dst.FuncLit{
-			Type: &dst.FuncType{
-				Params: &dst.FieldList{
-					List: []*dst.Field{
-						ast.Field("bt", ast.ArrayType(ast.Ident("byte"))),
-					},
-				},
-			},
-			Body: ast.BlockStmts(ast.ExprStmt(printCall)),
+		// The handler variables live in the instrumented package, next to
+		// the trampolines, not in the package that defines the hooks
+		target := linknameTarget(m)
+		if _, ok := uniqueTarget[target]; ok {
+			continue
 		}
-		printStackVar := ast.VarDecl(fmt.Sprintf("_printstack%d", i), printStackFunc)
-		printStackVar.Decs = dst.GenDeclDecorations{
-			NodeDecs: ast.LineComments(
-				fmt.Sprintf("//go:linkname _printstack%d %s.OtelPrintStackImpl", i, m.Path)),
+		uniqueTarget[target] = true
+		// One variable declaration per trampoline hook handler, e.g.
+		// //go:linkname _hookstart%d %s.OtelHookStartImpl
+		// var _hookstart%d = _otel_runtime.HookStart
+		for _, v := range []struct{ name, impl, fn string }{
+			{"_hookstart", "OtelHookStartImpl", "HookStart"},
+			{"_hookend", "OtelHookEndImpl", "HookEnd"},
+			{"_hookpanic", "OtelHookPanicImpl", "HookPanicked"},
+		} {
+			name := fmt.Sprintf("%s%d", v.name, i)
+			decl := ast.VarDecl(name, ast.SelectorExpr(ast.Ident("_otel_runtime"), v.fn))
+			decl.Decs = dst.GenDeclDecorations{
+				NodeDecs: ast.LineComments(
+					fmt.Sprintf("//go:linkname %s %s.%s", name, target, v.impl)),
+			}
+			decls = append(decls, decl)
 		}
-		decls = append(decls, getStackVar, printStackVar)
 	}
 	return decls
 }
@@ -127,21 +120,32 @@ func (sp *SetupPhase) addDeps(ctx context.Context, matched []*rule.InstRuleSet,
 	// Add required imports
 	importDecls := genImportDecl(funcRules, fileRules)
 	// Generate the variable declarations that used by otel runtime
-	varDecls := genVarDecl(funcRules)
+	varDecls := genVarDecl(matched)
+	return sp.writeRuntimeFile(ctx, append(importDecls, varDecls...), packagePath, packageName)
+}
+
+// writeRuntimeFile writes otelc.runtime.go with the given declarations to the
+// package at packagePath.
+func (sp *SetupPhase) writeRuntimeFile(ctx context.Context, decls []dst.Decl, packagePath, packageName string) error {
 	// Build the ast
-	root := buildOtelcRuntimeAst(append(importDecls, varDecls...), packageName)
+	root := buildOtelcRuntimeAst(decls, packageName)
 	otelcRuntimeFilePath := filepath.Join(packagePath, OtelcRuntimeFile)
-	// Track file in state manager
-	if stateManager, found := StateManagerFromContext(ctx); found {
+	shadow := shadowFromContext(ctx)
+	// Track file in state manager; a shadow copy leaves nothing to restore
+	if stateManager, found := StateManagerFromContext(ctx); found && shadow == nil {
 		if err := stateManager.Track(otelcRuntimeFilePath); err != nil {
 			return err
 		}
 	}
+	dst, err := shadow.redirect(otelcRuntimeFilePath)
+	if err != nil {
+		return err
+	}
 	// Write the ast to file
-	if err := ast.WriteFileAtomic(otelcRuntimeFilePath, root); err != nil {
-		return ex.Wrapf(err, "writing otelc runtime file %s", otelcRuntimeFilePath)
+	if err = ast.WriteFileAtomic(dst, root); err != nil {
+		return ex.Wrapf(err, "writing otelc runtime file %s", dst)
 	}
-	keepForDebug(ctx, otelcRuntimeFilePath)
-	sp.Info("Created otelc.runtime.go", "path", otelcRuntimeFilePath)
+	keepForDebug(ctx, dst)
+	sp.Info("Created otelc.runtime.go", "path", otelcRuntimeFilePath, "written", dst)
 	return nil
 }
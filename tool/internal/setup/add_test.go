@@ -83,6 +83,22 @@ func TestAddDeps(t *testing.T) {
 			packageName: "main",
 			goldenFile:  "multiple_rule_sets.otelc.runtime.go.golden",
 		},
+		{
+			name: "main_package_target",
+			matched: []*rule.InstRuleSet{
+				func() *rule.InstRuleSet {
+					rs := newTestRuleSet(
+						"github.com/example/app",
+						[]*rule.InstFuncRule{newTestFuncRule("github.com/example/hooks", "github.com/example/app")},
+						nil,
+					)
+					rs.SetPackageName("main")
+					return rs
+				}(),
+			},
+			packageName: "main",
+			goldenFile:  "main_package_target.otelc.runtime.go.golden",
+		},
 		{
 			name: "non_main_package_name",
 			matched: []*rule.InstRuleSet{
@@ -0,0 +1,972 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"go/parser"
+	"go/token"
+	goversion "go/version"
+	"io"
+	"io/fs"
+	"maps"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/sumdb/dirhash"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/internal/report"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+const (
+	// bundleFlag is the otelc flag that builds with the instrumentation of a
+	// rule bundle instead of the embedded one.
+	bundleFlag = "bundle"
+	// bundleKeyFlag is the otelc flag naming the public key a bundle must be
+	// signed with.
+	bundleKeyFlag = "bundle-key"
+	// bundleInsecureFlag is the otelc flag that builds with a bundle checked
+	// only against its checksum, without --bundle-key.
+	bundleInsecureFlag = "bundle-insecure"
+
+	// BundleChecksumExt is the extension of the detached checksum of a
+	// bundle, in the format of sha256sum.
+	BundleChecksumExt = ".sha256"
+	// BundleSignatureExt is the extension of the detached signature of a
+	// bundle, the base64 Ed25519 signature of its SHA-256 digest.
+	BundleSignatureExt = ".sig"
+
+	bundleDir          = "bundle"
+	bundleManifestFile = "otelc-bundle.json"
+	bundleModFile      = "go.mod"
+	bundleSumFile      = "go.sum"
+	bundleModulesDir   = "modules"
+	bundleCacheDir     = "cache"
+
+	// bundleDepsModule is the module the dependencies of a bundle are
+	// resolved in. It requires every bundled module.
+	bundleDepsModule = "otelc.bundle/deps"
+)
+
+// BundleManifest describes the instrumentation modules in a rule bundle.
+type BundleManifest struct {
+	// Version is the version the bundle was released as.
+	Version string `json:"version"`
+	// OtelcVersion is the version of otelc that created the bundle. The
+	// bundle caches the dependencies of its runtime.
+	OtelcVersion string         `json:"otelc_version"`
+	Modules      []BundleModule `json:"modules"`
+}
+
+// BundleModule is an instrumentation module in a rule bundle.
+type BundleModule struct {
+	Path string `json:"path"`
+	// Hash is the hash of the module sources, in the h1: format of go.sum.
+	Hash string `json:"hash"`
+}
+
+// BundleOptions configures CreateBundle.
+type BundleOptions struct {
+	// Output is the path of the bundle archive.
+	Output string
+	// Version is the version the bundle is released as.
+	Version string
+	// Modules are directories containing instrumentation modules, or the
+	// module paths of embedded instrumentation; a path also selects the
+	// modules below it. Empty selects all embedded instrumentation.
+	Modules []string
+	// Key is the PEM encoded PKCS #8 Ed25519 private key the bundle is signed
+	// with. Empty leaves the bundle unsigned.
+	Key string
+}
+
+// CreateBundle packages instrumentation modules, their rules and every module
+// they depend on into a reproducible archive at opts.Output, so that builds
+// can use them without network access. It writes the checksum of the archive
+// next to it and, given a key, its signature.
+//
+// The archive holds the manifest, the module sources under modules/, the
+// dependencies under cache/ in the layout of a GOPROXY, and the go.mod and
+// go.sum of the module they were resolved in. The dependencies are downloaded
+// with the go command, so creating a bundle needs the network or a GOPROXY that
+// serves them.
+func CreateBundle(ctx context.Context, opts BundleOptions) (*BundleManifest, error) {
+	if opts.Output == "" || opts.Version == "" {
+		return nil, ex.New("a bundle needs an output path and a version")
+	}
+	var key ed25519.PrivateKey
+	if opts.Key != "" {
+		var err error
+		if key, err = readPrivateKey(opts.Key); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := extractOtelcBundle(); err != nil {
+		return nil, ex.Wrapf(err, "extracting otelc package")
+	}
+	modules, err := selectBundleModules(opts.Modules)
+	if err != nil {
+		return nil, err
+	}
+
+	work, err := os.MkdirTemp("", "otelc-bundle-")
+	if err != nil {
+		return nil, ex.Wrapf(err, "creating bundle work directory")
+	}
+	defer os.RemoveAll(work)
+	modCache := filepath.Join(work, "modcache")
+	// The module cache is read-only, only the go command can remove it.
+	defer cleanModCache(ctx, modCache)
+
+	depsDir, err := downloadBundleDeps(ctx, modules, work, modCache)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &BundleManifest{Version: opts.Version, OtelcVersion: util.Version}
+	entries := []bundleEntry{
+		{name: bundleModFile, src: filepath.Join(depsDir, bundleModFile)},
+		{name: bundleSumFile, src: filepath.Join(depsDir, bundleSumFile)},
+	}
+	for _, modPath := range slices.Sorted(maps.Keys(modules)) {
+		files, filesErr := moduleFiles(modules[modPath])
+		if filesErr != nil {
+			return nil, filesErr
+		}
+		hash, hashErr := hashModule(modules[modPath], files)
+		if hashErr != nil {
+			return nil, hashErr
+		}
+		manifest.Modules = append(manifest.Modules, BundleModule{Path: modPath, Hash: hash})
+		for _, file := range files {
+			entries = append(entries, bundleEntry{
+				name: path.Join(bundleModulesDir, modPath, file),
+				src:  filepath.Join(modules[modPath], filepath.FromSlash(file)),
+			})
+		}
+	}
+	cached, err := cacheFiles(filepath.Join(modCache, "cache", "download"))
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, cached...)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, ex.Wrapf(err, "encoding bundle manifest")
+	}
+	entries = append(entries, bundleEntry{name: bundleManifestFile, data: append(data, '\n')})
+
+	digest, err := writeBundle(opts.Output, entries)
+	if err != nil {
+		return nil, err
+	}
+	checksum := fmt.Sprintf("%x  %s\n", digest, filepath.Base(opts.Output))
+	if err = util.WriteFileAtomic(opts.Output+BundleChecksumExt, []byte(checksum), 0o644); err != nil {
+		return nil, ex.Wrapf(err, "writing bundle checksum")
+	}
+	if key != nil {
+		sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, digest)) + "\n"
+		if err = util.WriteFileAtomic(opts.Output+BundleSignatureExt, []byte(sig), 0o644); err != nil {
+			return nil, ex.Wrapf(err, "writing bundle signature")
+		}
+	}
+	return manifest, nil
+}
+
+// VerifyBundle checks the bundle at path against its checksum and the hashes
+// of its modules against its manifest. Given a public key, it also checks the
+// bundle is signed with the matching private key.
+func VerifyBundle(bundlePath, keyPath string) (*BundleManifest, error) {
+	f, digest, err := openVerifiedBundle(bundlePath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dir, err := os.MkdirTemp("", "otelc-bundle-")
+	if err != nil {
+		return nil, ex.Wrapf(err, "creating bundle directory")
+	}
+	defer os.RemoveAll(dir)
+	return extractBundle(f, bundlePath, digest, dir)
+}
+
+// selectBundleModules returns the directories of the instrumentation modules
+// args select, keyed by module path, together with the embedded modules they
+// require.
+func selectBundleModules(args []string) (map[string]string, error) {
+	embedded, err := findModules(filepath.Join(util.GetBuildTempDir(), unzippedInstDir))
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return embedded, nil
+	}
+
+	selected := make(map[string]string)
+	for _, arg := range args {
+		if info, statErr := os.Stat(arg); statErr == nil && info.IsDir() {
+			found, findErr := findModules(arg)
+			if findErr != nil {
+				return nil, findErr
+			}
+			if len(found) == 0 {
+				return nil, ex.Newf("no go.mod found in %s", arg)
+			}
+			maps.Copy(selected, found)
+			continue
+		}
+		matched := false
+		for modPath, dir := range embedded {
+			if modPath == arg || strings.HasPrefix(modPath, arg+"/") {
+				selected[modPath] = dir
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, ex.Newf("%s is neither a directory nor an embedded instrumentation module", arg)
+		}
+	}
+
+	// Bundled modules resolve embedded ones they require, such as the shared
+	// semconv module, from the bundle too.
+	queue := slices.Collect(maps.Values(selected))
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		mf, parseErr := parseGoMod(filepath.Join(dir, "go.mod"))
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		for _, req := range mf.Require {
+			if reqDir, ok := embedded[req.Mod.Path]; ok && selected[req.Mod.Path] == "" {
+				selected[req.Mod.Path] = reqDir
+				queue = append(queue, reqDir)
+			}
+		}
+	}
+	return selected, nil
+}
+
+// findModules returns the directories of the modules in root, keyed by module
+// path.
+func findModules(root string) (map[string]string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, ex.Wrap(err)
+	}
+	modules := make(map[string]string)
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() && p != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata") {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "go.mod" {
+			return nil
+		}
+		mf, parseErr := parseGoMod(p)
+		if parseErr != nil {
+			return ex.Wrapf(parseErr, "loading %s", p)
+		}
+		modules[mf.Module.Mod.Path] = filepath.Dir(p)
+		return nil
+	})
+	if err != nil {
+		return nil, ex.Wrapf(err, "finding modules in %s", root)
+	}
+	return modules, nil
+}
+
+// downloadBundleDeps downloads the module graph of modules and of the otelc
+// runtime to modCache. It returns the directory of the module it resolved
+// the graph in, whose go.sum holds the checksums of the graph.
+func downloadBundleDeps(ctx context.Context, modules map[string]string, work, modCache string) (string, error) {
+	pkgDir := filepath.Join(util.GetBuildTempDir(), unzippedPkgDir)
+	replaces := map[string]string{
+		util.OtelcPkgRoot:              pkgDir,
+		util.OtelcPkgRoot + "/runtime": filepath.Join(pkgDir, "runtime"),
+		util.OtelcInstRoot:             filepath.Join(util.GetBuildTempDir(), unzippedInstDir),
+	}
+	maps.Copy(replaces, modules)
+
+	mf := new(modfile.File)
+	if err := mf.AddModuleStmt(bundleDepsModule); err != nil {
+		return "", ex.Wrap(err)
+	}
+	goVersion := "1.21"
+	for _, modPath := range slices.Sorted(maps.Keys(replaces)) {
+		dep, err := parseGoMod(filepath.Join(replaces[modPath], "go.mod"))
+		if err != nil {
+			return "", err
+		}
+		if dep.Go != nil && goversion.Compare("go"+dep.Go.Version, "go"+goVersion) > 0 {
+			goVersion = dep.Go.Version
+		}
+		if modPath != util.OtelcPkgRoot && modPath != util.OtelcInstRoot {
+			mf.AddNewRequire(modPath, "v0.0.0", false)
+		}
+		if err = mf.AddReplace(modPath, "", replaces[modPath], ""); err != nil {
+			return "", ex.Wrap(err)
+		}
+	}
+	if err := mf.AddGoStmt(goVersion); err != nil {
+		return "", ex.Wrap(err)
+	}
+	// Setup adds a release of otelc to the module it builds as a tool, so the
+	// bundle serves its modules too.
+	if added, err := ensureOtelcRequireVersion(mf, util.Version); err != nil {
+		return "", ex.Wrap(err)
+	} else if added {
+		if err = mf.AddTool(util.OtelcToolCmdRoot); err != nil {
+			return "", ex.Wrap(err)
+		}
+	}
+
+	// The module imports every package of the bundle, so that go mod tidy
+	// loads the same module graph as it does in a build that imports them.
+	imports := []string{util.OtelcPkgRoot + "/runtime"}
+	for _, modPath := range slices.Sorted(maps.Keys(modules)) {
+		pkgs, err := modulePackages(modPath, modules[modPath])
+		if err != nil {
+			return "", err
+		}
+		imports = append(imports, pkgs...)
+	}
+	var source strings.Builder
+	source.WriteString("// Code generated by otelc bundle create. DO NOT EDIT.\n\npackage deps\n\nimport (\n")
+	for _, pkg := range imports {
+		fmt.Fprintf(&source, "\t_ %q\n", pkg)
+	}
+	source.WriteString(")\n")
+
+	depsDir := filepath.Join(work, "deps")
+	if err := os.MkdirAll(depsDir, 0o755); err != nil {
+		return "", ex.Wrapf(err, "creating %s", depsDir)
+	}
+	if err := writeGoMod(filepath.Join(depsDir, "go.mod"), mf); err != nil {
+		return "", err
+	}
+	if err := util.WriteFileAtomic(filepath.Join(depsDir, "deps.go"), []byte(source.String())); err != nil {
+		return "", ex.Wrapf(err, "writing the imports of the bundle")
+	}
+	env := append(os.Environ(), "GOMODCACHE="+modCache, "GOWORK=off", "GOFLAGS=-mod=mod")
+	if err := util.RunCmdInDirWithEnv(ctx, depsDir, env, "go", "mod", "tidy"); err != nil {
+		return "", ex.Wrapf(err, "resolving the dependencies of the bundle")
+	}
+	if err := util.RunCmdInDirWithEnv(ctx, depsDir, env, "go", "mod", "download", "all"); err != nil {
+		return "", ex.Wrapf(err, "downloading the dependencies of the bundle")
+	}
+	return depsDir, nil
+}
+
+// modulePackages returns the import paths of the packages of the module
+// modPath in dir that other modules can import.
+func modulePackages(modPath, dir string) ([]string, error) {
+	files, err := moduleFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []string
+	for _, file := range files {
+		pkgDir := path.Dir(file)
+		if !util.IsGoFile(file) || strings.HasSuffix(file, "_test.go") ||
+			slices.Contains(pkgs, path.Join(modPath, pkgDir)) ||
+			slices.ContainsFunc(strings.Split(pkgDir, "/"), func(elem string) bool {
+				return elem == "internal" || elem == "testdata"
+			}) {
+			continue
+		}
+		f, parseErr := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, filepath.FromSlash(file)),
+			nil, parser.PackageClauseOnly)
+		if parseErr != nil {
+			return nil, ex.Wrapf(parseErr, "parsing %s", file)
+		}
+		if f.Name.Name != "main" {
+			pkgs = append(pkgs, path.Join(modPath, pkgDir))
+		}
+	}
+	return pkgs, nil
+}
+
+func cleanModCache(ctx context.Context, modCache string) {
+	if !util.PathExists(modCache) {
+		return
+	}
+	cmd := exec.CommandContext(ctx, "go", "clean", "-modcache")
+	cmd.Env = append(os.Environ(), "GOMODCACHE="+modCache)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		util.LoggerFromContext(ctx).WarnContext(ctx, "failed to remove bundle module cache",
+			"dir", modCache, "error", err, "output", string(out))
+	}
+}
+
+// moduleFiles returns the slash-separated paths of the files of the module in
+// dir, relative to dir. Like the extraction of a bundle, it skips hidden
+// files; nested modules are modules of their own.
+func moduleFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if util.PathExists(filepath.Join(p, "go.mod")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return ex.Newf("unsupported file type: %s", p)
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return ex.Wrap(err)
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, ex.Wrapf(err, "listing module files in %s", dir)
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func hashModule(dir string, files []string) (string, error) {
+	hash, err := dirhash.Hash1(files, func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+	})
+	if err != nil {
+		return "", ex.Wrapf(err, "hashing module in %s", dir)
+	}
+	return hash, nil
+}
+
+// cacheFiles returns the bundle entries of the module cache download
+// directory, which the go command can read as a GOPROXY. The checksum
+// database cache and the lock files of the go command are left out.
+func cacheFiles(downloadDir string) ([]bundleEntry, error) {
+	var entries []bundleEntry
+	err := filepath.WalkDir(downloadDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p == filepath.Join(downloadDir, "sumdb") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(p, ".lock") || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(downloadDir, p)
+		if err != nil {
+			return ex.Wrap(err)
+		}
+		entries = append(entries, bundleEntry{name: path.Join(bundleCacheDir, filepath.ToSlash(rel)), src: p})
+		return nil
+	})
+	if err != nil {
+		return nil, ex.Wrapf(err, "listing the dependencies of the bundle")
+	}
+	return entries, nil
+}
+
+// bundleEntry is a file in a bundle archive, read from src or given as data.
+type bundleEntry struct {
+	name string
+	src  string
+	data []byte
+}
+
+// writeBundle writes entries to a gzip compressed tar archive at out and
+// returns its SHA-256 digest. The archive only depends on the contents of the
+// entries: they are sorted, and their times and owners are zeroed.
+func writeBundle(out string, entries []bundleEntry) ([]byte, error) {
+	slices.SortFunc(entries, func(a, b bundleEntry) int { return strings.Compare(a.name, b.name) })
+
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return nil, ex.Wrapf(err, "creating %s", filepath.Dir(out))
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(out), filepath.Base(out)+".tmp-*")
+	if err != nil {
+		return nil, ex.Wrapf(err, "creating %s", out)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	digest := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(tmp, digest))
+	tw := tar.NewWriter(gz)
+	dirs := make(map[string]bool)
+	for _, entry := range entries {
+		if err = writeBundleDirs(tw, path.Dir(entry.name), dirs); err != nil {
+			return nil, err
+		}
+		data := entry.data
+		if entry.src != "" {
+			if data, err = os.ReadFile(entry.src); err != nil {
+				return nil, ex.Wrapf(err, "reading %s", entry.src)
+			}
+		}
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     entry.name,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+			ModTime:  time.Unix(0, 0),
+			Format:   tar.FormatPAX,
+		}
+		if err = tw.WriteHeader(header); err != nil {
+			return nil, ex.Wrapf(err, "writing %s to bundle", entry.name)
+		}
+		if _, err = tw.Write(data); err != nil {
+			return nil, ex.Wrapf(err, "writing %s to bundle", entry.name)
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return nil, ex.Wrapf(err, "writing bundle %s", out)
+	}
+	if err = gz.Close(); err != nil {
+		return nil, ex.Wrapf(err, "writing bundle %s", out)
+	}
+	if err = tmp.Close(); err != nil {
+		return nil, ex.Wrapf(err, "writing bundle %s", out)
+	}
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		return nil, ex.Wrapf(err, "writing bundle %s", out)
+	}
+	if err = os.Rename(tmp.Name(), out); err != nil {
+		return nil, ex.Wrapf(err, "writing bundle %s", out)
+	}
+	return digest.Sum(nil), nil
+}
+
+// writeBundleDirs writes the entries of dir and its parents that are not in
+// dirs yet. Extraction creates the directories of a file from them.
+func writeBundleDirs(tw *tar.Writer, dir string, dirs map[string]bool) error {
+	if dir == "." || dirs[dir] {
+		return nil
+	}
+	if err := writeBundleDirs(tw, path.Dir(dir), dirs); err != nil {
+		return err
+	}
+	dirs[dir] = true
+	header := &tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     dir + "/",
+		Mode:     0o755,
+		ModTime:  time.Unix(0, 0),
+		Format:   tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return ex.Wrapf(err, "writing %s to bundle", dir)
+	}
+	return nil
+}
+
+// openVerifiedBundle opens the bundle at bundlePath and checks it against its
+// detached checksum and, given a public key, its detached signature. It
+// returns the open bundle and its hex encoded digest. Extracting from the
+// returned file, see extractBundle, rather than reopening bundlePath keeps a
+// bundle swapped after the check from being used.
+func openVerifiedBundle(bundlePath, keyPath string) (*os.File, string, error) {
+	var key ed25519.PublicKey
+	if keyPath != "" {
+		var err error
+		if key, err = readPublicKey(keyPath); err != nil {
+			return nil, "", err
+		}
+	}
+
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, "", ex.Wrapf(err, "opening bundle")
+	}
+	digest, err := verifyBundleDigest(f, bundlePath, keyPath, key)
+	if err != nil {
+		f.Close()
+		return nil, "", err
+	}
+	return f, digest, nil
+}
+
+// verifyBundleDigest hashes f, the bundle at bundlePath, and checks the digest
+// against the checksum and, given key, the signature next to bundlePath.
+func verifyBundleDigest(f *os.File, bundlePath, keyPath string, key ed25519.PublicKey) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", ex.Wrapf(err, "reading bundle %s", bundlePath)
+	}
+	digest := h.Sum(nil)
+
+	checksum, err := os.ReadFile(bundlePath + BundleChecksumExt)
+	if err != nil {
+		return "", ex.Wrapf(err, "reading the checksum of bundle %s", bundlePath)
+	}
+	fields := strings.Fields(string(checksum))
+	if len(fields) == 0 || subtle.ConstantTimeCompare([]byte(fields[0]), []byte(hex.EncodeToString(digest))) != 1 {
+		return "", ex.Newf("bundle %s does not match its checksum %s", bundlePath, bundlePath+BundleChecksumExt)
+	}
+
+	if key != nil {
+		encoded, readErr := os.ReadFile(bundlePath + BundleSignatureExt)
+		if readErr != nil {
+			return "", ex.Wrapf(readErr, "bundle %s is not signed", bundlePath)
+		}
+		sig, decodeErr := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+		if decodeErr != nil || !ed25519.Verify(key, digest, sig) {
+			return "", ex.Newf("the signature of bundle %s does not match the key %s", bundlePath, keyPath)
+		}
+	}
+	return hex.EncodeToString(digest), nil
+}
+
+// extractBundle extracts the bundle f, opened by openVerifiedBundle, to dir
+// and checks its modules against the manifest. The file may still be
+// rewritten in place once verified, so what is extracted must hash to digest
+// too.
+func extractBundle(f *os.File, bundlePath, digest, dir string) (*BundleManifest, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, ex.Wrapf(err, "reading bundle %s", bundlePath)
+	}
+	h := sha256.New()
+	r := io.TeeReader(f, h)
+	if err := extractGZip(r, dir); err != nil {
+		return nil, ex.Wrapf(err, "extracting bundle %s", bundlePath)
+	}
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, ex.Wrapf(err, "reading bundle %s", bundlePath)
+	}
+	if hex.EncodeToString(h.Sum(nil)) != digest {
+		return nil, ex.Newf("bundle %s changed after it was verified", bundlePath)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, bundleManifestFile))
+	if err != nil {
+		return nil, ex.Wrapf(err, "reading the manifest of bundle %s", bundlePath)
+	}
+	manifest := new(BundleManifest)
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, ex.Wrapf(err, "parsing the manifest of bundle %s", bundlePath)
+	}
+	for _, m := range manifest.Modules {
+		modDir := filepath.Join(dir, bundleModulesDir, filepath.FromSlash(m.Path))
+		files, filesErr := moduleFiles(modDir)
+		if filesErr != nil {
+			return nil, filesErr
+		}
+		hash, hashErr := hashModule(modDir, files)
+		if hashErr != nil {
+			return nil, hashErr
+		}
+		if hash != m.Hash {
+			return nil, ex.Newf("module %s in bundle %s does not match its hash %s", m.Path, bundlePath, m.Hash)
+		}
+	}
+	return manifest, nil
+}
+
+func readPrivateKey(keyPath string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, ex.Wrapf(err, "parsing private key %s", keyPath)
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, ex.Newf("%s is not an Ed25519 private key", keyPath)
+	}
+	return private, nil
+}
+
+func readPublicKey(keyPath string) (ed25519.PublicKey, error) {
+	block, err := readPEM(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, ex.Wrapf(err, "parsing public key %s", keyPath)
+	}
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, ex.Newf("%s is not an Ed25519 public key", keyPath)
+	}
+	return public, nil
+}
+
+func readPEM(keyPath string) (*pem.Block, error) {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, ex.Wrapf(err, "reading key")
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ex.Newf("no PEM data in key %s", keyPath)
+	}
+	return block, nil
+}
+
+// ruleBundle is a verified rule bundle extracted to the build temp directory.
+// Setup takes the instrumentation modules from it instead of the embedded
+// ones, and the go command their dependencies.
+type ruleBundle struct {
+	dir      string
+	manifest *BundleManifest
+}
+
+type ruleBundleKey struct{}
+
+// contextWithBundle returns a copy of ctx containing b.
+func contextWithBundle(ctx context.Context, b *ruleBundle) context.Context {
+	return context.WithValue(ctx, ruleBundleKey{}, b)
+}
+
+// bundleFromContext returns the ruleBundle stored in ctx, or nil when the build
+// uses the embedded instrumentation. Every method is safe on a nil receiver.
+func bundleFromContext(ctx context.Context) *ruleBundle {
+	b, _ := ctx.Value(ruleBundleKey{}).(*ruleBundle)
+	return b
+}
+
+// openBundle verifies the bundle at bundlePath, see VerifyBundle, and
+// extracts it to the build temp directory unless an earlier build did. A
+// bundle replaces the instrumentation and adds checksums to go.sum, so without
+// a key it is refused unless insecure is set: its checksum, next to it, only
+// detects corruption, not a replaced bundle.
+func openBundle(ctx context.Context, bundlePath, keyPath string, insecure bool) (*ruleBundle, error) {
+	logger := util.LoggerFromContext(ctx)
+	if keyPath == "" {
+		if !insecure {
+			return nil, ex.Newf("bundle %s is not verified without --%s; pass --%s to build with it "+
+				"on its checksum only", bundlePath, bundleKeyFlag, bundleInsecureFlag)
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: the signature of rule bundle %s is not verified (--%s)\n",
+			bundlePath, bundleInsecureFlag)
+		logger.WarnContext(ctx, "rule bundle signature is not verified, "+
+			"a replaced bundle would go unnoticed", "bundle", bundlePath)
+		report.FromContext(ctx).AddWarning("", fmt.Sprintf("rule bundle %s was used without --%s",
+			bundlePath, bundleKeyFlag))
+	}
+
+	f, digest, err := openVerifiedBundle(bundlePath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// The directory is keyed on the digest, so what a build extracted is
+	// only reused for the same, verified, bundle.
+	dir := util.GetBuildTemp(filepath.Join(bundleDir, digest[:16]))
+	manifest := new(BundleManifest)
+	if data, readErr := os.ReadFile(filepath.Join(dir, bundleManifestFile)); readErr == nil {
+		if err = json.Unmarshal(data, manifest); err != nil {
+			return nil, ex.Wrapf(err, "parsing the manifest of bundle %s", bundlePath)
+		}
+	} else {
+		tmp := dir + ".tmp"
+		if err = os.RemoveAll(tmp); err != nil {
+			return nil, ex.Wrapf(err, "removing %s", tmp)
+		}
+		if manifest, err = extractBundle(f, bundlePath, digest, tmp); err != nil {
+			return nil, err
+		}
+		if err = os.Rename(tmp, dir); err != nil {
+			return nil, ex.Wrapf(err, "extracting bundle %s", bundlePath)
+		}
+	}
+
+	logger.InfoContext(ctx, "using rule bundle", "bundle", bundlePath, "version", manifest.Version,
+		"modules", len(manifest.Modules), "signed", keyPath != "")
+	if manifest.OtelcVersion != util.Version {
+		logger.WarnContext(ctx, "rule bundle was created by another otelc version, "+
+			"the dependencies of the otelc runtime may not be bundled",
+			"bundle", manifest.OtelcVersion, "otelc", util.Version)
+		report.FromContext(ctx).AddWarning("", fmt.Sprintf("rule bundle was created by otelc %s, not %s",
+			manifest.OtelcVersion, util.Version))
+	}
+	return &ruleBundle{dir: dir, manifest: manifest}, nil
+}
+
+// modulesDir returns the directory the bundled modules are extracted to.
+func (b *ruleBundle) modulesDir() string {
+	if b == nil {
+		return ""
+	}
+	return filepath.Join(b.dir, bundleModulesDir)
+}
+
+// modules returns the directories of the bundled modules, keyed by module path.
+func (b *ruleBundle) modules() map[string]string {
+	if b == nil {
+		return nil
+	}
+	modules := make(map[string]string, len(b.manifest.Modules))
+	for _, m := range b.manifest.Modules {
+		modules[m.Path] = filepath.Join(b.modulesDir(), filepath.FromSlash(m.Path))
+	}
+	return modules
+}
+
+// useProxy makes the go commands of the build download modules from the
+// bundle before any proxy that GOPROXY already lists.
+func (b *ruleBundle) useProxy(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	cache := filepath.ToSlash(filepath.Join(b.dir, bundleCacheDir))
+	if !strings.HasPrefix(cache, "/") {
+		cache = "/" + cache // A Windows drive letter
+	}
+	proxy := (&url.URL{Scheme: "file", Path: cache}).String()
+
+	out, err := exec.CommandContext(ctx, "go", "env", "GOPROXY").Output()
+	if err != nil {
+		return ex.Wrapf(err, "failed to get GOPROXY environment variable")
+	}
+	current := strings.TrimSpace(string(out))
+	if strings.HasPrefix(current, proxy) {
+		return nil
+	}
+	if current != "" {
+		proxy += "," + current
+	}
+	// Mutates GOPROXY process-wide, as setup does GOFLAGS for vendored
+	// builds, so the toolexec build resolves modules the same way.
+	if err = os.Setenv("GOPROXY", proxy); err != nil {
+		return ex.Wrapf(err, "setting GOPROXY for bundle")
+	}
+	return nil
+}
+
+// applyTo replaces the bundled modules with their sources in the go.mod at
+// goModPath and requires the bundled dependencies it does not require yet. It
+// adds their checksums to its go.sum, so that the go command verifies them
+// without the checksum database.
+func (b *ruleBundle) applyTo(ctx context.Context, goModPath string) error {
+	if b == nil {
+		return nil
+	}
+	mf, err := parseGoMod(goModPath)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]string, len(mf.Replace))
+	for _, r := range mf.Replace {
+		if r.Old.Version == "" {
+			current[r.Old.Path] = r.New.Path
+		}
+	}
+	modules := b.modules()
+	changed := false
+	for _, modPath := range slices.Sorted(maps.Keys(modules)) {
+		if current[modPath] == modules[modPath] {
+			continue
+		}
+		// Unlike syncDeps, override an existing replace: a bundle wins over
+		// the embedded instrumentation an earlier setup replaced it with.
+		if err = mf.AddReplace(modPath, "", modules[modPath], ""); err != nil {
+			return ex.Wrapf(err, "failed to add replace directive")
+		}
+		changed = true
+		util.LoggerFromContext(ctx).InfoContext(ctx, "Replace dependency", "old", modPath, "new", modules[modPath])
+	}
+
+	// Requiring the bundled dependencies lets go mod tidy find the packages
+	// the instrumentation imports without looking up their latest version,
+	// which fails without network access. Tidy drops what is not imported.
+	deps, err := parseGoMod(filepath.Join(b.dir, bundleModFile))
+	if err != nil {
+		return err
+	}
+	required := make(map[string]bool, len(mf.Require))
+	for _, req := range mf.Require {
+		required[req.Mod.Path] = true
+	}
+	for _, req := range deps.Require {
+		if required[req.Mod.Path] || strings.HasPrefix(req.Mod.Path, util.OtelcRoot+"/") || modules[req.Mod.Path] != "" {
+			continue
+		}
+		mf.AddNewRequire(req.Mod.Path, req.Mod.Version, true)
+		changed = true
+	}
+
+	if changed {
+		if err = writeGoMod(goModPath, mf); err != nil {
+			return ex.Wrapf(err, "writing updated go.mod at %s", goModPath)
+		}
+	}
+	return b.addSums(strings.TrimSuffix(goModPath, ".mod") + ".sum")
+}
+
+func (b *ruleBundle) addSums(goSumPath string) error {
+	bundled, err := os.ReadFile(filepath.Join(b.dir, bundleSumFile))
+	if err != nil {
+		return ex.Wrapf(err, "reading the checksums of the bundle")
+	}
+	existing, err := os.ReadFile(goSumPath)
+	if err != nil && !os.IsNotExist(err) {
+		return ex.Wrapf(err, "reading %s", goSumPath)
+	}
+	known := make(map[string]bool)
+	for line := range strings.Lines(string(existing)) {
+		known[strings.TrimSpace(line)] = true
+	}
+	sums := bytes.NewBuffer(existing)
+	if sums.Len() > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		sums.WriteByte('\n')
+	}
+	added := false
+	for line := range strings.Lines(string(bundled)) {
+		if line = strings.TrimSpace(line); line != "" && !known[line] {
+			sums.WriteString(line + "\n")
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+	if err = util.WriteFileAtomic(goSumPath, sums.Bytes()); err != nil {
+		return ex.Wrapf(err, "writing %s", goSumPath)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+const stubInstGoMod = `module example.com/greet
+
+go 1.25.0
+`
+
+// newBundleWorkDir sets up a work directory for creating bundles. The
+// dependencies of the otelc runtime are served from the local module cache,
+// falling back to the GOPROXY of the environment.
+func newBundleWorkDir(t *testing.T) {
+	t.Helper()
+	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
+	require.NoError(t, os.MkdirAll(util.GetBuildTempDir(), 0o755))
+
+	out, err := exec.Command("go", "env", "GOMODCACHE", "GOPROXY").Output()
+	require.NoError(t, err)
+	env := strings.Fields(string(out))
+	require.Len(t, env, 2)
+	t.Setenv("GOPROXY", "file://"+filepath.ToSlash(filepath.Join(env[0], "cache", "download"))+","+env[1])
+	t.Setenv("GOSUMDB", "off")
+}
+
+func writeStubInstrumentation(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(stubInstGoMod), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "greet.otelc.yml"), []byte(stubRules), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden"), []byte("skipped"), 0o644))
+	return dir
+}
+
+func writeKeyPair(t *testing.T) (string, string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "key.pem")
+	publicPath := filepath.Join(dir, "pub.pem")
+	require.NoError(t, os.WriteFile(privatePath,
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600))
+	require.NoError(t, os.WriteFile(publicPath,
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o644))
+	return privatePath, publicPath
+}
+
+func TestBundle(t *testing.T) {
+	newBundleWorkDir(t)
+	ctx := util.ContextWithLogger(t.Context(), discardLogger(t))
+	module := writeStubInstrumentation(t)
+	privateKey, publicKey := writeKeyPair(t)
+
+	output := filepath.Join(t.TempDir(), "rules.tgz")
+	manifest, err := CreateBundle(ctx, BundleOptions{
+		Output:  output,
+		Version: "1.0.0",
+		Modules: []string{module},
+		Key:     privateKey,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", manifest.Version)
+	require.Len(t, manifest.Modules, 1)
+	assert.Equal(t, "example.com/greet", manifest.Modules[0].Path)
+	assert.True(t, strings.HasPrefix(manifest.Modules[0].Hash, "h1:"))
+
+	t.Run("is reproducible", func(t *testing.T) {
+		again := filepath.Join(t.TempDir(), "rules.tgz")
+		_, err := CreateBundle(ctx, BundleOptions{Output: again, Version: "1.0.0", Modules: []string{module}})
+		require.NoError(t, err)
+		want, err := os.ReadFile(output)
+		require.NoError(t, err)
+		got, err := os.ReadFile(again)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("verifies", func(t *testing.T) {
+		verified, err := VerifyBundle(output, publicKey)
+		require.NoError(t, err)
+		assert.Equal(t, manifest, verified)
+	})
+
+	t.Run("rejects another key", func(t *testing.T) {
+		_, otherKey := writeKeyPair(t)
+		_, err := VerifyBundle(output, otherKey)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match the key")
+	})
+
+	t.Run("rejects an unsigned bundle given a key", func(t *testing.T) {
+		unsigned := filepath.Join(t.TempDir(), "rules.tgz")
+		require.NoError(t, util.CopyFile(output, unsigned))
+		require.NoError(t, util.CopyFile(output+BundleChecksumExt, unsigned+BundleChecksumExt))
+		_, err := VerifyBundle(unsigned, "")
+		require.NoError(t, err)
+		_, err = VerifyBundle(unsigned, publicKey)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not signed")
+	})
+
+	t.Run("rejects a modified bundle", func(t *testing.T) {
+		tampered := filepath.Join(t.TempDir(), "rules.tgz")
+		data, err := os.ReadFile(output)
+		require.NoError(t, err)
+		data[len(data)/2] ^= 0xff
+		require.NoError(t, os.WriteFile(tampered, data, 0o644))
+		require.NoError(t, util.CopyFile(output+BundleChecksumExt, tampered+BundleChecksumExt))
+		_, err = VerifyBundle(tampered, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match its checksum")
+	})
+
+	t.Run("extracts the verified file", func(t *testing.T) {
+		bundlePath := filepath.Join(t.TempDir(), "rules.tgz")
+		require.NoError(t, util.CopyFile(output, bundlePath))
+		require.NoError(t, util.CopyFile(output+BundleChecksumExt, bundlePath+BundleChecksumExt))
+		other := filepath.Join(t.TempDir(), "other.tgz")
+		_, err := CreateBundle(ctx, BundleOptions{Output: other, Version: "2.0.0", Modules: []string{module}})
+		require.NoError(t, err)
+
+		// A bundle swapped for another once verified is not used
+		f, digest, err := openVerifiedBundle(bundlePath, "")
+		require.NoError(t, err)
+		defer f.Close()
+		require.NoError(t, os.Rename(other, bundlePath))
+		extracted, err := extractBundle(f, bundlePath, digest, t.TempDir())
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", extracted.Version)
+
+		// Nor is one rewritten in place
+		rewritten := filepath.Join(t.TempDir(), "rules.tgz")
+		require.NoError(t, util.CopyFile(output, rewritten))
+		require.NoError(t, util.CopyFile(output+BundleChecksumExt, rewritten+BundleChecksumExt))
+		f2, digest2, err := openVerifiedBundle(rewritten, "")
+		require.NoError(t, err)
+		defer f2.Close()
+		data, err := os.ReadFile(bundlePath)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(rewritten, data, 0o644))
+		_, err = extractBundle(f2, rewritten, digest2, t.TempDir())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "changed after it was verified")
+	})
+
+	t.Run("refuses a build without a key unless insecure", func(t *testing.T) {
+		_, err := openBundle(ctx, output, "", false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--"+bundleInsecureFlag)
+		bundle, err := openBundle(ctx, output, "", true)
+		require.NoError(t, err)
+		assert.Contains(t, bundle.modules(), "example.com/greet")
+	})
+
+	t.Run("opens for a build", func(t *testing.T) {
+		bundle, err := openBundle(ctx, output, publicKey, false)
+		require.NoError(t, err)
+		dir := bundle.modules()["example.com/greet"]
+		assert.FileExists(t, filepath.Join(dir, "greet.otelc.yml"))
+		assert.NoFileExists(t, filepath.Join(dir, ".hidden"))
+
+		// The dependencies of the runtime are served from the bundle
+		runtimeMod, err := parseGoMod(filepath.Join(util.GetBuildTempDir(), unzippedPkgDir, "runtime", "go.mod"))
+		require.NoError(t, err)
+		require.NotEmpty(t, runtimeMod.Require)
+		dep := runtimeMod.Require[0].Mod
+		escaped := strings.ToLower(dep.Path) // The test dependencies have no upper case letters
+		assert.FileExists(t, filepath.Join(bundle.dir, bundleCacheDir, escaped, "@v", dep.Version+".mod"))
+
+		rules, err := loadMinimalRules(bundle.modulesDir())
+		require.NoError(t, err)
+		assert.Contains(t, rules, "example.com/greet")
+	})
+}
+
+func TestSelectBundleModules(t *testing.T) {
+	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
+	require.NoError(t, extractOtelcBundle())
+
+	t.Run("embedded module with its requirements", func(t *testing.T) {
+		modules, err := selectBundleModules([]string{util.OtelcInstRoot + "/net/http/client"})
+		require.NoError(t, err)
+		assert.Contains(t, modules, util.OtelcInstRoot+"/net/http/client")
+		assert.Contains(t, modules, util.OtelcInstRoot)
+		assert.NotContains(t, modules, util.OtelcInstRoot+"/net/http/server")
+	})
+
+	t.Run("embedded modules below a path", func(t *testing.T) {
+		modules, err := selectBundleModules([]string{util.OtelcInstRoot + "/net/http"})
+		require.NoError(t, err)
+		assert.Contains(t, modules, util.OtelcInstRoot+"/net/http/client")
+		assert.Contains(t, modules, util.OtelcInstRoot+"/net/http/server")
+	})
+
+	t.Run("all embedded modules", func(t *testing.T) {
+		modules, err := selectBundleModules(nil)
+		require.NoError(t, err)
+		assert.Contains(t, modules, util.OtelcInstRoot+"/net/http/client")
+		assert.Contains(t, modules, util.OtelcInstRoot+"/runtime")
+	})
+
+	t.Run("unknown module", func(t *testing.T) {
+		_, err := selectBundleModules([]string{"example.com/unknown"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "neither a directory nor an embedded instrumentation module")
+	})
+}
+
+func TestBundleApplyTo(t *testing.T) {
+	ctx := util.ContextWithLogger(t.Context(), discardLogger(t))
+	bundle := &ruleBundle{
+		dir:      t.TempDir(),
+		manifest: &BundleManifest{Modules: []BundleModule{{Path: util.OtelcInstRoot + "/net/http/client"}}},
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(bundle.dir, bundleModFile), []byte("module "+bundleDepsModule+"\n\n"+
+		"require (\n\texample.com/dep v1.0.0\n\texample.com/app/lib v1.0.0\n\t"+util.OtelcPkgRoot+" v0.0.0\n)\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(bundle.dir, bundleSumFile),
+		[]byte("example.com/dep v1.0.0 h1:abc=\nexample.com/dep v1.0.0/go.mod h1:def=\n"), 0o644))
+
+	moduleDir := t.TempDir()
+	goMod := filepath.Join(moduleDir, "go.mod")
+	require.NoError(t, os.WriteFile(goMod, []byte("module example.com/app\n\ngo 1.25.0\n\n"+
+		"require example.com/app/lib v1.1.0\n\n"+
+		"replace "+util.OtelcInstRoot+"/net/http/client => /embedded/net/http/client\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "go.sum"),
+		[]byte("example.com/dep v1.0.0 h1:abc=\n"), 0o644))
+
+	require.NoError(t, bundle.applyTo(ctx, goMod))
+
+	mf, err := parseGoMod(goMod)
+	require.NoError(t, err)
+	require.Len(t, mf.Replace, 1)
+	assert.Equal(t, bundle.modules()[util.OtelcInstRoot+"/net/http/client"], mf.Replace[0].New.Path)
+
+	// The bundled dependencies are required, but not over the module's own
+	// requirements or the modules otelc replaces
+	require.Len(t, mf.Require, 2)
+	assert.Equal(t, "example.com/app/lib", mf.Require[0].Mod.Path)
+	assert.Equal(t, "v1.1.0", mf.Require[0].Mod.Version)
+	assert.Equal(t, "example.com/dep", mf.Require[1].Mod.Path)
+	assert.Equal(t, "v1.0.0", mf.Require[1].Mod.Version)
+	assert.True(t, mf.Require[1].Indirect)
+
+	sums, err := os.ReadFile(filepath.Join(moduleDir, "go.sum"))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/dep v1.0.0 h1:abc=\nexample.com/dep v1.0.0/go.mod h1:def=\n", string(sums))
+
+	// A nil bundle leaves the module alone
+	require.NoError(t, (*ruleBundle)(nil).applyTo(ctx, filepath.Join(t.TempDir(), "go.mod")))
+}
@@ -19,7 +19,6 @@ import (
 	"go.opentelemetry.io/otelc/tool/ex"
 	"go.opentelemetry.io/otelc/tool/internal/ast"
 	"go.opentelemetry.io/otelc/tool/internal/pkgload"
-	"go.opentelemetry.io/otelc/tool/util"
 )
 
 const (
@@ -38,12 +37,13 @@ type InstrumentationConfig struct {
 //nolint:forbidigo // sentinel error; must not carry mutable stack state
 var ErrNotInstrumentation = errors.New("not an instrumentation package")
 
-func findToolFile(moduleDir string) (string, error) {
+func findToolFile(ctx context.Context, moduleDir string) (string, error) {
 	canonical := filepath.Join(moduleDir, ToolFileCanonical)
 	alias := filepath.Join(moduleDir, ToolFileAlias)
 
-	canonicalExists := util.PathExists(canonical)
-	aliasExists := util.PathExists(alias)
+	shadow := shadowFromContext(ctx)
+	canonicalExists := shadow.exists(canonical)
+	aliasExists := shadow.exists(alias)
 
 	switch {
 	case canonicalExists && aliasExists:
@@ -61,10 +61,10 @@ func findToolFile(moduleDir string) (string, error) {
 	}
 }
 
-func findToolFiles(moduleDirs map[string]bool) ([]string, error) {
+func findToolFiles(ctx context.Context, moduleDirs map[string]bool) ([]string, error) {
 	toolFiles := make([]string, 0, len(moduleDirs))
 	for dir := range moduleDirs {
-		toolFile, err := findToolFile(dir)
+		toolFile, err := findToolFile(ctx, dir)
 		if err != nil {
 			return nil, err
 		}
@@ -95,9 +95,10 @@ func resolveInstrumentationConfigs(
 	defer cancel()
 
 	pkgs, loadErr := packages.Load(&packages.Config{
-		Mode:    packages.NeedFiles | packages.NeedModule | packages.NeedName,
-		Context: ctx,
-		Dir:     dir,
+		Mode:       packages.NeedFiles | packages.NeedModule | packages.NeedName,
+		Context:    ctx,
+		Dir:        dir,
+		BuildFlags: shadowFromContext(ctx).flags(),
 	}, importPaths...)
 	if loadErr != nil {
 		return nil, ex.Wrapf(loadErr, "failed to load instrumentation packages")
@@ -132,7 +133,7 @@ func resolveInstrumentationConfigs(
 		}
 
 		// Always look for tool file in the module directory
-		toolFile, findErr := findToolFile(modDir)
+		toolFile, findErr := findToolFile(ctx, modDir)
 		if findErr != nil {
 			cfgs[importPath].Error = ex.Wrapf(
 				findErr,
@@ -237,7 +238,7 @@ func walkInstrumentation(ctx context.Context, toolFiles []string, visit Instrume
 		toolFile := queue[0]
 		queue = queue[1:]
 
-		f, parseErr := p.Parse(toolFile, parser.ImportsOnly)
+		f, parseErr := p.Parse(shadowFromContext(ctx).source(toolFile), parser.ImportsOnly)
 		if parseErr != nil {
 			return parseErr
 		}
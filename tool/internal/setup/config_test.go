@@ -70,7 +70,7 @@ func TestFindToolFile(t *testing.T) {
 			dir := t.TempDir()
 			tt.setup(dir)
 
-			got, err := findToolFile(dir)
+			got, err := findToolFile(t.Context(), dir)
 			if tt.wantErr {
 				require.Error(t, err)
 				return
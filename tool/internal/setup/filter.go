@@ -4,6 +4,7 @@
 package setup
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/dave/dst"
@@ -59,6 +60,10 @@ type MatchContext struct {
 	// AST is the parsed dst tree of the source file. Filters must treat it
 	// as read-only; node updates would corrupt downstream rule matching.
 	AST *dst.File
+
+	// Target is what the build compiles for. Like IsTest, it is identical for
+	// every file of a build.
+	Target BuildTarget
 }
 
 // --- Leaf filters ---
@@ -68,6 +73,7 @@ var (
 	_ Filter = (*StructFilter)(nil)
 	_ Filter = (*PackageNameFilter)(nil)
 	_ Filter = (*IsTestFilter)(nil)
+	_ Filter = (*BuildFilter)(nil)
 )
 
 // FuncFilter matches source files that declare the named function or method.
@@ -128,6 +134,29 @@ func (f *IsTestFilter) Match(ctx *MatchContext) bool {
 	return f.ShouldMatch == ctx.IsTest
 }
 
+// BuildFilter selects builds by their target (see MatchContext.Target). An
+// empty GOOS or GOARCH matches any; every tag in Tags must be set.
+type BuildFilter struct {
+	GOOS   string
+	GOARCH string
+	Tags   []string
+}
+
+func (f *BuildFilter) Match(ctx *MatchContext) bool {
+	if f.GOOS != "" && f.GOOS != ctx.Target.GOOS {
+		return false
+	}
+	if f.GOARCH != "" && f.GOARCH != ctx.Target.GOARCH {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !slices.Contains(ctx.Target.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
 // --- Combinators ---
 
 var _ Filter = (AllOf)(nil)
@@ -221,7 +250,7 @@ func Build(where *rule.WhereDef) (Filter, error) {
 func hasLeafPredicate(def *rule.FilterDef) bool {
 	return def.HasFunc != "" || def.HasRecv != "" ||
 		def.HasStruct != "" || def.HasDirective != "" ||
-		strings.TrimSpace(def.HasPackage) != "" || def.IsTest != nil
+		strings.TrimSpace(def.HasPackage) != "" || def.IsTest != nil || def.Build != nil
 }
 
 //nolint:nilnil // unreachable default branch is guarded by util.ShouldNotReachHere
@@ -292,6 +321,9 @@ func buildFile(def *rule.FilterDef) (Filter, error) {
 	if def.IsTest != nil {
 		active++
 	}
+	if def.Build != nil {
+		active++
+	}
 
 	if active == 0 {
 		return nil, ex.Newf("where.file has no active predicate")
@@ -311,6 +343,8 @@ func buildFile(def *rule.FilterDef) (Filter, error) {
 		return &PackageNameFilter{Name: strings.TrimSpace(def.HasPackage)}, nil
 	case def.IsTest != nil:
 		return &IsTestFilter{ShouldMatch: *def.IsTest}, nil
+	case def.Build != nil:
+		return buildBuild(def.Build)
 	default:
 		// The active-predicate counter above proves at least one leaf is set;
 		// matching the convention in match.go / instrument.go / trampoline.go,
@@ -320,6 +354,26 @@ func buildFile(def *rule.FilterDef) (Filter, error) {
 	}
 }
 
+// buildBuild compiles a where.file.build predicate. Tags are build tags, which
+// never contain commas or spaces: a list is written as a YAML sequence.
+func buildBuild(def *rule.BuildDef) (Filter, error) {
+	f := &BuildFilter{
+		GOOS:   strings.TrimSpace(def.GOOS),
+		GOARCH: strings.TrimSpace(def.GOARCH),
+	}
+	for _, tag := range def.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || strings.ContainsAny(tag, ", ") {
+			return nil, ex.Newf("where.file.build.tags has an invalid tag %q", tag)
+		}
+		f.Tags = append(f.Tags, tag)
+	}
+	if f.GOOS == "" && f.GOARCH == "" && len(f.Tags) == 0 {
+		return nil, ex.Newf("where.file.build needs goos, goarch or tags")
+	}
+	return f, nil
+}
+
 // buildChildren compiles each child of a where.file combinator group with the
 // same buildFile rules, so nesting (a combinator within a combinator) composes
 // naturally. The caller converts the result to the concrete combinator type
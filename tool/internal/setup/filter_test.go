@@ -6,6 +6,7 @@ package setup_test
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -170,6 +171,41 @@ func TestPackageNameFilter_Match(t *testing.T) {
 	}
 }
 
+func TestBuildFilter_Match(t *testing.T) {
+	target := setup.BuildTarget{GOOS: "linux", GOARCH: "amd64", Tags: []string{"netgo", "osusergo"}}
+	tests := []struct {
+		name   string
+		filter setup.BuildFilter
+		want   bool
+	}{
+		{name: "goos matches", filter: setup.BuildFilter{GOOS: "linux"}, want: true},
+		{name: "goos does not match", filter: setup.BuildFilter{GOOS: "windows"}, want: false},
+		{name: "goarch matches", filter: setup.BuildFilter{GOARCH: "amd64"}, want: true},
+		{name: "goarch does not match", filter: setup.BuildFilter{GOARCH: "arm64"}, want: false},
+		{name: "all tags set", filter: setup.BuildFilter{Tags: []string{"osusergo", "netgo"}}, want: true},
+		{name: "a tag is not set", filter: setup.BuildFilter{Tags: []string{"netgo", "purego"}}, want: false},
+		{
+			name:   "every field matches",
+			filter: setup.BuildFilter{GOOS: "linux", GOARCH: "amd64", Tags: []string{"netgo"}},
+			want:   true,
+		},
+		{
+			name:   "one field does not match",
+			filter: setup.BuildFilter{GOOS: "linux", GOARCH: "arm64", Tags: []string{"netgo"}},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &setup.MatchContext{Target: target}
+			if got := tt.filter.Match(ctx); got != tt.want {
+				t.Fatalf("%+v.Match(%v) = %v, want %v", tt.filter, target, got, tt.want)
+			}
+		})
+	}
+}
+
 // --- Build ---
 
 func TestBuild_NilWhere(t *testing.T) {
@@ -276,6 +312,21 @@ func TestBuild_PackageNameFilter(t *testing.T) {
 	}
 }
 
+func TestBuild_BuildFilter(t *testing.T) {
+	where := &rule.WhereDef{File: &rule.FilterDef{Build: &rule.BuildDef{GOOS: " linux ", Tags: []string{"netgo"}}}}
+	f, err := setup.Build(where)
+	if err != nil {
+		t.Fatalf("Build(Build=%+v) error = %v, want nil", where.File.Build, err)
+	}
+	bf, ok := f.(*setup.BuildFilter)
+	if !ok {
+		t.Fatalf("Build() returned %T, want *setup.BuildFilter", f)
+	}
+	if bf.GOOS != "linux" || bf.GOARCH != "" || len(bf.Tags) != 1 || bf.Tags[0] != "netgo" {
+		t.Errorf("BuildFilter = %+v, want goos=linux tags=[netgo]", bf)
+	}
+}
+
 func TestBuild_ErrorCases(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -297,6 +348,18 @@ func TestBuild_ErrorCases(t *testing.T) {
 			name:  "is_test combined with another predicate",
 			where: &rule.WhereDef{File: &rule.FilterDef{HasFunc: "Foo", IsTest: boolPtr(true)}},
 		},
+		{
+			name:  "build without a field",
+			where: &rule.WhereDef{File: &rule.FilterDef{Build: &rule.BuildDef{}}},
+		},
+		{
+			name:  "build with a comma-separated tag list",
+			where: &rule.WhereDef{File: &rule.FilterDef{Build: &rule.BuildDef{Tags: []string{"netgo,osusergo"}}}},
+		},
+		{
+			name:  "build combined with another predicate",
+			where: &rule.WhereDef{File: &rule.FilterDef{HasFunc: "Foo", Build: &rule.BuildDef{GOOS: "linux"}}},
+		},
 		{
 			// A combinator owns the node: is_test as a sibling must be rejected,
 			// not silently ignored (regression guard for hasLeafPredicate).
@@ -644,12 +707,15 @@ func TestNot_Match(t *testing.T) {
 }
 
 type filterExpected struct {
-	Type        string `yaml:"type"`
-	Func        string `yaml:"func"`
-	Recv        string `yaml:"recv"`
-	Struct      string `yaml:"struct"`
-	Package     string `yaml:"package"`
-	ShouldMatch *bool  `yaml:"should_match"`
+	Type        string   `yaml:"type"`
+	Func        string   `yaml:"func"`
+	Recv        string   `yaml:"recv"`
+	Struct      string   `yaml:"struct"`
+	Package     string   `yaml:"package"`
+	ShouldMatch *bool    `yaml:"should_match"`
+	GOOS        string   `yaml:"goos"`
+	GOARCH      string   `yaml:"goarch"`
+	Tags        []string `yaml:"tags"`
 	// Children describes the expected sub-filters for combinator types
 	// (e.g. AllOf). It is nil for leaf filters.
 	Children []filterExpected `yaml:"children"`
@@ -756,6 +822,14 @@ func assertBuiltFilter(t *testing.T, name string, got setup.Filter, want filterE
 		if itf.ShouldMatch != *want.ShouldMatch {
 			t.Fatalf("Build(%q) IsTestFilter.ShouldMatch = %v, want %v", name, itf.ShouldMatch, *want.ShouldMatch)
 		}
+	case "BuildFilter":
+		bf, ok := got.(*setup.BuildFilter)
+		if !ok {
+			t.Fatalf("Build(%q) = %T, want *setup.BuildFilter", name, got)
+		}
+		if bf.GOOS != want.GOOS || bf.GOARCH != want.GOARCH || !slices.Equal(bf.Tags, want.Tags) {
+			t.Fatalf("Build(%q) = %+v, want goos=%q goarch=%q tags=%v", name, bf, want.GOOS, want.GOARCH, want.Tags)
+		}
 	case "AllOf", "OneOf", "Not":
 		assertBuiltCombinator(t, name, got, want)
 	default:
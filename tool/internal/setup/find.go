@@ -94,7 +94,7 @@ func listBuildPlan(ctx context.Context, subcommand string, cmdArgs []string) ([]
 		planVerb = subcmdTest
 	}
 	// The full command is: "go build/test -a -x -n {...}"
-	prefix := []string{planVerb, "-a", "-x", "-n"}
+	prefix := append([]string{planVerb, "-a", "-x", "-n"}, shadowFromContext(ctx).flags()...)
 	args := make([]string, 0, len(prefix)+len(cmdArgs))
 	args = append(args, prefix...)
 	args = append(args, cmdArgs...) // args from original build/install or setup command
@@ -14,6 +14,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/dave/dst"
 	"golang.org/x/sync/errgroup"
@@ -21,6 +22,7 @@ import (
 
 	"go.opentelemetry.io/otelc/tool/ex"
 	"go.opentelemetry.io/otelc/tool/internal/ast"
+	"go.opentelemetry.io/otelc/tool/internal/report"
 	"go.opentelemetry.io/otelc/tool/internal/rule"
 	"go.opentelemetry.io/otelc/tool/util"
 )
@@ -262,11 +264,19 @@ func (sp *SetupPhase) preciseMatching(
 		ruleFilters = append(ruleFilters, ruleFilter{rule: r, where: f})
 	}
 
+	// An unchanged dependency matched against unchanged rules is answered
+	// from the match cache without parsing a single source file.
+	key, digests, cached := sp.matchFromCache(dep, rules, set)
+	if cached {
+		return set, nil
+	}
+	entry := &matchCacheEntry{Matches: make([]cacheMatch, 0)}
+
 	// IsTest is a property of the whole compile (every file in a test build
 	// shares it), so compute it once and reuse it across each file's context.
 	isTest := isTestBuild(dep.Sources)
 
-	for _, source := range dep.Sources {
+	for i, source := range dep.Sources {
 		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
@@ -292,19 +302,33 @@ func (sp *SetupPhase) preciseMatching(
 			IsTest:     isTest,
 			SourceFile: source,
 			AST:        tree,
+			Target:     sp.target,
 		}
 
-		for _, rf := range ruleFilters {
+		for j, rf := range ruleFilters {
 			// Evaluate the where filter if one is defined for this rule.
 			// A nil filter means the rule applies to all files unconditionally.
 			if rf.where != nil && !rf.where.Match(&mctx) {
 				continue
 			}
-			if err = sp.matchOneRule(tree, source, rf.rule, set, dep); err != nil {
-				return nil, err
+			ok, matchErr := matchOneRule(tree, rf.rule)
+			if matchErr != nil {
+				return nil, matchErr
+			}
+			if ok {
+				sp.addMatchedRule(source, rf.rule, set, dep)
+				if key != "" {
+					entry.Matches = append(entry.Matches, cacheMatch{Source: i, Rule: digests[j]})
+				}
 			}
 		}
 	}
+	if key != "" {
+		entry.PackageName = set.PackageName
+		if err := sp.matchCache.put(key, entry); err != nil {
+			sp.Debug("Failed to store match cache entry", "dep", dep.ImportPath, "error", err)
+		}
+	}
 	return set, nil
 }
 
@@ -334,63 +358,61 @@ func isTestBuild(sources []string) bool {
 }
 
 // matchOneRule performs precise AST matching for a single rule against a parsed
-// source file, adding the rule to the set if it matches.
-func (sp *SetupPhase) matchOneRule(
-	tree *dst.File,
-	source string,
-	r rule.InstRule,
-	set *rule.InstRuleSet,
-	dep *Dependency,
-) error {
+// source file, reporting whether the rule applies to the file.
+func matchOneRule(tree *dst.File, r rule.InstRule) (bool, error) {
 	switch rt := r.(type) {
 	case *rule.InstFuncRule:
 		_, ok, err := ast.FindFuncDecl(tree, rt)
-		if err != nil {
-			return err
-		}
-		if ok {
-			set.AddFuncRule(source, rt)
-			sp.Info("Match func rule", "rule", rt, "dep", dep)
-		}
+		return ok, err
 	case *rule.InstStructRule:
-		structDecl := ast.FindStructDecl(tree, rt.Struct)
-		if structDecl != nil {
-			set.AddStructRule(source, rt)
-			sp.Info("Match struct rule", "rule", rt, "dep", dep)
-		}
+		return ast.FindStructDecl(tree, rt.Struct) != nil, nil
 	case *rule.InstRawRule:
 		_, ok, err := ast.FindFuncDecl(tree, rt)
-		if err != nil {
-			return err
-		}
-		if ok {
-			set.AddRawRule(source, rt)
-			sp.Info("Match raw rule", "rule", rt, "dep", dep)
-		}
+		return ok, err
 	case *rule.InstCallRule:
 		// Call rules are added unconditionally to all source files in the
 		// target package. Unlike func/struct/raw rules, there is no cheap
 		// AST predicate to pre-filter files (the matching requires import
 		// alias resolution which happens during the instrument phase).
 		// Files without matching calls are a no-op in applyCallRule.
-		set.AddCallRule(source, rt)
-		sp.Info("Match call rule", "rule", rt, "dep", dep)
+		return true, nil
 	case *rule.InstDirectiveRule:
-		if ast.FileHasDirective(tree, rt.Directive) {
-			set.AddDirectiveRule(source, rt)
-			sp.Info("Match directive rule", "rule", rt, "dep", dep)
-		}
+		return ast.FileHasDirective(tree, rt.Directive), nil
 	case *rule.InstDeclRule:
-		if ast.FindNamedDecl(tree, rt.Identifier, rt.Kind) != nil {
-			set.AddDeclRule(source, rt)
-			sp.Info("Match decl rule", "rule", rt, "dep", dep)
-		}
+		return ast.FindNamedDecl(tree, rt.Identifier, rt.Kind) != nil, nil
 	case *rule.InstFileRule:
 		// Skip as it's already processed
+		return false, nil
+	default:
+		util.ShouldNotReachHere()
+	}
+	return false, nil
+}
+
+// addMatchedRule adds a rule that matched the source file to the set.
+func (sp *SetupPhase) addMatchedRule(source string, r rule.InstRule, set *rule.InstRuleSet, dep *Dependency) {
+	switch rt := r.(type) {
+	case *rule.InstFuncRule:
+		set.AddFuncRule(source, rt)
+		sp.Info("Match func rule", "rule", rt, "dep", dep)
+	case *rule.InstStructRule:
+		set.AddStructRule(source, rt)
+		sp.Info("Match struct rule", "rule", rt, "dep", dep)
+	case *rule.InstRawRule:
+		set.AddRawRule(source, rt)
+		sp.Info("Match raw rule", "rule", rt, "dep", dep)
+	case *rule.InstCallRule:
+		set.AddCallRule(source, rt)
+		sp.Info("Match call rule", "rule", rt, "dep", dep)
+	case *rule.InstDirectiveRule:
+		set.AddDirectiveRule(source, rt)
+		sp.Info("Match directive rule", "rule", rt, "dep", dep)
+	case *rule.InstDeclRule:
+		set.AddDeclRule(source, rt)
+		sp.Info("Match decl rule", "rule", rt, "dep", dep)
 	default:
 		util.ShouldNotReachHere()
 	}
-	return nil
 }
 
 func rulesFromDir(path string, skipSubmodules bool) ([]string, error) {
@@ -457,6 +479,11 @@ func loadCustomRules(ruleConfig string) ([]rule.InstRule, error) {
 			if err != nil {
 				return nil, err
 			}
+			source, absErr := filepath.Abs(file)
+			if absErr != nil {
+				return nil, ex.Wrap(absErr)
+			}
+			rule.SetOrigin(rules, rule.Origin{File: source})
 
 			// Group this file's rules by entry name, then replace any
 			// previously-seen entry of the same name as a single unit.
@@ -488,6 +515,7 @@ func loadRulesFromToolFiles(ctx context.Context, toolFiles []string) ([]rule.Ins
 			if parseErr != nil {
 				return false, parseErr
 			}
+			rule.SetOrigin(rules, rule.Origin{File: file, Instrumentation: v.Config.ImportPath})
 
 			ruleSet = append(ruleSet, rules...)
 		}
@@ -516,7 +544,7 @@ func (sp *SetupPhase) loadRules(ctx context.Context, moduleDirs map[string]bool)
 	}
 
 	// Load rules from tool files if available
-	toolFiles, err := findToolFiles(moduleDirs)
+	toolFiles, err := findToolFiles(ctx, moduleDirs)
 	if err != nil {
 		return nil, err
 	}
@@ -530,38 +558,28 @@ func (sp *SetupPhase) loadRules(ctx context.Context, moduleDirs map[string]bool)
 	return nil, nil
 }
 
-func (sp *SetupPhase) matchDeps(
+// indexRules splits rules into two matching tiers. Exact-target rules are
+// pre-indexed by import path so each dependency resolves them with one map
+// lookup (unchanged fast path). Glob-target rules cannot be keyed, so they are
+// kept in a flat slice and evaluated against every dependency's import path.
+func (sp *SetupPhase) indexRules(
 	ctx context.Context,
-	deps []*Dependency,
-	moduleDirs map[string]bool,
-) ([]*rule.InstRuleSet, error) {
-	// Construct the set of default allRules by parsing embedded data
-	allRules, err := sp.loadRules(ctx, moduleDirs)
-	if err != nil {
-		return nil, err
-	}
-	sp.Info("Found available rules", "rules", allRules)
-	if len(allRules) == 0 {
-		return nil, nil
-	}
-
-	// Split rules into two matching tiers. Exact-target rules are pre-indexed
-	// by import path so each dependency resolves them with one map lookup
-	// (unchanged fast path). Glob-target rules cannot be keyed, so they are
-	// kept in a flat slice and evaluated against every dependency's import path.
+	allRules []rule.InstRule,
+) (map[string][]rule.InstRule, []targetRule, error) {
 	exactRules := make(map[string][]rule.InstRule)
 	globRules := make([]targetRule, 0)
 	for _, r := range allRules {
 		target := r.GetTarget()
 		if rule.IsRootTarget(target) {
 			if len(sp.rootModulePaths) == 0 && len(sp.buildPackages) > 0 {
+				var err error
 				sp.rootModulePaths, err = rootModulePaths(ctx, sp.buildPackages)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 			}
 			if len(sp.rootModulePaths) == 0 {
-				return nil, ex.Newf("rule %q uses target %q, but no root module was found", r.GetName(), target)
+				return nil, nil, ex.Newf("rule %q uses target %q, but no root module was found", r.GetName(), target)
 			}
 			for _, root := range sp.rootModulePaths {
 				globRules = append(globRules, targetRule{target: root + "/**", rule: r})
@@ -574,8 +592,31 @@ func (sp *SetupPhase) matchDeps(
 		}
 		exactRules[target] = append(exactRules[target], r)
 	}
+	return exactRules, globRules, nil
+}
+
+func (sp *SetupPhase) matchDeps(
+	ctx context.Context,
+	deps []*Dependency,
+	moduleDirs map[string]bool,
+) ([]*rule.InstRuleSet, error) {
+	// Construct the set of default allRules by parsing embedded data
+	allRules, err := sp.loadRules(ctx, moduleDirs)
+	if err != nil {
+		return nil, err
+	}
+	sp.Info("Found available rules", "rules", allRules)
+	sp.report.AddRules(allRules)
+	if len(allRules) == 0 {
+		return nil, nil
+	}
+	exactRules, globRules, err := sp.indexRules(ctx, allRules)
+	if err != nil {
+		return nil, err
+	}
 
 	// Match the default rules with the found dependencies
+	start := time.Now()
 	matched := make([]*rule.InstRuleSet, 0)
 	var mu sync.Mutex
 	g, gCtx := errgroup.WithContext(ctx)
@@ -591,6 +632,7 @@ func (sp *SetupPhase) matchDeps(
 				mu.Lock()
 				matched = append(matched, m)
 				mu.Unlock()
+				sp.report.AddMatches(m, dep.Version)
 			}
 			return nil
 		})
@@ -599,9 +641,28 @@ func (sp *SetupPhase) matchDeps(
 	if err = g.Wait(); err != nil {
 		return nil, err
 	}
+	sp.report.AddDuration(report.PhaseMatch, time.Since(start))
+	if os.Getenv(util.EnvOtelcStats) != "" {
+		hits, misses := sp.matchCache.stats()
+		hitRate := 0.0
+		if lookups := hits + misses; lookups > 0 {
+			hitRate = float64(hits) / float64(lookups)
+		}
+		sp.Info("match stats",
+			"dependencies", len(deps),
+			"duration", time.Since(start),
+			"cache_hits", hits,
+			"cache_misses", misses,
+			"cache_hit_rate", fmt.Sprintf("%.1f%%", hitRate*100),
+		)
+	}
+	if err = checkApplied(allRules, matched, os.Getenv(util.EnvOtelcStrict) != ""); err != nil {
+		return nil, err
+	}
 	if len(matched) == 0 {
 		_, _ = fmt.Fprintf(os.Stderr, "Warning: no instrumentation will be applied\n")
 		sp.Warn("no instrumentation rules matched any dependencies")
+		sp.report.AddWarning("", "no instrumentation rules matched any dependencies")
 	}
 	return matched, nil
 }
@@ -13,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otelc/tool/internal/report"
 	"go.opentelemetry.io/otelc/tool/internal/rule"
 	"go.opentelemetry.io/otelc/tool/util"
 	"golang.org/x/tools/go/packages"
@@ -555,6 +556,7 @@ func TestDoSequenceLoadsAllExpandedRules(t *testing.T) {
 	require.Len(t, rules, 2)
 	for _, r := range rules {
 		require.Equal(t, "combo", r.GetName())
+		require.Equal(t, rule.Origin{File: p}, r.GetOrigin())
 	}
 
 	// Both modifiers must be represented: inject_hooks -> InstFuncRule and
@@ -617,6 +619,10 @@ func TestLoadRulesFromToolFiles(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, rules, 1)
 		require.Equal(t, "dummyrule", rules[0].GetName())
+		require.Equal(t, rule.Origin{
+			File:            filepath.Join(tmp, "foo", "dummy.otelc.yml"),
+			Instrumentation: "example.com/foo",
+		}, rules[0].GetOrigin())
 	})
 
 	t.Run("loads nested tool files recursively", func(t *testing.T) {
@@ -761,6 +767,45 @@ func TestPreciseMatching_WhereFileFilter(t *testing.T) {
 	require.Contains(t, result.FuncRules, matchFile)
 }
 
+func TestPreciseMatching_WhereFileBuild(t *testing.T) {
+	source := writeGoSource(t, "dial.go", "package netutil\n\nfunc Dial() {}\n")
+	dep := &Dependency{ImportPath: "example.com/netutil", Sources: []string{source}}
+	funcRule := &rule.InstFuncRule{
+		InstBaseRule: rule.InstBaseRule{
+			Name:   "test-where-file-build",
+			Target: "example.com/netutil",
+			Where: &rule.WhereDef{
+				File: &rule.FilterDef{Build: &rule.BuildDef{GOOS: "linux", Tags: []string{"netgo"}}},
+			},
+		},
+		Func:   "Dial",
+		Before: "BeforeDial",
+		Path:   "example.com/hooks",
+	}
+
+	for _, tt := range []struct {
+		target BuildTarget
+		match  bool
+	}{
+		{target: BuildTarget{GOOS: "linux", GOARCH: "amd64", Tags: []string{"netgo"}}, match: true},
+		{target: BuildTarget{GOOS: "linux", GOARCH: "amd64"}, match: false},
+		{target: BuildTarget{GOOS: "windows", GOARCH: "amd64", Tags: []string{"netgo"}}, match: false},
+	} {
+		t.Run(tt.target.String(), func(t *testing.T) {
+			sp := newTestSetupPhase()
+			sp.target = tt.target
+			result, err := sp.preciseMatching(t.Context(), dep, []rule.InstRule{funcRule},
+				rule.NewInstRuleSet(dep.ImportPath))
+			require.NoError(t, err)
+			if tt.match {
+				require.Contains(t, result.FuncRules, source)
+			} else {
+				require.Empty(t, result.FuncRules)
+			}
+		})
+	}
+}
+
 func TestPreciseMatching_WhereFileAllOf(t *testing.T) {
 	// all-of requires the file to declare BOTH a Handler func and a Server
 	// struct. Only match.go satisfies both; nomatch.go is gated out.
@@ -1488,3 +1533,88 @@ func TestMatchDeps_NoMatchesWarning(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, matched)
 }
+
+func TestMatchDeps_RecordsReport(t *testing.T) {
+	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
+	dir := t.TempDir()
+	ruleFile := filepath.Join(dir, "glob.yaml")
+	err := os.WriteFile(ruleFile, []byte(`glob_hook:
+  target: example.com/svc/**
+  func: Handler
+  before: BeforeHandler
+  path: "example.com/hooks"
+`), 0o644)
+	require.NoError(t, err)
+
+	usersSrc := writeGoSource(t, "users.go", "package users\n\nfunc Handler() {}\n")
+	otherSrc := writeGoSource(t, "other.go", "package other\n\nfunc Handler() {}\n")
+
+	sp := newTestSetupPhase()
+	sp.ruleConfig = ruleFile
+	sp.report = report.NewRecorder(nil)
+	_, err = sp.matchDeps(t.Context(), []*Dependency{
+		{ImportPath: "example.com/svc/users", Version: "v1.0.0", Sources: []string{usersSrc}},
+		{ImportPath: "example.com/other", Sources: []string{otherSrc}},
+	}, nil)
+	require.NoError(t, err)
+
+	got, err := sp.report.Build(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []report.Rule{{
+		Name: "glob_hook", Kind: "func", Target: "example.com/svc/**", Source: ruleFile,
+	}}, got.Rules)
+	assert.Equal(t, []report.Package{{
+		ImportPath: "example.com/svc/users",
+		Version:    "v1.0.0",
+		Matches:    []report.Match{{Rule: "glob_hook", Kind: "func", File: usersSrc}},
+	}}, got.Packages)
+	assert.Empty(t, got.Warnings)
+
+	t.Run("no matches", func(t *testing.T) {
+		sp.report = report.NewRecorder(nil)
+		_, err = sp.matchDeps(t.Context(), []*Dependency{
+			{ImportPath: "example.com/other", Sources: []string{otherSrc}},
+		}, nil)
+		require.NoError(t, err)
+		got, err = sp.report.Build(nil)
+		require.NoError(t, err)
+		assert.Empty(t, got.Packages)
+		assert.Equal(t, []report.Warning{{Message: "no instrumentation rules matched any dependencies"}}, got.Warnings)
+	})
+}
+
+func TestMatchDeps_RequiredRule(t *testing.T) {
+	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
+	ruleFile := filepath.Join(t.TempDir(), "required.yaml")
+	err := os.WriteFile(ruleFile, []byte(`users_hook:
+  target: example.com/svc/users
+  required: true
+  func: Handler
+  before: BeforeHandler
+  path: "example.com/hooks"
+`), 0o644)
+	require.NoError(t, err)
+
+	sp := newTestSetupPhase()
+	sp.ruleConfig = ruleFile
+	sp.matchCache = newMatchCache()
+	src := filepath.Join(t.TempDir(), "users.go")
+	matchUsers := func(source string) error {
+		require.NoError(t, os.WriteFile(src, []byte(source), 0o644))
+		_, matchErr := sp.matchDeps(t.Context(), []*Dependency{
+			{ImportPath: "example.com/svc/users", Version: "v1.0.0", Sources: []string{src}},
+		}, nil)
+		return matchErr
+	}
+
+	// The second match of the same source is answered by the match cache
+	require.NoError(t, matchUsers("package users\n\nfunc Handler() {}\n"))
+	require.NoError(t, matchUsers("package users\n\nfunc Handler() {}\n"))
+	hits, _ := sp.matchCache.stats()
+	assert.Equal(t, int64(1), hits)
+
+	// The library renamed the function
+	err = matchUsers("package users\n\nfunc Serve() {}\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `required func rule "users_hook" for example.com/svc/users matched nothing`)
+}
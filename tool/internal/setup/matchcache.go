@@ -0,0 +1,194 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/internal/rule"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+const (
+	// matchCacheDir holds the match cache in the build temp directory, next to
+	// the persistent go build cache.
+	matchCacheDir = "matchcache"
+	// matchCacheFormat is hashed into every key; bump it whenever the entry
+	// layout or the matching semantics change.
+	matchCacheFormat = "otelc-match-v1"
+)
+
+// matchCache remembers the result of preciseMatching across builds. Entries
+// are content addressed: the key covers the package import path and version,
+// the path and content of every source file, and the full definition of every
+// rule that was matched, so a changed source or rule simply misses.
+type matchCache struct {
+	dir    string
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// matchCacheEntry records the outcome of precise matching: the declared
+// package name and, for each match, the index of the source file and the
+// digest of the rule.
+type matchCacheEntry struct {
+	PackageName string       `json:"package_name"`
+	Matches     []cacheMatch `json:"matches"`
+}
+
+type cacheMatch struct {
+	Source int    `json:"source"`
+	Rule   string `json:"rule"`
+}
+
+func newMatchCache() *matchCache {
+	return &matchCache{dir: util.GetBuildTemp(matchCacheDir)}
+}
+
+// ruleDigest identifies a rule by its type and full definition.
+func ruleDigest(r rule.InstRule) (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", ex.Wrapf(err, "hashing rule %q", r.GetName())
+	}
+	sum := sha256.Sum256(fmt.Appendf(nil, "%T\x00%s", r, data))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// key computes the cache key for matching rules against the sources of dep,
+// for a build with the given target.
+// Rules are loaded in no particular order, so the key covers the sorted rule
+// digests; the digests are returned in the order of rules.
+func (mc *matchCache) key(dep *Dependency, target BuildTarget, rules []rule.InstRule) (string, []string, error) {
+	digests := make([]string, 0, len(rules))
+	for _, r := range rules {
+		digest, err := ruleDigest(r)
+		if err != nil {
+			return "", nil, err
+		}
+		digests = append(digests, digest)
+	}
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", matchCacheFormat, util.Version, dep.ImportPath, dep.Version)
+	tags := strings.Join(slices.Sorted(slices.Values(target.Tags)), ",")
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\x00", target.GOOS, target.GOARCH, tags)
+	for _, source := range dep.Sources {
+		f, err := os.Open(source)
+		if err != nil {
+			return "", nil, ex.Wrap(err)
+		}
+		_, _ = fmt.Fprintf(h, "source\x00%s\x00", source)
+		_, err = io.Copy(h, f)
+		_ = f.Close()
+		if err != nil {
+			return "", nil, ex.Wrapf(err, "hashing %s", source)
+		}
+		_, _ = h.Write([]byte{0})
+	}
+	for _, digest := range slices.Sorted(slices.Values(digests)) {
+		_, _ = fmt.Fprintf(h, "rule\x00%s\x00", digest)
+	}
+	return hex.EncodeToString(h.Sum(nil)), digests, nil
+}
+
+func (mc *matchCache) path(key string) string {
+	return filepath.Join(mc.dir, key[:2], key+".json")
+}
+
+// get returns the entry for key, or nil when there is none. An entry that
+// refers to sources or rules it was not keyed by is corrupt and counts as a
+// miss. A nil cache never has entries.
+func (mc *matchCache) get(key string, sources int, rules map[string]rule.InstRule) *matchCacheEntry {
+	if mc == nil {
+		return nil
+	}
+	data, err := os.ReadFile(mc.path(key))
+	if err != nil {
+		mc.misses.Add(1)
+		return nil
+	}
+	var entry matchCacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil || entry.PackageName == "" {
+		mc.misses.Add(1)
+		return nil
+	}
+	for _, m := range entry.Matches {
+		if m.Source < 0 || m.Source >= sources || rules[m.Rule] == nil {
+			mc.misses.Add(1)
+			return nil
+		}
+	}
+	mc.hits.Add(1)
+	return &entry
+}
+
+// put stores the entry for key. Entries are written atomically, so
+// concurrent builds sharing the work directory never read a partial entry.
+func (mc *matchCache) put(key string, entry *matchCacheEntry) error {
+	if mc == nil {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	path := mc.path(key)
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return ex.Wrap(err)
+	}
+	return util.WriteFileAtomic(path, data)
+}
+
+// stats returns the number of lookups that hit and missed the cache.
+func (mc *matchCache) stats() (int64, int64) {
+	if mc == nil {
+		return 0, 0
+	}
+	return mc.hits.Load(), mc.misses.Load()
+}
+
+// matchFromCache fills set from the cached result of matching rules against
+// the sources of dep, reporting whether there was one. Otherwise it returns
+// the key and the rule digests to store the result under; the key is empty
+// when the result cannot be cached.
+func (sp *SetupPhase) matchFromCache(
+	dep *Dependency,
+	rules []rule.InstRule,
+	set *rule.InstRuleSet,
+) (string, []string, bool) {
+	if sp.matchCache == nil {
+		return "", nil, false
+	}
+	key, digests, err := sp.matchCache.key(dep, sp.target, rules)
+	if err != nil {
+		sp.Debug("Skip match cache", "dep", dep.ImportPath, "error", err)
+		return "", nil, false
+	}
+	byDigest := make(map[string]rule.InstRule, len(rules))
+	for i, r := range rules {
+		if _, ok := byDigest[digests[i]]; !ok {
+			byDigest[digests[i]] = r
+		}
+	}
+	entry := sp.matchCache.get(key, len(dep.Sources), byDigest)
+	if entry == nil {
+		return key, digests, false
+	}
+	set.SetPackageName(entry.PackageName)
+	for _, m := range entry.Matches {
+		sp.addMatchedRule(dep.Sources[m.Source], byDigest[m.Rule], set, dep)
+	}
+	return key, digests, true
+}
@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otelc/tool/internal/rule"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+func newCachedSetupPhase(t *testing.T) *SetupPhase {
+	t.Helper()
+	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
+	sp := newTestSetupPhase()
+	sp.matchCache = newMatchCache()
+	return sp
+}
+
+func TestMatchCache(t *testing.T) {
+	sp := newCachedSetupPhase(t)
+	handler := writeGoSource(t, "handler.go", "package svc\n\nfunc Handler() {}\n")
+	server := writeGoSource(t, "server.go", "package svc\n\ntype Server struct{}\n")
+	dep := &Dependency{
+		ImportPath: "example.com/svc",
+		Version:    "v1.0.0",
+		Sources:    []string{handler, server},
+	}
+	funcRule := &rule.InstFuncRule{
+		InstBaseRule: rule.InstBaseRule{Name: "handler", Target: "example.com/svc"},
+		Func:         "Handler",
+		Before:       "BeforeHandler",
+		Path:         "example.com/hooks",
+	}
+	structRule := &rule.InstStructRule{
+		InstBaseRule: rule.InstBaseRule{Name: "server", Target: "example.com/svc"},
+		Struct:       "Server",
+	}
+
+	match := func(t *testing.T, rules ...rule.InstRule) *rule.InstRuleSet {
+		t.Helper()
+		set, err := sp.preciseMatching(t.Context(), dep, rules, rule.NewInstRuleSet(dep.ImportPath))
+		require.NoError(t, err)
+		return set
+	}
+	assertStats := func(t *testing.T, hits, misses int64) {
+		t.Helper()
+		gotHits, gotMisses := sp.matchCache.stats()
+		assert.Equal(t, hits, gotHits, "hits")
+		assert.Equal(t, misses, gotMisses, "misses")
+	}
+
+	want := match(t, funcRule, structRule)
+	assertStats(t, 0, 1)
+	require.Contains(t, want.FuncRules, handler)
+	require.Contains(t, want.StructRules, server)
+
+	t.Run("hits for unchanged sources and rules", func(t *testing.T) {
+		assert.Equal(t, want, match(t, funcRule, structRule))
+		assertStats(t, 1, 1)
+	})
+
+	t.Run("hits regardless of rule order", func(t *testing.T) {
+		assert.Equal(t, want, match(t, structRule, funcRule))
+		assertStats(t, 2, 1)
+	})
+
+	t.Run("misses for another target", func(t *testing.T) {
+		sp.target = BuildTarget{GOOS: "windows", GOARCH: "amd64"}
+		defer func() { sp.target = BuildTarget{} }()
+		assert.Equal(t, want, match(t, funcRule, structRule))
+		assertStats(t, 2, 2)
+	})
+
+	t.Run("misses for a changed rule", func(t *testing.T) {
+		changed := *funcRule
+		changed.Func = "Other"
+		set := match(t, &changed, structRule)
+		assert.Empty(t, set.FuncRules)
+		assertStats(t, 2, 3)
+	})
+
+	t.Run("misses for a changed source", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(handler, []byte("package svc\n\nfunc Other() {}\n"), 0o644))
+		set := match(t, funcRule, structRule)
+		assert.Empty(t, set.FuncRules)
+		assert.Contains(t, set.StructRules, server)
+		assertStats(t, 2, 4)
+	})
+
+	t.Run("misses for a corrupt entry", func(t *testing.T) {
+		key, _, err := sp.matchCache.key(dep, sp.target, []rule.InstRule{funcRule, structRule})
+		require.NoError(t, err)
+		require.FileExists(t, sp.matchCache.path(key))
+		require.NoError(t, os.WriteFile(sp.matchCache.path(key),
+			[]byte(`{"package_name":"svc","matches":[{"source":5,"rule":"unknown"}]}`), 0o644))
+		set := match(t, funcRule, structRule)
+		assert.Contains(t, set.StructRules, server)
+		assertStats(t, 2, 5)
+	})
+}
+
+func TestMatchCache_Disabled(t *testing.T) {
+	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
+	source := writeGoSource(t, "handler.go", "package svc\n\nfunc Handler() {}\n")
+	dep := &Dependency{ImportPath: "example.com/svc", Sources: []string{source}}
+	funcRule := &rule.InstFuncRule{
+		InstBaseRule: rule.InstBaseRule{Name: "handler", Target: "example.com/svc"},
+		Func:         "Handler",
+	}
+
+	sp := newTestSetupPhase()
+	set, err := sp.preciseMatching(t.Context(), dep, []rule.InstRule{funcRule}, rule.NewInstRuleSet(dep.ImportPath))
+	require.NoError(t, err)
+	assert.Contains(t, set.FuncRules, source)
+	assert.NoDirExists(t, util.GetBuildTemp(matchCacheDir))
+	hits, misses := sp.matchCache.stats()
+	assert.Zero(t, hits)
+	assert.Zero(t, misses)
+}
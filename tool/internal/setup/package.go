@@ -0,0 +1,296 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/internal/ast"
+	"go.opentelemetry.io/otelc/tool/internal/imports"
+	"go.opentelemetry.io/otelc/tool/internal/instrument"
+	"go.opentelemetry.io/otelc/tool/internal/rule"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+// PackageOptions describes one package to instrument outside of a go build,
+// as an action of a build system such as Bazel.
+type PackageOptions struct {
+	// ImportPath is the import path the package is compiled as
+	ImportPath string
+	// Version is the module version of the package, for versioned rules
+	Version string
+	// ModuleVersions maps module paths to their versions. The package takes
+	// the version of the longest module path that prefixes its import path
+	// when Version is empty, as one compiler wrapper serves every package.
+	ModuleVersions map[string]string
+	// Sources are the Go files of the package, after cgo processing
+	Sources []string
+	// ImportCfg is the importcfg of the compile. It must list every package
+	// the instrumentation imports, since nothing is resolved with go list.
+	ImportCfg string
+	// Rules are rule files or directories as for --rules. The rules of the
+	// embedded instrumentation bundle are used when empty.
+	Rules string
+	// Hooks maps hook package import paths to their source directories
+	Hooks map[string]string
+	// RootModules are the module paths the $root target expands to
+	RootModules []string
+	// Tags are the build tags of the compile, for where.file.build. The
+	// platform is taken from GOOS and GOARCH, which build systems set for a
+	// compile as the go command does.
+	Tags []string
+	// OutDir receives the instrumented and generated files
+	OutDir string
+}
+
+// InstrumentPackage matches the rules against one package and instruments it
+// without running the go command. It returns the sources to compile in place
+// of opts.Sources. For a main package, it also generates otelc.runtime.go to
+// link the hook packages that the importcfg lists into the binary.
+func InstrumentPackage(ctx context.Context, opts PackageOptions) ([]string, error) {
+	goos, goarch := util.ToolPlatform()
+	sp := &SetupPhase{
+		logger:          util.LoggerFromContext(ctx),
+		ruleConfig:      opts.Rules,
+		rootModulePaths: opts.RootModules,
+		target:          BuildTarget{GOOS: goos, GOARCH: goarch, Tags: opts.Tags},
+	}
+	if len(opts.Sources) == 0 {
+		return nil, ex.Newf("no source files for package %s", opts.ImportPath)
+	}
+	// Matched rules are keyed by the absolute path of their source file
+	sources := make([]string, 0, len(opts.Sources))
+	for _, source := range opts.Sources {
+		abs, err := filepath.Abs(source)
+		if err != nil {
+			return nil, ex.Wrap(err)
+		}
+		sources = append(sources, abs)
+	}
+	outDir, err := filepath.Abs(opts.OutDir)
+	if err != nil {
+		return nil, ex.Wrap(err)
+	}
+	opts.OutDir = outDir
+	if err = os.MkdirAll(opts.OutDir, 0o755); err != nil {
+		return nil, ex.Wrapf(err, "creating output directory %s", opts.OutDir)
+	}
+
+	rules, err := sp.loadPackageRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exactRules, globRules, err := sp.indexRules(ctx, rules)
+	if err != nil {
+		return nil, err
+	}
+	version := opts.Version
+	if version == "" {
+		version = moduleVersion(opts.ModuleVersions, opts.ImportPath)
+	}
+	dep := &Dependency{ImportPath: opts.ImportPath, Version: version, Sources: sources}
+	matched, err := sp.runMatch(ctx, dep, exactRules, globRules)
+	if err != nil {
+		return nil, err
+	}
+
+	result := sources
+	if !matched.IsEmpty() {
+		if err = resolveHookDirs(matched, opts.Hooks); err != nil {
+			return nil, err
+		}
+		result, err = instrument.InstrumentPackage(ctx, matched, sources, opts.ImportCfg, opts.OutDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	name, err := ast.ParsePackageName(sources[0])
+	if err != nil {
+		return nil, err
+	}
+	if name != "main" {
+		return result, nil
+	}
+	runtimeFile, err := sp.linkHooks(ctx, opts, rules)
+	if err != nil {
+		return nil, ex.Wrapf(err, "linking hooks into package %s", opts.ImportPath)
+	}
+	if runtimeFile != "" {
+		result = append(result, runtimeFile)
+	}
+	return result, nil
+}
+
+// loadPackageRules loads the rules from OTELC_RULES or --rules, like a go
+// build does, and otherwise the rules of the embedded instrumentation bundle,
+// extracted to the build temp directory together with their hook sources.
+func (sp *SetupPhase) loadPackageRules(ctx context.Context) ([]rule.InstRule, error) {
+	if os.Getenv(util.EnvOtelcRules) != "" || sp.ruleConfig != "" {
+		return sp.loadRules(ctx, nil)
+	}
+	if err := extractOtelcBundle(); err != nil {
+		return nil, ex.Wrapf(err, "extracting instrumentation bundle")
+	}
+	return loadCustomRules(util.GetBuildTemp(unzippedInstDir))
+}
+
+// moduleVersion returns the version of the module importPath belongs to, or
+// an empty version, which only rules without a version range match.
+func moduleVersion(versions map[string]string, importPath string) string {
+	module := ""
+	for path := range versions {
+		if (importPath == path || strings.HasPrefix(importPath, path+"/")) && len(path) > len(module) {
+			module = path
+		}
+	}
+	return versions[module]
+}
+
+// resolveHookDirs points the rules at the sources of their hook packages. It
+// is the counterpart of resolveRulePaths, which asks go list instead: a hook
+// package resolves to the directory given for it in hooks, or else to its
+// copy in the extracted instrumentation bundle.
+func resolveHookDirs(matched *rule.InstRuleSet, hooks map[string]string) error {
+	resolve := func(path string) (string, error) {
+		if dir, ok := hooks[path]; ok {
+			return dir, nil
+		}
+		if rel, ok := strings.CutPrefix(path, util.OtelcInstRoot+"/"); ok {
+			dir := filepath.Join(util.GetBuildTemp(unzippedInstDir), filepath.FromSlash(rel))
+			if util.PathExists(dir) {
+				return dir, nil
+			}
+		}
+		return "", ex.Newf("no sources for hook package %s; pass them with --hook %s=<dir>", path, path)
+	}
+
+	for _, fileRule := range matched.FileRules {
+		dir, err := resolve(fileRule.Path)
+		if err != nil {
+			return err
+		}
+		fileRule.ResolvedPath = dir
+	}
+	for _, funcRule := range matched.AllFuncRules() {
+		dir, err := resolve(funcRule.Path)
+		if err != nil {
+			return err
+		}
+		funcRule.ResolvedPath = dir
+	}
+	return nil
+}
+
+// linkHooks writes otelc.runtime.go for a main package and returns its path,
+// or an empty path when the importcfg lists no hook package. Only the hook
+// packages the importcfg lists are imported, so the binary declares which
+// instrumentations it links by depending on them. An action sees no other
+// package of the binary, so the hook handlers are linked into every exact
+// target of those rules instead of the packages that were instrumented; the
+// targets of glob rules get their hooks but no handlers.
+func (sp *SetupPhase) linkHooks(ctx context.Context, opts PackageOptions, rules []rule.InstRule) (string, error) {
+	cfg, err := imports.ParseImportCfg(opts.ImportCfg)
+	if err != nil {
+		return "", ex.Wrapf(err, "parsing importcfg")
+	}
+	linked := func(path string) bool {
+		_, ok := cfg.PackageFile[path]
+		return ok
+	}
+
+	funcRules := []*rule.InstFuncRule{}
+	fileRules := []*rule.InstFileRule{}
+	targets := make(map[string]*rule.InstRuleSet)
+	for _, r := range rules {
+		switch rt := r.(type) {
+		case *rule.InstFileRule:
+			if linked(rt.Path) {
+				fileRules = append(fileRules, rt)
+			}
+		case *rule.InstFuncRule:
+			if !linked(rt.Path) {
+				continue
+			}
+			funcRules = append(funcRules, rt)
+			target := rt.GetTarget()
+			if rule.IsRootTarget(target) || rule.IsGlobTarget(target) {
+				continue
+			}
+			if targets[target] == nil {
+				targets[target] = rule.NewInstRuleSet(target)
+				if target == opts.ImportPath {
+					targets[target].SetPackageName("main")
+				}
+			}
+			// The set only tells genVarDecl which targets have trampolines;
+			// the file it keys the rule by is never read
+			targets[target].AddFuncRule(opts.OutDir, rt)
+		}
+	}
+	if len(funcRules) == 0 && len(fileRules) == 0 {
+		return "", nil
+	}
+	// The hook handlers live in otelc runtime, see requiredImports
+	if runtimePkg := util.OtelcPkgRoot + "/runtime"; len(funcRules) > 0 && !linked(runtimePkg) {
+		return "", ex.Newf("package %q is not in the importcfg; "+
+			"add it to the dependencies of the binary", runtimePkg)
+	}
+
+	matched := make([]*rule.InstRuleSet, 0, len(targets))
+	for _, target := range slices.Sorted(maps.Keys(targets)) {
+		matched = append(matched, targets[target])
+	}
+	decls := append(genImportDecl(funcRules, fileRules), genVarDecl(matched)...)
+	if err = sp.writeRuntimeFile(ctx, decls, opts.OutDir, "main"); err != nil {
+		return "", err
+	}
+	return filepath.Join(opts.OutDir, OtelcRuntimeFile), nil
+}
+
+// InstrumentCompile instruments the package a compile command builds and runs
+// the command with the instrumented sources. It lets otelc stand in for the
+// compiler of a build system toolchain, taking the import path, importcfg and
+// sources from the command; opts supplies the rest. Commands other than a
+// package compile, such as the -V=full version probe, run unchanged.
+func InstrumentCompile(ctx context.Context, opts PackageOptions, args []string) error {
+	if len(args) == 0 {
+		return ex.New("missing compile command")
+	}
+	opts.ImportPath = util.FindFlagValue(args, "-p")
+	opts.ImportCfg = util.FindFlagValue(args, "-importcfg")
+	if opts.ImportPath == "" || opts.ImportCfg == "" || !slices.ContainsFunc(args[1:], isSourceArg) {
+		return util.RunCmd(ctx, args...)
+	}
+
+	outDir, err := os.MkdirTemp(util.GetBuildTempDir(), "compile-")
+	if err != nil {
+		return ex.Wrapf(err, "creating instrumentation directory")
+	}
+	defer os.RemoveAll(outDir)
+	opts.OutDir = outDir
+	opts.Sources = slices.DeleteFunc(slices.Clone(args[1:]), func(arg string) bool { return !isSourceArg(arg) })
+
+	sources, err := InstrumentPackage(ctx, opts)
+	if err != nil {
+		return err
+	}
+	// Trampolines declare the hooks they call without a body, which the
+	// compiler rejects under -complete
+	compile := slices.DeleteFunc(slices.Clone(args), func(arg string) bool {
+		return isSourceArg(arg) || arg == "-complete"
+	})
+	return util.RunCmd(ctx, append(compile, sources...)...)
+}
+
+// isSourceArg reports whether a compile command argument is a source file.
+func isSourceArg(arg string) bool {
+	return !strings.HasPrefix(arg, "-") && util.IsGoFile(arg)
+}
@@ -0,0 +1,257 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package setup
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+const (
+	stubLibSource = `package lib
+
+func Greet(name string) string {
+	return "hello " + name
+}
+`
+	stubMainSource = `package main
+
+import "example.com/lib"
+
+func main() {
+	println(lib.Greet("otelc"))
+}
+`
+	stubHookSource = `package hooks
+
+import "go.opentelemetry.io/otelc/pkg/hook"
+
+func BeforeGreet(ictx hook.HookContext, name string) {}
+`
+	stubRules = `greet:
+  target: example.com/lib
+  where:
+    func: Greet
+  do:
+    - inject_hooks:
+        before: BeforeGreet
+        path: example.com/hooks
+`
+)
+
+// stubGraph is the build graph of a binary as a build system such as Bazel
+// declares it: one directory of sources per package, and the rules and hook
+// sources otelc instruments them with. Nothing in it is a Go module.
+type stubGraph struct {
+	lib, main, hooks, rules string
+}
+
+func newStubGraph(t *testing.T) stubGraph {
+	t.Helper()
+	t.Setenv(util.EnvOtelcRules, "")
+	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
+	require.NoError(t, os.MkdirAll(util.GetBuildTempDir(), 0o755))
+
+	root := t.TempDir()
+	g := stubGraph{
+		lib:   filepath.Join(root, "lib", "lib.go"),
+		main:  filepath.Join(root, "cmd", "main.go"),
+		hooks: filepath.Join(root, "hooks"),
+		rules: filepath.Join(root, "greet.otelc.yml"),
+	}
+	for file, content := range map[string]string{
+		g.lib:                              stubLibSource,
+		g.main:                             stubMainSource,
+		filepath.Join(g.hooks, "hooks.go"): stubHookSource,
+		g.rules:                            stubRules,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	}
+	return g
+}
+
+func (g stubGraph) options(t *testing.T, importPath, importCfg, source string) PackageOptions {
+	t.Helper()
+	return PackageOptions{
+		ImportPath: importPath,
+		Sources:    []string{source},
+		ImportCfg:  importCfg,
+		Rules:      g.rules,
+		Hooks:      map[string]string{"example.com/hooks": g.hooks},
+		OutDir:     t.TempDir(),
+	}
+}
+
+func writeImportCfg(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "importcfg")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644))
+	return path
+}
+
+func goTool(t *testing.T, name string) string {
+	t.Helper()
+	out, err := exec.Command("go", "env", "GOTOOLDIR").Output()
+	require.NoError(t, err)
+	return filepath.Join(strings.TrimSpace(string(out)), name)
+}
+
+func discardLogger(*testing.T) *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestInstrumentPackage(t *testing.T) {
+	g := newStubGraph(t)
+	ctx := util.ContextWithLogger(t.Context(), discardLogger(t))
+	importCfg := writeImportCfg(t)
+
+	opts := g.options(t, "example.com/lib", importCfg, g.lib)
+	sources, err := InstrumentPackage(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(opts.OutDir, "lib.go"),
+		filepath.Join(opts.OutDir, "otelc.globals.go"),
+	}, sources)
+
+	instrumented, err := os.ReadFile(sources[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(instrumented), "OtelBeforeTrampoline_Greet")
+
+	// The instrumented package compiles with nothing but its importcfg
+	archive := filepath.Join(t.TempDir(), "lib.a")
+	compile := exec.Command(goTool(t, "compile"),
+		append([]string{"-p", "example.com/lib", "-importcfg", importCfg, "-o", archive}, sources...)...)
+	out, err := compile.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestInstrumentPackage_Unmatched(t *testing.T) {
+	g := newStubGraph(t)
+	ctx := util.ContextWithLogger(t.Context(), discardLogger(t))
+
+	opts := g.options(t, "example.com/other", writeImportCfg(t), g.lib)
+	sources, err := InstrumentPackage(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{g.lib}, sources)
+}
+
+func TestInstrumentPackage_MissingHookSources(t *testing.T) {
+	g := newStubGraph(t)
+	ctx := util.ContextWithLogger(t.Context(), discardLogger(t))
+
+	opts := g.options(t, "example.com/lib", writeImportCfg(t), g.lib)
+	opts.Hooks = nil
+	_, err := InstrumentPackage(ctx, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--hook example.com/hooks=<dir>")
+}
+
+func TestInstrumentPackage_MainLinksHooks(t *testing.T) {
+	g := newStubGraph(t)
+	ctx := util.ContextWithLogger(t.Context(), discardLogger(t))
+
+	t.Run("links the hook packages of the importcfg", func(t *testing.T) {
+		importCfg := writeImportCfg(t,
+			"packagefile example.com/lib=lib.a",
+			"packagefile example.com/hooks=hooks.a",
+			"packagefile go.opentelemetry.io/otelc/pkg/runtime=runtime.a")
+		opts := g.options(t, "example.com/cmd", importCfg, g.main)
+		sources, err := InstrumentPackage(ctx, opts)
+		require.NoError(t, err)
+		runtimeFile := filepath.Join(opts.OutDir, OtelcRuntimeFile)
+		assert.Equal(t, []string{g.main, runtimeFile}, sources)
+
+		content, err := os.ReadFile(runtimeFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `import _ "example.com/hooks"`)
+		assert.Contains(t, string(content), "//go:linkname _hookstart0 example.com/lib.OtelHookStartImpl")
+	})
+
+	t.Run("requires the otelc runtime", func(t *testing.T) {
+		importCfg := writeImportCfg(t,
+			"packagefile example.com/lib=lib.a",
+			"packagefile example.com/hooks=hooks.a")
+		_, err := InstrumentPackage(ctx, g.options(t, "example.com/cmd", importCfg, g.main))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `package "go.opentelemetry.io/otelc/pkg/runtime" is not in the importcfg`)
+	})
+
+	t.Run("nothing to link without hook packages", func(t *testing.T) {
+		importCfg := writeImportCfg(t, "packagefile example.com/lib=lib.a")
+		sources, err := InstrumentPackage(ctx, g.options(t, "example.com/cmd", importCfg, g.main))
+		require.NoError(t, err)
+		assert.Equal(t, []string{g.main}, sources)
+	})
+}
+
+func TestInstrumentCompile(t *testing.T) {
+	g := newStubGraph(t)
+	ctx := util.ContextWithLogger(t.Context(), discardLogger(t))
+	importCfg := writeImportCfg(t)
+
+	// The stub compiler records its arguments and checks the instrumented
+	// sources exist while it runs.
+	record := filepath.Join(t.TempDir(), "args")
+	compiler := filepath.Join(t.TempDir(), "compile")
+	require.NoError(t, os.WriteFile(compiler, []byte("#!/bin/sh\n"+
+		`for arg in "$@"; do case "$arg" in *.go) test -f "$arg" || exit 1;; esac; done`+"\n"+
+		`echo "$@" > `+record+"\n"), 0o755))
+
+	t.Run("compiles the instrumented sources", func(t *testing.T) {
+		args := []string{compiler, "-o", "lib.a", "-p", "example.com/lib", "-importcfg", importCfg, "-complete", g.lib}
+		require.NoError(t, InstrumentCompile(ctx, PackageOptions{Rules: g.rules, Hooks: map[string]string{
+			"example.com/hooks": g.hooks,
+		}}, args))
+
+		recorded, err := os.ReadFile(record)
+		require.NoError(t, err)
+		fields := strings.Fields(string(recorded))
+		assert.NotContains(t, fields, "-complete")
+		assert.NotContains(t, fields, g.lib)
+		assert.Contains(t, string(recorded), "otelc.globals.go")
+		assert.Equal(t, []string{"-o", "lib.a", "-p", "example.com/lib", "-importcfg", importCfg}, fields[:6])
+	})
+
+	t.Run("runs other commands unchanged", func(t *testing.T) {
+		require.NoError(t, InstrumentCompile(ctx, PackageOptions{Rules: g.rules}, []string{compiler, "-V=full"}))
+		recorded, err := os.ReadFile(record)
+		require.NoError(t, err)
+		assert.Equal(t, "-V=full\n", string(recorded))
+	})
+}
+
+func TestModuleVersion(t *testing.T) {
+	versions := map[string]string{
+		"go.opentelemetry.io/otel":     "v1.40.0",
+		"go.opentelemetry.io/otel/sdk": "v1.44.0",
+	}
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{"go.opentelemetry.io/otel", "v1.40.0"},
+		{"go.opentelemetry.io/otel/trace", "v1.40.0"},
+		{"go.opentelemetry.io/otel/sdk/trace", "v1.44.0"},
+		{"go.opentelemetry.io/otelc/pkg/runtime", ""},
+		{"net/http", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			assert.Equal(t, tt.want, moduleVersion(versions, tt.importPath))
+		})
+	}
+}
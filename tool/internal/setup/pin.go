@@ -260,8 +260,9 @@ func ensureOtelcRequireVersion(f *modfile.File, version string) (bool, error) {
 	return true, nil
 }
 
-func ensureOtelcRequire(moduleDir, version string) (bool, error) {
-	goModPath := filepath.Join(moduleDir, "go.mod")
+// ensureOtelcRequire adds the otelc tool directive and requirement to the
+// go.mod at goModPath.
+func ensureOtelcRequire(goModPath, version string) (bool, error) {
 	data, err := os.ReadFile(goModPath)
 	if err != nil {
 		return false, err
@@ -342,7 +343,7 @@ func matchInstrumentationImports(deps []*Dependency, ruleset map[string][]yamlRu
 func updateToolFile(ctx context.Context, toolFile string, prunedImports map[string]bool, opts PinOptions) error {
 	p := ast.NewAstParser()
 
-	f, parseErr := p.Parse(toolFile, parser.ParseComments)
+	f, parseErr := p.Parse(shadowFromContext(ctx).source(toolFile), parser.ParseComments)
 	if parseErr != nil {
 		return ex.Wrapf(parseErr, "parsing tool file %s", toolFile)
 	}
@@ -355,15 +356,24 @@ func updateToolFile(ctx context.Context, toolFile string, prunedImports map[stri
 
 	updateGenerateDirective(f, opts)
 
-	if writeErr := ast.WriteFileAtomic(toolFile, f); writeErr != nil {
+	shadow := shadowFromContext(ctx)
+	dst, redirectErr := shadow.redirect(toolFile)
+	if redirectErr != nil {
+		return redirectErr
+	}
+	if writeErr := ast.WriteFileAtomic(dst, f); writeErr != nil {
 		return writeErr
 	}
 
-	_, ensureErr := ensureOtelcRequire(filepath.Dir(toolFile), util.Version)
+	_, ensureErr := ensureOtelcRequire(shadow.goModPath(filepath.Dir(toolFile)), util.Version)
 	if ensureErr != nil {
 		return ex.Wrapf(ensureErr, "ensuring otelc require in go.mod in %s", filepath.Dir(toolFile))
 	}
 
+	if bundleErr := bundleFromContext(ctx).applyTo(ctx, shadow.goModPath(filepath.Dir(toolFile))); bundleErr != nil {
+		return ex.Wrapf(bundleErr, "using rule bundle in %s", filepath.Dir(toolFile))
+	}
+
 	if tidyErr := runModTidy(ctx, filepath.Dir(toolFile)); tidyErr != nil {
 		return ex.Wrapf(tidyErr, "running go mod tidy in %s", filepath.Dir(toolFile))
 	}
@@ -463,7 +473,13 @@ func generatePinnedProjects(ctx context.Context, moduleDirs map[string]bool, opt
 		return nil, ex.Wrapf(extractErr, "extracting otelc package")
 	}
 
-	ruleset, err := loadMinimalRules(filepath.Join(util.GetBuildTempDir(), unzippedInstDir))
+	// The embedded package is still needed for the runtime, but a rule
+	// bundle replaces its instrumentation.
+	rulesRoot := filepath.Join(util.GetBuildTempDir(), unzippedInstDir)
+	if bundle := bundleFromContext(ctx); bundle != nil {
+		rulesRoot = bundle.modulesDir()
+	}
+	ruleset, err := loadMinimalRules(rulesRoot)
 	if err != nil {
 		return nil, ex.Wrapf(err, "loading instrumentation rules")
 	}
@@ -487,13 +503,17 @@ func generatePinnedProjects(ctx context.Context, moduleDirs map[string]bool, opt
 
 	// Generate otel.instrumentation.go file with imports for all matched rules.
 	f := generateOtelInstrumentationGo(imports, opts)
+	shadow := shadowFromContext(ctx)
 	for moduleDir := range moduleDirs {
-		path := filepath.Join(moduleDir, ToolFileCanonical)
+		path, redirectErr := shadow.redirect(filepath.Join(moduleDir, ToolFileCanonical))
+		if redirectErr != nil {
+			return nil, redirectErr
+		}
 		if writeErr := ast.WriteFileAtomic(path, f); writeErr != nil {
 			return nil, ex.Wrapf(writeErr, "writing %s", path)
 		}
 
-		if _, ensureErr := ensureOtelcRequire(moduleDir, util.Version); ensureErr != nil {
+		if _, ensureErr := ensureOtelcRequire(shadow.goModPath(moduleDir), util.Version); ensureErr != nil {
 			return nil, ex.Wrapf(ensureErr, "ensuring otelc require in go.mod in %s", moduleDir)
 		}
 
@@ -586,7 +606,7 @@ func pinLocked(ctx context.Context, opts PinOptions) (*PinResult, error) {
 		}
 	}
 
-	toolFiles, findToolErr := findToolFiles(moduleDirs)
+	toolFiles, findToolErr := findToolFiles(ctx, moduleDirs)
 	if findToolErr != nil {
 		return nil, findToolErr
 	}
@@ -603,19 +623,22 @@ func pinLocked(ctx context.Context, opts PinOptions) (*PinResult, error) {
 }
 
 // AutoPin is a convenience function that automatically tracks generated/modified files before calling Pin
-// in order to restore them after the build completes.
+// in order to restore them after the build completes. In a read-only build, Pin
+// writes to the shadow workspace and there is nothing to track.
 func AutoPin(ctx context.Context, moduleDirs map[string]bool, subcommand string, args []string) (*PinResult, error) {
 	stateManager, found := StateManagerFromContext(ctx)
 	if !found {
 		return nil, ex.New("state manager not found in context")
 	}
 
-	backupFiles, getBackupErr := getBackupFiles(ctx, moduleDirs)
-	if getBackupErr != nil {
-		return nil, ex.Wrapf(getBackupErr, "getting backup files")
-	}
-	if trackErr := stateManager.TrackAll(backupFiles...); trackErr != nil {
-		return nil, ex.Wrapf(trackErr, "tracking backup files")
+	if shadowFromContext(ctx) == nil {
+		backupFiles, getBackupErr := getBackupFiles(ctx, moduleDirs)
+		if getBackupErr != nil {
+			return nil, ex.Wrapf(getBackupErr, "getting backup files")
+		}
+		if trackErr := stateManager.TrackAll(backupFiles...); trackErr != nil {
+			return nil, ex.Wrapf(trackErr, "tracking backup files")
+		}
 	}
 
 	pinResult, pinErr := Pin(ctx, PinOptions{
@@ -392,7 +392,7 @@ require %s v1.0.0
 				0o644,
 			))
 
-			modified, err := ensureOtelcRequire(dir, testVersion)
+			modified, err := ensureOtelcRequire(goModPath, testVersion)
 			if tt.wantErr {
 				require.Error(t, err)
 				return
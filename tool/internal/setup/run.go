@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+// runProgram is the program `otelc go run` builds. Unlike `go run`, otelc
+// builds it with `go build -o` and runs the binary only once go.mod, go.sum and
+// the generated files are restored and the build lock is released: the
+// program may run for hours, e.g. a server.
+type runProgram struct {
+	binary string   // path of the built binary
+	exec   []string // -exec command that runs the binary, if any
+	args   []string // arguments of the program
+}
+
+type runProgramKey struct{}
+
+func contextWithRunProgram(ctx context.Context, p *runProgram) context.Context {
+	return context.WithValue(ctx, runProgramKey{}, p)
+}
+
+// runProgramFromContext returns the program `otelc go run` builds, or nil for
+// the other go commands.
+func runProgramFromContext(ctx context.Context) *runProgram {
+	p, _ := ctx.Value(runProgramKey{}).(*runProgram)
+	return p
+}
+
+// buildRunProgram builds the program of goRun under the build lock. Tests
+// replace it to observe what runs after the build.
+//
+//nolint:gochecknoglobals // test seam
+var buildRunProgram = runGoBuild
+
+// goRun implements `otelc go run`: it builds the program to a temporary
+// binary, then runs it with the arguments following the package. The exit
+// status of otelc is the program's.
+func goRun(ctx context.Context, cmd *cli.Command) error {
+	dir, err := os.MkdirTemp("", "otelc-run-")
+	if err != nil {
+		return ex.Wrapf(err, "creating the directory of the program")
+	}
+	defer os.RemoveAll(dir)
+
+	buildArgs, _ := splitRunArgs(cmd.Args().Tail())
+	program := &runProgram{binary: filepath.Join(dir, runBinaryName(buildArgs))}
+	err = withBuildLock(contextWithRunProgram(ctx, program), func(ctx context.Context) error {
+		return buildRunProgram(ctx, cmd)
+	})
+	if err != nil {
+		return err
+	}
+	return program.run(ctx)
+}
+
+// buildArgs turns the arguments of `go run` following the subcommand into
+// those of `go build`: the program arguments and -exec are set aside and the
+// binary is written to p.binary.
+func (p *runProgram) buildArgs(args []string) []string {
+	args, p.args = splitRunArgs(args)
+	buildArgs := []string{"-o", p.binary}
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-exec" && i+1 < len(args):
+			i++
+			p.exec = strings.Fields(args[i])
+		case strings.HasPrefix(arg, "-exec="):
+			p.exec = strings.Fields(strings.TrimPrefix(arg, "-exec="))
+		default:
+			buildArgs = append(buildArgs, arg)
+		}
+	}
+	return buildArgs
+}
+
+// run runs the program, forwarding the interrupt that cancels ctx to it rather
+// than killing it, so it can shut down.
+func (p *runProgram) run(ctx context.Context) error {
+	args := append(slices.Clone(p.exec), p.binary)
+	args = append(args, p.args...)
+	util.LoggerFromContext(ctx).InfoContext(ctx, "Running the program", "args", args)
+
+	c := exec.CommandContext(ctx, args[0], args[1:]...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Cancel = func() error { return c.Process.Signal(os.Interrupt) }
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code <= 0 {
+			code = 1 // killed by a signal
+		}
+		return &ex.ExitError{Code: code}
+	}
+	if err != nil {
+		return ex.Wrapf(err, "running %s", p.binary)
+	}
+	return nil
+}
+
+// runBinaryName names the binary after the package or the first file of
+// buildArgs, as `go run` does, since programs may print os.Args[0].
+func runBinaryName(buildArgs []string) string {
+	name := "main"
+	if pkgs, files, err := splitBuildTargets(buildArgs); err == nil {
+		switch {
+		case len(files) > 0:
+			name = strings.TrimSuffix(filepath.Base(files[0]), ".go")
+		case len(pkgs) > 0 && (filepath.IsAbs(pkgs[0]) || strings.HasPrefix(pkgs[0], ".")):
+			if abs, absErr := filepath.Abs(pkgs[0]); absErr == nil {
+				name = filepath.Base(abs)
+			}
+		case len(pkgs) > 0:
+			name = path.Base(pkgs[0])
+		}
+	}
+	if util.IsWindows() {
+		name += ".exe"
+	}
+	return name
+}
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+// runTestProgram reports the go.mod it finds and whether the build lock is
+// still held, then exits with status 3.
+const runTestProgram = `package main
+
+import (
+	"os"
+)
+
+func main() {
+	goMod, _ := os.ReadFile(os.Args[1])
+	_, err := os.Stat(os.Args[2])
+	report := string(goMod)
+	if err == nil {
+		report += "locked"
+	}
+	_ = os.WriteFile(os.Args[3], []byte(report), 0o644)
+	os.Exit(3)
+}
+`
+
+func TestGoRun_ProgramRunsAfterRestore(t *testing.T) {
+	lockTestDir(t)
+	tmpDir, err := os.Getwd()
+	require.NoError(t, err)
+	goMod := "module example.com/run\n\ngo 1.25\n"
+	goModPath := filepath.Join(tmpDir, "go.mod")
+	require.NoError(t, os.WriteFile(goModPath, []byte(goMod), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "prog"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "prog", "main.go"), []byte(runTestProgram), 0o644))
+
+	// Stand in for runGoBuild: go.mod is modified for the build and restored
+	// by the time it returns, as the deferred Cleanup does.
+	t.Cleanup(func() { buildRunProgram = runGoBuild })
+	buildRunProgram = func(ctx context.Context, cmd *cli.Command) error {
+		program := runProgramFromContext(ctx)
+		require.NotNil(t, program)
+		require.NoError(t, os.WriteFile(goModPath, []byte(goMod+"\nrequire example.com/dep v1.0.0\n"), 0o644))
+		defer func() { require.NoError(t, os.WriteFile(goModPath, []byte(goMod), 0o644)) }()
+
+		build := exec.CommandContext(ctx, "go", append([]string{"build"}, program.buildArgs(cmd.Args().Tail())...)...)
+		out, buildErr := build.CombinedOutput()
+		require.NoError(t, buildErr, string(out))
+		return nil
+	}
+
+	reportPath := filepath.Join(tmpDir, "report.txt")
+	cmd := &cli.Command{Name: "go", SkipFlagParsing: true, Action: GoBuild}
+	err = cmd.Run(t.Context(), []string{"go", "run", "./prog", goModPath, buildLockPath(), reportPath})
+
+	var exitErr *ex.ExitError
+	require.True(t, errors.As(err, &exitErr), "got %v", err)
+	assert.Equal(t, 3, exitErr.Code)
+	report, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+	assert.Equal(t, goMod, string(report), "the program must see the restored go.mod and no build lock")
+}
+
+func TestRunProgramBuildArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		buildArgs []string
+		exec      []string
+		progArgs  []string
+	}{
+		{
+			name:      "package and program arguments",
+			args:      []string{"-race", "./cmd/api", "-port", "8080"},
+			buildArgs: []string{"-o", "bin", "-race", "./cmd/api"},
+			progArgs:  []string{"-port", "8080"},
+		},
+		{
+			name:      "exec flag",
+			args:      []string{"-exec", "sudo -E", "."},
+			buildArgs: []string{"-o", "bin", "."},
+			exec:      []string{"sudo", "-E"},
+		},
+		{
+			name:      "exec flag with equals",
+			args:      []string{"-exec=wasmtime", "main.go", "serve"},
+			buildArgs: []string{"-o", "bin", "main.go"},
+			exec:      []string{"wasmtime"},
+			progArgs:  []string{"serve"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := &runProgram{binary: "bin"}
+			assert.Equal(t, tt.buildArgs, program.buildArgs(tt.args))
+			assert.Equal(t, tt.exec, program.exec)
+			if tt.progArgs == nil {
+				assert.Empty(t, program.args)
+			} else {
+				assert.Equal(t, tt.progArgs, program.args)
+			}
+		})
+	}
+}
+
+func TestRunBinaryName(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "relative package", args: []string{"-race", "./cmd/api"}, want: "api"},
+		{name: "import path", args: []string{"example.com/tools/gen"}, want: "gen"},
+		{name: "files", args: []string{"serve.go", "util.go"}, want: "serve"},
+		{name: "no package", args: nil, want: "main"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			if util.IsWindows() {
+				want += ".exe"
+			}
+			assert.Equal(t, want, runBinaryName(tt.args))
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"go.opentelemetry.io/otelc/tool/ex"
 	"go.opentelemetry.io/otelc/tool/internal/instrument"
 	"go.opentelemetry.io/otelc/tool/internal/pkgload"
+	"go.opentelemetry.io/otelc/tool/internal/report"
 	"go.opentelemetry.io/otelc/tool/internal/rule"
 	"go.opentelemetry.io/otelc/tool/util"
 	"golang.org/x/tools/go/packages"
@@ -29,6 +30,13 @@ type SetupPhase struct {
 	ruleConfig      string
 	buildPackages   []*packages.Package
 	rootModulePaths []string
+	// matchCache persists precise matching results across builds; nil
+	// disables it
+	matchCache *matchCache
+	// target is what the build compiles for
+	target BuildTarget
+	// report records the build report; nil when none is written
+	report *report.Recorder
 }
 
 func (sp *SetupPhase) Info(msg string, args ...any)  { sp.logger.Info(msg, args...) }
@@ -74,6 +82,7 @@ var flagsWithPathValues = map[string]bool{
 	"-o":             true,
 	"-p":             true,
 	"-covermode":     true,
+	"-exec":          true, // go run and go test
 	"-coverpkg":      true,
 	"-asmflags":      true,
 	"-buildmode":     true,
@@ -130,9 +139,18 @@ var testFlagsWithValues = map[string]bool{
 const (
 	subcmdBuild   = "build"
 	subcmdInstall = "install"
+	subcmdRun     = "run"
 	subcmdTest    = "test"
 )
 
+// Go subcommands that otelc runs unchanged: they read the original sources,
+// so vet analyzers report on the code as written and generators see no
+// generated otelc files.
+const (
+	subcmdVet      = "vet"
+	subcmdGenerate = "generate"
+)
+
 // GetBuildPackages loads all packages from the otelc go build/install or otelc setup command arguments.
 // Returns a list of loaded packages. If no package patterns are found in args,
 // defaults to loading the current directory package.
@@ -197,6 +215,16 @@ func getBuildPackages(ctx context.Context, args []string) ([]*packages.Package,
 	return buildPkgs, nil
 }
 
+// splitBuildTargets classifies the arguments of `otelc go build`, `install`
+// and `test` (and of `otelc setup`) into package and .go file targets. Flags
+// and their separate values are skipped, as is everything after `-args`.
+//
+// The arguments of `go run` must go through splitRunArgs first: the arguments
+// after its package belong to the program being run, and would otherwise be
+// taken for packages. `go vet` and `go generate` never reach it; they run
+// unchanged on the original sources, so vet analyzers never see the
+// instrumented code.
+//
 //nolint:revive // if we add named returns then nonamedreturns will complain
 func splitBuildTargets(args []string) ([]string, []string, error) {
 	var pkgs, files []string
@@ -257,6 +285,32 @@ func splitBuildTargets(args []string) ([]string, []string, error) {
 	return pkgs, files, nil
 }
 
+// splitRunArgs splits the arguments of `go run` into the build arguments and
+// the arguments of the program. As for the go command, the package is the
+// first argument that is not a flag, or the .go files starting there; what
+// follows it is passed to the program (e.g. `go run ./cmd/api -port 8080`).
+//
+//nolint:revive // if we add named returns then nonamedreturns will complain
+func splitRunArgs(args []string) ([]string, []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			if !strings.Contains(arg, "=") && flagsWithPathValues[arg] {
+				i++ // skip this flag's separate value
+			}
+			continue
+		}
+		end := i + 1
+		if filepath.Ext(arg) == ".go" {
+			for end < len(args) && filepath.Ext(args[end]) == ".go" {
+				end++
+			}
+		}
+		return args[:end], args[end:]
+	}
+	return args, nil
+}
+
 func rootModulePaths(ctx context.Context, pkgs []*packages.Package) ([]string, error) {
 	roots := make(map[string]bool)
 	for _, pkg := range pkgs {
@@ -290,6 +344,7 @@ func (sp *SetupPhase) generateRuntimePerPackage(
 		pkgDir := pkgload.PackageDir(pkg)
 		if pkgDir == "" {
 			sp.Warn("skipping package without Go files", "package", pkg.PkgPath)
+			sp.report.AddWarning(pkg.PkgPath, "skipping package without Go files")
 			continue
 		}
 
@@ -317,9 +372,13 @@ func setupLocked(ctx context.Context, cmd *cli.Command) error {
 	args := cmd.Args().Slice()
 	subcommand := subcmdBuild
 	if cmd.Name == "go" {
-		subcommand = cmd.Args().First() // build / install / test
+		subcommand = cmd.Args().First() // build / install / run / test
 		args = cmd.Args().Tail()        // trim the subcommand
 	}
+	if subcommand == subcmdRun {
+		// Setup looks at the package only, not the arguments of the program
+		args, _ = splitRunArgs(args)
+	}
 
 	logger := util.LoggerFromContext(ctx)
 
@@ -349,6 +408,24 @@ func setupLocked(ctx context.Context, cmd *cli.Command) error {
 		args = rewriteModVendor(args)
 	}
 
+	// A rule bundle replaces the embedded instrumentation and serves the
+	// modules it depends on. Like GOFLAGS above, GOPROXY must be set before
+	// isSetup() so that a cached-setup build resolves modules the same way.
+	if bundlePath := cmd.String(bundleFlag); bundlePath != "" {
+		bundle, err := openBundle(ctx, bundlePath, cmd.String(bundleKeyFlag), cmd.Bool(bundleInsecureFlag))
+		if err != nil {
+			return ex.Wrapf(err, "opening rule bundle")
+		}
+		if err = bundle.useProxy(ctx); err != nil {
+			return err
+		}
+		ctx = contextWithBundle(ctx, bundle)
+	} else if cmd.String(bundleKeyFlag) != "" {
+		return ex.Newf("--%s requires --%s", bundleKeyFlag, bundleFlag)
+	} else if cmd.Bool(bundleInsecureFlag) {
+		return ex.Newf("--%s requires --%s", bundleInsecureFlag, bundleFlag)
+	}
+
 	if isSetup() {
 		logger.InfoContext(ctx, "Setup has already been completed, skipping setup.")
 		return nil
@@ -357,6 +434,8 @@ func setupLocked(ctx context.Context, cmd *cli.Command) error {
 	sp := &SetupPhase{
 		logger:     logger,
 		ruleConfig: cmd.String("rules"),
+		matchCache: newMatchCache(),
+		report:     report.FromContext(ctx),
 	}
 
 	// Introduce additional hook code by generating otelc.runtime.go
@@ -380,6 +459,21 @@ func setupLocked(ctx context.Context, cmd *cli.Command) error {
 		ctx = ContextWithStateManager(ctx, stateManager)
 	}
 
+	// A read-only build writes go.mod, go.sum and the generated files to a
+	// shadow workspace instead of the module. GoBuild creates the shadow so
+	// that the toolexec build reads it too.
+	shadow := shadowFromContext(ctx)
+	if shadow == nil && cmd.Bool(readOnlyFlag) {
+		// go/packages cannot load packages with -modfile in GOFLAGS, so a
+		// GOFLAGS drop-in build could not read the shadow workspace.
+		return ex.New("read-only builds must run through `otelc go`")
+	}
+	if shadow != nil {
+		if err = shadow.init(ctx, moduleDirs, args); err != nil {
+			return ex.Wrapf(err, "setting up read-only build")
+		}
+	}
+
 	// Auto-pin generates/updates otel.instrumentation.go file
 	var deps []*Dependency
 	if sp.ruleConfig == "" && os.Getenv(util.EnvOtelcRules) == "" {
@@ -398,7 +492,13 @@ func setupLocked(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 
-	// Match the hook code with these dependencies
+	// Match the hook code with these dependencies, for the target of the build
+	sp.target, err = buildTarget(ctx, args)
+	if err != nil {
+		return err
+	}
+	sp.Info("Build target", "target", sp.target)
+	sp.report.SetTarget(sp.target.GOOS, sp.target.GOARCH, sp.target.Tags)
 	matched, err := sp.matchDeps(ctx, deps, moduleDirs)
 	if err != nil {
 		return ex.Wrapf(err, "matching dependencies to hook rules")
@@ -546,13 +646,25 @@ func BuildWithToolexec(ctx context.Context, cmd *cli.Command, vendored bool) err
 	newArgs := make([]string, 0, len(args)+additionalCount) // Avoid in-place modification
 	// Add "go build"
 	newArgs = append(newArgs, "go")
-	newArgs = append(newArgs, args[:1]...)
+	program := runProgramFromContext(ctx)
+	if program != nil {
+		// goRun runs the binary once the module is restored
+		newArgs = append(newArgs, subcmdBuild)
+	} else {
+		newArgs = append(newArgs, args[:1]...)
+	}
 	// Add "-work" to give us a chance to debug instrumented code if needed
 	newArgs = append(newArgs, "-work")
 	// Add "-toolexec=..."
 	newArgs = append(newArgs, insert)
+	// Add "-modfile=... -overlay=..." to read the shadow workspace of a read-only build
+	shadow := shadowFromContext(ctx)
+	newArgs = append(newArgs, shadow.flags()...)
 	// Add the rest
 	restArgs := args[1:]
+	if program != nil {
+		restArgs = program.buildArgs(restArgs)
+	}
 	if vendored {
 		restArgs = rewriteModVendor(restArgs)
 	}
@@ -560,7 +672,7 @@ func BuildWithToolexec(ctx context.Context, cmd *cli.Command, vendored bool) err
 		// add otelc.runtime.go manually to command line for file targets
 		dir := filepath.Dir(fileTargets[0])
 		otelcRuntimePath := filepath.Join(dir, OtelcRuntimeFile)
-		if util.PathExists(otelcRuntimePath) {
+		if shadow.exists(otelcRuntimePath) {
 			restArgs = append(restArgs, otelcRuntimePath)
 		}
 	}
@@ -575,10 +687,11 @@ func BuildWithToolexec(ctx context.Context, cmd *cli.Command, vendored bool) err
 
 	// Extract and forward build flags that affect the build context
 	// This ensures `go list` resolves archives matching the current build
-	buildFlags := extractBuildFlags(args)
+	buildFlags := extractBuildFlags(restArgs)
 	if vendored {
 		buildFlags = rewriteModVendor(buildFlags)
 	}
+	buildFlags = append(buildFlags, shadow.flags()...)
 	if len(buildFlags) > 0 {
 		encoded := util.EncodeBuildFlags(buildFlags)
 		env = append(env, fmt.Sprintf("%s=%s", util.EnvOtelcBuildFlags, encoded))
@@ -598,17 +711,26 @@ func GoBuild(ctx context.Context, cmd *cli.Command) error {
 	// Validate the invocation before taking the build lock: a bad command
 	// line must fail immediately, not wait behind a running build.
 	if !cmd.Args().Present() {
-		return ex.Newf("no command provided. Only 'go build', 'go install' and 'go test' are supported")
+		return ex.Newf("no command provided. Only 'go build', 'go install', 'go run' and 'go test' " +
+			"are instrumented")
 	}
 
 	switch cmd.Args().First() {
-	case subcmdBuild, subcmdInstall, subcmdTest:
+	case subcmdBuild, subcmdInstall, subcmdRun, subcmdTest:
 		// supported
+	case subcmdVet, subcmdGenerate:
+		// Nothing is instrumented, so there is nothing to set up or restore
+		util.LoggerFromContext(ctx).InfoContext(ctx, "Running go command on the original sources",
+			"args", cmd.Args().Slice())
+		return util.RunCmd(ctx, append([]string{"go"}, cmd.Args().Slice()...)...)
 	default:
-		return ex.Newf("unsupported command: %s. Only 'go build', 'go install' and 'go test' are supported",
-			cmd.Args().First())
+		return ex.Newf("unsupported command: %s. Only 'go build', 'go install', 'go run' and 'go test' "+
+			"are instrumented", cmd.Args().First())
 	}
 
+	if cmd.Args().First() == subcmdRun {
+		return goRun(ctx, cmd)
+	}
 	// Serialize with other otelc invocations in this module before touching
 	// any shared state (tracking files, go.mod, .otelc-build contents).
 	return withBuildLock(ctx, func(ctx context.Context) error {
@@ -616,14 +738,33 @@ func GoBuild(ctx context.Context, cmd *cli.Command) error {
 	})
 }
 
-func runGoBuild(ctx context.Context, cmd *cli.Command) error {
+func runGoBuild(ctx context.Context, cmd *cli.Command) (err error) {
 	ctx = ContextWithStateManager(ctx, NewStateManager())
+	if cmd.Bool(readOnlyFlag) {
+		// Setup fills in the shadow workspace; the toolexec build reads it.
+		ctx = contextWithShadow(ctx, &shadowWorkspace{})
+	}
 	logger := util.LoggerFromContext(ctx)
 
 	// Clean up import tracking files from previous builds at the start
 	// to prevent stale data from affecting this build.
 	instrument.CleanupImportTrackingFiles()
 
+	// The report covers failed builds too, so it is written last, after
+	// go.mod and go.sum are restored by the deferred cleanup below.
+	if reportPath := os.Getenv(report.EnvReport); reportPath != "" {
+		rec := report.NewRecorder(append([]string{"go"}, cmd.Args().Slice()...))
+		ctx = report.ContextWithRecorder(ctx, rec)
+		if err = report.CleanFragments(); err != nil {
+			return err
+		}
+		defer func() {
+			if writeErr := rec.Write(reportPath, os.Getenv(report.EnvReportFormat), err); writeErr != nil {
+				err = ex.Join(err, writeErr)
+			}
+		}()
+	}
+
 	defer func() {
 		// Restore backed-up go.mod/go.sum but keep .otelc-build/ for debugging.
 		// Users can run `otelc cleanup` to remove it explicitly. Deferred
@@ -643,7 +784,8 @@ func runGoBuild(ctx context.Context, cmd *cli.Command) error {
 	vendored := vendoringActive(ctx, pwd)
 
 	setupStart := time.Now()
-	err := Setup(ctx, cmd)
+	err = Setup(ctx, cmd)
+	report.FromContext(ctx).AddDuration(report.PhaseSetup, time.Since(setupStart))
 	if err != nil {
 		return err
 	}
@@ -654,6 +796,7 @@ func runGoBuild(ctx context.Context, cmd *cli.Command) error {
 
 	buildStart := time.Now()
 	err = BuildWithToolexec(ctx, cmd, vendored)
+	report.FromContext(ctx).AddDuration(report.PhaseBuild, time.Since(buildStart))
 	if err != nil {
 		return err
 	}
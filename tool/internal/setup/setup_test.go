@@ -24,19 +24,43 @@ func TestGoBuild_RejectsUnsupportedSubcommand(t *testing.T) {
 		args []string
 	}{
 		{name: "no subcommand", args: []string{"go"}},
-		{name: "unsupported subcommand run", args: []string{"go", "run", "./..."}},
-		{name: "unsupported subcommand vet", args: []string{"go", "vet", "./..."}},
+		{name: "unsupported subcommand mod", args: []string{"go", "mod", "tidy"}},
+		{name: "unsupported subcommand get", args: []string{"go", "get", "./..."}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cmd := &cli.Command{Name: "go", SkipFlagParsing: true, Action: GoBuild}
 			err := cmd.Run(t.Context(), tt.args)
 			require.Error(t, err)
-			require.Contains(t, err.Error(), "supported")
+			require.Contains(t, err.Error(), "are instrumented")
 		})
 	}
 }
 
+func TestGoBuild_VetRunsOnOriginalSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	goMod := []byte("module example.com/vetted\n\ngo 1.25\n")
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "go.mod"), goMod, 0o644))
+	t.Chdir(tmpDir)
+
+	run := func(source string) error {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644))
+		cmd := &cli.Command{Name: "go", SkipFlagParsing: true, Action: GoBuild}
+		return cmd.Run(t.Context(), []string{"go", "vet", "."})
+	}
+
+	require.NoError(t, run("package main\n\nfunc main() {}\n"))
+	// Vet reports the user's code as written
+	err := run("package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Printf(\"%d\\n\", \"x\") }\n")
+	require.Error(t, err)
+
+	// Nothing was set up for instrumentation
+	assert.NoFileExists(t, filepath.Join(tmpDir, OtelcRuntimeFile))
+	content, err := os.ReadFile(filepath.Join(tmpDir, "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, goMod, content)
+}
+
 func TestGetPackages(t *testing.T) {
 	setupTestModule(t, []string{"cmd", "foo/demo"})
 
@@ -311,6 +335,55 @@ func setupTestModule(t *testing.T, subDirs []string) {
 	t.Chdir(tmpDir)
 }
 
+func TestSplitRunArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		buildArgs   []string
+		programArgs []string
+	}{
+		{
+			name:      "package only",
+			args:      []string{"./cmd/api"},
+			buildArgs: []string{"./cmd/api"},
+		},
+		{
+			name:        "program flags after the package",
+			args:        []string{"-race", "./cmd/api", "-port", "8080", "./data"},
+			buildArgs:   []string{"-race", "./cmd/api"},
+			programArgs: []string{"-port", "8080", "./data"},
+		},
+		{
+			name:        "flag values are not the package",
+			args:        []string{"-tags", "dev", "-exec", "sudo", ".", "serve"},
+			buildArgs:   []string{"-tags", "dev", "-exec", "sudo", "."},
+			programArgs: []string{"serve"},
+		},
+		{
+			name:        "go files up to the first other argument",
+			args:        []string{"main.go", "util.go", "input.go.txt", "other.go"},
+			buildArgs:   []string{"main.go", "util.go"},
+			programArgs: []string{"input.go.txt", "other.go"},
+		},
+		{
+			name:      "no package",
+			args:      []string{"-race"},
+			buildArgs: []string{"-race"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buildArgs, programArgs := splitRunArgs(tt.args)
+			assert.Equal(t, tt.buildArgs, buildArgs)
+			if tt.programArgs == nil {
+				assert.Empty(t, programArgs)
+			} else {
+				assert.Equal(t, tt.programArgs, programArgs)
+			}
+		})
+	}
+}
+
 func TestRootModulePaths(t *testing.T) {
 	tmpDir := t.TempDir()
 	appDir := filepath.Join(tmpDir, "app")
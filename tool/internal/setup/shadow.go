@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/internal/pkgload"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+const (
+	// readOnlyFlag is the otelc flag that enables read-only builds.
+	readOnlyFlag = "read-only"
+
+	shadowDir         = "shadow"
+	shadowOverlayFile = "overlay.json"
+)
+
+// shadowWorkspace redirects the files setup would write into the source tree
+// (go.mod, go.sum, otel.instrumentation.go and otelc.runtime.go) to the build
+// temp directory. The go commands otelc runs then see them through -modfile
+// and -overlay, so a read-only build never writes to the workspace and leaves
+// nothing to revert if it is interrupted.
+//
+// -modfile only applies to the main module, so a shadow workspace supports a
+// single module outside workspace mode.
+//
+// shadowWorkspace is not safe for concurrent use.
+type shadowWorkspace struct {
+	moduleDir string
+	modFile   string
+	// overlay maps the source path of every generated file to its shadow copy.
+	overlay map[string]string
+}
+
+type shadowWorkspaceKey struct{}
+
+// contextWithShadow returns a copy of ctx containing s.
+func contextWithShadow(ctx context.Context, s *shadowWorkspace) context.Context {
+	return context.WithValue(ctx, shadowWorkspaceKey{}, s)
+}
+
+// shadowFromContext returns the shadowWorkspace stored in ctx, or nil when the
+// build writes to the source tree. Every method is safe on a nil receiver.
+func shadowFromContext(ctx context.Context) *shadowWorkspace {
+	s, _ := ctx.Value(shadowWorkspaceKey{}).(*shadowWorkspace)
+	return s
+}
+
+// init copies the go.mod and go.sum of the single module in moduleDirs to the
+// shadow directory. args are the go command arguments, which must not set
+// -modfile or -overlay themselves. init is a no-op once the shadow is set up.
+func (s *shadowWorkspace) init(ctx context.Context, moduleDirs map[string]bool, args []string) error {
+	if s.modFile != "" {
+		return nil
+	}
+	for _, flag := range []string{"-modfile", "-overlay"} {
+		if util.FindFlagValue(args, flag) != "" {
+			return ex.Newf("read-only builds cannot be combined with %s", flag)
+		}
+	}
+	if len(moduleDirs) != 1 {
+		return ex.Newf("read-only builds support a single module, found %d: %v",
+			len(moduleDirs), slices.Sorted(maps.Keys(moduleDirs)))
+	}
+	moduleDir := slices.Collect(maps.Keys(moduleDirs))[0]
+	if _, workspace, err := pkgload.ModuleAndWorkspace(ctx, moduleDir); err != nil {
+		return err
+	} else if workspace {
+		return ex.Newf("read-only builds do not support workspace mode, set GOWORK=off")
+	}
+
+	dir := util.GetBuildTemp(shadowDir)
+	// A previous build may have left generated files behind.
+	if err := os.RemoveAll(dir); err != nil {
+		return ex.Wrapf(err, "removing shadow directory %s", dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return ex.Wrapf(err, "creating shadow directory %s", dir)
+	}
+	modFile := filepath.Join(dir, "go.mod")
+	if err := copyWritable(filepath.Join(moduleDir, "go.mod"), modFile); err != nil {
+		return err
+	}
+	goSum := filepath.Join(moduleDir, "go.sum")
+	if util.PathExists(goSum) {
+		if err := copyWritable(goSum, filepath.Join(dir, "go.sum")); err != nil {
+			return err
+		}
+	}
+
+	s.moduleDir = moduleDir
+	s.modFile = modFile
+	s.overlay = make(map[string]string)
+	util.LoggerFromContext(ctx).InfoContext(ctx, "using shadow go.mod", "module", moduleDir, "modfile", modFile)
+	return s.writeOverlay()
+}
+
+// goModPath returns the go.mod of moduleDir that setup reads and edits.
+func (s *shadowWorkspace) goModPath(moduleDir string) string {
+	if s != nil && s.modFile != "" && moduleDir == s.moduleDir {
+		return s.modFile
+	}
+	return filepath.Join(moduleDir, "go.mod")
+}
+
+// redirect returns the path to write the generated source file path to. In a
+// shadow workspace that is a copy under the shadow directory, which replaces
+// path in every later go command; otherwise it is path itself.
+func (s *shadowWorkspace) redirect(path string) (string, error) {
+	if s == nil || s.modFile == "" {
+		return path, nil
+	}
+	if dst, ok := s.overlay[path]; ok {
+		return dst, nil
+	}
+	// One directory per source directory keeps the base name, which is what
+	// the toolchain and the instrument phase see.
+	dst := filepath.Join(util.GetBuildTemp(shadowDir), util.CRC32(filepath.Dir(path)), filepath.Base(path))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", ex.Wrapf(err, "creating shadow directory for %s", path)
+	}
+	s.overlay[path] = dst
+	return dst, s.writeOverlay()
+}
+
+// source returns the file the go command reads for path: its shadow copy once
+// path has been redirected, otherwise path itself.
+func (s *shadowWorkspace) source(path string) string {
+	if s != nil {
+		if dst, ok := s.overlay[path]; ok {
+			return dst
+		}
+	}
+	return path
+}
+
+// exists reports whether path exists in the source tree or in the overlay.
+func (s *shadowWorkspace) exists(path string) bool {
+	return util.PathExists(s.source(path))
+}
+
+// flags returns the go command flags that make it read the shadow files, or
+// nil when there is no shadow workspace.
+func (s *shadowWorkspace) flags() []string {
+	if s == nil || s.modFile == "" {
+		return nil
+	}
+	return []string{
+		"-modfile=" + s.modFile,
+		"-overlay=" + util.GetBuildTemp(filepath.Join(shadowDir, shadowOverlayFile)),
+	}
+}
+
+func (s *shadowWorkspace) writeOverlay() error {
+	data, err := json.MarshalIndent(struct{ Replace map[string]string }{s.overlay}, "", "  ")
+	if err != nil {
+		return ex.Wrapf(err, "encoding overlay")
+	}
+	path := util.GetBuildTemp(filepath.Join(shadowDir, shadowOverlayFile))
+	if err = util.WriteFileAtomic(path, data, 0o644); err != nil {
+		return ex.Wrapf(err, "writing overlay %s", path)
+	}
+	return nil
+}
+
+// copyWritable copies src to dst. Unlike util.CopyFile it does not carry over
+// the permissions of src: the go command must be able to update the shadow
+// go.mod and go.sum of a read-only checkout.
+func copyWritable(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return ex.Wrapf(err, "reading %s", src)
+	}
+	if err = util.WriteFileAtomic(dst, data, 0o644); err != nil {
+		return ex.Wrapf(err, "writing %s", dst)
+	}
+	return nil
+}
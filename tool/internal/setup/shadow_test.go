@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+// shadowTestModule creates a read-only module and points the work dir at a
+// separate temp dir, as a read-only build does.
+func shadowTestModule(t *testing.T) string {
+	t.Helper()
+	t.Setenv(util.EnvOtelcWorkDir, t.TempDir())
+	t.Setenv("GOWORK", "off")
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.25\n"), 0o444))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), []byte(""), 0o444))
+	return dir
+}
+
+func TestShadowWorkspace_Nil(t *testing.T) {
+	s := shadowFromContext(t.Context())
+	require.Nil(t, s)
+
+	path := filepath.Join(t.TempDir(), "otelc.runtime.go")
+	dst, err := s.redirect(path)
+	require.NoError(t, err)
+	assert.Equal(t, path, dst)
+	assert.Equal(t, filepath.Join("app", "go.mod"), s.goModPath("app"))
+	assert.Nil(t, s.flags())
+	assert.Equal(t, path, s.source(path))
+	assert.False(t, s.exists(path))
+}
+
+func TestShadowWorkspace_Init(t *testing.T) {
+	dir := shadowTestModule(t)
+	s := &shadowWorkspace{}
+	ctx := contextWithShadow(t.Context(), s)
+	require.Same(t, s, shadowFromContext(ctx))
+
+	require.NoError(t, s.init(ctx, map[string]bool{dir: true}, []string{"build", "."}))
+
+	modFile := s.goModPath(dir)
+	assert.Equal(t, util.GetBuildTemp(filepath.Join(shadowDir, "go.mod")), modFile)
+	assert.Equal(t, filepath.Join("other", "go.mod"), s.goModPath("other"))
+	require.NoError(t, os.WriteFile(modFile, []byte("module example.com/app\n"), 0o644),
+		"the shadow go.mod is writable")
+	assert.FileExists(t, util.GetBuildTemp(filepath.Join(shadowDir, "go.sum")))
+	assert.Equal(t, []string{
+		"-modfile=" + modFile,
+		"-overlay=" + util.GetBuildTemp(filepath.Join(shadowDir, shadowOverlayFile)),
+	}, s.flags())
+
+	content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, "module example.com/app\n\ngo 1.25\n", string(content), "the source go.mod is untouched")
+}
+
+func TestShadowWorkspace_InitErrors(t *testing.T) {
+	dir := shadowTestModule(t)
+
+	err := (&shadowWorkspace{}).init(t.Context(), map[string]bool{dir: true}, []string{"build", "-modfile=alt.mod", "."})
+	require.ErrorContains(t, err, "cannot be combined with -modfile")
+
+	err = (&shadowWorkspace{}).init(t.Context(), map[string]bool{dir: true, t.TempDir(): true}, []string{"build"})
+	require.ErrorContains(t, err, "single module")
+}
+
+func TestShadowWorkspace_Redirect(t *testing.T) {
+	dir := shadowTestModule(t)
+	s := &shadowWorkspace{}
+	require.NoError(t, s.init(t.Context(), map[string]bool{dir: true}, nil))
+
+	path := filepath.Join(dir, "otelc.runtime.go")
+	dst, err := s.redirect(path)
+	require.NoError(t, err)
+	assert.NotEqual(t, path, dst)
+	assert.Equal(t, "otelc.runtime.go", filepath.Base(dst))
+	assert.False(t, s.exists(path))
+	require.NoError(t, os.WriteFile(dst, []byte("package main\n"), 0o644))
+	assert.True(t, s.exists(path), "redirected files exist in the overlay")
+	assert.Equal(t, dst, s.source(path))
+	assert.NoFileExists(t, path)
+
+	again, err := s.redirect(path)
+	require.NoError(t, err)
+	assert.Equal(t, dst, again)
+
+	data, err := os.ReadFile(util.GetBuildTemp(filepath.Join(shadowDir, shadowOverlayFile)))
+	require.NoError(t, err)
+	var overlay struct{ Replace map[string]string }
+	require.NoError(t, json.Unmarshal(data, &overlay))
+	assert.Equal(t, map[string]string{path: dst}, overlay.Replace)
+}
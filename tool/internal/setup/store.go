@@ -31,9 +31,10 @@ func resolveRulePaths(ctx context.Context, matched []*rule.InstRuleSet, moduleDi
 		var lastErr error
 		for moduleDir := range moduleDirs {
 			pkgs, err := packages.Load(&packages.Config{
-				Mode:    packages.NeedFiles,
-				Context: ctx,
-				Dir:     moduleDir,
+				Mode:       packages.NeedFiles,
+				Context:    ctx,
+				Dir:        moduleDir,
+				BuildFlags: shadowFromContext(ctx).flags(),
 			}, goPath)
 			if err != nil {
 				lastErr = err
@@ -86,7 +87,7 @@ func (sp *SetupPhase) store(ctx context.Context, matched []*rule.InstRuleSet, mo
 		return ex.Wrapf(err, "resolving rule paths")
 	}
 
-	f := util.GetMatchedRuleFile()
+	f := util.GetMatchedRuleFile(sp.target.GOOS, sp.target.GOARCH, sp.target.Tags)
 	file, err := os.Create(f)
 	if err != nil {
 		return ex.Wrapf(err, "failed to create file %s", f)
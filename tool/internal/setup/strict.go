@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/internal/report"
+	"go.opentelemetry.io/otelc/tool/internal/rule"
+)
+
+// checkApplied fails the build when expected instrumentation matched nothing:
+// a rule marked required, or in strict mode an instrumentation package pinned
+// in otel.instrumentation.go none of whose rules matched a package. Otherwise
+// a rule that stops matching after a dependency bump, because a function was
+// renamed or the version range excludes the new release, silently yields less
+// telemetry.
+//
+// Instrumentation packages are checked as a whole rather than rule by rule, as
+// many carry rules for versions or packages the build does not use.
+func checkApplied(allRules []rule.InstRule, matched []*rule.InstRuleSet, strict bool) error {
+	applied := make(map[rule.InstRule]bool)
+	for _, set := range matched {
+		for _, r := range set.AllRules() {
+			applied[r] = true
+		}
+	}
+
+	problems := make([]string, 0)
+	// The rules of each pinned instrumentation, and whether any applied
+	pinned := make(map[string][]rule.InstRule)
+	pinnedApplied := make(map[string]bool)
+	for _, r := range allRules {
+		if r.IsRequired() && !applied[r] {
+			problems = append(problems, fmt.Sprintf("required %s rule %q for %s matched nothing",
+				report.Kind(r), r.GetName(), describeTarget(r)))
+		}
+		if inst := r.GetOrigin().Instrumentation; strict && inst != "" {
+			pinned[inst] = append(pinned[inst], r)
+			pinnedApplied[inst] = pinnedApplied[inst] || applied[r]
+		}
+	}
+	for _, inst := range slices.Sorted(maps.Keys(pinned)) {
+		if pinnedApplied[inst] {
+			continue
+		}
+		targets := make([]string, 0, len(pinned[inst]))
+		for _, r := range pinned[inst] {
+			targets = append(targets, describeTarget(r))
+		}
+		slices.Sort(targets)
+		problems = append(problems, fmt.Sprintf("pinned instrumentation %s matched nothing (targets: %s)",
+			inst, strings.Join(slices.Compact(targets), ", ")))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	// A do: sequence expands into several rules of the same name
+	slices.Sort(problems)
+	problems = slices.Compact(problems)
+	return ex.Newf("expected instrumentation did not apply: %s", strings.Join(problems, "; "))
+}
+
+// describeTarget returns the target of a rule with its version range, if any.
+func describeTarget(r rule.InstRule) string {
+	if r.GetVersion() == "" {
+		return r.GetTarget()
+	}
+	return fmt.Sprintf("%s (version %s)", r.GetTarget(), r.GetVersion())
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otelc/tool/internal/rule"
+)
+
+func TestCheckApplied(t *testing.T) {
+	rules, err := parseRuleFromYaml([]byte(`
+client:
+  target: example.com/client
+  version: v1.0.0,v2.0.0
+  required: true
+  where:
+    func: Do
+  do:
+    - inject_hooks:
+        before: BeforeDo
+        path: example.com/instrumentation/client
+    - inject_code:
+        raw: "_ = 1"
+server:
+  target: example.com/server
+  where:
+    struct: Server
+  do:
+    - add_struct_fields:
+        new_field:
+          - name: span
+            type: any
+`))
+	require.NoError(t, err)
+	var client, server []rule.InstRule
+	for _, r := range rules {
+		if r.GetName() == "client" {
+			client = append(client, r)
+		} else {
+			server = append(server, r)
+		}
+	}
+	// required is shared by every rule a do: sequence expands into
+	require.Len(t, client, 2)
+	assert.True(t, client[0].IsRequired())
+	assert.True(t, client[1].IsRequired())
+	require.Len(t, server, 1)
+	assert.False(t, server[0].IsRequired())
+	rule.SetOrigin(client, rule.Origin{Instrumentation: "example.com/instrumentation/client"})
+	rule.SetOrigin(server, rule.Origin{Instrumentation: "example.com/instrumentation/server"})
+
+	matchedSet := func(rules ...rule.InstRule) []*rule.InstRuleSet {
+		set := rule.NewInstRuleSet("example.com/client")
+		for _, r := range rules {
+			switch rt := r.(type) {
+			case *rule.InstFuncRule:
+				set.AddFuncRule("/src/client/do.go", rt)
+			case *rule.InstRawRule:
+				set.AddRawRule("/src/client/do.go", rt)
+			}
+		}
+		return []*rule.InstRuleSet{set}
+	}
+
+	t.Run("required rules must match", func(t *testing.T) {
+		require.NoError(t, checkApplied(rules, matchedSet(client...), false))
+
+		err := checkApplied(rules, nil, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			`required func rule "client" for example.com/client (version v1.0.0,v2.0.0) matched nothing`)
+		assert.Contains(t, err.Error(),
+			`required raw rule "client" for example.com/client (version v1.0.0,v2.0.0) matched nothing`)
+		assert.NotContains(t, err.Error(), "pinned instrumentation")
+
+		// Each rule of the sequence is required
+		err = checkApplied(rules, matchedSet(client[0]), false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "required")
+	})
+
+	t.Run("strict mode requires pinned instrumentations to match", func(t *testing.T) {
+		err := checkApplied(rules, matchedSet(client...), true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"pinned instrumentation example.com/instrumentation/server matched nothing (targets: example.com/server)")
+		assert.NotContains(t, err.Error(), "example.com/instrumentation/client")
+	})
+
+	t.Run("rules passed with --rules are not pinned", func(t *testing.T) {
+		rule.SetOrigin(server, rule.Origin{File: "/rules/server.otelc.yml"})
+		require.NoError(t, checkApplied(server, nil, true))
+	})
+}
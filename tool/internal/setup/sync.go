@@ -15,6 +15,7 @@ import (
 	"golang.org/x/mod/semver"
 
 	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/internal/report"
 	"go.opentelemetry.io/otelc/tool/util"
 )
 
@@ -44,7 +45,8 @@ func writeGoMod(gomod string, modfile *modfile.File) error {
 }
 
 func runModTidy(ctx context.Context, moduleDir string) error {
-	return util.RunCmdInDir(ctx, moduleDir, "go", "mod", "tidy")
+	args := append([]string{"go", "mod", "tidy"}, shadowFromContext(ctx).flags()...)
+	return util.RunCmdInDir(ctx, moduleDir, args...)
 }
 
 func addReplace(modfile *modfile.File, oldPath, newPath string) (bool, error) {
@@ -99,6 +101,8 @@ func warnVersion(ctx context.Context, goModPath string, before versionSnapshot)
 		if goversion.Compare("go"+after.Go.Version, "go"+before.goVersion) > 0 {
 			_, _ = fmt.Fprintf(os.Stdout, "Bumped go version (%s -> %s)\n", before.goVersion, after.Go.Version)
 			logger.WarnContext(ctx, "bumped go version", "old", before.goVersion, "new", after.Go.Version)
+			report.FromContext(ctx).AddWarning("",
+				fmt.Sprintf("bumped go version (%s -> %s)", before.goVersion, after.Go.Version))
 		}
 	}
 
@@ -111,6 +115,8 @@ func warnVersion(ctx context.Context, goModPath string, before versionSnapshot)
 					"module", req.Mod.Path,
 					"old", oldVer,
 					"new", req.Mod.Version)
+				report.FromContext(ctx).AddWarning("",
+					fmt.Sprintf("bumped dependency %s (%s -> %s)", req.Mod.Path, oldVer, req.Mod.Version))
 			}
 		}
 	}
@@ -124,7 +130,14 @@ func syncDeps(ctx context.Context, modPaths map[string]bool, moduleDir string) e
 
 	logger := util.LoggerFromContext(ctx)
 
-	goModFile := filepath.Join(moduleDir, "go.mod")
+	goModFile := shadowFromContext(ctx).goModPath(moduleDir)
+
+	// Bundled modules come first, so the replace directives below only
+	// cover what the bundle does not.
+	if err := bundleFromContext(ctx).applyTo(ctx, goModFile); err != nil {
+		return ex.Wrapf(err, "using rule bundle in %s", moduleDir)
+	}
+
 	modfile, err := parseGoMod(goModFile)
 	if err != nil {
 		return err
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+
+	"go.opentelemetry.io/otelc/tool/ex"
+	"go.opentelemetry.io/otelc/tool/util"
+)
+
+// BuildTarget is what a build compiles for, as where.file.build predicates see
+// it: the target platform and the build tags set with -tags.
+type BuildTarget struct {
+	GOOS   string
+	GOARCH string
+	Tags   []string
+}
+
+func (t BuildTarget) String() string {
+	return t.GOOS + "/" + t.GOARCH + " tags=" + strings.Join(t.Tags, ",")
+}
+
+// buildTarget resolves the target of a build with the given go build flags.
+// The platform comes from go env, so GOOS and GOARCH count whether they are set
+// in the environment or with go env -w. As for the go command, -tags on the
+// command line takes precedence over -tags in GOFLAGS.
+func buildTarget(ctx context.Context, args []string) (BuildTarget, error) {
+	out, err := exec.CommandContext(ctx, "go", "env", "GOOS", "GOARCH", "GOFLAGS").Output()
+	if err != nil {
+		return BuildTarget{}, ex.Wrapf(err, "running go env")
+	}
+	env := strings.SplitN(string(out), "\n", 3)
+	if len(env) != 3 {
+		return BuildTarget{}, ex.Newf("unexpected go env output %q", out)
+	}
+	tags, ok := util.TagsFlag(args)
+	if !ok {
+		tags, _ = util.TagsFlag(strings.Fields(env[2]))
+	}
+	return BuildTarget{GOOS: env[0], GOARCH: env[1], Tags: tags}, nil
+}
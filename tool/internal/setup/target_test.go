@@ -0,0 +1,26 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package setup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTarget(t *testing.T) {
+	t.Setenv("GOOS", "windows")
+	t.Setenv("GOARCH", "arm64")
+	t.Setenv("GOFLAGS", "-tags=netgo")
+
+	target, err := buildTarget(t.Context(), []string{"."})
+	require.NoError(t, err)
+	assert.Equal(t, BuildTarget{GOOS: "windows", GOARCH: "arm64", Tags: []string{"netgo"}}, target)
+
+	// The command line takes precedence over GOFLAGS
+	target, err = buildTarget(t.Context(), []string{"-tags", "purego", "."})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"purego"}, target.Tags)
+}
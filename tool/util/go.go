@@ -92,6 +92,45 @@ func IsCgoCommand(line string) bool {
 		!strings.Contains(line, "-dynimport")
 }
 
+// TagsFlag returns the build tags of the last -tags flag in args, and whether
+// there is one. Tags are separated by commas or, in the legacy form, spaces.
+func TagsFlag(args []string) ([]string, bool) {
+	var value string
+	found := false
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-args" {
+			// The rest goes to the test binary
+			break
+		}
+		name, v, hasValue := strings.Cut(args[i], "=")
+		if name != "-tags" && name != "--tags" {
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				break
+			}
+			i++
+			v = args[i]
+		}
+		value, found = v, true
+	}
+	tags := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	return tags, found
+}
+
+// ToolTags returns the build tags of the build otelc runs as a tool of, as
+// setup resolves them: -tags in the build flags `otelc go build` forwards takes
+// precedence over -tags in GOFLAGS. A drop-in build must set its tags in
+// GOFLAGS, the go command does not pass the command line ones to tools.
+func ToolTags() []string {
+	tags, ok := TagsFlag(GetBuildFlags())
+	if !ok {
+		tags, _ = TagsFlag(strings.Fields(os.Getenv("GOFLAGS")))
+	}
+	return tags
+}
+
 // splitGoflags splits a GOFLAGS value into tokens like the go command does
 // (https://cs.opensource.google/go/go/+/master:src/cmd/internal/quoted/quoted.go)
 //
@@ -289,6 +328,29 @@ func mergeWindowsPathsWithSpaces(args []string) []string {
 	return result
 }
 
+// LinknamePath returns the import path as it appears in the symbol names of
+// the package, which is the form //go:linkname directives must use. Like the
+// linker, it escapes the dots in the last path element, so that the symbols of
+// "github.com/nats-io/nats.go" are prefixed with "github.com/nats-io/nats%2ego",
+// as well as '%', '"', spaces, control and non-ASCII characters.
+func LinknamePath(path string) string {
+	const hex = "0123456789abcdef"
+	slash := strings.LastIndex(path, "/")
+	var b strings.Builder
+	b.Grow(len(path))
+	for i := range len(path) {
+		c := path[i]
+		if c <= ' ' || (c == '.' && i > slash) || c == '%' || c == '"' || c >= 0x7f {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xf])
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
 func IsGoFile(path string) bool {
 	return strings.HasSuffix(strings.ToLower(path), ".go")
 }
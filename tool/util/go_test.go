@@ -584,6 +584,30 @@ func TestIsGoFile(t *testing.T) {
 	}
 }
 
+func TestLinknamePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"net/http", "net/http"},
+		{"go.opentelemetry.io/otelc/pkg/hook", "go.opentelemetry.io/otelc/pkg/hook"},
+		{"github.com/nats-io/nats.go", "github.com/nats-io/nats%2ego"},
+		{
+			"go.opentelemetry.io/otelc/instrumentation/github.com/nats-io/nats.go",
+			"go.opentelemetry.io/otelc/instrumentation/github.com/nats-io/nats%2ego",
+		},
+		{"gopkg.in/yaml.v3", "gopkg.in/yaml%2ev3"},
+		{"example.com/a%b", "example.com/a%25b"},
+		{"main", "main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, LinknamePath(tt.path))
+		})
+	}
+}
+
 func TestSplitCompileCmds(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -642,3 +666,43 @@ func TestSplitCompileCmds(t *testing.T) {
 		})
 	}
 }
+
+func TestTagsFlag(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		tags  []string
+		found bool
+	}{
+		{name: "no flag", args: []string{"-o", "app", "."}},
+		{name: "joined", args: []string{"-tags=netgo,osusergo", "."}, tags: []string{"netgo", "osusergo"}, found: true},
+		{name: "separate", args: []string{"-tags", "netgo", "."}, tags: []string{"netgo"}, found: true},
+		{name: "double dash", args: []string{"--tags=netgo"}, tags: []string{"netgo"}, found: true},
+		{name: "legacy spaces", args: []string{"-tags", "netgo osusergo"}, tags: []string{"netgo", "osusergo"}, found: true},
+		{name: "last wins", args: []string{"-tags=netgo", "-tags=purego"}, tags: []string{"purego"}, found: true},
+		{name: "empty clears", args: []string{"-tags=netgo", "-tags="}, found: true},
+		{name: "package named tags", args: []string{"tags"}},
+		{name: "test binary flag", args: []string{"./pkg", "-args", "-tags=netgo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, found := TagsFlag(tt.args)
+			assert.Equal(t, tt.found, found)
+			assert.ElementsMatch(t, tt.tags, tags)
+		})
+	}
+}
+
+func TestToolTags(t *testing.T) {
+	t.Setenv(EnvOtelcBuildFlags, "")
+	t.Setenv("GOFLAGS", "'-toolexec=otelc toolexec' -tags=netgo")
+	assert.Equal(t, []string{"netgo"}, ToolTags())
+
+	// The flags of otelc go build override GOFLAGS, as for the go command
+	t.Setenv(EnvOtelcBuildFlags, EncodeBuildFlags([]string{"-tags", "purego,osusergo"}))
+	assert.Equal(t, []string{"purego", "osusergo"}, ToolTags())
+
+	t.Setenv(EnvOtelcBuildFlags, EncodeBuildFlags([]string{"-race"}))
+	t.Setenv("GOFLAGS", "")
+	assert.Empty(t, ToolTags())
+}
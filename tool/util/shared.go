@@ -4,10 +4,14 @@
 package util
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 
 	"golang.org/x/mod/semver"
@@ -23,6 +27,22 @@ const (
 	// EnvOtelcDebug enables debug-level logging when set to "1".
 	// Set automatically when --debug is used; propagated to child processes.
 	EnvOtelcDebug = "OTELC_DEBUG"
+	// EnvOtelcReadOnly enables read-only builds when set to "true", the
+	// environment form of --read-only.
+	EnvOtelcReadOnly = "OTELC_READ_ONLY"
+	// EnvOtelcBundle is the environment form of --bundle, the rule bundle
+	// to build with.
+	EnvOtelcBundle = "OTELC_BUNDLE"
+	// EnvOtelcBundleKey is the environment form of --bundle-key, the public
+	// key the rule bundle must be signed with.
+	EnvOtelcBundleKey = "OTELC_BUNDLE_KEY"
+	// EnvOtelcBundleInsecure is the environment form of --bundle-insecure,
+	// which allows a rule bundle without EnvOtelcBundleKey when set to "true".
+	EnvOtelcBundleInsecure = "OTELC_BUNDLE_INSECURE"
+	// EnvOtelcStrict fails the build when expected instrumentation does not
+	// apply, when set to "1". Set automatically when --strict is used;
+	// propagated to child processes.
+	EnvOtelcStrict = "OTELC_STRICT"
 	// EnvOtelcNestedToolexec marks toolexec invocations spawned by a go
 	// command otelc itself ran (e.g. `go list -export`).
 	EnvOtelcNestedToolexec = "OTELC_NESTED_TOOLEXEC"
@@ -35,9 +55,33 @@ const (
 	OtelcToolExe           = "otelc"
 )
 
-func GetMatchedRuleFile() string {
-	const matchedRuleFile = "matched.json"
-	return GetBuildTemp(matchedRuleFile)
+// GetMatchedRuleFile returns the file that holds the rules matched for a
+// target platform and build tags, so that builds for different targets can
+// share a work directory without instrumenting with each other's rules. Rules
+// with a where.file.build predicate match on tags, so the name includes a
+// hash of the sorted tags, if any.
+func GetMatchedRuleFile(goos, goarch string, tags []string) string {
+	name := goos + "_" + goarch
+	if len(tags) > 0 {
+		sorted := slices.Compact(slices.Sorted(slices.Values(tags)))
+		sum := sha256.Sum256([]byte(strings.Join(sorted, ",")))
+		name += "_" + hex.EncodeToString(sum[:4])
+	}
+	return GetBuildTemp(fmt.Sprintf("matched.%s.json", name))
+}
+
+// ToolPlatform returns the target platform of the tool otelc runs as. The go
+// command sets GOOS and GOARCH for every tool it runs, toolexec included;
+// otherwise the platform otelc runs on is the target.
+func ToolPlatform() (string, string) {
+	goos, goarch := os.Getenv("GOOS"), os.Getenv("GOARCH")
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	return goos, goarch
 }
 
 // GetAddedImportsFileForProcess returns the per-process import tracking file.
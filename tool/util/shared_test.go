@@ -4,6 +4,8 @@
 package util
 
 import (
+	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -115,3 +117,36 @@ func TestVersionInRange(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchedRuleFilePerPlatform(t *testing.T) {
+	t.Setenv(EnvOtelcWorkDir, "/work")
+	t.Setenv("GOOS", "windows")
+	t.Setenv("GOARCH", "arm64")
+
+	goos, goarch := ToolPlatform()
+	if goos != "windows" || goarch != "arm64" {
+		t.Errorf("ToolPlatform() = %s/%s, want windows/arm64", goos, goarch)
+	}
+	want := filepath.Join("/work", BuildTempDir, "matched.windows_arm64.json")
+	if got := GetMatchedRuleFile(goos, goarch, nil); got != want {
+		t.Errorf("GetMatchedRuleFile() = %q, want %q", got, want)
+	}
+
+	// Builds with other tags may match other rules; the order of the tags
+	// does not matter
+	tagged := GetMatchedRuleFile(goos, goarch, []string{"netgo", "osusergo"})
+	if tagged == want {
+		t.Errorf("GetMatchedRuleFile() with tags = %q, want another file", tagged)
+	}
+	if got := GetMatchedRuleFile(goos, goarch, []string{"osusergo", "netgo"}); got != tagged {
+		t.Errorf("GetMatchedRuleFile() = %q, want %q", got, tagged)
+	}
+
+	// Outside of the go command, the target is the platform otelc runs on
+	t.Setenv("GOOS", "")
+	t.Setenv("GOARCH", "")
+	goos, goarch = ToolPlatform()
+	if goos != runtime.GOOS || goarch != runtime.GOARCH {
+		t.Errorf("ToolPlatform() = %s/%s, want %s/%s", goos, goarch, runtime.GOOS, runtime.GOARCH)
+	}
+}
@@ -54,6 +54,12 @@ func RunCmdInDir(ctx context.Context, dir string, args ...string) error {
 	return runCmd(ctx, dir, nil, args...)
 }
 
+// RunCmdInDirWithEnv executes a command in a specific directory with custom
+// environment variables.
+func RunCmdInDirWithEnv(ctx context.Context, dir string, env []string, args ...string) error {
+	return runCmd(ctx, dir, env, args...)
+}
+
 func IsWindows() bool {
 	return runtime.GOOS == "windows"
 }